@@ -0,0 +1,131 @@
+package layer1
+
+// DocumentDiff describes the structural changes between two Guidance Documents.
+// Categories, guidelines and parts are matched by ID: an ID found in only one
+// document is added or removed, and a matched element whose title differs is
+// renamed.
+type DocumentDiff struct {
+	AddedCategories   []ElementChange `json:"added-categories,omitempty" yaml:"added-categories,omitempty"`
+	RemovedCategories []ElementChange `json:"removed-categories,omitempty" yaml:"removed-categories,omitempty"`
+	RenamedCategories []ElementChange `json:"renamed-categories,omitempty" yaml:"renamed-categories,omitempty"`
+
+	AddedGuidelines   []ElementChange `json:"added-guidelines,omitempty" yaml:"added-guidelines,omitempty"`
+	RemovedGuidelines []ElementChange `json:"removed-guidelines,omitempty" yaml:"removed-guidelines,omitempty"`
+	RenamedGuidelines []ElementChange `json:"renamed-guidelines,omitempty" yaml:"renamed-guidelines,omitempty"`
+
+	// Parts of guidelines found in both documents whose text differs,
+	// including parts only one of them has
+	ChangedParts []PartChange `json:"changed-parts,omitempty" yaml:"changed-parts,omitempty"`
+}
+
+// ElementChange identifies a category or guideline that was added, removed or
+// renamed. OldTitle is empty for added elements and NewTitle for removed ones.
+type ElementChange struct {
+	Id string `json:"id" yaml:"id"`
+
+	// Category containing a guideline, in the document it is found in
+	// (the new one unless it was removed)
+	Category string `json:"category,omitempty" yaml:"category,omitempty"`
+
+	OldTitle string `json:"old-title,omitempty" yaml:"old-title,omitempty"`
+	NewTitle string `json:"new-title,omitempty" yaml:"new-title,omitempty"`
+}
+
+// PartChange holds the old and new text of a guideline part. OldText is empty
+// for an added part and NewText for a removed one.
+type PartChange struct {
+	GuidelineId string `json:"guideline-id" yaml:"guideline-id"`
+	PartId      string `json:"part-id" yaml:"part-id"`
+	OldText     string `json:"old-text,omitempty" yaml:"old-text,omitempty"`
+	NewText     string `json:"new-text,omitempty" yaml:"new-text,omitempty"`
+}
+
+// Empty reports whether the documents compared have the same structure
+func (d DocumentDiff) Empty() bool {
+	return len(d.AddedCategories) == 0 && len(d.RemovedCategories) == 0 && len(d.RenamedCategories) == 0 &&
+		len(d.AddedGuidelines) == 0 && len(d.RemovedGuidelines) == 0 && len(d.RenamedGuidelines) == 0 &&
+		len(d.ChangedParts) == 0
+}
+
+// Diff compares the categories, guidelines and part text of a (the old
+// document) and b (the new one). Changes are listed in document order, that of
+// b for added and matched elements and that of a for removed ones.
+func Diff(a, b *GuidanceDocument) DocumentDiff {
+	var diff DocumentDiff
+
+	oldCategories := make(map[string]*Category)
+	oldGuidelines := make(map[string]*Guideline)
+	for i := range a.Categories {
+		category := &a.Categories[i]
+		oldCategories[category.Id] = category
+		for j := range category.Guidelines {
+			oldGuidelines[category.Guidelines[j].Id] = &category.Guidelines[j]
+		}
+	}
+
+	newCategories := make(map[string]bool)
+	newGuidelines := make(map[string]bool)
+	for _, category := range b.Categories {
+		newCategories[category.Id] = true
+		if old, ok := oldCategories[category.Id]; !ok {
+			diff.AddedCategories = append(diff.AddedCategories, ElementChange{Id: category.Id, NewTitle: category.Title})
+		} else if old.Title != category.Title {
+			diff.RenamedCategories = append(diff.RenamedCategories, ElementChange{Id: category.Id, OldTitle: old.Title, NewTitle: category.Title})
+		}
+
+		for _, guideline := range category.Guidelines {
+			newGuidelines[guideline.Id] = true
+			old, ok := oldGuidelines[guideline.Id]
+			if !ok {
+				diff.AddedGuidelines = append(diff.AddedGuidelines, ElementChange{Id: guideline.Id, Category: category.Id, NewTitle: guideline.Title})
+				continue
+			}
+			if old.Title != guideline.Title {
+				diff.RenamedGuidelines = append(diff.RenamedGuidelines, ElementChange{
+					Id:       guideline.Id,
+					Category: category.Id,
+					OldTitle: old.Title,
+					NewTitle: guideline.Title,
+				})
+			}
+			diff.ChangedParts = append(diff.ChangedParts, diffParts(old, &guideline)...)
+		}
+	}
+
+	for _, category := range a.Categories {
+		if !newCategories[category.Id] {
+			diff.RemovedCategories = append(diff.RemovedCategories, ElementChange{Id: category.Id, OldTitle: category.Title})
+		}
+		for _, guideline := range category.Guidelines {
+			if !newGuidelines[guideline.Id] {
+				diff.RemovedGuidelines = append(diff.RemovedGuidelines, ElementChange{Id: guideline.Id, Category: category.Id, OldTitle: guideline.Title})
+			}
+		}
+	}
+
+	return diff
+}
+
+// diffParts lists the parts whose text differs between two versions of a
+// guideline: changed parts and added ones in b's order, then removed ones
+func diffParts(a, b *Guideline) []PartChange {
+	oldText := make(map[string]string, len(a.GuidelineParts))
+	for _, part := range a.GuidelineParts {
+		oldText[part.Id] = part.Text
+	}
+
+	var changes []PartChange
+	newParts := make(map[string]bool, len(b.GuidelineParts))
+	for _, part := range b.GuidelineParts {
+		newParts[part.Id] = true
+		if text, ok := oldText[part.Id]; !ok || text != part.Text {
+			changes = append(changes, PartChange{GuidelineId: b.Id, PartId: part.Id, OldText: text, NewText: part.Text})
+		}
+	}
+	for _, part := range a.GuidelineParts {
+		if !newParts[part.Id] {
+			changes = append(changes, PartChange{GuidelineId: b.Id, PartId: part.Id, OldText: part.Text})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,67 @@
+package layer1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	a := &GuidanceDocument{
+		Categories: []Category{
+			{
+				Id:    "AC",
+				Title: "Access Control",
+				Guidelines: []Guideline{
+					{
+						Id:    "AC-1",
+						Title: "Policy",
+						GuidelineParts: []Part{
+							{Id: "AC-1.a", Text: "Define a policy."},
+							{Id: "AC-1.b", Text: "Review the policy."},
+						},
+					},
+					{Id: "AC-2", Title: "Accounts"},
+				},
+			},
+			{Id: "AU", Title: "Audit"},
+		},
+	}
+	b := &GuidanceDocument{
+		Categories: []Category{
+			{
+				Id:    "AC",
+				Title: "Access Management",
+				Guidelines: []Guideline{
+					{
+						Id:    "AC-1",
+						Title: "Access Policy",
+						GuidelineParts: []Part{
+							{Id: "AC-1.a", Text: "Define and publish a policy."},
+							{Id: "AC-1.c", Text: "Enforce the policy."},
+						},
+					},
+					{Id: "AC-3", Title: "Enforcement"},
+				},
+			},
+			{Id: "IR", Title: "Incident Response"},
+		},
+	}
+
+	diff := Diff(a, b)
+
+	assert.Equal(t, []ElementChange{{Id: "IR", NewTitle: "Incident Response"}}, diff.AddedCategories)
+	assert.Equal(t, []ElementChange{{Id: "AU", OldTitle: "Audit"}}, diff.RemovedCategories)
+	assert.Equal(t, []ElementChange{{Id: "AC", OldTitle: "Access Control", NewTitle: "Access Management"}}, diff.RenamedCategories)
+	assert.Equal(t, []ElementChange{{Id: "AC-3", Category: "AC", NewTitle: "Enforcement"}}, diff.AddedGuidelines)
+	assert.Equal(t, []ElementChange{{Id: "AC-2", Category: "AC", OldTitle: "Accounts"}}, diff.RemovedGuidelines)
+	assert.Equal(t, []ElementChange{{Id: "AC-1", Category: "AC", OldTitle: "Policy", NewTitle: "Access Policy"}}, diff.RenamedGuidelines)
+	assert.Equal(t, []PartChange{
+		{GuidelineId: "AC-1", PartId: "AC-1.a", OldText: "Define a policy.", NewText: "Define and publish a policy."},
+		{GuidelineId: "AC-1", PartId: "AC-1.c", NewText: "Enforce the policy."},
+		{GuidelineId: "AC-1", PartId: "AC-1.b", OldText: "Review the policy."},
+	}, diff.ChangedParts)
+	assert.False(t, diff.Empty())
+
+	assert.True(t, Diff(a, a).Empty())
+}
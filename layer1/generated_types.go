@@ -38,6 +38,9 @@ type Metadata struct {
 	Applicability	*Applicability	`json:"applicability,omitempty" yaml:"applicability,omitempty"`
 
 	Exemptions	[]string	`json:"exemptions,omitempty" yaml:"exemptions,omitempty"`
+
+	// Entries from the document's change-history table
+	RevisionHistory	[]Revision	`json:"revision-history,omitempty" yaml:"revision-history,omitempty"`
 }
 
 // Mapping references is the same from Layer2, but intended for Layer 1 to Layer 1 mappings
@@ -69,6 +72,15 @@ type Applicability struct {
 	IndustrySectors	[]string	`json:"industry-sectors,omitempty" yaml:"industry-sectors,omitempty"`
 }
 
+// Revision is one release of the document as listed in its change history
+type Revision struct {
+	Version	string	`json:"version" yaml:"version"`
+
+	Date	string	`json:"date" yaml:"date"`
+
+	Description	string	`json:"description,omitempty" yaml:"description,omitempty"`
+}
+
 // Category represents a logical group of guidelines (i.e. control family)
 type Category struct {
 	Id	string	`json:"id" yaml:"id"`
@@ -77,6 +89,10 @@ type Category struct {
 
 	Description	string	`json:"description" yaml:"description"`
 
+	// Overrides the metadata document-type for this category, e.g. a best practice
+	// appendix in a regulation
+	DocumentType	DocumentType	`json:"document-type,omitempty" yaml:"document-type,omitempty"`
+
 	Guidelines	[]Guideline	`json:"guidelines,omitempty" yaml:"guidelines,omitempty"`
 }
 
@@ -107,6 +123,9 @@ type Guideline struct {
 
 	// This is akin to related controls, but using more explicit terminology
 	SeeAlso	[]string	`json:"see-also,omitempty" yaml:"see-also,omitempty"`
+
+	// Strongest RFC 2119 keyword in the guideline's recommendations
+	Normativity	Normativity	`json:"normativity,omitempty" yaml:"normativity,omitempty"`
 }
 
 // Rationale provides contextual information to help with development and understanding of
@@ -140,8 +159,15 @@ type Part struct {
 	Text	string	`json:"text" yaml:"text"`
 
 	Recommendations	[]string	`json:"recommendations,omitempty" yaml:"recommendations,omitempty"`
+
+	// Strongest RFC 2119 keyword in the part's text
+	Normativity	Normativity	`json:"normativity,omitempty" yaml:"normativity,omitempty"`
 }
 
+// Normative strength of a requirement per RFC 2119: MUST and SHALL, SHOULD and RECOMMENDED,
+// MAY and OPTIONAL
+type Normativity string
+
 type Mapping struct {
 	ReferenceId	string	`json:"reference-id" yaml:"reference-id"`
 
@@ -12,9 +12,12 @@ import (
 )
 
 type generateOpts struct {
-	version       string
-	imports       map[string]string
-	canonicalHref string
+	version           string
+	imports           map[string]string
+	canonicalHref     string
+	defaultImportHref string
+	requireImportHref bool
+	catalogUUID       string
 }
 
 func (g *generateOpts) complete(doc GuidanceDocument) {
@@ -43,12 +46,41 @@ func WithVersion(version string) GenerateOption {
 
 // WithOSCALImports is a GenerateOption that provides the `href` to guidance document mappings in OSCAL
 // by mapping unique identifier. If unset, the mapping URL of the guidance document will be used.
+// Hrefs are used as given, so relative (e.g. ./baseline.json) and file:// hrefs are supported.
 func WithOSCALImports(imports map[string]string) GenerateOption {
 	return func(opts *generateOpts) {
 		opts.imports = imports
 	}
 }
 
+// WithDefaultImportHref is a GenerateOption that provides the `href` used for imported
+// guidelines whose mapping has no URL or no entry in WithOSCALImports. If unset, such a
+// mapping is imported with an empty `href`, unless WithRequiredImportHrefs is given.
+func WithDefaultImportHref(href string) GenerateOption {
+	return func(opts *generateOpts) {
+		opts.defaultImportHref = href
+	}
+}
+
+// WithRequiredImportHrefs is a GenerateOption that makes an imported guideline mapping
+// without a URL (and no WithDefaultImportHref) an error rather than an import with an
+// empty `href`.
+func WithRequiredImportHrefs() GenerateOption {
+	return func(opts *generateOpts) {
+		opts.requireImportHref = true
+	}
+}
+
+// WithCatalogUUID is a GenerateOption that links a profile to the OSCAL Catalog generated
+// for the guidance document. The catalog's UUID and location are recorded in a back-matter
+// resource, and the local import references that resource by UUID (e.g. #<resource-uuid>)
+// instead of the catalog's location.
+func WithCatalogUUID(catalogUUID string) GenerateOption {
+	return func(opts *generateOpts) {
+		opts.catalogUUID = catalogUUID
+	}
+}
+
 // WithCanonicalHrefFormat is a GenerateOption that provides an `href` format string
 // for the canonical version of the guidance document. If set, this will be added as a
 // link in the metadata with the rel="canonical" attribute. Ex - https://myguidance.org/versions/%s
@@ -79,7 +111,7 @@ func (g *GuidanceDocument) ToOSCALProfile(guidanceDocHref string, opts ...Genera
 
 	for _, mapping := range g.ImportedGuidelines {
 		imp, ok := importMap[mapping.ReferenceId]
-		if !ok {
+		if !ok && options.defaultImportHref == "" {
 			continue
 		}
 
@@ -88,6 +120,14 @@ func (g *GuidanceDocument) ToOSCALProfile(guidanceDocHref string, opts ...Genera
 			withIds = append(withIds, oscalUtils.NormalizeControl(entry.ReferenceId, false))
 		}
 
+		if imp.Href == "" {
+			if options.defaultImportHref != "" {
+				imp.Href = options.defaultImportHref
+			} else if options.requireImportHref {
+				return oscal.Profile{}, fmt.Errorf("mapping reference %s has no URL for the import href", mapping.ReferenceId)
+			}
+		}
+
 		selector := oscal.SelectControlById{WithIds: &withIds}
 		imp.IncludeControls = &[]oscal.SelectControlById{selector}
 		importMap[mapping.ReferenceId] = imp
@@ -106,12 +146,20 @@ func (g *GuidanceDocument) ToOSCALProfile(guidanceDocHref string, opts ...Genera
 		Href:       guidanceDocHref,
 		IncludeAll: &oscal.IncludeAll{},
 	}
+
+	var backmatter *oscal.BackMatter
+	if options.catalogUUID != "" {
+		resource := catalogResource(g, guidanceDocHref, options.catalogUUID)
+		localImport.Href = fmt.Sprintf("#%s", resource.UUID)
+		backmatter = &oscal.BackMatter{Resources: &[]oscal.Resource{resource}}
+	}
 	imports = append(imports, localImport)
 
 	profile := oscal.Profile{
-		UUID:     uuid.NewUUID(),
-		Imports:  imports,
-		Metadata: metadata,
+		UUID:       uuid.NewUUID(),
+		Imports:    imports,
+		Metadata:   metadata,
+		BackMatter: backmatter,
 	}
 	return profile, nil
 }
@@ -169,6 +217,8 @@ func createMetadata(guidance *GuidanceDocument, opts generateOpts) (oscal.Metada
 		Version:      opts.version,
 		Published:    oscalUtils.GetTime(guidance.Metadata.PublicationDate),
 		LastModified: oscalUtils.GetTimeWithFallback(guidance.Metadata.LastModified, fallbackTime),
+		// Carry the introductory text so it survives a round trip
+		Remarks: guidance.FrontMatter,
 	}
 
 	if opts.canonicalHref != "" {
@@ -209,6 +259,10 @@ func (g *GuidanceDocument) createControlGroup(category Category, resourcesMap ma
 		ID:    category.Id,
 		Title: category.Title,
 	}
+	// A category that overrides the document type is classed by its own type
+	if category.DocumentType != "" && category.DocumentType != g.Metadata.DocumentType {
+		group.Class = documentTypeClass(category.DocumentType)
+	}
 
 	controlMap := make(map[string]oscal.Control)
 	for _, guideline := range category.Guidelines {
@@ -235,6 +289,12 @@ func (g *GuidanceDocument) createControlGroup(category Category, resourcesMap ma
 	return group
 }
 
+// documentTypeClass turns a document type into an OSCAL class token
+// ("Best Practice" becomes "best-practice")
+func documentTypeClass(documentType DocumentType) string {
+	return strings.ReplaceAll(strings.ToLower(string(documentType)), " ", "-")
+}
+
 func (g *GuidanceDocument) guidelineToControl(guideline Guideline, resourcesMap map[string]string) (oscal.Control, string) {
 	controlId := oscalUtils.NormalizeControl(guideline.Id, false)
 
@@ -343,6 +403,28 @@ func mappingToLinks(mappings []Mapping, resourcesMap map[string]string) []oscal.
 	return links
 }
 
+// catalogResource describes the guidance document's own OSCAL Catalog as a back-matter
+// resource, so a profile can reference the catalog by resource UUID
+func catalogResource(guidance *GuidanceDocument, catalogHref, catalogUUID string) oscal.Resource {
+	return oscal.Resource{
+		UUID:  uuid.NewUUID(),
+		Title: guidance.Metadata.Title,
+		Props: &[]oscal.Property{
+			{
+				Name:  "catalog-uuid",
+				Value: catalogUUID,
+				Ns:    oscalUtils.GemaraNamespace,
+			},
+		},
+		Rlinks: &[]oscal.ResourceLink{
+			{
+				Href:      catalogHref,
+				MediaType: "application/oscal.catalog+json",
+			},
+		},
+	}
+}
+
 func mappingToBackMatter(resourceRefs []MappingReference) *oscal.BackMatter {
 	var resources []oscal.Resource
 	for _, ref := range resourceRefs {
@@ -181,11 +181,29 @@ func TestToOSCALProfile(t *testing.T) {
 	guidanceWithImports.Metadata.MappingReferences = append(guidanceWithImports.Metadata.MappingReferences, mapping)
 	guidanceWithImports.ImportedGuidelines = append(guidanceWithImports.ImportedGuidelines, importedGuidelines)
 
+	// The same imports from a mapping reference that has no URL
+	guidanceWithoutURL := goodAIFG
+	mappingWithoutURL := mapping
+	mappingWithoutURL.Url = ""
+	guidanceWithoutURL.Metadata.MappingReferences = append(guidanceWithoutURL.Metadata.MappingReferences, mappingWithoutURL)
+	guidanceWithoutURL.ImportedGuidelines = append(guidanceWithoutURL.ImportedGuidelines, importedGuidelines)
+
+	wantIncludeControls := &[]oscalTypes.SelectControlById{
+		{
+			WithIds: &[]string{
+				"ex-1",
+				"ex-1.2",
+				"ex-2",
+			},
+		},
+	}
+
 	tests := []struct {
 		name        string
 		guidance    GuidanceDocument
 		options     []GenerateOption
 		wantImports []oscalTypes.Import
+		wantErr     bool
 	}{
 		{
 			name:     "Success/LocalOnly",
@@ -246,11 +264,90 @@ func TestToOSCALProfile(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "Success/WithRelativeImport",
+			guidance: guidanceWithImports,
+			options: []GenerateOption{
+				WithOSCALImports(map[string]string{
+					"EXP": "./baseline.json",
+				}),
+			},
+			wantImports: []oscalTypes.Import{
+				{
+					Href:            "./baseline.json",
+					IncludeControls: wantIncludeControls,
+				},
+				{
+					Href:       "testHref",
+					IncludeAll: &oscalTypes.IncludeAll{},
+				},
+			},
+		},
+		{
+			name:     "Success/WithFileSchemeImport",
+			guidance: guidanceWithImports,
+			options: []GenerateOption{
+				WithOSCALImports(map[string]string{
+					"EXP": "file:///baselines/example.json",
+				}),
+			},
+			wantImports: []oscalTypes.Import{
+				{
+					Href:            "file:///baselines/example.json",
+					IncludeControls: wantIncludeControls,
+				},
+				{
+					Href:       "testHref",
+					IncludeAll: &oscalTypes.IncludeAll{},
+				},
+			},
+		},
+		{
+			name:     "Success/WithDefaultImportHref",
+			guidance: guidanceWithoutURL,
+			options: []GenerateOption{
+				WithDefaultImportHref("./baseline.json"),
+			},
+			wantImports: []oscalTypes.Import{
+				{
+					Href:            "./baseline.json",
+					IncludeControls: wantIncludeControls,
+				},
+				{
+					Href:       "testHref",
+					IncludeAll: &oscalTypes.IncludeAll{},
+				},
+			},
+		},
+		{
+			name:     "Success/WithEmptyImportHref",
+			guidance: guidanceWithoutURL,
+			wantImports: []oscalTypes.Import{
+				{
+					Href:            "",
+					IncludeControls: wantIncludeControls,
+				},
+				{
+					Href:       "testHref",
+					IncludeAll: &oscalTypes.IncludeAll{},
+				},
+			},
+		},
+		{
+			name:     "Failure/MissingRequiredImportHref",
+			guidance: guidanceWithoutURL,
+			options:  []GenerateOption{WithRequiredImportHrefs()},
+			wantErr:  true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			profile, err := tt.guidance.ToOSCALProfile("testHref", tt.options...)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
 			require.NoError(t, err)
 			oscalDocument := oscalTypes.OscalModels{
 				Profile: &profile,
@@ -262,6 +359,90 @@ func TestToOSCALProfile(t *testing.T) {
 	}
 }
 
+func TestToOSCALProfileWithCatalogUUID(t *testing.T) {
+	guidance, err := goodAIGFExample()
+	require.NoError(t, err)
+
+	catalog, err := guidance.ToOSCALCatalog()
+	require.NoError(t, err)
+
+	profile, err := guidance.ToOSCALProfile("./catalog.json", WithCatalogUUID(catalog.UUID))
+	require.NoError(t, err)
+
+	require.NotNil(t, profile.BackMatter)
+	require.NotNil(t, profile.BackMatter.Resources)
+	require.Len(t, *profile.BackMatter.Resources, 1)
+	resource := (*profile.BackMatter.Resources)[0]
+	require.NotNil(t, resource.Props)
+	assert.Equal(t, "catalog-uuid", (*resource.Props)[0].Name)
+	assert.Equal(t, catalog.UUID, (*resource.Props)[0].Value)
+	require.NotNil(t, resource.Rlinks)
+	assert.Equal(t, "./catalog.json", (*resource.Rlinks)[0].Href)
+
+	// The local import references the catalog resource
+	localImport := profile.Imports[len(profile.Imports)-1]
+	assert.Equal(t, "#"+resource.UUID, localImport.Href)
+	assert.NotNil(t, localImport.IncludeAll)
+
+	// Without the option the import uses the catalog's location directly
+	profile, err = guidance.ToOSCALProfile("./catalog.json")
+	require.NoError(t, err)
+	assert.Nil(t, profile.BackMatter)
+	assert.Equal(t, "./catalog.json", profile.Imports[len(profile.Imports)-1].Href)
+}
+
+func TestFrontMatterRoundTrip(t *testing.T) {
+	goodAIFG, err := goodAIGFExample()
+	require.NoError(t, err)
+	require.NotEmpty(t, goodAIFG.FrontMatter)
+
+	catalog, err := goodAIFG.ToOSCALCatalog()
+	require.NoError(t, err)
+	assert.Equal(t, goodAIFG.FrontMatter, catalog.Metadata.Remarks)
+
+	restored, err := FromOSCALCatalog(catalog)
+	require.NoError(t, err)
+	assert.Equal(t, goodAIFG.FrontMatter, restored.FrontMatter)
+	assert.Equal(t, goodAIFG.Metadata.Id, restored.Metadata.Id)
+	assert.Equal(t, goodAIFG.Metadata.Title, restored.Metadata.Title)
+	require.Len(t, restored.Categories, len(goodAIFG.Categories))
+	assert.Equal(t, "DET", restored.Categories[0].Id)
+	require.NotEmpty(t, restored.Categories[0].Guidelines)
+	assert.Equal(t, "air-det-011", restored.Categories[0].Guidelines[0].Id)
+	assert.NotEmpty(t, restored.Categories[0].Guidelines[0].GuidelineParts)
+}
+
+func TestCategoryDocumentTypeRoundTrip(t *testing.T) {
+	goodAIFG, err := goodAIGFExample()
+	require.NoError(t, err)
+	require.NotEmpty(t, goodAIFG.Categories)
+	goodAIFG.Categories = append(goodAIFG.Categories, Category{
+		Id:           "GOV",
+		Title:        "Governance",
+		Description:  "Restates the document type",
+		DocumentType: goodAIFG.Metadata.DocumentType,
+	})
+	goodAIFG.Categories[0].DocumentType = "Best Practice"
+	last := len(goodAIFG.Categories) - 1
+
+	catalog, err := goodAIFG.ToOSCALCatalog()
+	require.NoError(t, err)
+	require.NotNil(t, catalog.Groups)
+	groups := *catalog.Groups
+	assert.Equal(t, "best-practice", groups[0].Class)
+	assert.Equal(t, "category", groups[last].Class)
+
+	restored, err := FromOSCALCatalog(catalog)
+	require.NoError(t, err)
+	assert.Equal(t, DocumentType("Best Practice"), restored.Categories[0].DocumentType)
+	assert.Empty(t, restored.Categories[last].DocumentType)
+}
+
+func TestFromOSCALCatalogEmpty(t *testing.T) {
+	_, err := FromOSCALCatalog(oscalTypes.Catalog{})
+	assert.Error(t, err)
+}
+
 func goodAIGFExample() (GuidanceDocument, error) {
 	testdataPath := "./test-data/good-aigf.yaml"
 	data, err := os.ReadFile(testdataPath)
@@ -0,0 +1,128 @@
+package layer1
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	oscal "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+)
+
+// FromOSCALCatalog creates a Layer 1 Guidance Document from an OSCAL Catalog produced
+// by ToOSCALCatalog. Groups become categories and controls become guidelines. IDs are
+// kept in their normalized OSCAL form, and content that ToOSCALCatalog flattens (such as
+// joined recommendations) is restored as-is.
+func FromOSCALCatalog(catalog oscal.Catalog) (GuidanceDocument, error) {
+	if catalog.Groups == nil || len(*catalog.Groups) == 0 {
+		return GuidanceDocument{}, fmt.Errorf("catalog %s does not have groups to convert to categories", catalog.UUID)
+	}
+
+	doc := GuidanceDocument{
+		Metadata: Metadata{
+			Title:        catalog.Metadata.Title,
+			Version:      catalog.Metadata.Version,
+			LastModified: catalog.Metadata.LastModified.Format(time.RFC3339),
+		},
+		FrontMatter: catalog.Metadata.Remarks,
+	}
+	if catalog.Metadata.Published != nil {
+		doc.Metadata.PublicationDate = catalog.Metadata.Published.Format(time.DateOnly)
+	}
+	if catalog.Metadata.Parties != nil && len(*catalog.Metadata.Parties) > 0 {
+		doc.Metadata.Author = (*catalog.Metadata.Parties)[0].Name
+	}
+
+	for _, group := range *catalog.Groups {
+		category := Category{
+			Id:    group.ID,
+			Title: group.Title,
+		}
+		// ToOSCALCatalog classes categories that override the document type by that type
+		if group.Class != "" && group.Class != "category" {
+			category.DocumentType = classDocumentType(group.Class)
+		}
+		if group.Controls != nil {
+			for _, control := range *group.Controls {
+				category.Guidelines = append(category.Guidelines, controlToGuideline(control, "")...)
+				// ToOSCALCatalog stores the document ID as the control class
+				if doc.Metadata.Id == "" {
+					doc.Metadata.Id = control.Class
+				}
+			}
+		}
+		doc.Categories = append(doc.Categories, category)
+	}
+
+	return doc, nil
+}
+
+// classDocumentType reverses documentTypeClass ("best-practice" becomes "Best Practice")
+func classDocumentType(class string) DocumentType {
+	words := strings.Split(class, "-")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return DocumentType(strings.Join(words, " "))
+}
+
+// controlToGuideline converts a control and its enhancements into guidelines
+func controlToGuideline(control oscal.Control, baseGuidelineID string) []Guideline {
+	guideline := Guideline{
+		Id:              control.ID,
+		Title:           control.Title,
+		BaseGuidelineID: baseGuidelineID,
+	}
+
+	if control.Links != nil {
+		for _, link := range *control.Links {
+			if link.Rel == "related" {
+				guideline.SeeAlso = append(guideline.SeeAlso, strings.TrimPrefix(link.Href, "#"))
+			}
+		}
+	}
+
+	if control.Parts != nil {
+		for _, part := range *control.Parts {
+			switch part.Name {
+			case "statement":
+				if part.Parts == nil {
+					continue
+				}
+				for _, item := range *part.Parts {
+					guideline.GuidelineParts = append(guideline.GuidelineParts, Part{
+						Id:    strings.TrimPrefix(item.ID, control.ID+"_smt."),
+						Title: item.Title,
+						Text:  item.Prose,
+					})
+				}
+			case "assessment-objective":
+				if part.Prose != "" {
+					guideline.Recommendations = []string{part.Prose}
+				}
+				if part.Parts == nil {
+					continue
+				}
+				for _, objective := range *part.Parts {
+					partID := strings.TrimPrefix(objective.ID, control.ID+"_obj.")
+					for i := range guideline.GuidelineParts {
+						if guideline.GuidelineParts[i].Id == partID {
+							guideline.GuidelineParts[i].Recommendations = []string{objective.Prose}
+						}
+					}
+				}
+			case "overview":
+				guideline.Objective = part.Prose
+			}
+		}
+	}
+
+	guidelines := []Guideline{guideline}
+	if control.Controls != nil {
+		for _, enhancement := range *control.Controls {
+			guidelines = append(guidelines, controlToGuideline(enhancement, control.ID)...)
+		}
+	}
+	return guidelines
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"github.com/ossf/gemara/layer1/pipeline"
+	"github.com/ossf/gemara/layer1/pipeline/parser"
+)
+
+// Exit codes returned by the pipeline CLI. Scripts can rely on these to
+// tell bad invocations apart from validation failures and environment issues.
+const (
+	exitOK         = 0
+	exitUsage      = 1 // Missing/invalid flags, unknown command or provider
+	exitValidation = 2 // Document failed schema validation
+	exitDependency = 3 // External tool (pdftotext, python3, ...) not installed
+	exitIO         = 4 // Reading or writing files/storage failed
+	exitFailure    = 5 // Any other processing failure (conversion, LLM call, ...)
+)
+
+// cmdError carries the exit code for a failure returned by a cmd* function
+type cmdError struct {
+	code int
+	err  error
+}
+
+func (e *cmdError) Error() string {
+	return e.err.Error()
+}
+
+func (e *cmdError) Unwrap() error {
+	return e.err
+}
+
+// usageErrorf reports a bad invocation
+func usageErrorf(format string, args ...interface{}) error {
+	return &cmdError{code: exitUsage, err: fmt.Errorf(format, args...)}
+}
+
+// validationErrorf reports a schema validation failure
+func validationErrorf(format string, args ...interface{}) error {
+	return &cmdError{code: exitValidation, err: fmt.Errorf(format, args...)}
+}
+
+// dependencyErrorf reports a missing external dependency
+func dependencyErrorf(format string, args ...interface{}) error {
+	return &cmdError{code: exitDependency, err: fmt.Errorf(format, args...)}
+}
+
+// ioErrorf reports a file or storage failure
+func ioErrorf(format string, args ...interface{}) error {
+	return &cmdError{code: exitIO, err: fmt.Errorf(format, args...)}
+}
+
+// exitCode maps an error returned by a cmd* function to a process exit code
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	var cmdErr *cmdError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.code
+	}
+
+	// Errors surfaced by the pipeline library carry sentinel classes
+	switch {
+	case errors.Is(err, pipeline.ErrInvalidConfig):
+		return exitUsage
+	case errors.Is(err, pipeline.ErrValidationFailed):
+		return exitValidation
+	case errors.Is(err, pipeline.ErrStorage):
+		return exitIO
+	case errors.Is(err, parser.ErrDocumentTooLarge), errors.Is(err, parser.ErrIncorrectPassword):
+		return exitUsage
+	}
+
+	// Parsers wrap exec lookups, so a missing binary is detectable even
+	// when the caller didn't classify the error
+	if errors.Is(err, exec.ErrNotFound) {
+		return exitDependency
+	}
+
+	return exitFailure
+}
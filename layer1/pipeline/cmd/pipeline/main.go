@@ -1,22 +1,25 @@
 package main
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/ossf/gemara/layer1"
+	"github.com/ossf/gemara/layer1/pipeline"
 	"github.com/ossf/gemara/layer1/pipeline/converter"
 	"github.com/ossf/gemara/layer1/pipeline/llm"
 	"github.com/ossf/gemara/layer1/pipeline/parser"
-	"github.com/ossf/gemara/layer1/pipeline/segmenter"
 	"github.com/ossf/gemara/layer1/pipeline/storage"
 	"github.com/ossf/gemara/layer1/pipeline/types"
 	"github.com/ossf/gemara/layer1/pipeline/validator"
@@ -29,18 +32,35 @@ var (
 	verbose    = flag.Bool("verbose", false, "Enable verbose output")
 	
 	// Parse flags
-	inputFile    = flag.String("input", "", "Input PDF file path")
-	parserType   = flag.String("parser", "simple", "Parser type (simple, docling, pymupdf)")
+	inputFile    = flag.String("input", "", "Input PDF file path (or .docx/.md with --parser docx/markdown)")
+	parserType   = flag.String("parser", "simple", "Parser type (simple, docling, pymupdf, docx, markdown)")
 	_ = flag.String("parser-config", "", "Parser configuration file") // Reserved for future use
+	pdftotextMode = flag.String("pdftotext-mode", "", "pdftotext mode for the simple parser (layout, raw, auto)")
+	maxBytes      = flag.Int64("max-bytes", 0, "Maximum input/extracted text size in bytes (0 = 256MB default, negative = unlimited)")
+	textOut       = flag.String("text-out", "", "Also write the parsed document as plain text to this file")
+	pdfPassword   = flag.String("pdf-password", "", "Password for encrypted PDFs")
+	columns       = flag.String("columns", "", "Column-aware extraction for the simple parser (auto, 1, 2)")
+	headingPatterns = flag.String("heading-patterns", "", "Extra comma-separated heading regexes for the simple parser")
 	
 	// Segment flags
-	segmenterType   = flag.String("segmenter", "generic", "Segmenter type (generic, pci-dss, nist-800-53)")
-	_ = flag.String("segmenter-config", "", "Segmenter configuration file") // Reserved for future use
+	segmenterType   = flag.String("segmenter", "generic", "Segmenter type (generic, pci-dss, nist-800-53, cis-benchmark, or one registered with segmenter.Register)")
+	structureBy     = flag.String("structure-by", "", "How to find categories/guidelines/parts (regex, level, both)")
+	maxRecommendations = flag.Int("max-recommendations", 0, "Keep at most n of the most relevant recommendation lines per guideline (0 = all)")
+	flatDocument    = flag.Bool("flat", false, "Segment top-level numbered items as guidelines of a single implicit category")
+	flatTitle       = flag.String("flat-title", "", "Title of the implicit category with --flat (default: document title)")
+	segmenterRules  = flag.String("segmenter-config", "", "YAML rules file overriding the segmenter's built-in patterns and keywords")
 	sourceVersion   = flag.Int("source-version", 0, "Source version (0 = latest)")
+	parsedFile      = flag.String("parsed-file", "", "ParsedDocument JSON from an external parser to segment instead of a stored parse")
 	
 	// Convert flags
-	outputFile   = flag.String("output", "", "Output file path")
-	outputFormat = flag.String("format", "yaml", "Output format (yaml, json)")
+	outputFile      = flag.String("output", "", "Output file path")
+	outputFormat    = flag.String("format", "", "Output format; the default depends on the command (yaml for convert and run-all)")
+	validateOutput  = flag.Bool("validate", true, "Validate converted output and fail on schema errors")
+	saveInvalid     = flag.Bool("save-invalid", false, "On validation failure, still save the document under invalid/ for inspection")
+	normalizeIDs    = flag.Bool("normalize-ids", false, "Rewrite all IDs to a canonical scheme")
+	idPrefix        = flag.String("id-prefix", "", "Prefix for normalized IDs (with --normalize-ids)")
+	idSeparator     = flag.String("id-separator", ".", "Separator for normalized IDs (with --normalize-ids)")
+	synthesizeParts = flag.Bool("synthesize-parts", false, "Give guidelines without parts a part built from their objective and recommendations")
 	
 	// Enhance flags
 	llmProvider = flag.String("llm-provider", "mock", "LLM provider (openai, anthropic, mock)")
@@ -48,82 +68,165 @@ var (
 	llmAPIKey   = flag.String("llm-api-key", "", "LLM API key (or set env var)")
 	temperature = flag.Float64("temperature", 0.3, "LLM temperature")
 	maxTokens   = flag.Int("max-tokens", 2000, "LLM max tokens")
+	saveLLMArtifacts = flag.Bool("save-llm-artifacts", false, "Store the raw LLM prompt/response with the enhanced version")
+	minChangeConfidence = flag.Float64("min-change-confidence", 0, "Drop LLM changes below this confidence (0-1)")
+	llmMaxRetries = flag.Int("llm-max-retries", 3, "Retries for rate-limited or failed LLM requests")
+	llmRetryBaseMs = flag.Int("llm-retry-base-ms", 500, "Backoff before the first LLM retry in milliseconds, doubled for each retry")
+	llmJSONMode = flag.String("llm-json-mode", "auto", "Constrain LLM responses to JSON via the provider API (auto, on, off)")
+	allowRemovals = flag.Bool("allow-removals", false, "Accept an enhancement that removes categories or guidelines")
 
 	// Validate flags
 	strictValidation = flag.Bool("strict", true, "Enable strict validation mode")
 	validateFile     = flag.String("validate-file", "", "Path to Layer-1 file to validate (optional)")
 	saveReport       = flag.Bool("save-report", true, "Save validation reports for audit trail")
+	strictDecode     = flag.Bool("strict-decode", false, "Reject unknown keys when loading Layer-1 YAML/JSON")
+	warningsAsErrors = flag.Bool("warnings-as-errors", false, "Report validation warnings as errors")
+	schemaSource     = flag.String("schema", "", "Also validate against a JSON Schema file path or http(s) URL")
+	schemaCache      = flag.String("schema-cache", "", "Directory to cache a remote --schema in")
+	
+	// Import flags
+	importFile = flag.String("file", "", "Edited Layer-1 YAML/JSON file to import as the next final version")
+	
+	// Run-all flags
+	jsonOutput = flag.Bool("json", false, "Emit the run-all result or convert-diff/coverage-diff/compare report as JSON on stdout (logs go to stderr)")
+	resume     = flag.Bool("resume", false, "Reuse stored parsed/segmented versions in run-all when the input is unchanged")
+
+	// Trace flags
+	element = flag.String("element", "", "ID of the Layer-1 category, guideline or part to trace")
+	
+	// Prune flags
+	keepVersions = flag.Int("keep", 3, "Number of newest parsed/segmented versions to keep when pruning")
+
+	// Coverage flags
+	decimals     = flag.Int("decimals", 1, "Decimal places for scores and percentages in the coverage report display")
+	fromVersion  = flag.String("from", "", "Segmented version to compare from in coverage-diff (e.g. v2)")
+	toVersion    = flag.String("to", "", "Segmented version to compare to in coverage-diff (e.g. v5, default: latest)")
+	reportFormat = flag.String("report-format", "", "Render the coverage report as md, html or json instead of printing it")
+
+	// Compare flags
+	compareA = flag.String("a", "", "Layer-1 file to compare from")
+	compareB = flag.String("b", "", "Layer-1 file to compare to")
+
+	// Lint flags
+	errorOnLint       = flag.Bool("error-on-lint", false, "Exit non-zero when lint findings are reported")
+	lintDisable       = flag.String("lint-disable", "", "Comma-separated lint rules to disable")
+	lintMinPartLength = flag.Int("lint-min-part-length", 20, "Minimum part text length before lint flags it")
+	lintMaxTextLength = flag.Int("lint-max-text-length", 2000, "Maximum part text or recommendation length before lint flags it (0 = no limit)")
+
+	// Bundle flags
+	bundleOut = flag.String("out", "", "Bundle path for bundle (default: <id>-bundle.tar.gz; --output also works)")
 )
 
 func main() {
 	if len(os.Args) < 2 {
 		printUsage()
-		os.Exit(1)
+		os.Exit(exitUsage)
 	}
 	
 	command := os.Args[1]
-	_ = flag.CommandLine.Parse(os.Args[2:]) // Error intentionally ignored; invalid flags will be handled by flag package
+	
+	// ContinueOnError so flag problems map to exitUsage rather than the
+	// flag package's own exit code (2), which would collide with exitValidation
+	flag.CommandLine.Init(os.Args[0], flag.ContinueOnError)
+	if err := flag.CommandLine.Parse(os.Args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(exitOK)
+		}
+		os.Exit(exitUsage)
+	}
 	
 	// Initialize storage
 	store, err := storage.NewStorage(*baseDir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitIO)
 	}
 	
 	ctx := context.Background()
 	
+	var prefix string
 	switch command {
 	case "parse":
-		if err := cmdParse(ctx, store); err != nil {
-			fmt.Fprintf(os.Stderr, "Parse error: %v\n", err)
-			os.Exit(1)
-		}
+		prefix = "Parse error"
+		err = cmdParse(ctx, store)
+	case "metadata":
+		prefix = "Metadata error"
+		err = cmdMetadata()
 	case "segment":
-		if err := cmdSegment(ctx, store); err != nil {
-			fmt.Fprintf(os.Stderr, "Segment error: %v\n", err)
-			os.Exit(1)
-		}
+		prefix = "Segment error"
+		err = cmdSegment(ctx, store)
 	case "convert":
-		if err := cmdConvert(ctx, store); err != nil {
-			fmt.Fprintf(os.Stderr, "Convert error: %v\n", err)
-			os.Exit(1)
-		}
+		prefix = "Convert error"
+		err = cmdConvert(ctx, store)
+	case "convert-diff":
+		prefix = "Convert-diff error"
+		err = cmdConvertDiff(store)
 	case "enhance":
-		if err := cmdEnhance(ctx, store); err != nil {
-			fmt.Fprintf(os.Stderr, "Enhance error: %v\n", err)
-			os.Exit(1)
-		}
+		prefix = "Enhance error"
+		err = cmdEnhance(ctx, store)
 	case "run-all":
-		if err := cmdRunAll(ctx, store); err != nil {
-			fmt.Fprintf(os.Stderr, "Pipeline error: %v\n", err)
-			os.Exit(1)
-		}
+		prefix = "Pipeline error"
+		err = cmdRunAll(ctx, store)
 	case "list":
-		if err := cmdList(store); err != nil {
-			fmt.Fprintf(os.Stderr, "List error: %v\n", err)
-			os.Exit(1)
-		}
+		prefix = "List error"
+		err = cmdList(store)
+	case "trace":
+		prefix = "Trace error"
+		err = cmdTrace(store)
+	case "prune":
+		prefix = "Prune error"
+		err = cmdPrune(store)
 	case "validate":
-		if err := cmdValidate(ctx, store); err != nil {
-			fmt.Fprintf(os.Stderr, "Validation error: %v\n", err)
-			os.Exit(1)
-		}
+		prefix = "Validation error"
+		err = cmdValidate(ctx, store)
+	case "revalidate-all":
+		prefix = "Revalidation error"
+		err = cmdRevalidateAll(store)
+	case "export-all":
+		prefix = "Export error"
+		err = cmdExportAll(store)
+	case "import":
+		prefix = "Import error"
+		err = cmdImport(store)
+	case "bundle":
+		prefix = "Bundle error"
+		err = cmdBundle(store)
+	case "schema":
+		prefix = "Schema error"
+		err = cmdSchema()
 	case "coverage":
-		if err := cmdCoverage(ctx, store); err != nil {
-			fmt.Fprintf(os.Stderr, "Coverage analysis error: %v\n", err)
-			os.Exit(1)
-		}
+		prefix = "Coverage analysis error"
+		err = cmdCoverage(ctx, store)
+	case "coverage-diff":
+		prefix = "Coverage diff error"
+		err = cmdCoverageDiff(store)
+	case "lint":
+		prefix = "Lint error"
+		err = cmdLint(store)
+	case "compare":
+		prefix = "Compare error"
+		err = cmdCompare()
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
 		printUsage()
-		os.Exit(1)
+		os.Exit(exitUsage)
+	}
+	
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", prefix, err)
+		os.Exit(exitCode(err))
 	}
 }
 
 func cmdParse(ctx context.Context, store *storage.Storage) error {
+	_, err := parseStage(ctx, store)
+	return err
+}
+
+// parseStage parses the input file and saves the parsed document
+func parseStage(ctx context.Context, store *storage.Storage) (*types.ParsedDocument, error) {
 	if *inputFile == "" {
-		return fmt.Errorf("--input is required")
+		return nil, usageErrorf("--input is required")
 	}
 	if *documentID == "" {
 		// Generate document ID from filename
@@ -133,86 +236,281 @@ func cmdParse(ctx context.Context, store *storage.Storage) error {
 	
 	log("Parsing %s with %s parser...\n", *inputFile, *parserType)
 	
-	// Configure parser
-	config := types.ParserConfig{
-		Provider:      *parserType,
-		TempDir:       filepath.Join(*baseDir, "temp"),
-		KeepTempFiles: *verbose,
-	}
-	
-	// Create parser
-	p, err := parser.NewParser(config)
+	doc, err := pipeline.ParseInput(pipeline.Config{
+		DocumentID: *documentID,
+		InputPath:  *inputFile,
+		Parser:     parserConfig(),
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create parser: %w", err)
-	}
-	
-	// Parse PDF
-	doc, err := p.Parse(*inputFile)
-	if err != nil {
-		return fmt.Errorf("parsing failed: %w", err)
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, dependencyErrorf("%w", err)
+		}
+		return nil, err
 	}
 	
-	doc.Metadata.DocumentID = *documentID
-	
 	// Save parsed document
 	if err := store.SaveParsed(doc); err != nil {
-		return fmt.Errorf("failed to save parsed document: %w", err)
+		return nil, ioErrorf("failed to save parsed document: %w", err)
 	}
 	
 	log("Parsed document saved: %s v%d\n", *documentID, doc.Metadata.Version)
 	log("  Pages: %d\n", len(doc.Pages))
 	log("  Total blocks: %d\n", countBlocks(doc))
 	
-	return nil
+	if *textOut != "" {
+		if err := os.WriteFile(*textOut, []byte(doc.ToText()), 0644); err != nil {
+			return nil, ioErrorf("failed to write text output: %w", err)
+		}
+		log("Plain text written to: %s\n", *textOut)
+	}
+	
+	return doc, nil
 }
 
-func cmdSegment(ctx context.Context, store *storage.Storage) error {
-	if *documentID == "" {
-		return fmt.Errorf("--document-id is required")
+// cmdMetadata prints a PDF's document metadata without parsing its structure
+func cmdMetadata() error {
+	if *inputFile == "" {
+		return usageErrorf("--input is required")
 	}
 	
-	log("Loading parsed document %s...\n", *documentID)
-	
-	// Load parsed document
-	parsed, err := store.LoadParsed(*documentID, *sourceVersion)
+	meta, err := parser.ExtractMetadataOnly(*inputFile)
 	if err != nil {
-		return fmt.Errorf("failed to load parsed document: %w", err)
+		if errors.Is(err, exec.ErrNotFound) {
+			return dependencyErrorf("%w", err)
+		}
+		return err
 	}
 	
-	log("Segmenting with %s segmenter...\n", *segmenterType)
-	
-	// Configure segmenter
+	if *outputFile != "" {
+		if err := saveToFile(*outputFile, meta, formatOr("yaml")); err != nil {
+			return ioErrorf("failed to write metadata: %w", err)
+		}
+		log("Metadata written to: %s\n", *outputFile)
+		return nil
+	}
+	data, err := marshalOutput(meta, formatOr("yaml"))
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// validatorOptions builds the validator options from the CLI flags
+func validatorOptions() []validator.Option {
+	opts := []validator.Option{validator.WithStrictMode(*strictValidation)}
+	if *warningsAsErrors {
+		opts = append(opts, validator.WithWarningsAsErrors(true))
+	}
+	if *schemaSource != "" {
+		opts = append(opts, validator.WithSchema(*schemaSource))
+		if *schemaCache != "" {
+			opts = append(opts, validator.WithSchemaCacheDir(*schemaCache))
+		}
+	}
+	return opts
+}
+
+// segmenterConfig builds the segmenter configuration from the CLI flags
+func segmenterConfig() types.SegmenterConfig {
 	config := types.SegmenterConfig{
+		RulesFile:    *segmenterRules,
 		DocumentType: *segmenterType,
+		Options:      map[string]string{},
+	}
+	if *structureBy != "" {
+		config.Options["structure_by"] = *structureBy
+	}
+	if *maxRecommendations != 0 {
+		config.Options["max_recommendations"] = strconv.Itoa(*maxRecommendations)
+	}
+	if *flatDocument {
+		config.Options["flat"] = "true"
+		if *flatTitle != "" {
+			config.Options["flat_title"] = *flatTitle
+		}
+	}
+	return config
+}
+
+// parserConfig builds the parser configuration from the CLI flags
+func parserConfig() types.ParserConfig {
+	config := types.ParserConfig{
+		Provider:      *parserType,
+		TempDir:       filepath.Join(*baseDir, "temp"),
+		KeepTempFiles: *verbose,
+		MaxBytes:      *maxBytes,
+		Options:       map[string]string{},
+	}
+	if *pdftotextMode != "" {
+		config.Options["pdftotext_mode"] = *pdftotextMode
+	}
+	if *pdfPassword != "" {
+		config.Options["pdf_password"] = *pdfPassword
+	}
+	if *columns != "" {
+		config.Options["columns"] = *columns
+	}
+	if *headingPatterns != "" {
+		config.Options["heading_patterns"] = *headingPatterns
+	}
+	return config
+}
+
+func cmdSegment(ctx context.Context, store *storage.Storage) error {
+	_, err := segmentStage(ctx, store)
+	return err
+}
+
+// segmentStage segments the stored parsed document, or one from
+// --parsed-file, and saves the result
+func segmentStage(ctx context.Context, store *storage.Storage) (*types.SegmentedDocument, error) {
+	if *documentID == "" {
+		return nil, usageErrorf("--document-id is required")
 	}
 	
-	// Create segmenter
-	seg, err := segmenter.NewSegmenter(config)
+	var parsed *types.ParsedDocument
+	var err error
+	if *parsedFile != "" {
+		parsed, err = loadExternalParsed(store)
+	} else {
+		log("Loading parsed document %s...\n", *documentID)
+		parsed, err = store.LoadParsed(*documentID, *sourceVersion)
+		if err != nil {
+			err = ioErrorf("failed to load parsed document: %w", err)
+		}
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create segmenter: %w", err)
+		return nil, err
 	}
 	
-	// Segment document
-	segmented, err := seg.Segment(parsed)
+	log("Segmenting with %s segmenter...\n", *segmenterType)
+	
+	segmented, err := pipeline.Segment(parsed, segmenterConfig())
 	if err != nil {
-		return fmt.Errorf("segmentation failed: %w", err)
+		return nil, err
+	}
+	for _, warning := range segmented.Warnings {
+		log("Warning: %s\n", warning)
 	}
 	
 	// Save segmented document
 	if err := store.SaveSegmented(segmented); err != nil {
-		return fmt.Errorf("failed to save segmented document: %w", err)
+		return nil, ioErrorf("failed to save segmented document: %w", err)
 	}
 	
 	log("Segmented document saved: %s v%d\n", *documentID, segmented.Metadata.Version)
 	log("  Categories: %d\n", len(segmented.Categories))
 	log("  Guidelines: %d\n", countSegmentedGuidelines(segmented))
 	
-	return nil
+	return segmented, nil
+}
+
+// loadExternalParsed loads --parsed-file and stores it as the document's
+// next parsed version, so later stages and trace see it like any parse
+func loadExternalParsed(store *storage.Storage) (*types.ParsedDocument, error) {
+	log("Loading parsed document from file: %s\n", *parsedFile)
+	parsed, err := parser.LoadParsedDocument(*parsedFile)
+	if err != nil {
+		return nil, ioErrorf("failed to load parsed file: %w", err)
+	}
+	parsed.Metadata.DocumentID = *documentID
+	
+	if err := store.SaveParsed(parsed); err != nil {
+		return nil, ioErrorf("failed to save parsed document: %w", err)
+	}
+	log("Parsed document saved: %s v%d (%d pages)\n", *documentID, parsed.Metadata.Version, len(parsed.Pages))
+	return parsed, nil
+}
+
+// printUsageAndCost logs the tokens an enhancement used and its estimated
+// cost, saying so when the provider or price table can't tell
+func printUsageAndCost(result *types.EnhancementResult) {
+	if result.Usage == nil {
+		log("  Tokens: not reported by provider\n")
+		return
+	}
+	log("  Tokens: %d prompt + %d completion = %d total\n",
+		result.Usage.PromptTokens, result.Usage.CompletionTokens, result.Usage.TotalTokens)
+	if _, ok := llm.EstimateCost(result.Model, result.Usage); !ok {
+		log("  Estimated cost: unknown (no price for model %s)\n", result.Model)
+		return
+	}
+	log("  Estimated cost: $%.4f\n", result.CostEstimate)
 }
 
 func cmdConvert(ctx context.Context, store *storage.Storage) error {
+	_, _, err := convertStage(ctx, store)
+	return err
+}
+
+// cmdConvertDiff converts the stored segmented document without saving it
+// and reports which fields the converter mapped, dropped or synthesized
+func cmdConvertDiff(store *storage.Storage) error {
+	if *documentID == "" {
+		return usageErrorf("--document-id is required")
+	}
+	
+	segmented, err := store.LoadSegmented(*documentID, *sourceVersion)
+	if err != nil {
+		return ioErrorf("failed to load segmented document: %w", err)
+	}
+	
+	conv := converter.NewConverter(converterOptions()...)
+	if _, err := conv.Convert(segmented); err != nil {
+		return fmt.Errorf("conversion failed: %w", err)
+	}
+	report := conv.Report()
+	
+	if *jsonOutput {
+		data, err := storage.MarshalCanonicalJSON(report)
+		if err != nil {
+			return fmt.Errorf("failed to marshal conversion report: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	
+	printConversionReport(*documentID, segmented.Metadata.Version, report)
+	return nil
+}
+
+func printConversionReport(docID string, version int, report *converter.ConversionReport) {
+	fmt.Printf("\nConversion of %s (segmented v%d)\n", docID, version)
+	
+	fmt.Printf("\nMapped: %d fields\n", len(report.Mapped))
+	for _, m := range report.Mapped {
+		if m.Source == m.Target {
+			fmt.Printf("  %s (%d)\n", m.Source, m.Count)
+		} else {
+			fmt.Printf("  %s -> %s (%d)\n", m.Source, m.Target, m.Count)
+		}
+	}
+	
+	printFieldChanges := func(title string, changes []converter.FieldChange) {
+		fmt.Printf("\n%s: %d\n", title, len(changes))
+		for _, c := range changes {
+			if c.Target != "" && c.Target != c.Path {
+				fmt.Printf("  %s -> %s: %s\n", c.Path, c.Target, c.Detail)
+			} else {
+				fmt.Printf("  %s: %s\n", c.Path, c.Detail)
+			}
+		}
+	}
+	printFieldChanges("Dropped", report.Dropped)
+	printFieldChanges("Synthesized", report.Synthesized)
+	printFieldChanges("Transformed", report.Transformed)
+}
+
+// convertStage converts the stored segmented document to Layer-1, validates
+// it (unless --validate=false) and saves it. The document and validation
+// result are returned even when validation fails.
+func convertStage(ctx context.Context, store *storage.Storage) (*layer1.GuidanceDocument, *validator.ValidationResult, error) {
 	if *documentID == "" {
-		return fmt.Errorf("--document-id is required")
+		return nil, nil, usageErrorf("--document-id is required")
+	}
+	if err := checkOutputFormat(); err != nil {
+		return nil, nil, err
 	}
 	
 	log("Loading segmented document %s...\n", *documentID)
@@ -220,48 +518,39 @@ func cmdConvert(ctx context.Context, store *storage.Storage) error {
 	// Load segmented document
 	segmented, err := store.LoadSegmented(*documentID, *sourceVersion)
 	if err != nil {
-		return fmt.Errorf("failed to load segmented document: %w", err)
+		return nil, nil, ioErrorf("failed to load segmented document: %w", err)
 	}
 	
 	log("Converting to Layer-1 format...\n")
-	
-	// Create converter
-	conv := converter.NewConverter()
-	
-	// Convert to Layer-1
-	layer1Doc, err := conv.Convert(segmented)
-	if err != nil {
-		return fmt.Errorf("conversion failed: %w", err)
+	if *validateOutput {
+		log("Validating against Layer-1 schema...\n")
+	} else {
+		log("Skipping schema validation (--validate=false)\n")
 	}
 	
-	// Validate against Layer-1 schema
-	log("Validating against Layer-1 schema...\n")
-	v := validator.NewValidator(validator.WithStrictMode(*strictValidation))
-	result := v.Validate(layer1Doc)
+	conv := converter.NewConverter(converterOptions()...)
+	layer1Doc, result, err := pipeline.ConvertWith(conv, segmented, *strictValidation, *validateOutput, validatorOptions()...)
+	for _, issue := range conv.IDIssues() {
+		log("Warning: %s\n", issue)
+	}
+	if err != nil && layer1Doc == nil {
+		return nil, nil, err
+	}
 	
-	// Create validation report for audit trail
+	// Create validation report for audit trail. Without the validation gate
+	// the report is marked as unvalidated so it can't be mistaken for a
+	// passing result.
 	var report *storage.ValidationReport
 	if *saveReport {
-		report = &storage.ValidationReport{
-			DocumentID:    *documentID,
-			Timestamp:     time.Now(),
-			StrictMode:    *strictValidation,
-			Valid:         result.Valid,
-			ErrorCount:    len(result.Errors),
-			SourceVersion: segmented.Metadata.Version,
-			Stage:         "convert",
-		}
-		// Convert errors for storage
-		for _, e := range result.Errors {
-			report.Errors = append(report.Errors, storage.ValidationError{
-				Path:    e.Path,
-				Message: e.Message,
-				Value:   e.Value,
-			})
-		}
+		report = pipeline.NewValidationReport(*documentID, "convert", segmented.Metadata.Version, *strictValidation, result)
+		report.Toolchain = toolchain(store, segmented, conv)
 	}
+	if !*validateOutput {
+		return layer1Doc, nil, saveConverted(store, layer1Doc, conv.Provenance(), report)
+	}
+	printValidationWarnings(result)
 	
-	if !result.Valid {
+	if err != nil {
 		log("Validation errors found:\n")
 		for _, e := range result.Errors {
 			log("  - %s\n", e.Error())
@@ -274,22 +563,77 @@ func cmdConvert(ctx context.Context, store *storage.Storage) error {
 				log("  Validation report saved for reference\n")
 			}
 		}
-		return fmt.Errorf("schema validation failed with %d errors", len(result.Errors))
+		if *saveInvalid {
+			if path, err := store.SaveInvalid(*documentID, layer1Doc, storedFormat()); err != nil {
+				log("Warning: failed to save invalid document: %v\n", err)
+			} else {
+				log("  Invalid document saved to %s\n", path)
+			}
+		}
+		return layer1Doc, result, validationErrorf("schema validation failed with %d errors (%d warnings)", len(result.Errors), len(result.Warnings))
 	}
-	log("  Schema validation passed ✓\n")
+	log("  Schema validation passed ✓ (%d warnings)\n", len(result.Warnings))
 	
+	return layer1Doc, result, saveConverted(store, layer1Doc, conv.Provenance(), report)
+}
+
+// toolchain names the components behind a converted document, looking up
+// the parser from the segmented document's source version. The parser is
+// left out when that parsed version is no longer stored.
+func toolchain(store *storage.Storage, segmented *types.SegmentedDocument, conv converter.Converter) *types.Toolchain {
+	parsed, err := store.LoadParsed(segmented.Metadata.DocumentID, segmented.Metadata.SourceVersion)
+	if err != nil {
+		parsed = nil
+	}
+	return pipeline.NewToolchain(parsed, segmented, conv)
+}
+
+// converterOptions builds converter options from the CLI flags
+func converterOptions() []converter.Option {
+	var opts []converter.Option
+	if scheme := idScheme(); scheme != nil {
+		opts = append(opts, converter.WithNormalizedIDs(*scheme))
+	}
+	if *synthesizeParts {
+		opts = append(opts, converter.WithSynthesizeParts(true))
+	}
+	return opts
+}
+
+// idScheme returns the ID normalization scheme, or nil when IDs are preserved
+func idScheme() *converter.IDScheme {
+	if !*normalizeIDs {
+		return nil
+	}
+	return &converter.IDScheme{
+		Prefix:    *idPrefix,
+		Separator: *idSeparator,
+	}
+}
+
+// saveConverted saves the final Layer-1 document (with its provenance and
+// report, if any) to storage and to the custom output path when one was
+// specified
+func saveConverted(store *storage.Storage, layer1Doc *layer1.GuidanceDocument, prov *types.Provenance, report *storage.ValidationReport) error {
 	// Save final document with validation report
-	if err := store.SaveFinalWithValidation(*documentID, layer1Doc, *outputFormat, report); err != nil {
-		return fmt.Errorf("failed to save final document: %w", err)
+	if err := store.SaveFinalWithValidation(*documentID, layer1Doc, storedFormat(), report); err != nil {
+		return ioErrorf("failed to save final document: %w", err)
 	}
-	if *saveReport {
-		log("  Validation report saved\n")
+	if err := store.SaveProvenance(*documentID, prov); err != nil {
+		return ioErrorf("failed to save provenance: %w", err)
+	}
+	if report != nil {
+		if report.Unvalidated {
+			log("  Validation report saved (unvalidated)\n")
+		} else {
+			log("  Validation report saved\n")
+		}
 	}
 	
 	// Also save to custom output path if specified
 	if *outputFile != "" {
-		if err := saveToFile(*outputFile, layer1Doc, *outputFormat); err != nil {
-			return fmt.Errorf("failed to save to output file: %w", err)
+		if err := saveLayer1ToFile(*outputFile, layer1Doc, formatOr("yaml")); err != nil {
+			return ioErrorf("failed to save to output file: %w", err)
 		}
 		log("Saved to: %s\n", *outputFile)
 	}
@@ -303,7 +647,10 @@ func cmdConvert(ctx context.Context, store *storage.Storage) error {
 
 func cmdEnhance(ctx context.Context, store *storage.Storage) error {
 	if *documentID == "" {
-		return fmt.Errorf("--document-id is required")
+		return usageErrorf("--document-id is required")
+	}
+	if *minChangeConfidence < 0 || *minChangeConfidence > 1 {
+		return usageErrorf("--min-change-confidence must be between 0 and 1")
 	}
 	
 	log("Loading segmented document %s...\n", *documentID)
@@ -311,7 +658,7 @@ func cmdEnhance(ctx context.Context, store *storage.Storage) error {
 	// Load segmented document
 	segmented, err := store.LoadSegmented(*documentID, *sourceVersion)
 	if err != nil {
-		return fmt.Errorf("failed to load segmented document: %w", err)
+		return ioErrorf("failed to load segmented document: %w", err)
 	}
 	
 	preEnhanceVersion := segmented.Metadata.Version
@@ -324,7 +671,7 @@ func cmdEnhance(ctx context.Context, store *storage.Storage) error {
 	if apiKey == "" {
 		apiKey = os.Getenv("LLM_API_KEY")
 		if apiKey == "" && *llmProvider != "mock" {
-			return fmt.Errorf("LLM API key required (--llm-api-key or LLM_API_KEY env var)")
+			return usageErrorf("LLM API key required (--llm-api-key or LLM_API_KEY env var)")
 		}
 	}
 	
@@ -334,64 +681,77 @@ func cmdEnhance(ctx context.Context, store *storage.Storage) error {
 		APIKey:      apiKey,
 		Temperature: *temperature,
 		MaxTokens:   *maxTokens,
+		Options: map[string]string{
+			"max_retries":   strconv.Itoa(*llmMaxRetries),
+			"retry_base_ms": strconv.Itoa(*llmRetryBaseMs),
+			"json_mode":     *llmJSONMode,
+		},
 	}
 	
-	// Create enhancer
-	enhancer, err := llm.NewEnhancer(config)
+	_, result, err := pipeline.Enhance(ctx, segmented, config)
 	if err != nil {
-		return fmt.Errorf("failed to create enhancer: %w", err)
+		return err
 	}
 	
-	// Enhance segmentation
-	result, err := enhancer.EnhanceSegmentation(ctx, segmented)
+	enhancedDoc, dropped, err := pipeline.FilterEnhancement(result, *minChangeConfidence)
 	if err != nil {
-		return fmt.Errorf("enhancement failed: %w", err)
+		return err
 	}
 	
 	log("Enhancement complete:\n")
 	log("  Provider: %s\n", result.Provider)
 	log("  Confidence: %.2f\n", result.Confidence)
 	log("  Changes: %d\n", len(result.Changes))
+	if dropped > 0 {
+		log("  Dropped: %d below confidence %.2f\n", dropped, *minChangeConfidence)
+	}
 	
 	if *verbose {
+		printUsageAndCost(result)
 		for i, change := range result.Changes {
 			log("  %d. %s: %s (%s)\n", i+1, change.Path, change.Type, change.Reason)
 		}
 	}
 	
-	// Extract enhanced document from result
-	enhancedDoc, ok := result.EnhancedData.(*types.SegmentedDocument)
-	if !ok {
-		return fmt.Errorf("enhanced data is not a SegmentedDocument")
+	// Content the LLM dropped must not silently replace the original
+	if err := llm.CheckRetention(segmented, enhancedDoc); err != nil {
+		if !*allowRemovals {
+			return fmt.Errorf("%w (re-run with --allow-removals to accept)", err)
+		}
+		log("⚠ High-risk change accepted with --allow-removals: %v\n", err)
 	}
 	
 	// Save enhanced segmented document with descriptive label
 	log("Saving enhanced segmented document...\n")
 	enhanceLabel := fmt.Sprintf("post-enhance-%s (pre-enhance: v%d)", *llmProvider, preEnhanceVersion)
 	if err := store.SaveSegmentedWithLabel(enhancedDoc, enhanceLabel); err != nil {
-		return fmt.Errorf("failed to save enhanced document: %w", err)
+		return ioErrorf("failed to save enhanced document: %w", err)
 	}
 	log("  Saved as version %d (label: %s)\n", enhancedDoc.Metadata.Version, enhanceLabel)
 	log("  Pre-enhance reference: version %d\n", preEnhanceVersion)
 	
+	if *saveLLMArtifacts {
+		artifact := pipeline.NewEnhancementArtifact(*documentID, enhancedDoc.Metadata.Version, preEnhanceVersion, result)
+		if err := store.SaveEnhancementArtifact(artifact); err != nil {
+			return ioErrorf("failed to save LLM artifact: %w", err)
+		}
+		log("  LLM artifact saved with version %d\n", enhancedDoc.Metadata.Version)
+	}
+	
 	// CRITICAL: Validate the enhanced document by converting to Layer-1 and checking schema
 	log("Validating enhanced document against Layer-1 schema...\n")
-	conv := converter.NewConverter()
-	layer1Doc, err := conv.Convert(enhancedDoc)
-	if err != nil {
+	layer1Doc, validationResult, err := pipeline.ConvertWith(converter.NewConverter(), enhancedDoc, *strictValidation, true, validatorOptions()...)
+	if err != nil && layer1Doc == nil {
 		return fmt.Errorf("conversion of enhanced document failed: %w", err)
 	}
-	
-	// Perform schema validation
-	v := validator.NewValidator(validator.WithStrictMode(*strictValidation))
-	validationResult := v.Validate(layer1Doc)
-	if !validationResult.Valid {
+	printValidationWarnings(validationResult)
+	if err != nil {
 		log("⚠ Validation WARNINGS after enhancement:\n")
 		for _, e := range validationResult.Errors {
 			log("  - %s\n", e.Error())
 		}
 		if *strictValidation {
-			return fmt.Errorf("enhanced document failed schema validation with %d errors", len(validationResult.Errors))
+			return validationErrorf("enhanced document failed schema validation with %d errors", len(validationResult.Errors))
 		}
 		log("  Continuing despite warnings (use --strict to fail on validation errors)\n")
 	} else {
@@ -403,15 +763,17 @@ func cmdEnhance(ctx context.Context, store *storage.Storage) error {
 
 func cmdRunAll(ctx context.Context, store *storage.Storage) error {
 	// Run complete pipeline: parse -> segment -> convert
-	if err := cmdParse(ctx, store); err != nil {
-		return err
-	}
+	result, err := runAll(ctx, store)
 	
-	if err := cmdSegment(ctx, store); err != nil {
-		return err
+	if *jsonOutput {
+		data, marshalErr := storage.MarshalCanonicalJSON(result)
+		if marshalErr != nil {
+			return fmt.Errorf("failed to marshal pipeline result: %w", marshalErr)
+		}
+		fmt.Println(string(data))
 	}
 	
-	if err := cmdConvert(ctx, store); err != nil {
+	if err != nil {
 		return err
 	}
 	
@@ -419,62 +781,354 @@ func cmdRunAll(ctx context.Context, store *storage.Storage) error {
 	return nil
 }
 
+// runAll maps the CLI flags onto a pipeline.Config and runs the library
+// pipeline against the configured storage
+func runAll(ctx context.Context, store *storage.Storage) (*pipeline.PipelineResult, error) {
+	if *inputFile == "" {
+		return &pipeline.PipelineResult{Error: "--input is required"}, usageErrorf("--input is required")
+	}
+	if err := checkOutputFormat(); err != nil {
+		return &pipeline.PipelineResult{Error: err.Error()}, err
+	}
+	
+	config := pipeline.Config{
+		DocumentID: *documentID,
+		InputPath:  *inputFile,
+		Parser:     parserConfig(),
+		Segmenter:  segmenterConfig(),
+		Strict:          *strictValidation,
+		SkipValidation:  !*validateOutput,
+		ValidatorOptions: validatorOptions(),
+		Coverage:        true,
+		NormalizeIDs:    idScheme(),
+		SynthesizeParts: *synthesizeParts,
+		Storage:         store,
+		OutputFormat:    storedFormat(),
+		SaveReport:      *saveReport,
+		SaveInvalid:     *saveInvalid,
+		Resume:          *resume,
+		Logf:            log,
+	}
+	log("Running pipeline on %s...\n", *inputFile)
+	result, err := pipeline.Run(ctx, config)
+	if result.Validation != nil {
+		printValidationWarnings(result.Validation)
+	}
+	if result.Validation != nil && !result.Validation.Valid {
+		log("Validation errors found:\n")
+		for _, e := range result.Validation.Errors {
+			log("  - %s\n", e.Error())
+		}
+	}
+	if err != nil {
+		return result, err
+	}
+	
+	// Also save to custom output path if specified
+	if *outputFile != "" {
+		if err := saveLayer1ToFile(*outputFile, result.Layer1, formatOr("yaml")); err != nil {
+			return result, ioErrorf("failed to save to output file: %w", err)
+		}
+		log("Saved to: %s\n", *outputFile)
+	}
+	
+	return result, nil
+}
+
 func cmdList(store *storage.Storage) error {
 	if *documentID == "" {
-		return fmt.Errorf("--document-id is required")
+		return usageErrorf("--document-id is required")
 	}
 	
 	// List all versions
 	parsed, err := store.ListVersions(*documentID, "parsed")
 	if err != nil {
-		return err
+		return ioErrorf("failed to list parsed versions: %w", err)
+	}
+	
+	segmented, err := store.ListVersions(*documentID, "segmented")
+	if err != nil {
+		return ioErrorf("failed to list segmented versions: %w", err)
+	}
+	
+	final, err := store.ListVersions(*documentID, "final")
+	if err != nil {
+		return ioErrorf("failed to list final versions: %w", err)
+	}
+	
+	fmt.Printf("Document: %s\n\n", *documentID)
+	
+	fmt.Println("Parsed versions:")
+	for _, v := range parsed {
+		fmt.Printf("  v%d - %s (%d bytes)%s\n", v.Version, v.StoredAt.Format(time.RFC3339), v.Size, checksumStatus(store, v))
+	}
+	
+	fmt.Println("\nSegmented versions:")
+	for _, v := range segmented {
+		fmt.Printf("  v%d - %s (%d bytes)%s\n", v.Version, v.StoredAt.Format(time.RFC3339), v.Size, checksumStatus(store, v))
+	}
+	
+	if len(final) > 0 {
+		fmt.Println("\nFinal versions:")
+		for _, v := range final {
+			fmt.Printf("  v%d - %s (%d bytes) %s%s\n", v.Version, v.StoredAt.Format(time.RFC3339), v.Size, v.Description, checksumStatus(store, v))
+		}
+	}
+	
+	return nil
+}
+
+func cmdPrune(store *storage.Storage) error {
+	if *documentID == "" {
+		return usageErrorf("--document-id is required")
+	}
+	if *keepVersions < 1 {
+		return usageErrorf("--keep must be at least 1")
+	}
+	
+	if err := store.PruneVersions(*documentID, *keepVersions); err != nil {
+		return ioErrorf("failed to prune versions: %w", err)
+	}
+	
+	log("Pruned %s to the %d newest parsed and segmented versions\n", *documentID, *keepVersions)
+	return nil
+}
+
+// cmdTrace prints the parsed blocks a final document's element came from
+func cmdTrace(store *storage.Storage) error {
+	if *documentID == "" {
+		return usageErrorf("--document-id is required")
+	}
+	if *element == "" {
+		return usageErrorf("--element is required")
+	}
+	
+	prov, err := store.LoadProvenance(*documentID)
+	if err != nil {
+		return ioErrorf("failed to load provenance (re-run convert to record it): %w", err)
+	}
+	matches := prov.Find(*element)
+	if len(matches) == 0 {
+		return usageErrorf("element %s not found in %s", *element, *documentID)
+	}
+	
+	parsed, err := store.LoadParsed(*documentID, prov.ParsedVersion)
+	if err != nil {
+		return ioErrorf("failed to load parsed document: %w", err)
+	}
+	pages := make(map[int]types.Page, len(parsed.Pages))
+	for _, page := range parsed.Pages {
+		pages[page.PageNumber] = page
+	}
+	
+	for _, match := range matches {
+		fmt.Printf("%s %s (%s), from parsed v%d:\n", match.Kind, match.ID, match.Path, parsed.Metadata.Version)
+		if len(match.Sources) == 0 {
+			fmt.Println("  no source blocks recorded")
+		}
+		for _, source := range match.Sources {
+			page, ok := pages[source.Page]
+			if !ok || source.Block >= len(page.Blocks) {
+				fmt.Printf("  page %d, block %d: not in the parsed document\n", source.Page, source.Block)
+				continue
+			}
+			block := page.Blocks[source.Block]
+			text := block.Text
+			if len(text) > 100 {
+				text = text[:97] + "..."
+			}
+			fmt.Printf("  page %d, block %d (%s): %s\n", source.Page, source.Block, block.Type, text)
+		}
+	}
+	
+	return nil
+}
+
+// checksumStatus returns a note for versions whose stored file doesn't
+// match its checksum or predates checksums, and "" for intact ones
+func checksumStatus(store *storage.Storage, v storage.StorageMetadata) string {
+	ok, err := store.VerifyChecksum(v.DocumentID, v.Type, v.Version)
+	switch {
+	case errors.Is(err, storage.ErrNoChecksum):
+		return " [unverified]"
+	case err != nil:
+		return fmt.Sprintf(" [unreadable: %v]", err)
+	case !ok:
+		return " [CHECKSUM MISMATCH]"
+	}
+	return ""
+}
+
+// cmdRevalidateAll re-runs the current validator over every stored final
+// document and saves fresh reports, so verdicts from older validator
+// versions can be replaced
+func cmdRevalidateAll(store *storage.Storage) error {
+	ids, err := store.ListFinal()
+	if err != nil {
+		return ioErrorf("failed to list final documents: %w", err)
+	}
+	if len(ids) == 0 {
+		log("No final documents found in %s\n", store.GetBaseDir())
+		return nil
+	}
+	
+	log("Revalidating %d documents with validator v%s (strict=%v)...\n", len(ids), validator.Version(), *strictValidation)
+	v := validator.NewValidator(validatorOptions()...)
+	failed := 0
+	for _, id := range ids {
+		layer1Doc, err := loadFinal(store, id)
+		if err != nil {
+			return ioErrorf("failed to load %s: %w", id, err)
+		}
+		
+		result := v.Validate(layer1Doc)
+		if result.Valid {
+			log("  ✓ %s\n", id)
+		} else {
+			failed++
+			log("  ✗ %s (%d errors)\n", id, len(result.Errors))
+		}
+		
+		if *saveReport {
+			report := pipeline.NewValidationReport(id, "revalidate", 0, *strictValidation, result)
+			if err := store.SaveValidationReport(report); err != nil {
+				return ioErrorf("failed to save validation report for %s: %w", id, err)
+			}
+		}
+	}
+	
+	log("\n%d of %d documents passed\n", len(ids)-failed, len(ids))
+	if failed > 0 {
+		return validationErrorf("%d documents failed schema validation", failed)
+	}
+	return nil
+}
+
+// cmdExportAll streams every stored final document as NDJSON to --output,
+// or to stdout when no output file is given
+func cmdExportAll(store *storage.Storage) error {
+	if format := formatOr("ndjson"); format != "ndjson" {
+		return usageErrorf("export-all only supports --format ndjson, not %q", format)
+	}
+	
+	if *outputFile == "" {
+		if err := store.StreamFinal(os.Stdout); err != nil {
+			return ioErrorf("failed to export final documents: %w", err)
+		}
+		return nil
+	}
+	
+	f, err := os.Create(*outputFile)
+	if err != nil {
+		return ioErrorf("failed to create output file: %w", err)
+	}
+	if err := store.StreamFinal(f); err != nil {
+		_ = f.Close()
+		return ioErrorf("failed to export final documents: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return ioErrorf("failed to write output file: %w", err)
+	}
+	log("✓ Exported final documents to: %s\n", *outputFile)
+	return nil
+}
+
+func cmdBundle(store *storage.Storage) error {
+	if *documentID == "" {
+		return usageErrorf("--document-id is required")
+	}
+	outPath := *bundleOut
+	if outPath == "" {
+		outPath = *outputFile
+	} else if *outputFile != "" && *outputFile != outPath {
+		return usageErrorf("--out and --output name different bundle paths")
+	}
+	if outPath == "" {
+		outPath = *documentID + "-bundle.tar.gz"
+	}
+	
+	f, err := os.Create(outPath)
+	if err != nil {
+		return ioErrorf("failed to create bundle file: %w", err)
+	}
+	manifest, err := store.WriteBundle(*documentID, f)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(outPath)
+		return ioErrorf("failed to write bundle: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return ioErrorf("failed to write bundle file: %w", err)
+	}
+	
+	log("✓ Bundled %d files for %s into: %s\n", len(manifest.Files), *documentID, outPath)
+	for _, file := range manifest.Files {
+		log("  - %s\n", file.Name)
+	}
+	return nil
+}
+
+func cmdSchema() error {
+	if format := formatOr("json"); format != "json" {
+		return usageErrorf("schema only supports --format json, not %q", format)
 	}
 	
-	segmented, err := store.ListVersions(*documentID, "segmented")
+	data, err := validator.JSONSchema()
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to generate schema: %w", err)
 	}
+	data = append(data, '\n')
 	
-	fmt.Printf("Document: %s\n\n", *documentID)
-	
-	fmt.Println("Parsed versions:")
-	for _, v := range parsed {
-		fmt.Printf("  v%d - %s (%d bytes)\n", v.Version, v.StoredAt.Format(time.RFC3339), v.Size)
+	if *outputFile == "" {
+		if _, err := os.Stdout.Write(data); err != nil {
+			return ioErrorf("failed to write schema: %w", err)
+		}
+		return nil
 	}
-	
-	fmt.Println("\nSegmented versions:")
-	for _, v := range segmented {
-		fmt.Printf("  v%d - %s (%d bytes)\n", v.Version, v.StoredAt.Format(time.RFC3339), v.Size)
+	if err := os.WriteFile(*outputFile, data, 0644); err != nil {
+		return ioErrorf("failed to write schema: %w", err)
 	}
-	
+	log("✓ JSON Schema written to: %s\n", *outputFile)
 	return nil
 }
 
 func cmdValidate(ctx context.Context, store *storage.Storage) error {
 	var layer1Doc *layer1.GuidanceDocument
+	var sourcePath string
+	var index validator.SourceIndex
 	var err error
 	
 	// Load from file or from storage
 	if *validateFile != "" {
 		log("Loading Layer-1 document from file: %s\n", *validateFile)
-		layer1Doc, err = loadLayer1FromFile(*validateFile)
+		layer1Doc, index, err = loadLayer1FromFile(*validateFile)
 		if err != nil {
-			return fmt.Errorf("failed to load file: %w", err)
+			return ioErrorf("failed to load file: %w", err)
 		}
+		sourcePath = *validateFile
 	} else if *documentID != "" {
 		log("Loading Layer-1 document from storage: %s\n", *documentID)
-		layer1Doc, err = store.LoadFinal(*documentID)
+		layer1Doc, err = loadFinal(store, *documentID)
 		if err != nil {
-			return fmt.Errorf("failed to load from storage: %w", err)
+			return ioErrorf("failed to load from storage: %w", err)
+		}
+		sourcePath, _ = store.FinalPath(*documentID)
+		if data, err := os.ReadFile(sourcePath); err == nil {
+			index, _ = validator.IndexSource(data)
 		}
 	} else {
-		return fmt.Errorf("either --document-id or --validate-file is required")
+		return usageErrorf("either --document-id or --validate-file is required")
 	}
 	
 	// Perform validation
 	log("Validating against Layer-1 schema (strict=%v)...\n", *strictValidation)
-	v := validator.NewValidator(validator.WithStrictMode(*strictValidation))
+	v := validator.NewValidator(validatorOptions()...)
 	result := v.Validate(layer1Doc)
+	result.Locate(index)
+	printValidationWarnings(result)
+	if *outputFormat == "github" {
+		printGitHubAnnotations(result, sourcePath)
+	}
 	
 	if result.Valid {
 		log("\n✓ Validation PASSED\n")
@@ -486,17 +1140,143 @@ func cmdValidate(ctx context.Context, store *storage.Storage) error {
 		return nil
 	}
 	
-	// Report validation errors
+	printValidationErrors(result)
+	return validationErrorf("schema validation failed")
+}
+
+// printValidationErrors logs each validation error with its location
+func printValidationErrors(result *validator.ValidationResult) {
 	log("\n✗ Validation FAILED with %d errors:\n\n", len(result.Errors))
 	for i, e := range result.Errors {
 		log("  %d. [%s] %s", i+1, e.Path, e.Message)
 		if e.Value != nil {
 			log(" (got: %v)", e.Value)
 		}
+		if e.Line > 0 {
+			log(" (line %d)", e.Line)
+		}
 		log("\n")
 	}
+}
+
+// cmdImport stores an externally edited Layer-1 file as the document's
+// next final version. The file must pass validation, and the stored copy
+// is checked against the file's checksum before the import is reported.
+func cmdImport(store *storage.Storage) error {
+	if *documentID == "" {
+		return usageErrorf("--document-id is required")
+	}
+	if *importFile == "" {
+		return usageErrorf("--file is required")
+	}
+	
+	var format string
+	switch strings.ToLower(filepath.Ext(*importFile)) {
+	case ".yaml", ".yml":
+		format = "yaml"
+	case ".json":
+		format = "json"
+	default:
+		return usageErrorf("--file must be a .yaml, .yml or .json file")
+	}
+	
+	data, err := os.ReadFile(*importFile)
+	if err != nil {
+		return ioErrorf("failed to read file: %w", err)
+	}
+	layer1Doc, err := storage.DecodeLayer1(data, format, *strictDecode)
+	if err != nil {
+		return validationErrorf("%s is not a Layer-1 document: %w", *importFile, err)
+	}
+	if layer1Doc.Metadata.Id != *documentID {
+		log("⚠ metadata.id %q differs from --document-id %s\n", layer1Doc.Metadata.Id, *documentID)
+	}
 	
-	return fmt.Errorf("schema validation failed")
+	log("Validating %s (strict=%v)...\n", *importFile, *strictValidation)
+	result := validator.NewValidator(validatorOptions()...).Validate(layer1Doc)
+	index, _ := validator.IndexSource(data)
+	result.Locate(index)
+	printValidationWarnings(result)
+	if !result.Valid {
+		printValidationErrors(result)
+		return validationErrorf("%s failed schema validation; nothing was imported", *importFile)
+	}
+	
+	meta, err := store.SaveFinalVersion(*documentID, data, format, "imported from "+filepath.Base(*importFile))
+	if err != nil {
+		return ioErrorf("failed to save final version: %w", err)
+	}
+	ok, err := store.VerifyChecksum(*documentID, "final", meta.Version)
+	if err != nil {
+		return ioErrorf("failed to verify stored copy: %w", err)
+	}
+	if !ok {
+		return ioErrorf("stored copy of final v%d does not match checksum %s", meta.Version, meta.Checksum)
+	}
+	
+	if *saveReport {
+		report := pipeline.NewValidationReport(*documentID, "import", 0, *strictValidation, result)
+		if err := store.SaveValidationReport(report); err != nil {
+			return ioErrorf("failed to save validation report: %w", err)
+		}
+	}
+	
+	log("Imported %s as final v%d of %s\n", *importFile, meta.Version, *documentID)
+	log("  SHA-256: %s\n", meta.Checksum)
+	log("  Categories: %d\n", len(layer1Doc.Categories))
+	log("  Total guidelines: %d\n", countLayer1Guidelines(layer1Doc))
+	return nil
+}
+
+// printValidationWarnings logs non-fatal validation warnings
+func printValidationWarnings(result *validator.ValidationResult) {
+	if len(result.Warnings) == 0 {
+		return
+	}
+	log("Validation warnings:\n")
+	for _, w := range result.Warnings {
+		log("  ⚠ %s\n", w.Error())
+	}
+}
+
+// printGitHubAnnotations prints validation errors and warnings as GitHub
+// Actions workflow commands, so they show up inline on a pull request. The
+// line is looked up from each error's path in the validated file.
+// Errors located by ValidationResult.Locate use their Line and Column.
+func printGitHubAnnotations(result *validator.ValidationResult, file string) {
+	data, _ := os.ReadFile(file)
+	annotate := func(level string, e validator.ValidationError) {
+		var props []string
+		if file != "" {
+			props = append(props, "file="+escapeGitHubProperty(file))
+			if e.Line > 0 {
+				props = append(props, fmt.Sprintf("line=%d", e.Line), fmt.Sprintf("col=%d", e.Column))
+			} else if line := validator.PathLine(data, e.Path); line > 0 {
+				props = append(props, fmt.Sprintf("line=%d", line))
+			}
+		}
+		command := "::" + level
+		if len(props) > 0 {
+			command += " " + strings.Join(props, ",")
+		}
+		fmt.Printf("%s::%s\n", command, escapeGitHubData(e.Error()))
+	}
+	for _, w := range result.Warnings {
+		annotate("warning", w)
+	}
+	for _, e := range result.Errors {
+		annotate("error", e)
+	}
+}
+
+// escapeGitHubData escapes a workflow command's message
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty escapes a workflow command's property value
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
 }
 
 func cmdCoverage(ctx context.Context, store *storage.Storage) error {
@@ -508,15 +1288,15 @@ func cmdCoverage(ctx context.Context, store *storage.Storage) error {
 	// Load documents for coverage analysis
 	if *validateFile != "" {
 		log("Loading Layer-1 document from file: %s\n", *validateFile)
-		layer1Doc, err = loadLayer1FromFile(*validateFile)
+		layer1Doc, _, err = loadLayer1FromFile(*validateFile)
 		if err != nil {
-			return fmt.Errorf("failed to load file: %w", err)
+			return ioErrorf("failed to load file: %w", err)
 		}
 	} else if *documentID != "" {
 		log("Loading documents for coverage analysis: %s\n", *documentID)
 		
 		// Try to load all available documents for comprehensive analysis
-		layer1Doc, err = store.LoadFinal(*documentID)
+		layer1Doc, err = loadFinal(store, *documentID)
 		if err != nil {
 			log("  Note: Final Layer-1 document not found, will analyze available data\n")
 		}
@@ -531,7 +1311,16 @@ func cmdCoverage(ctx context.Context, store *storage.Storage) error {
 			log("  Note: Parsed document not found\n")
 		}
 	} else {
-		return fmt.Errorf("either --document-id or --validate-file is required")
+		return usageErrorf("either --document-id or --validate-file is required")
+	}
+	
+	if *decimals < 0 {
+		return usageErrorf("--decimals must not be negative")
+	}
+	switch *reportFormat {
+	case "", "md", "html", "json":
+	default:
+		return usageErrorf("unsupported --report-format %q (md, html or json)", *reportFormat)
 	}
 	
 	// Perform coverage analysis
@@ -546,11 +1335,22 @@ func cmdCoverage(ctx context.Context, store *storage.Storage) error {
 		log("Analyzing schema coverage from Layer-1 document...\n")
 		report = analyzer.AnalyzeLayer1(layer1Doc)
 	} else {
-		return fmt.Errorf("no documents available for coverage analysis")
+		return ioErrorf("no documents available for coverage analysis")
+	}
+	
+	// Record how the Layer-1 document fares against the schema, keeping
+	// warnings apart from errors
+	if layer1Doc != nil {
+		result := validator.NewValidator(validatorOptions()...).Validate(layer1Doc)
+		report.Validation = validator.SummarizeValidation(result)
 	}
 	
-	// Display coverage report
-	printCoverageReport(report)
+	// Display coverage report, or render it for sharing
+	if *reportFormat == "" {
+		printCoverageReport(report, *decimals)
+	} else if err := writeCoverageReport(report, *reportFormat); err != nil {
+		return err
+	}
 	
 	// Save report if requested
 	if *saveReport {
@@ -558,7 +1358,7 @@ func cmdCoverage(ctx context.Context, store *storage.Storage) error {
 		if err := os.MkdirAll(reportPath, 0755); err == nil {
 			filename := fmt.Sprintf("%s-%s.json", report.DocumentID, report.Timestamp.Format("20060102-150405"))
 			filePath := filepath.Join(reportPath, filename)
-			if data, err := json.MarshalIndent(report, "", "  "); err == nil {
+			if data, err := storage.MarshalCanonicalJSON(report); err == nil {
 				if err := os.WriteFile(filePath, data, 0644); err == nil {
 					log("\nCoverage report saved to: %s\n", filePath)
 				}
@@ -569,7 +1369,245 @@ func cmdCoverage(ctx context.Context, store *storage.Storage) error {
 	return nil
 }
 
-func printCoverageReport(report *validator.CoverageReport) {
+func cmdLint(store *storage.Storage) error {
+	var layer1Doc *layer1.GuidanceDocument
+	var err error
+	
+	// Load from file or from storage
+	if *validateFile != "" {
+		log("Loading Layer-1 document from file: %s\n", *validateFile)
+		layer1Doc, _, err = loadLayer1FromFile(*validateFile)
+		if err != nil {
+			return ioErrorf("failed to load file: %w", err)
+		}
+	} else if *documentID != "" {
+		log("Loading Layer-1 document from storage: %s\n", *documentID)
+		layer1Doc, err = loadFinal(store, *documentID)
+		if err != nil {
+			return ioErrorf("failed to load from storage: %w", err)
+		}
+	} else {
+		return usageErrorf("either --document-id or --validate-file is required")
+	}
+	
+	lintConfig := validator.DefaultLintConfig()
+	lintConfig.MinPartTextLength = *lintMinPartLength
+	lintConfig.MaxTextLength = *lintMaxTextLength
+	
+	opts := []validator.LintOption{validator.WithLintConfig(lintConfig)}
+	if *lintDisable != "" {
+		opts = append(opts, validator.WithDisabledLintRules(strings.Split(*lintDisable, ",")...))
+	}
+	linter := validator.NewLinter(opts...)
+	
+	findings := linter.Lint(layer1Doc)
+	printLintFindings(findings)
+	
+	if len(findings) > 0 && *errorOnLint {
+		return validationErrorf("%d lint findings reported", len(findings))
+	}
+	
+	return nil
+}
+
+func printLintFindings(findings []validator.SchemaRecommendation) {
+	if len(findings) == 0 {
+		fmt.Println("\n✓ No lint findings")
+		return
+	}
+	
+	fmt.Printf("\nLint findings: %d\n", len(findings))
+	groups := validator.GroupBySeverity(findings)
+	for _, severity := range validator.Severities {
+		group := groups[severity]
+		if len(group) == 0 {
+			continue
+		}
+		fmt.Printf("\n[%s] %d\n", strings.ToUpper(severity), len(group))
+		for _, f := range group {
+			fmt.Printf("  - %s: %s\n", f.Type, f.Description)
+			fmt.Printf("      at %s\n", f.Target)
+			for _, ex := range f.Examples {
+				fmt.Printf("      > %s\n", ex)
+			}
+		}
+	}
+}
+
+// cmdCoverageDiff compares the coverage of two segmented versions of a
+// document, each analyzed against the parsed version it was segmented from
+func cmdCoverageDiff(store *storage.Storage) error {
+	if *documentID == "" {
+		return usageErrorf("--document-id is required")
+	}
+	if *fromVersion == "" {
+		return usageErrorf("--from is required")
+	}
+	if *decimals < 0 {
+		return usageErrorf("--decimals must not be negative")
+	}
+	from, err := parseVersionFlag("--from", *fromVersion)
+	if err != nil {
+		return err
+	}
+	to := 0
+	if *toVersion != "" {
+		if to, err = parseVersionFlag("--to", *toVersion); err != nil {
+			return err
+		}
+	}
+	
+	analyzer := validator.NewCoverageAnalyzer(*strictValidation)
+	analyze := func(version int) (*validator.CoverageReport, int, error) {
+		segmented, err := store.LoadSegmented(*documentID, version)
+		if err != nil {
+			return nil, 0, ioErrorf("failed to load segmented document: %w", err)
+		}
+		parsed, err := store.LoadParsed(*documentID, segmented.Metadata.SourceVersion)
+		if err != nil {
+			return nil, 0, ioErrorf("failed to load parsed v%d of segmented v%d: %w", segmented.Metadata.SourceVersion, segmented.Metadata.Version, err)
+		}
+		return analyzer.AnalyzeFromSegmented(parsed, segmented), segmented.Metadata.Version, nil
+	}
+	
+	oldReport, from, err := analyze(from)
+	if err != nil {
+		return err
+	}
+	newReport, to, err := analyze(to)
+	if err != nil {
+		return err
+	}
+	diff := analyzer.Diff(oldReport, newReport)
+	
+	if *jsonOutput {
+		data, err := storage.MarshalCanonicalJSON(diff)
+		if err != nil {
+			return fmt.Errorf("failed to marshal coverage diff: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	
+	printCoverageDiff(diff, from, to, *decimals)
+	return nil
+}
+
+// parseVersionFlag parses a version given as "v5" or "5"
+func parseVersionFlag(name, value string) (int, error) {
+	version, err := strconv.Atoi(strings.TrimPrefix(value, "v"))
+	if err != nil || version < 1 {
+		return 0, usageErrorf("%s must be a version such as v2, got %q", name, value)
+	}
+	return version, nil
+}
+
+// printCoverageDiff prints a before/after table of two coverage reports
+func printCoverageDiff(diff *validator.CoverageDiff, from, to, decimals int) {
+	fmt.Printf("\nCoverage of %s: segmented v%d -> v%d\n\n", diff.DocumentID, from, to)
+	
+	fromLabel, toLabel := fmt.Sprintf("v%d", from), fmt.Sprintf("v%d", to)
+	fmt.Printf("  %-20s %10s %10s %10s\n", "", fromLabel, toLabel, "change")
+	metric := func(name string, m validator.MetricDelta) {
+		fmt.Printf("  %-20s %10.*f %10.*f %+10.*f\n", name, decimals, m.Old, decimals, m.New, decimals, m.Delta)
+	}
+	count := func(name string, c validator.CountDelta) {
+		fmt.Printf("  %-20s %10d %10d %+10d\n", name, c.Old, c.New, c.Delta)
+	}
+	metric("Overall score", diff.OverallScore)
+	metric("Block coverage %", diff.BlockCoverage)
+	metric("Character coverage %", diff.CharacterCoverage)
+	count("Categories", diff.Categories)
+	count("Guidelines", diff.Guidelines)
+	count("Parts", diff.Parts)
+	
+	if len(diff.NewlyMapped) > 0 {
+		fmt.Printf("\nNewly mapped content types: %s\n", strings.Join(diff.NewlyMapped, ", "))
+	}
+	if len(diff.NewlyUnmapped) > 0 {
+		fmt.Printf("\nNewly unmapped content types: %s\n", strings.Join(diff.NewlyUnmapped, ", "))
+	}
+}
+
+// cmdCompare reports the structural differences between two Layer-1 files
+func cmdCompare() error {
+	if *compareA == "" || *compareB == "" {
+		return usageErrorf("--a and --b are required")
+	}
+	a, _, err := loadLayer1FromFile(*compareA)
+	if err != nil {
+		return ioErrorf("failed to load %s: %w", *compareA, err)
+	}
+	b, _, err := loadLayer1FromFile(*compareB)
+	if err != nil {
+		return ioErrorf("failed to load %s: %w", *compareB, err)
+	}
+	diff := layer1.Diff(a, b)
+	
+	if *jsonOutput {
+		data, err := storage.MarshalCanonicalJSON(diff)
+		if err != nil {
+			return fmt.Errorf("failed to marshal document diff: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	
+	printDocumentDiff(diff, *compareA, *compareB)
+	return nil
+}
+
+// printDocumentDiff prints a document diff with -, + and ~ marking
+// removed, added and renamed or changed elements
+func printDocumentDiff(diff layer1.DocumentDiff, a, b string) {
+	fmt.Printf("\nComparing %s -> %s\n", a, b)
+	if diff.Empty() {
+		fmt.Println("\nNo structural differences.")
+		return
+	}
+	
+	elements := func(heading string, added, removed, renamed []layer1.ElementChange) {
+		if len(added)+len(removed)+len(renamed) == 0 {
+			return
+		}
+		fmt.Printf("\n%s:\n", heading)
+		in := func(c layer1.ElementChange) string {
+			if c.Category == "" {
+				return ""
+			}
+			return " (in " + c.Category + ")"
+		}
+		for _, c := range removed {
+			fmt.Printf("  - %s%s: %s\n", c.Id, in(c), c.OldTitle)
+		}
+		for _, c := range added {
+			fmt.Printf("  + %s%s: %s\n", c.Id, in(c), c.NewTitle)
+		}
+		for _, c := range renamed {
+			fmt.Printf("  ~ %s%s: %q -> %q\n", c.Id, in(c), c.OldTitle, c.NewTitle)
+		}
+	}
+	elements("Categories", diff.AddedCategories, diff.RemovedCategories, diff.RenamedCategories)
+	elements("Guidelines", diff.AddedGuidelines, diff.RemovedGuidelines, diff.RenamedGuidelines)
+	
+	if len(diff.ChangedParts) > 0 {
+		fmt.Println("\nParts:")
+		for _, c := range diff.ChangedParts {
+			fmt.Printf("  ~ %s (in %s)\n", c.PartId, c.GuidelineId)
+			if c.OldText != "" {
+				fmt.Printf("      - %s\n", c.OldText)
+			}
+			if c.NewText != "" {
+				fmt.Printf("      + %s\n", c.NewText)
+			}
+		}
+	}
+}
+
+// printCoverageReport prints a coverage report, rounding scores and
+// percentages to the given number of decimal places. Only the display is
+// rounded; saved reports keep full precision.
+func printCoverageReport(report *validator.CoverageReport, decimals int) {
 	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Printf("SCHEMA COVERAGE REPORT: %s\n", report.DocumentID)
 	fmt.Println(strings.Repeat("=", 60))
@@ -609,11 +1647,22 @@ func printCoverageReport(report *validator.CoverageReport) {
 		}
 	}
 	
+	// Schema validation
+	if report.Validation != nil {
+		fmt.Println("\n🔎 SCHEMA VALIDATION:")
+		fmt.Printf("  Valid: %v\n", report.Validation.Valid)
+		fmt.Printf("  Errors: %d\n", report.Validation.ErrorCount)
+		fmt.Printf("  Warnings: %d\n", report.Validation.WarningCount)
+	}
+	
 	// Coverage metrics
 	fmt.Println("\n📊 COVERAGE METRICS:")
-	fmt.Printf("  Overall Score: %.1f/100\n", report.CoverageMetrics.OverallScore)
+	fmt.Printf("  Overall Score: %.*f/100\n", decimals, report.CoverageMetrics.OverallScore)
 	if report.CoverageMetrics.BlockCoverage > 0 {
-		fmt.Printf("  Block Coverage: %.1f%%\n", report.CoverageMetrics.BlockCoverage)
+		fmt.Printf("  Block Coverage: %.*f%%\n", decimals, report.CoverageMetrics.BlockCoverage)
+	}
+	if report.CoverageMetrics.CharacterCoverage > 0 {
+		fmt.Printf("  Character Coverage: %.*f%%\n", decimals, report.CoverageMetrics.CharacterCoverage)
 	}
 	fmt.Printf("  Required Fields: %d/%d\n", report.CoverageMetrics.RequiredFieldsCovered, report.CoverageMetrics.RequiredFieldsTotal)
 	fmt.Printf("  Optional Fields: %d/%d\n", report.CoverageMetrics.OptionalFieldsCovered, report.CoverageMetrics.OptionalFieldsTotal)
@@ -681,44 +1730,132 @@ func printCoverageReport(report *validator.CoverageReport) {
 	fmt.Println("\n" + strings.Repeat("=", 60))
 }
 
-// loadLayer1FromFile loads a Layer-1 document from a YAML or JSON file
-func loadLayer1FromFile(path string) (*layer1.GuidanceDocument, error) {
-	data, err := os.ReadFile(path)
+// writeCoverageReport renders a coverage report in the given --report-format
+// to --output, or to stdout
+func writeCoverageReport(report *validator.CoverageReport, format string) error {
+	var rendered string
+	var err error
+	switch format {
+	case "md":
+		rendered, err = report.ToMarkdown()
+	case "html":
+		rendered, err = report.ToHTML()
+	case "json":
+		var data []byte
+		data, err = storage.MarshalCanonicalJSON(report)
+		rendered = string(data) + "\n"
+	}
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to render coverage report: %w", err)
 	}
 	
-	var doc layer1.GuidanceDocument
+	if *outputFile == "" {
+		fmt.Print(rendered)
+		return nil
+	}
+	if err := os.WriteFile(*outputFile, []byte(rendered), 0644); err != nil {
+		return ioErrorf("failed to write coverage report: %w", err)
+	}
+	log("Coverage report written to: %s\n", *outputFile)
+	return nil
+}
+
+// loadLayer1FromFile loads a Layer-1 document from a YAML or JSON file,
+// along with the source positions of its fields for locating validation
+// errors (nil if they can't be read)
+func loadLayer1FromFile(path string) (*layer1.GuidanceDocument, validator.SourceIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
 	
 	// Try YAML first (it's a superset of JSON)
-	if err := yaml.Unmarshal(data, &doc); err != nil {
+	doc, err := storage.DecodeLayer1(data, "yaml", *strictDecode)
+	if err != nil {
 		// Try JSON
-		if jsonErr := json.Unmarshal(data, &doc); jsonErr != nil {
-			return nil, fmt.Errorf("failed to parse as YAML (%v) or JSON (%v)", err, jsonErr)
+		var jsonErr error
+		if doc, jsonErr = storage.DecodeLayer1(data, "json", *strictDecode); jsonErr != nil {
+			return nil, nil, fmt.Errorf("failed to parse as YAML (%v) or JSON (%v)", err, jsonErr)
 		}
 	}
 	
-	return &doc, nil
+	index, _ := validator.IndexSource(data)
+	return doc, index, nil
+}
+
+// loadFinal loads a stored final document, honoring --strict-decode
+func loadFinal(store *storage.Storage, documentID string) (*layer1.GuidanceDocument, error) {
+	if *strictDecode {
+		return store.LoadFinalStrict(documentID)
+	}
+	return store.LoadFinal(documentID)
 }
 
 func saveToFile(path string, data interface{}, format string) error {
-	var bytes []byte
-	var err error
+	bytes, err := marshalOutput(data, format)
+	if err != nil {
+		return err
+	}
 	
+	return os.WriteFile(path, bytes, 0644)
+}
+
+// saveLayer1ToFile writes a Layer-1 document to path with the converter
+// formatter registered for format
+func saveLayer1ToFile(path string, doc *layer1.GuidanceDocument, format string) error {
+	var buf bytes.Buffer
+	if err := converter.WriteFormat(doc, format, &buf); err != nil {
+		return err
+	}
+	
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// checkOutputFormat rejects a --format with no registered formatter. Formats
+// storage can't load back (anything but yaml or json) are only written to
+// --output, so they require it.
+func checkOutputFormat() error {
+	format := formatOr("yaml")
+	if _, ok := converter.LookupFormat(format); !ok {
+		return usageErrorf("unsupported --format %q (available: %s)", format, strings.Join(converter.Formats(), ", "))
+	}
+	if storedFormat() != format && *outputFile == "" {
+		return usageErrorf("--format %s requires --output", format)
+	}
+	return nil
+}
+
+// storedFormat returns the format the final document is stored in: the
+// --format when storage can load it back, else yaml
+func storedFormat() string {
+	switch format := formatOr("yaml"); format {
+	case "yaml", "yml", "json":
+		return format
+	default:
+		return "yaml"
+	}
+}
+
+// formatOr returns --format, or the command's default format when the flag
+// isn't set. Commands share the flag but not its formats, so each passes its
+// own default rather than inheriting convert's yaml.
+func formatOr(def string) string {
+	if *outputFormat == "" {
+		return def
+	}
+	return *outputFormat
+}
+
+// marshalOutput encodes data in the given --format (yaml or json)
+func marshalOutput(data interface{}, format string) ([]byte, error) {
 	switch format {
 	case "yaml", "yml":
-		bytes, err = yaml.Marshal(data)
+		return yaml.Marshal(data)
 	case "json":
-		bytes, err = json.MarshalIndent(data, "", "  ")
+		return storage.MarshalCanonicalJSON(data)
 	default:
-		return fmt.Errorf("unsupported format: %s", format)
-	}
-	
-	if err != nil {
-		return err
+		return nil, fmt.Errorf("unsupported format: %s", format)
 	}
-	
-	return os.WriteFile(path, bytes, 0644)
 }
 
 func countBlocks(doc *types.ParsedDocument) int {
@@ -747,6 +1884,11 @@ func countLayer1Guidelines(doc *layer1.GuidanceDocument) int {
 
 func log(format string, args ...interface{}) {
 	if *verbose || true { // Always show for now
+		// Keep stdout clean for machine-readable output
+		if *jsonOutput {
+			fmt.Fprintf(os.Stderr, format, args...)
+			return
+		}
 		fmt.Printf(format, args...)
 	}
 }
@@ -758,30 +1900,82 @@ Gemara Layer-1 PDF Conversion Pipeline
 Usage: pipeline <command> [options]
 
 Commands:
-  parse       Parse PDF into structured blocks
+  parse       Parse PDF (or DOCX/Markdown) into structured blocks
+  metadata    Print a PDF's title, author, version and date without parsing it
   segment     Segment parsed data into categories/guidelines
   convert     Convert segmented data to Layer-1 format (includes validation)
+  convert-diff  Show what converting the segmented data maps, drops and synthesizes
   enhance     Enhance with LLM (can be re-run on existing data)
   validate    Validate Layer-1 document against schema
+  revalidate-all  Re-validate every stored final document and save fresh reports
+  export-all  Export every stored final document as one NDJSON stream
+  import      Validate an edited Layer-1 file and store it as the next final version
+  bundle      Archive a document's final version and all its reports for reviewers
+  schema      Print a JSON Schema generated from the validator's rules
   coverage    Analyze schema coverage (what info couldn't be captured)
+  coverage-diff  Compare the coverage of two segmented versions
+  lint        Report soft-quality issues in a Layer-1 document
+  compare     Show the structural differences between two Layer-1 files
   run-all     Run complete pipeline (parse -> segment -> convert)
   list        List all versions of a document
+  trace       Show the parsed blocks a category, guideline or part came from
+  prune       Delete all but the newest versions of a document
 
 Parse Options:
-  --input <file>           Input PDF file (required)
+  --input <file>           Input PDF file, or .docx/.md with --parser docx/markdown (required)
   --document-id <id>       Document ID (default: filename)
-  --parser <type>          Parser type (simple, docling) [default: simple]
+  --parser <type>          Parser type (simple, docling, pymupdf, docx, markdown) [default: simple]
+  --pdftotext-mode <mode>  Simple parser text mode (layout, raw, auto) [default: layout]
+  --columns <n>            Order multi-column pages by word positions (auto, 1, 2);
+                           replaces --pdftotext-mode when set
+  --heading-patterns <re>  Extra comma-separated heading regexes for the simple parser,
+                           added to the numbered and ALL-CAPS defaults
+  --max-bytes <n>          Reject inputs/extracted text larger than n bytes [default: 268435456]
+  --text-out <file>        Also write the reconstructed plain text, for diffing against the source
+  --pdf-password <pw>      Password for encrypted PDFs (user or owner password)
+
+Metadata Options:
+  --input <file>           Input PDF file (required)
+  --format <fmt>           Output format (yaml, json) [default: yaml]
+  --output <file>          Write to a file instead of stdout
 
 Segment Options:
   --document-id <id>       Document ID (required)
-  --segmenter <type>       Segmenter type (generic, pci-dss, nist-800-53) [default: generic]
+  --segmenter <type>       Segmenter type (generic, pci-dss, nist-800-53, cis-benchmark) [default: generic]
+  --structure-by <mode>    Match structure by numbering regex, heading level, or both [default: regex]
+  --max-recommendations <n>  Keep at most n recommendation lines per guideline, preferring lines
+                           that open with an imperative or normative keyword [default: 0 = all]
+  --flat                   Treat the document as one category: top-level numbered items become
+                           guidelines and second-level items parts [default: false]
+  --flat-title <title>     Title of that category [default: document title, or General]
+  --segmenter-config <file>  YAML rules file overriding the segmenter's patterns and keywords
   --source-version <n>     Source version (0 = latest) [default: 0]
+  --parsed-file <file>     Segment a ParsedDocument JSON from an external parser, storing it
+                           as the next parsed version, instead of a stored parse
 
 Convert Options:
   --document-id <id>       Document ID (required)
   --output <file>          Output file path (optional)
-  --format <fmt>           Output format (yaml, json) [default: yaml]
+  --format <fmt>           Output format, any registered with converter.RegisterFormat
+                           (yaml, json, markdown, html, oscal-catalog, oscal-profile built in)
+                           [default: yaml].
+                           Formats other than yaml and json need --output; storage keeps the
+                           document as yaml
   --strict                 Enable strict validation [default: true]
+  --validate               Validate output and fail on schema errors [default: true]
+                           (--validate=false saves output without the validation gate)
+  --save-invalid           On validation failure, still save the document to invalid/ next to
+                           the report for inspection; the command still fails [default: false]
+  --normalize-ids          Rewrite all IDs to a canonical scheme [default: false]
+  --id-prefix <prefix>     Prefix for normalized IDs (e.g. REQ-)
+  --id-separator <sep>     Separator for normalized IDs [default: .]
+  --synthesize-parts       Give part-less guidelines a part from their objective/recommendations [default: false]
+
+Convert-Diff Options:
+  --document-id <id>       Document ID (required)
+  --source-version <n>     Segmented version to convert [default: latest]
+  --normalize-ids          Include ID normalization in the report (with --id-prefix, --id-separator)
+  --json                   Print the report as JSON [default: false]
 
 Enhance Options:
   --document-id <id>       Document ID (required)
@@ -790,22 +1984,113 @@ Enhance Options:
   --llm-api-key <key>      LLM API key (or set LLM_API_KEY env var)
   --temperature <t>        Temperature [default: 0.3]
   --max-tokens <n>         Max tokens [default: 2000]
+  --save-llm-artifacts     Store the raw prompt, response and token usage for auditing [default: false]
+  --min-change-confidence <c>  Drop suggested changes with confidence below c (0-1) [default: 0]
+  --llm-max-retries <n>    Retries for rate-limited (429) or failed (5xx) requests [default: 3]
+  --llm-retry-base-ms <ms> Backoff before the first retry, doubled for each retry [default: 500]
+  --llm-json-mode <mode>   Constrain responses to JSON: OpenAI response_format or a forced Anthropic
+                           tool call. auto uses it for models known to support it (on, off) [default: auto]
+  --allow-removals         Accept an enhancement that removes categories or guidelines [default: false]
 
 Validate Options:
   --document-id <id>       Document ID to validate from storage
   --validate-file <path>   Path to external Layer-1 file to validate
-  --strict                 Enable strict validation [default: true]
+  --strict                 Enable strict validation; empty optional fields are errors rather
+                           than warnings (also for convert, enhance, import) [default: true]
+  --warnings-as-errors     Report every validation warning as an error (also for convert,
+                           enhance, import, coverage) [default: false]
   --save-report            Save validation report for audit [default: true]
+  --format github          Also print errors and warnings as GitHub Actions annotations
+  --schema <path|url>      Also validate against a shared JSON Schema (also for convert, enhance)
+  --schema-cache <dir>     Cache a remote --schema in this directory
+  --strict-decode          Report unknown keys (e.g. typos) in the loaded document as errors
+                           (also for coverage, lint, revalidate-all) [default: false]
+
+Revalidate-All Options:
+  --strict                 Enable strict validation [default: true]
+  --save-report            Save a fresh report per document, tagged with the validator version [default: true]
+  --schema <path|url>      Also validate against a shared JSON Schema
+
+Export-All Options:
+  --format ndjson          Output format, the only one supported: one compact JSON document
+                           per line [default: ndjson]
+  --output <file>          Output file path [default: stdout]
+
+Import Options:
+  --document-id <id>       Document ID (required)
+  --file <path>            Edited Layer-1 .yaml/.yml/.json file (required)
+  --strict                 Enable strict validation [default: true]
+  --strict-decode          Reject unknown keys (e.g. typos) in the file [default: false]
+  --save-report            Save the validation report for audit [default: true]
+  --schema <path|url>      Also validate against a shared JSON Schema
+
+Bundle Options:
+  --document-id <id>       Document ID (required)
+  --out <file>             Bundle path [default: <id>-bundle.tar.gz]; --output also works
+
+Schema Options:
+  --format json            Output format, the only one supported [default: json]
+  --output <file>          Output file path [default: stdout]
 
 Coverage Options:
   --document-id <id>       Document ID to analyze from storage
   --validate-file <path>   Path to external Layer-1 file to analyze
+  --strict                 Strict mode for the report's validation summary [default: true]
+  --decimals <n>           Decimal places for displayed scores and percentages; saved
+                           reports keep full precision [default: 1]
+  --report-format <fmt>    Render the report as md, html or json instead of printing it
+                           (md and html show one decimal place)
+  --output <file>          Write the rendered report to a file [default: stdout]
   --save-report            Save coverage report [default: true]
 
+Coverage-Diff Options:
+  --document-id <id>       Document ID (required)
+  --from <version>         Segmented version to compare from, e.g. v2 (required)
+  --to <version>           Segmented version to compare to, e.g. v5 [default: latest]
+  --decimals <n>           Decimal places for scores and percentages [default: 1]
+  --json                   Print the diff as JSON [default: false]
+
+Compare Options:
+  --a <file>               Layer-1 file to compare from (required)
+  --b <file>               Layer-1 file to compare to (required)
+  --strict-decode          Reject unknown keys when loading the files [default: false]
+  --json                   Print the diff as JSON [default: false]
+
+Lint Options:
+  --document-id <id>       Document ID to lint from storage
+  --validate-file <path>   Path to external Layer-1 file to lint
+  --error-on-lint          Exit non-zero if any findings are reported [default: false]
+  --lint-disable <rules>   Comma-separated rules to skip (missing-objective, short-part-text,
+                           single-guideline-category, fragment-recommendation, oversized-text)
+  --lint-min-part-length <n>  Minimum part text length [default: 20]
+  --lint-max-text-length <n>  Maximum part text or recommendation length, above which the
+                           content likely needs splitting [default: 2000, 0 = no limit]
+
+Run-All Options:
+  Accepts all Parse, Segment and Convert options, plus:
+  --json                   Print the combined pipeline result as JSON [default: false]
+  --resume                 Reuse stored parsed/segmented versions if the input is unchanged [default: false]
+
+Trace Options:
+  --document-id <id>       Document ID (required)
+  --element <id>           Category, guideline or part ID in the final document (required)
+
+Prune Options:
+  --document-id <id>       Document ID (required)
+  --keep <n>               Newest parsed and segmented versions to keep [default: 3]
+
 Global Options:
   --base-dir <dir>         Base directory for storage [default: ./layer1/pipeline/test-data]
   --verbose                Enable verbose output
 
+Exit Codes:
+  0  Success
+  1  Usage error (missing/invalid flags, unknown command or provider)
+  2  Schema validation failed (or lint findings with --error-on-lint)
+  3  Missing external dependency (e.g. pdftotext, python3)
+  4  I/O error (reading or writing files or storage)
+  5  Other processing failure (conversion, LLM request, ...)
+
 Examples:
   # Complete pipeline
   pipeline run-all --input PCI_DSS_v3-2-1.pdf --document-id pci-dss-3.2.1 --segmenter pci-dss
@@ -825,6 +2110,17 @@ Examples:
   # Analyze schema coverage (what info couldn't be captured)
   pipeline coverage --document-id pci-dss-3.2.1
   pipeline coverage --validate-file ./my-document.yaml
+  pipeline coverage --document-id pci-dss-3.2.1 --report-format html --output coverage.html
+  pipeline coverage-diff --document-id pci-dss-3.2.1 --from v2 --to v5
+  
+  # Compare two Layer-1 documents
+  pipeline compare --a pci-dss-v3.yaml --b pci-dss-v4.yaml
+
+  # Report soft-quality issues
+  pipeline lint --document-id pci-dss-3.2.1
+  
+  # Archive the final document and all reports for reviewers
+  pipeline bundle --document-id pci-dss-3.2.1 --out pci-dss-bundle.tar.gz
   
   # List versions
   pipeline list --document-id pci-dss-3.2.1
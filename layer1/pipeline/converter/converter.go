@@ -2,6 +2,7 @@ package converter
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/ossf/gemara/layer1"
 	"github.com/ossf/gemara/layer1/pipeline/types"
@@ -19,14 +20,63 @@ type Converter interface {
 
 // DefaultConverter provides standard conversion logic
 type DefaultConverter struct {
-	preserveIDs bool
+	preserveIDs     bool
+	idScheme        IDScheme
+	synthesizeParts bool
+	idIssues        []IDIssue
+	report          *ConversionReport
+	provenance      *types.Provenance
 }
 
-// NewConverter creates a new converter
-func NewConverter() *DefaultConverter {
-	return &DefaultConverter{
+// Option is a functional option for configuring the converter
+type Option func(*DefaultConverter)
+
+// WithSynthesizeParts gives guidelines without parts a single part built
+// from their objective and recommendations, so every guideline yields at
+// least one assessable statement
+func WithSynthesizeParts(enabled bool) Option {
+	return func(c *DefaultConverter) {
+		c.synthesizeParts = enabled
+	}
+}
+
+// WithNormalizedIDs rewrites all IDs to the given scheme instead of
+// preserving the IDs produced by the segmenter
+func WithNormalizedIDs(scheme IDScheme) Option {
+	return func(c *DefaultConverter) {
+		c.preserveIDs = false
+		c.idScheme = scheme
+	}
+}
+
+// NewConverter creates a new converter. IDs are preserved unless
+// WithNormalizedIDs is given.
+func NewConverter(opts ...Option) *DefaultConverter {
+	c := &DefaultConverter{
 		preserveIDs: true,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// IDIssues returns the problems found while normalizing IDs during the
+// last conversion. It is always empty when IDs are preserved.
+func (c *DefaultConverter) IDIssues() []IDIssue {
+	return c.idIssues
+}
+
+// Report returns what the last conversion mapped, dropped, synthesized and
+// transformed, or nil before the first conversion
+func (c *DefaultConverter) Report() *ConversionReport {
+	return c.report
+}
+
+// Provenance returns the source blocks of each element of the last
+// converted document, or nil before the first conversion
+func (c *DefaultConverter) Provenance() *types.Provenance {
+	return c.provenance
 }
 
 // Name returns the converter name
@@ -40,13 +90,24 @@ func (c *DefaultConverter) Convert(doc *types.SegmentedDocument) (*layer1.Guidan
 		return nil, fmt.Errorf("segmented document is nil")
 	}
 	
+	c.report = newConversionReport()
+	
 	// Convert metadata
 	metadata := c.convertMetadata(&doc.DocumentMetadata)
+	metadata.MappingReferences = c.convertMappingReferences(doc)
+	for _, rev := range doc.RevisionHistory {
+		metadata.RevisionHistory = append(metadata.RevisionHistory, layer1.Revision{
+			Version:     rev.Version,
+			Date:        rev.Date,
+			Description: rev.Description,
+		})
+		c.report.mapped("revision_history[]", "metadata.revision-history[]", true)
+	}
 	
 	// Convert categories
 	categories := make([]layer1.Category, 0, len(doc.Categories))
-	for _, segCat := range doc.Categories {
-		cat := c.convertCategory(&segCat)
+	for i, segCat := range doc.Categories {
+		cat := c.convertCategory(&segCat, metadata.DocumentType, fmt.Sprintf("categories[%d]", i))
 		categories = append(categories, cat)
 	}
 	
@@ -55,6 +116,21 @@ func (c *DefaultConverter) Convert(doc *types.SegmentedDocument) (*layer1.Guidan
 		FrontMatter: doc.FrontMatter,
 		Categories:  categories,
 	}
+	c.report.mapped("front_matter", "front-matter", doc.FrontMatter != "")
+	
+	// Segmented-only data with no Layer-1 field
+	for i, content := range doc.UnmappedContent {
+		c.report.dropped(fmt.Sprintf("unmapped_content[%d]", i), fmt.Sprintf("%s at %s: %s", content.ContentType, content.SourceLocation, content.Reason))
+	}
+	
+	c.idIssues = nil
+	if !c.preserveIDs {
+		before := collectIDs(guidanceDoc)
+		c.idIssues = normalizeIDs(guidanceDoc, c.idScheme)
+		c.report.recordIDChanges(before, collectIDs(guidanceDoc))
+	}
+	c.report.sortMapped()
+	c.provenance = buildProvenance(doc, guidanceDoc)
 	
 	return guidanceDoc, nil
 }
@@ -70,10 +146,18 @@ func (c *DefaultConverter) convertMetadata(meta *types.DocumentMetadata) layer1.
 		PublicationDate: meta.PublicationDate,
 	}
 	
+	c.report.mapped("document_metadata.id", "metadata.id", meta.ID != "")
+	c.report.mapped("document_metadata.title", "metadata.title", meta.Title != "")
+	c.report.mapped("document_metadata.description", "metadata.description", meta.Description != "")
+	c.report.mapped("document_metadata.author", "metadata.author", meta.Author != "")
+	c.report.mapped("document_metadata.version", "metadata.version", meta.Version != "")
+	c.report.mapped("document_metadata.publication_date", "metadata.publication-date", meta.PublicationDate != "")
+	c.report.mapped("document_metadata.document_type", "metadata.document-type", meta.DocumentType != "")
+	c.report.mapped("document_metadata.jurisdictions", "metadata.applicability.jurisdictions", len(meta.Jurisdictions) > 0)
+	c.report.mapped("document_metadata.industry_sectors", "metadata.applicability.industry-sectors", len(meta.IndustrySectors) > 0)
+	
 	// Convert document type
-	if meta.DocumentType != "" {
-		l1Meta.DocumentType = layer1.DocumentType(meta.DocumentType)
-	}
+	l1Meta.DocumentType = normalizeDocumentType(meta.DocumentType)
 	
 	// Convert applicability
 	if len(meta.IndustrySectors) > 0 || len(meta.Jurisdictions) > 0 {
@@ -86,51 +170,375 @@ func (c *DefaultConverter) convertMetadata(meta *types.DocumentMetadata) layer1.
 	return l1Meta
 }
 
-// convertCategory converts SegmentCategory to Layer-1 Category
-func (c *DefaultConverter) convertCategory(cat *types.SegmentCategory) layer1.Category {
+// convertCategory converts SegmentCategory to Layer-1 Category. A category
+// type matching the document's is left unset, since categories inherit it.
+func (c *DefaultConverter) convertCategory(cat *types.SegmentCategory, docType layer1.DocumentType, path string) layer1.Category {
+	c.report.mapped("categories[].id", "categories[].id", cat.ID != "")
+	c.report.mapped("categories[].title", "categories[].title", cat.Title != "")
+	c.report.mapped("categories[].description", "categories[].description", cat.Description != "")
+	c.report.mapped("categories[].document_type", "categories[].document-type", cat.DocumentType != "")
+	
+	catType := normalizeDocumentType(cat.DocumentType)
+	if catType == docType {
+		catType = ""
+	}
+	
 	guidelines := make([]layer1.Guideline, 0, len(cat.Guidelines))
-	for _, segGuide := range cat.Guidelines {
-		guide := c.convertGuideline(&segGuide)
+	for i, segGuide := range cat.Guidelines {
+		guide := c.convertGuideline(&segGuide, fmt.Sprintf("%s.guidelines[%d]", path, i))
 		guidelines = append(guidelines, guide)
 	}
 	
 	return layer1.Category{
-		Id:          cat.ID,
-		Title:       cat.Title,
-		Description: cat.Description,
-		Guidelines:  guidelines,
+		Id:           cat.ID,
+		Title:        cat.Title,
+		Description:  cat.Description,
+		DocumentType: catType,
+		Guidelines:   guidelines,
+	}
+}
+
+// normalizeDocumentType spells a segmented document type the way the
+// schema does, matching case-insensitively and reading hyphens and
+// underscores as spaces ("best-practice" becomes "Best Practice"). Other
+// values are kept, trimmed, for the validator to report.
+func normalizeDocumentType(docType string) layer1.DocumentType {
+	docType = strings.TrimSpace(docType)
+	words := strings.Fields(strings.NewReplacer("-", " ", "_", " ").Replace(docType))
+	for valid := range validator.ValidDocumentTypes {
+		if strings.EqualFold(strings.Join(words, " "), string(valid)) {
+			return valid
+		}
 	}
+	return layer1.DocumentType(docType)
 }
 
 // convertGuideline converts SegmentGuideline to Layer-1 Guideline
-func (c *DefaultConverter) convertGuideline(guide *types.SegmentGuideline) layer1.Guideline {
-	parts := make([]layer1.Part, 0, len(guide.Parts))
-	for _, segPart := range guide.Parts {
-		part := c.convertPart(&segPart)
+func (c *DefaultConverter) convertGuideline(guide *types.SegmentGuideline, path string) layer1.Guideline {
+	c.report.mapped("categories[].guidelines[].id", "categories[].guidelines[].id", guide.ID != "")
+	c.report.mapped("categories[].guidelines[].title", "categories[].guidelines[].title", guide.Title != "")
+	c.report.mapped("categories[].guidelines[].objective", "categories[].guidelines[].objective", guide.Objective != "")
+	c.report.mapped("categories[].guidelines[].recommendations", "categories[].guidelines[].recommendations", len(guide.Recommendations) > 0)
+	c.report.mapped("categories[].guidelines[].risks", "categories[].guidelines[].rationale.risks", len(guide.Risks) > 0)
+	c.report.mapped("categories[].guidelines[].outcomes", "categories[].guidelines[].rationale.outcomes", len(guide.Outcomes) > 0)
+	c.report.mapped("categories[].guidelines[].mappings", "categories[].guidelines[].guideline-mappings", len(guide.Mappings) > 0)
+	c.report.mapped("categories[].guidelines[].normativity", "categories[].guidelines[].normativity", guide.Normativity != "")
+	
+	parts := make([]layer1.Part, 0, len(guide.Parts)+len(guide.Tables)+len(guide.Code))
+	
+	// Tables become parts with Markdown text so their content survives. A
+	// table goes before the first part it precedes in the source; tables
+	// without a source position follow the parts.
+	tables := 0
+	addTables := func(before *types.SourceRef) {
+		for ; tables < len(guide.Tables); tables++ {
+			table := guide.Tables[tables]
+			if before != nil && (table.Source == nil || !sourceBefore(*table.Source, *before)) {
+				return
+			}
+			if len(table.Rows) == 0 {
+				c.report.dropped(fmt.Sprintf("%s.tables[%d]", path, tables), "table has no rows")
+				continue
+			}
+			c.report.synthesized(fmt.Sprintf("%s.guideline-parts[%d]", path, len(parts)), fmt.Sprintf("part rendered from tables[%d] as Markdown", tables))
+			parts = append(parts, c.convertTable(&table, guide.ID, tables+1))
+		}
+	}
+	
+	for i, segPart := range guide.Parts {
+		if len(segPart.Sources) > 0 {
+			addTables(&segPart.Sources[0])
+		}
+		part := c.convertPart(&segPart, fmt.Sprintf("%s.parts[%d]", path, i), fmt.Sprintf("%s.guideline-parts[%d]", path, len(parts)))
 		parts = append(parts, part)
+		parts = c.convertAnnotations(segPart.Annotations, segPart.ID, fmt.Sprintf("parts[%d].annotations", i), path, parts)
+	}
+	if len(parts) == 0 && c.synthesizeParts {
+		if part, ok := c.statementPart(guide); ok {
+			c.report.synthesized(fmt.Sprintf("%s.guideline-parts[%d]", path, len(parts)), "part synthesized from objective and recommendations")
+			parts = append(parts, part)
+		}
+	}
+	
+	// Callouts become parts titled by their kind, right after what they
+	// qualify, so their emphasis survives
+	parts = c.convertAnnotations(guide.Annotations, guide.ID, "annotations", path, parts)
+	
+	addTables(nil)
+	
+	// Code examples become parts with fenced text so they stay verbatim
+	for i, code := range guide.Code {
+		c.report.synthesized(fmt.Sprintf("%s.guideline-parts[%d]", path, len(parts)), fmt.Sprintf("part rendered from code[%d] as a fenced block", i))
+		parts = append(parts, c.convertCode(code, guide.ID, i+1))
+	}
+	
+	// External links from the guideline's body become a references part
+	c.reportInternalLinks(guide.Links, path+".links")
+	if links := externalLinks(guide.Links); len(links) > 0 {
+		c.report.synthesized(fmt.Sprintf("%s.guideline-parts[%d]", path, len(parts)), fmt.Sprintf("part listing %d external links", len(links)))
+		parts = append(parts, c.convertLinks(links, guide.ID))
+	}
+	
+	// Benchmark profile levels become a part, so the level can still be
+	// filtered on
+	if guide.Profile != "" {
+		c.report.synthesized(fmt.Sprintf("%s.guideline-parts[%d]", path, len(parts)), fmt.Sprintf("part holding profile '%s'", guide.Profile))
+		parts = append(parts, layer1.Part{
+			Id:    guide.ID + ".profile",
+			Title: "Profile",
+			Text:  guide.Profile,
+		})
 	}
 	
 	l1Guide := layer1.Guideline{
-		Id:              guide.ID,
-		Title:           guide.Title,
-		Objective:       guide.Objective,
-		Recommendations: guide.Recommendations,
-		GuidelineParts:  parts,
+		Id:                guide.ID,
+		Title:             guide.Title,
+		Objective:         guide.Objective,
+		Recommendations:   guide.Recommendations,
+		Rationale:         convertRationale(guide),
+		GuidelineMappings: convertMappings(guide),
+		GuidelineParts:    parts,
+		Normativity:       layer1.Normativity(guide.Normativity),
 	}
 	
 	return l1Guide
 }
 
+// convertRationale maps a guideline's risks and outcomes to a Layer-1
+// rationale, or nil when it has neither. Both lists are always set since
+// the schema requires them.
+func convertRationale(guide *types.SegmentGuideline) *layer1.Rationale {
+	if len(guide.Risks) == 0 && len(guide.Outcomes) == 0 {
+		return nil
+	}
+	rationale := &layer1.Rationale{
+		Risks:    make([]layer1.Risk, 0, len(guide.Risks)),
+		Outcomes: make([]layer1.Outcome, 0, len(guide.Outcomes)),
+	}
+	for _, risk := range guide.Risks {
+		rationale.Risks = append(rationale.Risks, layer1.Risk{Title: risk.Title, Description: risk.Description})
+	}
+	for _, outcome := range guide.Outcomes {
+		rationale.Outcomes = append(rationale.Outcomes, layer1.Outcome{Title: outcome.Title, Description: outcome.Description})
+	}
+	return rationale
+}
+
+// statementPart builds a part from a guideline's objective and
+// recommendations, skipping recommendations that repeat the objective
+func (c *DefaultConverter) statementPart(guide *types.SegmentGuideline) (layer1.Part, bool) {
+	var lines []string
+	if guide.Objective != "" {
+		lines = append(lines, guide.Objective)
+	}
+	for _, rec := range guide.Recommendations {
+		if rec != guide.Objective {
+			lines = append(lines, rec)
+		}
+	}
+	if len(lines) == 0 {
+		return layer1.Part{}, false
+	}
+	
+	return layer1.Part{
+		Id:    guide.ID + ".statement",
+		Title: "Statement",
+		Text:  strings.Join(lines, "\n"),
+	}, true
+}
+
 // convertPart converts SegmentPart to Layer-1 Part
-func (c *DefaultConverter) convertPart(part *types.SegmentPart) layer1.Part {
+func (c *DefaultConverter) convertPart(part *types.SegmentPart, path, target string) layer1.Part {
+	c.report.mapped("categories[].guidelines[].parts[].id", "categories[].guidelines[].guideline-parts[].id", part.ID != "")
+	c.report.mapped("categories[].guidelines[].parts[].title", "categories[].guidelines[].guideline-parts[].title", part.Title != "")
+	c.report.mapped("categories[].guidelines[].parts[].text", "categories[].guidelines[].guideline-parts[].text", part.Text != "")
+	c.report.mapped("categories[].guidelines[].parts[].recommendations", "categories[].guidelines[].guideline-parts[].recommendations", len(part.Recommendations) > 0)
+	c.report.mapped("categories[].guidelines[].parts[].normativity", "categories[].guidelines[].guideline-parts[].normativity", part.Normativity != "")
+	
+	text := appendLinks(part.Text, externalLinks(part.Links))
+	if text != part.Text {
+		c.report.transformed(path+".text", target+".text", "external link URLs appended to text")
+	}
+	c.reportInternalLinks(part.Links, path+".links")
+	
 	return layer1.Part{
 		Id:              part.ID,
 		Title:           part.Title,
-		Text:            part.Text,
+		Text:            text,
 		Recommendations: part.Recommendations,
+		Normativity:     layer1.Normativity(part.Normativity),
+	}
+}
+
+// reportInternalLinks records links that externalLinks filters out
+func (c *DefaultConverter) reportInternalLinks(links []types.Link, path string) {
+	external := make(map[string]bool)
+	for _, link := range externalLinks(links) {
+		external[link.URL] = true
+	}
+	for i, link := range links {
+		if !external[strings.TrimSpace(link.URL)] {
+			c.report.dropped(fmt.Sprintf("%s[%d]", path, i), fmt.Sprintf("internal reference '%s' has no Layer-1 field", link.URL))
+		}
+	}
+}
+
+// convertLinks converts a guideline's external links into a Layer-1 Part
+func (c *DefaultConverter) convertLinks(links []types.Link, guidelineID string) layer1.Part {
+	lines := make([]string, 0, len(links))
+	for _, link := range links {
+		lines = append(lines, "- "+renderMarkdownLink(link))
+	}
+	return layer1.Part{
+		Id:    guidelineID + ".links",
+		Title: "Links",
+		Text:  strings.Join(lines, "\n"),
+	}
+}
+
+// externalLinks returns the http(s) and mailto links, dropping duplicates
+// and the internal cross-references parsers may also report
+func externalLinks(links []types.Link) []types.Link {
+	var external []types.Link
+	seen := make(map[string]bool)
+	for _, link := range links {
+		url := strings.TrimSpace(link.URL)
+		lower := strings.ToLower(url)
+		if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") && !strings.HasPrefix(lower, "mailto:") {
+			continue
+		}
+		if seen[url] {
+			continue
+		}
+		seen[url] = true
+		external = append(external, types.Link{Text: strings.TrimSpace(link.Text), URL: url})
+	}
+	return external
+}
+
+// appendLinks adds URLs that don't already appear in a part's text
+func appendLinks(text string, links []types.Link) string {
+	for _, link := range links {
+		if strings.Contains(text, link.URL) {
+			continue
+		}
+		if text != "" {
+			text += " "
+		}
+		text += "(" + link.URL + ")"
+	}
+	return text
+}
+
+// renderMarkdownLink renders a link as Markdown, falling back to the bare URL
+// when the anchor text is empty or is the URL itself
+func renderMarkdownLink(link types.Link) string {
+	if link.Text == "" || link.Text == link.URL {
+		return link.URL
+	}
+	return fmt.Sprintf("[%s](%s)", link.Text, link.URL)
+}
+
+// convertAnnotations appends a part per annotation, titled by its kind
+// ("Warning") and numbered per kind within its guideline or part
+// ("1.1.warning-1"). source is the annotations' path relative to the
+// guideline, path the guideline's Layer-1 path. Annotations without a kind
+// (e.g. from an external segmenter) are treated as notes.
+func (c *DefaultConverter) convertAnnotations(annotations []types.Annotation, ownerID, source, path string, parts []layer1.Part) []layer1.Part {
+	counts := make(map[types.AnnotationKind]int)
+	for i, annotation := range annotations {
+		if annotation.Kind == "" {
+			annotation.Kind = types.AnnotationNote
+		}
+		counts[annotation.Kind]++
+		c.report.synthesized(fmt.Sprintf("%s.guideline-parts[%d]", path, len(parts)), fmt.Sprintf("part rendered from %s[%d]", source, i))
+		kind := string(annotation.Kind)
+		parts = append(parts, layer1.Part{
+			Id:    fmt.Sprintf("%s.%s-%d", ownerID, kind, counts[annotation.Kind]),
+			Title: strings.ToUpper(kind[:1]) + kind[1:],
+			Text:  annotation.Text,
+		})
+	}
+	return parts
+}
+
+// convertTable converts a guideline's table into a Layer-1 Part
+func (c *DefaultConverter) convertTable(table *types.TableData, guidelineID string, index int) layer1.Part {
+	return layer1.Part{
+		Id:    fmt.Sprintf("%s.table-%d", guidelineID, index),
+		Title: fmt.Sprintf("Table %d", index),
+		Text:  renderMarkdownTable(table.Rows),
 	}
 }
 
+// sourceBefore reports whether block a comes before block b in the parsed document
+func sourceBefore(a, b types.SourceRef) bool {
+	if a.Page != b.Page {
+		return a.Page < b.Page
+	}
+	return a.Block < b.Block
+}
+
+// convertCode converts a guideline's code example into a Layer-1 Part
+func (c *DefaultConverter) convertCode(code, guidelineID string, index int) layer1.Part {
+	return layer1.Part{
+		Id:    fmt.Sprintf("%s.code-%d", guidelineID, index),
+		Title: fmt.Sprintf("Example %d", index),
+		Text:  renderCodeFence(code),
+	}
+}
+
+// renderCodeFence wraps code in a Markdown fence longer than any run of
+// backticks inside it
+func renderCodeFence(code string) string {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + "\n" + strings.TrimRight(code, "\n") + "\n" + fence
+}
+
+// renderMarkdownTable renders table rows as a Markdown table, treating the
+// first row as the header. Short rows are padded to the widest row.
+func renderMarkdownTable(rows [][]string) string {
+	columns := 0
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	if columns == 0 {
+		return ""
+	}
+	
+	var sb strings.Builder
+	writeRow := func(row []string) {
+		sb.WriteString("|")
+		for i := 0; i < columns; i++ {
+			cell := ""
+			if i < len(row) {
+				cell = escapeMarkdownCell(row[i])
+			}
+			sb.WriteString(" " + cell + " |")
+		}
+		sb.WriteString("\n")
+	}
+	
+	writeRow(rows[0])
+	sb.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// escapeMarkdownCell keeps cell content on one line and escapes pipes
+func escapeMarkdownCell(cell string) string {
+	cell = strings.Join(strings.Fields(cell), " ")
+	return strings.ReplaceAll(cell, "|", "\\|")
+}
+
 // ValidateLayer1 validates a Layer-1 GuidanceDocument using the schema validator
 func ValidateLayer1(doc *layer1.GuidanceDocument) error {
 	v := validator.NewValidator()
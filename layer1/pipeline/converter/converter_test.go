@@ -1,10 +1,17 @@
 package converter
 
 import (
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/ossf/gemara/layer1"
+	"github.com/ossf/gemara/layer1/pipeline/segmenter"
 	"github.com/ossf/gemara/layer1/pipeline/types"
+	"github.com/ossf/gemara/layer1/pipeline/validator"
 )
 
 func TestDefaultConverter(t *testing.T) {
@@ -266,3 +273,936 @@ func TestConvertAndValidate(t *testing.T) {
 }
 
 
+
+func TestConvertTableRoundTrip(t *testing.T) {
+	parsed := &types.ParsedDocument{
+		Metadata: types.ParsedMetadata{
+			DocumentID: "table-doc",
+			Version:    1,
+		},
+		Pages: []types.Page{
+			{
+				PageNumber: 1,
+				Blocks: []types.Block{
+					{Type: types.BlockTypeHeading, Level: 1, Text: "1. Access Control"},
+					{Type: types.BlockTypeHeading, Level: 2, Text: "1.1 Password Requirements"},
+					{Type: types.BlockTypeParagraph, Text: "Passwords must meet the following minimums."},
+					{
+						Type: types.BlockTypeTable,
+						Text: "[Table]",
+						TableData: &types.TableData{
+							Rows: [][]string{
+								{"Setting", "Minimum"},
+								{"Length", "12"},
+								{"History | reuse", "5"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	seg, err := segmenter.NewGenericSegmenter(types.SegmenterConfig{DocumentType: "generic"})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	segmented, err := seg.Segment(parsed)
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+
+	if len(segmented.Categories) != 1 || len(segmented.Categories[0].Guidelines) != 1 {
+		t.Fatalf("Expected 1 category with 1 guideline, got %+v", segmented.Categories)
+	}
+	if len(segmented.Categories[0].Guidelines[0].Tables) != 1 {
+		t.Fatalf("Expected table to be attached to guideline, got %d tables", len(segmented.Categories[0].Guidelines[0].Tables))
+	}
+
+	layer1Doc, err := NewConverter().Convert(segmented)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	parts := layer1Doc.Categories[0].Guidelines[0].GuidelineParts
+	if len(parts) != 1 {
+		t.Fatalf("Expected 1 part from table, got %d", len(parts))
+	}
+
+	if parts[0].Id != "1.1.table-1" {
+		t.Errorf("Expected part ID '1.1.table-1', got '%s'", parts[0].Id)
+	}
+
+	expected := "| Setting | Minimum |\n" +
+		"| --- | --- |\n" +
+		"| Length | 12 |\n" +
+		"| History \\| reuse | 5 |"
+	if parts[0].Text != expected {
+		t.Errorf("Unexpected table text:\n%s\nwant:\n%s", parts[0].Text, expected)
+	}
+}
+
+func TestConvertTablesInSourceOrder(t *testing.T) {
+	parsed := &types.ParsedDocument{
+		Metadata: types.ParsedMetadata{DocumentID: "table-order-doc", Version: 1},
+		Pages: []types.Page{
+			{
+				PageNumber: 1,
+				Blocks: []types.Block{
+					{Type: types.BlockTypeHeading, Level: 1, Text: "1. Access Control"},
+					{Type: types.BlockTypeHeading, Level: 2, Text: "1.1 Password Requirements"},
+					{Type: types.BlockTypeParagraph, Text: "1.1.1 Passwords must meet the following minimums."},
+					{Type: types.BlockTypeTable, Text: "[Table]", TableData: &types.TableData{Rows: [][]string{{"Setting", "Minimum"}, {"Length", "12"}}}},
+					{Type: types.BlockTypeParagraph, Text: "1.1.2 Passwords must be rotated after a breach."},
+				},
+			},
+		},
+	}
+
+	seg, err := segmenter.NewGenericSegmenter(types.SegmenterConfig{DocumentType: "generic"})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	segmented, err := seg.Segment(parsed)
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+
+	layer1Doc, err := NewConverter().Convert(segmented)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	var ids []string
+	for _, part := range layer1Doc.Categories[0].Guidelines[0].GuidelineParts {
+		ids = append(ids, part.Id)
+	}
+	if want := []string{"1.1.1", "1.1.table-1", "1.1.2"}; !slices.Equal(ids, want) {
+		t.Errorf("Expected parts %v, got %v", want, ids)
+	}
+}
+
+func TestConvertCode(t *testing.T) {
+	segmented := &types.SegmentedDocument{
+		Categories: []types.SegmentCategory{{
+			ID:    "1",
+			Title: "Access Control",
+			Guidelines: []types.SegmentGuideline{{
+				ID:    "1.1",
+				Title: "Remote Access",
+				Code:  []string{"PermitRootLogin = no", "Use ```sshd -t``` to check"},
+			}},
+		}},
+	}
+	
+	layer1Doc, err := NewConverter().Convert(segmented)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	
+	parts := layer1Doc.Categories[0].Guidelines[0].GuidelineParts
+	if len(parts) != 2 {
+		t.Fatalf("Expected 2 parts from code, got %+v", parts)
+	}
+	if parts[0].Id != "1.1.code-1" || parts[0].Text != "```\nPermitRootLogin = no\n```" {
+		t.Errorf("Unexpected code part: %+v", parts[0])
+	}
+	// A fence inside the code gets a longer fence around it
+	if parts[1].Text != "````\nUse ```sshd -t``` to check\n````" {
+		t.Errorf("Unexpected fence for code containing backticks: %q", parts[1].Text)
+	}
+}
+
+func TestConvertAnnotations(t *testing.T) {
+	segmented := &types.SegmentedDocument{
+		Categories: []types.SegmentCategory{{
+			ID:    "1",
+			Title: "Access Control",
+			Guidelines: []types.SegmentGuideline{{
+				ID:          "1.1",
+				Title:       "Passwords",
+				Annotations: []types.Annotation{
+					{Kind: types.AnnotationNote, Text: "Passphrases are easier to remember."},
+					{Text: "Annotations without a kind are notes."},
+				},
+				Sources:     []types.SourceRef{{Page: 1, Block: 1}},
+				Parts: []types.SegmentPart{
+					{
+						ID:   "1.1.1",
+						Text: "Rotate passwords after a breach.",
+						Annotations: []types.Annotation{
+							{Kind: types.AnnotationWarning, Text: "Forced periodic rotation weakens passwords."},
+							{Kind: types.AnnotationWarning, Text: "Notify users first."},
+						},
+						Sources: []types.SourceRef{{Page: 1, Block: 2}},
+					},
+					{ID: "1.1.2", Text: "Lock accounts after failed attempts.", Sources: []types.SourceRef{{Page: 1, Block: 4}}},
+				},
+			}},
+		}},
+	}
+	
+	conv := NewConverter()
+	layer1Doc, err := conv.Convert(segmented)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	
+	// Part callouts follow their part; guideline callouts follow the parts
+	var got []string
+	for _, part := range layer1Doc.Categories[0].Guidelines[0].GuidelineParts {
+		got = append(got, part.Id+" "+part.Title)
+	}
+	want := []string{"1.1.1 ", "1.1.1.warning-1 Warning", "1.1.1.warning-2 Warning", "1.1.2 ", "1.1.note-1 Note", "1.1.note-2 Note"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected parts %q, got %q", want, got)
+	}
+	if len(conv.Report().Synthesized) != 4 {
+		t.Errorf("Expected 4 synthesized parts, got %+v", conv.Report().Synthesized)
+	}
+	
+	// Parts after the annotation parts keep their own sources
+	for _, element := range conv.Provenance().Elements {
+		if element.ID == "1.1.2" && (len(element.Sources) != 1 || element.Sources[0].Block != 4) {
+			t.Errorf("Expected 1.1.2 to keep its sources, got %+v", element.Sources)
+		}
+	}
+}
+
+func TestConvertRationale(t *testing.T) {
+	seg, err := segmenter.NewGenericSegmenter(types.SegmenterConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	parsed := &types.ParsedDocument{Pages: []types.Page{{
+		PageNumber: 1,
+		Blocks: []types.Block{
+			{Type: types.BlockTypeHeading, Level: 1, Text: "1. Access Control"},
+			{Type: types.BlockTypeHeading, Level: 2, Text: "1.1 Passwords"},
+			{Type: types.BlockTypeParagraph, Text: "Passwords must be rotated after compromise."},
+			{Type: types.BlockTypeParagraph, Text: "Risks:"},
+			{Type: types.BlockTypeList, Text: "Credential stuffing: reused passwords let attackers sign in"},
+			{Type: types.BlockTypeList, Text: "Stolen passwords stay valid indefinitely."},
+			{Type: types.BlockTypeParagraph, Text: "Outcome: Compromised credentials stop working quickly."},
+			{Type: types.BlockTypeList, Text: "An unrelated list item"},
+		},
+	}}}
+	segmented, err := seg.Segment(parsed)
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+	
+	layer1Doc, err := NewConverter().Convert(segmented)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	
+	rationale := layer1Doc.Categories[0].Guidelines[0].Rationale
+	if rationale == nil {
+		t.Fatal("Expected a rationale from the Risks: list")
+	}
+	wantRisks := []layer1.Risk{
+		{Title: "Credential stuffing", Description: "reused passwords let attackers sign in"},
+		{Title: "Stolen passwords stay valid indefinitely", Description: "Stolen passwords stay valid indefinitely."},
+	}
+	if !slices.Equal(rationale.Risks, wantRisks) {
+		t.Errorf("Unexpected risks: %+v", rationale.Risks)
+	}
+	if len(rationale.Outcomes) != 1 || rationale.Outcomes[0].Title != "Compromised credentials stop working quickly" || rationale.Outcomes[0].Description != "Compromised credentials stop working quickly." {
+		t.Errorf("Unexpected outcomes: %+v", rationale.Outcomes)
+	}
+	
+	// Guidelines without risks or outcomes get no rationale
+	plain := &types.SegmentedDocument{Categories: []types.SegmentCategory{{
+		ID:         "1",
+		Guidelines: []types.SegmentGuideline{{ID: "1.1", Title: "Passwords"}},
+	}}}
+	layer1Doc, err = NewConverter().Convert(plain)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	if rationale := layer1Doc.Categories[0].Guidelines[0].Rationale; rationale != nil {
+		t.Errorf("Expected no rationale, got %+v", rationale)
+	}
+}
+
+func TestConvertMappings(t *testing.T) {
+	seg, err := segmenter.NewGenericSegmenter(types.SegmenterConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	parsed := &types.ParsedDocument{Pages: []types.Page{{
+		PageNumber: 1,
+		Blocks: []types.Block{
+			{Type: types.BlockTypeHeading, Level: 1, Text: "1. Access Control"},
+			{Type: types.BlockTypeHeading, Level: 2, Text: "1.1 Access Reviews"},
+			{Type: types.BlockTypeParagraph, Text: "Access must be reviewed quarterly. Maps to ISO 27001:2013 A.9.2, A.9.4 and NIST CSF PR.AC-1."},
+			{Type: types.BlockTypeParagraph, Text: "See also section 1.2 and the OWASP ASVS."},
+			{Type: types.BlockTypeHeading, Level: 2, Text: "1.2 Accounts"},
+			{Type: types.BlockTypeParagraph, Text: "Cross-reference: ISO 27001:2013 A.9.2.1 for account provisioning."},
+		},
+	}}}
+	segmented, err := seg.Segment(parsed)
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+	
+	layer1Doc, err := NewConverter().Convert(segmented)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	
+	mappings := layer1Doc.Categories[0].Guidelines[0].GuidelineMappings
+	if len(mappings) != 3 {
+		t.Fatalf("Expected 3 mappings, got %+v", mappings)
+	}
+	if mappings[0].ReferenceId != "ISO-27001-2013" || len(mappings[0].Entries) != 2 || mappings[0].Entries[1].ReferenceId != "A.9.4" {
+		t.Errorf("Unexpected ISO mapping: %+v", mappings[0])
+	}
+	if mappings[1].ReferenceId != "NIST-CSF" || len(mappings[1].Entries) != 1 || mappings[1].Entries[0].ReferenceId != "PR.AC-1" {
+		t.Errorf("Unexpected NIST mapping: %+v", mappings[1])
+	}
+	if mappings[2].ReferenceId != "OWASP-ASVS" || len(mappings[2].Entries) != 0 {
+		t.Errorf("Expected the internal section reference skipped, got %+v", mappings[2])
+	}
+	
+	// Each framework is referenced once, whichever guidelines map to it
+	wantRefs := []layer1.MappingReference{
+		{Id: "ISO-27001-2013", Title: "ISO 27001:2013", Version: "2013"},
+		{Id: "NIST-CSF", Title: "NIST CSF", Version: "unspecified"},
+		{Id: "OWASP-ASVS", Title: "OWASP ASVS", Version: "unspecified"},
+	}
+	if !slices.Equal(layer1Doc.Metadata.MappingReferences, wantRefs) {
+		t.Errorf("Unexpected mapping references: %+v", layer1Doc.Metadata.MappingReferences)
+	}
+	
+	result := validator.NewValidator().Validate(layer1Doc)
+	for _, e := range result.Errors {
+		if strings.Contains(e.Path, "mapping") {
+			t.Errorf("Unexpected mapping validation error: %v", e)
+		}
+	}
+}
+
+func TestConvertLinks(t *testing.T) {
+	parsed := &types.ParsedDocument{
+		Metadata: types.ParsedMetadata{
+			DocumentID: "link-doc",
+			Version:    1,
+		},
+		Pages: []types.Page{
+			{
+				PageNumber: 1,
+				Blocks: []types.Block{
+					{Type: types.BlockTypeHeading, Level: 1, Text: "1. Cryptography"},
+					{Type: types.BlockTypeHeading, Level: 2, Text: "1.1 Approved Algorithms"},
+					{
+						Type:  types.BlockTypeParagraph,
+						Text:  "See the NIST guidance for approved algorithms.",
+						Links: []types.Link{{Text: "NIST guidance", URL: "https://csrc.nist.gov/projects"}},
+					},
+					{
+						Type:  types.BlockTypeParagraph,
+						Text:  "Refer to section 2 for key management.",
+						Links: []types.Link{{Text: "section 2", URL: "#/texts/12"}},
+					},
+					{
+						Type: types.BlockTypeParagraph,
+						Text: "1.1.1 Use algorithms from the approved list",
+						Links: []types.Link{
+							{Text: "approved list", URL: "https://example.com/approved"},
+							{Text: "approved list", URL: "https://example.com/approved"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	seg, err := segmenter.NewGenericSegmenter(types.SegmenterConfig{DocumentType: "generic"})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	segmented, err := seg.Segment(parsed)
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+
+	layer1Doc, err := NewConverter().Convert(segmented)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	parts := layer1Doc.Categories[0].Guidelines[0].GuidelineParts
+	if len(parts) != 2 {
+		t.Fatalf("Expected part and links part, got %+v", parts)
+	}
+
+	if want := "Use algorithms from the approved list (https://example.com/approved)"; parts[0].Text != want {
+		t.Errorf("Part text = %q, want %q", parts[0].Text, want)
+	}
+
+	if parts[1].Id != "1.1.links" {
+		t.Errorf("Expected links part ID '1.1.links', got '%s'", parts[1].Id)
+	}
+	if want := "- [NIST guidance](https://csrc.nist.gov/projects)"; parts[1].Text != want {
+		t.Errorf("Links part text = %q, want %q (internal references should be dropped)", parts[1].Text, want)
+	}
+}
+
+func TestNormalizeIDs(t *testing.T) {
+	doc := &types.SegmentedDocument{
+		DocumentMetadata: types.DocumentMetadata{
+			ID:          "pci",
+			Title:       "PCI",
+			Description: "PCI",
+			Author:      "PCI SSC",
+		},
+		Categories: []types.SegmentCategory{
+			{
+				ID:          "1",
+				Title:       "Firewalls",
+				Description: "Firewalls",
+				Guidelines: []types.SegmentGuideline{
+					{
+						ID:    "PCI-DSS-1.1",
+						Title: "Standards",
+						Parts: []types.SegmentPart{
+							{ID: "PCI-DSS-1.1.1", Text: "Approve connections"},
+						},
+					},
+					{ID: "REQ-1.2", Title: "Restrict"},
+					{ID: "1.2", Title: "Duplicate after normalization"},
+				},
+			},
+		},
+	}
+	
+	// IDs are preserved by default
+	conv := NewConverter()
+	layer1Doc, err := conv.Convert(doc)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	if layer1Doc.Categories[0].Guidelines[0].Id != "PCI-DSS-1.1" || len(conv.IDIssues()) != 0 {
+		t.Errorf("Expected IDs to be preserved, got %q", layer1Doc.Categories[0].Guidelines[0].Id)
+	}
+	
+	conv = NewConverter(WithNormalizedIDs(IDScheme{Prefix: "REQ-", Separator: "."}))
+	layer1Doc, err = conv.Convert(doc)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	
+	cat := layer1Doc.Categories[0]
+	if cat.Id != "REQ-1" {
+		t.Errorf("Expected category ID REQ-1, got %q", cat.Id)
+	}
+	if cat.Guidelines[0].Id != "REQ-1.1" || cat.Guidelines[1].Id != "REQ-1.2" {
+		t.Errorf("Expected canonical guideline IDs, got %q and %q", cat.Guidelines[0].Id, cat.Guidelines[1].Id)
+	}
+	if cat.Guidelines[0].GuidelineParts[0].Id != "REQ-1.1.1" {
+		t.Errorf("Expected canonical part ID, got %q", cat.Guidelines[0].GuidelineParts[0].Id)
+	}
+	
+	// The colliding ID keeps its original value and is reported
+	if cat.Guidelines[2].Id != "1.2" {
+		t.Errorf("Expected colliding ID to be kept, got %q", cat.Guidelines[2].Id)
+	}
+	issues := conv.IDIssues()
+	if len(issues) != 1 || issues[0].Path != "categories[0].guidelines[2].id" {
+		t.Errorf("Expected one collision issue, got %v", issues)
+	}
+}
+
+func TestIDSchemeCanonical(t *testing.T) {
+	scheme := IDScheme{Separator: "-"}
+	tests := map[string]string{
+		"1.1":          "1-1",
+		"REQ-1.1":      "1-1",
+		"PCI-DSS-10.2": "10-2",
+		"AC-2.1":       "AC-2-1",
+		"":             "",
+	}
+	for id, want := range tests {
+		if got := scheme.Canonical(id); got != want {
+			t.Errorf("Canonical(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestNormalizeIDReferences(t *testing.T) {
+	doc := &layer1.GuidanceDocument{
+		Categories: []layer1.Category{
+			{
+				Id: "1",
+				Guidelines: []layer1.Guideline{
+					{Id: "PCI-DSS-1.1"},
+					{Id: "PCI-DSS-1.1.1", BaseGuidelineID: "PCI-DSS-1.1", SeeAlso: []string{"PCI-DSS-1.1", "9.9"}},
+				},
+			},
+		},
+	}
+	
+	issues := normalizeIDs(doc, IDScheme{})
+	
+	guide := doc.Categories[0].Guidelines[1]
+	if guide.BaseGuidelineID != "1.1" || guide.SeeAlso[0] != "1.1" {
+		t.Errorf("Expected references to be rewritten, got %q and %v", guide.BaseGuidelineID, guide.SeeAlso)
+	}
+	if guide.SeeAlso[1] != "9.9" {
+		t.Errorf("Expected unknown reference to be left unchanged, got %q", guide.SeeAlso[1])
+	}
+	if len(issues) != 1 || issues[0].ID != "9.9" {
+		t.Errorf("Expected the unknown reference to be reported, got %v", issues)
+	}
+	
+	// A kept original ID is reserved, so a later ID can't be renamed onto it
+	doc = &layer1.GuidanceDocument{
+		Categories: []layer1.Category{{
+			Id:         "1",
+			Guidelines: []layer1.Guideline{{Id: "1.1"}, {Id: "X1.1"}, {Id: "XX1.1"}},
+		}},
+	}
+	issues = normalizeIDs(doc, IDScheme{StripPrefixes: []string{"X"}})
+	guidelines := doc.Categories[0].Guidelines
+	if guidelines[0].Id != "1.1" || guidelines[1].Id != "X1.1" || guidelines[2].Id != "XX1.1" {
+		t.Errorf("Expected both colliding IDs to keep their originals, got %v", []string{guidelines[0].Id, guidelines[1].Id, guidelines[2].Id})
+	}
+	if len(issues) != 2 {
+		t.Errorf("Expected both collisions to be reported, got %v", issues)
+	}
+	
+	// References resolve within their own category first, and are
+	// ambiguous when other categories' guidelines with that ID were
+	// normalized differently
+	doc = &layer1.GuidanceDocument{
+		Categories: []layer1.Category{
+			{Id: "A", Guidelines: []layer1.Guideline{{Id: "1_1"}, {Id: "2", SeeAlso: []string{"1_1"}}}},
+			{Id: "B", Guidelines: []layer1.Guideline{{Id: "1.1"}, {Id: "1_1", BaseGuidelineID: "1_1"}}},
+			{Id: "C", Guidelines: []layer1.Guideline{{Id: "3", SeeAlso: []string{"1_1"}}}},
+		},
+	}
+	issues = normalizeIDs(doc, IDScheme{Prefix: "X-"})
+	if ref := doc.Categories[0].Guidelines[1].SeeAlso[0]; ref != "X-1.1" {
+		t.Errorf("Expected the reference to resolve within category A, got %q", ref)
+	}
+	if ref := doc.Categories[1].Guidelines[1].BaseGuidelineID; ref != "1_1" {
+		t.Errorf("Expected the base guideline to resolve within category B, got %q", ref)
+	}
+	if ref := doc.Categories[2].Guidelines[0].SeeAlso[0]; ref != "1_1" {
+		t.Errorf("Expected the ambiguous reference left unchanged, got %q", ref)
+	}
+	if len(issues) != 2 || !strings.Contains(issues[1].Message, "several categories") {
+		t.Errorf("Expected the collision and the ambiguous reference reported, got %v", issues)
+	}
+}
+
+func TestConversionReport(t *testing.T) {
+	doc := &types.SegmentedDocument{
+		DocumentMetadata: types.DocumentMetadata{
+			ID:    "report-doc",
+			Title: "Report Doc",
+		},
+		RevisionHistory: []types.Revision{{Version: "1.0", Date: "2024-01-01"}},
+		Categories: []types.SegmentCategory{
+			{
+				ID:    "1",
+				Title: "Access",
+				Guidelines: []types.SegmentGuideline{
+					{
+						ID:        "1.1",
+						Title:     "Passwords",
+						Objective: "Use strong passwords",
+						Parts: []types.SegmentPart{
+							{ID: "1.1.1", Text: "Follow the guide", Links: []types.Link{
+								{Text: "guide", URL: "https://example.com/guide"},
+								{Text: "section 2", URL: "#/texts/4"},
+							}},
+						},
+						Tables: []types.TableData{{Rows: [][]string{{"Setting", "Value"}, {"Length", "12"}}}},
+					},
+					{ID: "1.2", Title: "Sessions"},
+				},
+			},
+		},
+	}
+	
+	conv := NewConverter(WithNormalizedIDs(IDScheme{Prefix: "REQ-", Separator: "."}))
+	if conv.Report() != nil {
+		t.Error("Expected no report before the first conversion")
+	}
+	if _, err := conv.Convert(doc); err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	report := conv.Report()
+	
+	counts := make(map[string]int)
+	for _, m := range report.Mapped {
+		counts[m.Source] = m.Count
+	}
+	if counts["categories[].guidelines[].title"] != 2 {
+		t.Errorf("Expected 2 mapped guideline titles, got %d", counts["categories[].guidelines[].title"])
+	}
+	if counts["categories[].guidelines[].objective"] != 1 {
+		t.Errorf("Expected 1 mapped objective, got %d", counts["categories[].guidelines[].objective"])
+	}
+	if counts["revision_history[]"] != 1 {
+		t.Errorf("Expected 1 mapped revision, got %d", counts["revision_history[]"])
+	}
+	if _, ok := counts["document_metadata.author"]; ok {
+		t.Error("Expected empty author not to be reported as mapped")
+	}
+	
+	dropped := make(map[string]bool)
+	for _, d := range report.Dropped {
+		dropped[d.Path] = true
+	}
+	if !dropped["categories[0].guidelines[0].parts[0].links[1]"] || len(report.Dropped) != 1 {
+		t.Errorf("Expected the internal link to be dropped, got %+v", report.Dropped)
+	}
+	
+	if len(report.Synthesized) != 1 || report.Synthesized[0].Path != "categories[0].guidelines[0].guideline-parts[1]" {
+		t.Errorf("Expected the table part to be synthesized, got %+v", report.Synthesized)
+	}
+	
+	transformed := make(map[string]bool)
+	for _, tr := range report.Transformed {
+		transformed[tr.Path] = true
+	}
+	if !transformed["categories[0].guidelines[0].parts[0].text"] {
+		t.Errorf("Expected link URL appended to part text, got %+v", report.Transformed)
+	}
+	if !transformed["categories[0].id"] || !transformed["categories[0].guidelines[1].id"] {
+		t.Errorf("Expected normalized IDs to be reported, got %+v", report.Transformed)
+	}
+}
+
+func TestSynthesizeParts(t *testing.T) {
+	doc := &types.SegmentedDocument{
+		DocumentMetadata: types.DocumentMetadata{ID: "synth", Title: "Synth"},
+		Categories: []types.SegmentCategory{
+			{
+				ID:    "1",
+				Title: "Access",
+				Guidelines: []types.SegmentGuideline{
+					{
+						ID:              "1.1",
+						Title:           "Passwords",
+						Objective:       "Use strong passwords",
+						Recommendations: []string{"Use strong passwords", "Rotate passwords after compromise"},
+					},
+					{ID: "1.2", Title: "Sessions", Parts: []types.SegmentPart{{ID: "1.2.1", Text: "Expire idle sessions"}}},
+					{ID: "1.3", Title: "Empty"},
+				},
+			},
+		},
+	}
+	
+	// Off by default
+	result, err := NewConverter().Convert(doc)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	if parts := result.Categories[0].Guidelines[0].GuidelineParts; len(parts) != 0 {
+		t.Errorf("Expected no synthesized parts by default, got %+v", parts)
+	}
+	
+	conv := NewConverter(WithSynthesizeParts(true))
+	result, err = conv.Convert(doc)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	guidelines := result.Categories[0].Guidelines
+	parts := guidelines[0].GuidelineParts
+	if len(parts) != 1 || parts[0].Id != "1.1.statement" {
+		t.Fatalf("Expected a single synthesized part, got %+v", parts)
+	}
+	if parts[0].Text != "Use strong passwords\nRotate passwords after compromise" {
+		t.Errorf("Unexpected synthesized text: %q", parts[0].Text)
+	}
+	if len(guidelines[1].GuidelineParts) != 1 || guidelines[1].GuidelineParts[0].Id != "1.2.1" {
+		t.Errorf("Expected existing parts to be kept as-is, got %+v", guidelines[1].GuidelineParts)
+	}
+	if len(guidelines[2].GuidelineParts) != 0 {
+		t.Errorf("Expected no part without objective or recommendations, got %+v", guidelines[2].GuidelineParts)
+	}
+	if synthesized := conv.Report().Synthesized; len(synthesized) != 1 || synthesized[0].Path != "categories[0].guidelines[0].guideline-parts[0]" {
+		t.Errorf("Expected the synthesized part in the report, got %+v", synthesized)
+	}
+}
+
+func TestConvertProvenance(t *testing.T) {
+	doc := &types.SegmentedDocument{
+		Metadata: types.SegmentedMetadata{DocumentID: "traced-doc", SourceVersion: 3},
+		Categories: []types.SegmentCategory{
+			{
+				ID:      "1",
+				Title:   "Access Control",
+				Sources: []types.SourceRef{{Page: 1, Block: 0}},
+				Guidelines: []types.SegmentGuideline{
+					{
+						ID:      "1.1",
+						Title:   "Passwords",
+						Sources: []types.SourceRef{{Page: 1, Block: 1}, {Page: 1, Block: 2}},
+						Parts: []types.SegmentPart{
+							{ID: "1.1.1", Text: "Passwords must be unique.", Sources: []types.SourceRef{{Page: 2, Block: 0}}},
+						},
+						Links: []types.Link{{Text: "Guide", URL: "https://example.com/guide"}},
+					},
+				},
+			},
+		},
+	}
+	
+	conv := NewConverter(WithNormalizedIDs(IDScheme{Prefix: "REQ-"}))
+	if conv.Provenance() != nil {
+		t.Error("Expected no provenance before the first conversion")
+	}
+	if _, err := conv.Convert(doc); err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	
+	prov := conv.Provenance()
+	if prov.DocumentID != "traced-doc" || prov.ParsedVersion != 3 {
+		t.Errorf("Unexpected provenance header: %+v", prov)
+	}
+	
+	tests := []struct {
+		id      string
+		kind    string
+		sources []types.SourceRef
+	}{
+		{"REQ-1", "category", []types.SourceRef{{Page: 1, Block: 0}}},
+		{"REQ-1.1", "guideline", []types.SourceRef{{Page: 1, Block: 1}, {Page: 1, Block: 2}}},
+		{"REQ-1.1.1", "part", []types.SourceRef{{Page: 2, Block: 0}}},
+		// The synthesized links part comes from the guideline's content
+		{"REQ-1.1.links", "part", []types.SourceRef{{Page: 1, Block: 1}, {Page: 1, Block: 2}}},
+	}
+	for _, tt := range tests {
+		found := prov.Find(tt.id)
+		if len(found) != 1 {
+			t.Errorf("Expected one element %s, got %+v", tt.id, found)
+			continue
+		}
+		if found[0].Kind != tt.kind || !slices.Equal(found[0].Sources, tt.sources) {
+			t.Errorf("Element %s: expected %s from %v, got %+v", tt.id, tt.kind, tt.sources, found[0])
+		}
+	}
+}
+
+func TestConvertProfile(t *testing.T) {
+	segmented := &types.SegmentedDocument{
+		DocumentMetadata: types.DocumentMetadata{ID: "CIS", Title: "CIS Benchmark", Description: "Benchmark", Author: "CIS"},
+		Categories: []types.SegmentCategory{{
+			ID: "1", Title: "Initial Setup", Description: "Setup",
+			Guidelines: []types.SegmentGuideline{{
+				ID:      "1.1.1.1",
+				Title:   "Ensure mounting of cramfs filesystems is disabled",
+				Profile: "L1",
+				Parts:   []types.SegmentPart{{ID: "1.1.1.1.a", Text: "Disable cramfs."}},
+			}},
+		}},
+	}
+	
+	conv := NewConverter()
+	doc, err := conv.Convert(segmented)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	parts := doc.Categories[0].Guidelines[0].GuidelineParts
+	if len(parts) != 2 || parts[1].Id != "1.1.1.1.profile" || parts[1].Title != "Profile" || parts[1].Text != "L1" {
+		t.Errorf("Expected a profile part after the guideline's own, got %+v", parts)
+	}
+	for _, change := range conv.Report().Dropped {
+		if strings.HasSuffix(change.Path, ".profile") {
+			t.Errorf("Expected the profile not to be reported as dropped, got %+v", change)
+		}
+	}
+}
+
+func TestConvertCategoryDocumentType(t *testing.T) {
+	segmented := &types.SegmentedDocument{
+		DocumentMetadata: types.DocumentMetadata{
+			ID:           "TEST-STD",
+			Title:        "Test Standard",
+			Description:  "A standard with regulatory annexes",
+			Author:       "Test Author",
+			DocumentType: "Standard",
+		},
+		Categories: []types.SegmentCategory{
+			{ID: "1", Title: "Core", Description: "Core controls"},
+			{ID: "2", Title: "Annex", Description: "Regulatory annex", DocumentType: "Regulation"},
+			{ID: "3", Title: "Extras", Description: "Restates the default", DocumentType: "Standard"},
+			{ID: "4", Title: "Tips", Description: "Spelled loosely", DocumentType: " best-practice"},
+			{ID: "5", Title: "Core again", Description: "Restates the default loosely", DocumentType: "STANDARD"},
+		},
+	}
+	
+	conv := NewConverter()
+	doc, err := conv.Convert(segmented)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	
+	// Only the override is kept; the others inherit the document type
+	want := []layer1.DocumentType{"", "Regulation", "", "Best Practice", ""}
+	for i, cat := range doc.Categories {
+		if cat.DocumentType != want[i] {
+			t.Errorf("Category %s: expected document type %q, got %q", cat.Id, want[i], cat.DocumentType)
+		}
+	}
+	
+	result := validator.NewValidator(validator.WithStrictMode(true)).Validate(doc)
+	if !result.Valid {
+		t.Errorf("Expected converted document to validate, got: %v", result.Errors)
+	}
+}
+
+func TestReverse(t *testing.T) {
+	segmented := &types.SegmentedDocument{
+		DocumentMetadata: types.DocumentMetadata{
+			ID:            "TEST-STD",
+			Title:         "Test Standard",
+			Description:   "A standard for round trips",
+			Author:        "Test Author",
+			Version:       "1.0",
+			DocumentType:  "Standard",
+			Jurisdictions: []string{"EU"},
+		},
+		FrontMatter:     "Introduction",
+		RevisionHistory: []types.Revision{{Version: "1.0", Date: "2024-01-01", Description: "Initial release"}},
+		Categories: []types.SegmentCategory{{
+			ID:          "1",
+			Title:       "Access Control",
+			Description: "Controlling access",
+			Guidelines: []types.SegmentGuideline{{
+				ID:              "1.1",
+				Title:           "Passwords",
+				Objective:       "Protect accounts",
+				Recommendations: []string{"Use MFA"},
+				Normativity:     types.NormativityMust,
+				Code:            []string{"PermitRootLogin = no"},
+				Risks:           []types.RationaleItem{{Title: "Takeover", Description: "Accounts are taken over"}},
+				Mappings:        []types.SegmentMapping{{Framework: "ISO 27001:2013", Entries: []string{"A.9.2"}, Text: "Maps to ISO 27001:2013 A.9.2"}},
+			}, {
+				ID:    "1.2",
+				Title: "Sessions",
+				Parts: []types.SegmentPart{{ID: "1.2.1", Text: "Expire idle sessions", Normativity: types.NormativityShould}},
+			}},
+		}},
+	}
+	
+	conv := NewConverter(WithSynthesizeParts(true))
+	forward, err := conv.Convert(segmented)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	if got := forward.Categories[0].Guidelines; got[0].Normativity != "must" || got[1].GuidelineParts[0].Normativity != "should" {
+		t.Errorf("Expected normativity carried into the guideline and part, got %q and %q", got[0].Normativity, got[1].GuidelineParts[0].Normativity)
+	}
+	reversed, err := conv.Reverse(forward)
+	if err != nil {
+		t.Fatalf("Reverse failed: %v", err)
+	}
+	
+	if reversed.DocumentMetadata.ID != "TEST-STD" || reversed.DocumentMetadata.Title != "Test Standard" || reversed.DocumentMetadata.DocumentType != "Standard" || reversed.FrontMatter != "Introduction" {
+		t.Errorf("Unexpected metadata: %+v", reversed.DocumentMetadata)
+	}
+	if len(reversed.RevisionHistory) != 1 || reversed.RevisionHistory[0] != (types.Revision{Version: "1.0", Date: "2024-01-01", Description: "Initial release"}) {
+		t.Errorf("Expected the revision history carried through, got %+v", reversed.RevisionHistory)
+	}
+	if len(reversed.Categories) != 1 || reversed.Categories[0].ID != "1" || reversed.Categories[0].Title != "Access Control" {
+		t.Fatalf("Unexpected categories: %+v", reversed.Categories)
+	}
+	guidelines := reversed.Categories[0].Guidelines
+	if len(guidelines) != 2 || guidelines[0].ID != "1.1" || guidelines[0].Title != "Passwords" || guidelines[1].ID != "1.2" || guidelines[1].Title != "Sessions" {
+		t.Fatalf("Unexpected guidelines: %+v", guidelines)
+	}
+	if guidelines[0].Normativity != types.NormativityMust || guidelines[1].Parts[0].Normativity != types.NormativityShould {
+		t.Errorf("Expected normativity to carry back, got %+v", guidelines)
+	}
+	if len(guidelines[0].Risks) != 1 || len(guidelines[0].Mappings) != 1 || guidelines[0].Mappings[0].Framework != "ISO 27001:2013" {
+		t.Errorf("Expected rationale and mappings to carry over, got %+v", guidelines[0])
+	}
+	
+	// Synthesized parts come back as parts, so a second forward conversion
+	// reproduces the first rather than synthesizing them again
+	again, err := NewConverter(WithSynthesizeParts(true)).Convert(reversed)
+	if err != nil {
+		t.Fatalf("Second conversion failed: %v", err)
+	}
+	firstJSON, _ := json.Marshal(forward)
+	againJSON, _ := json.Marshal(again)
+	if string(firstJSON) != string(againJSON) {
+		t.Errorf("Round trip changed the document:\n%s\n%s", firstJSON, againJSON)
+	}
+	
+	// Fields without a segmented equivalent are reported as dropped
+	forward.Categories[0].Guidelines[0].SeeAlso = []string{"1.2"}
+	forward.Metadata.MappingReferences[0].Url = "https://www.iso.org/standard/54534.html"
+	if _, err := conv.Reverse(forward); err != nil {
+		t.Fatalf("Reverse failed: %v", err)
+	}
+	dropped := map[string]bool{}
+	for _, change := range conv.Report().Dropped {
+		dropped[change.Path] = true
+	}
+	if !dropped["categories[0].guidelines[0].see-also"] || !dropped["metadata.mapping-references[0]"] || len(dropped) != 2 {
+		t.Errorf("Unexpected dropped fields: %+v", conv.Report().Dropped)
+	}
+}
+
+func TestFormatRegistry(t *testing.T) {
+	doc := &layer1.GuidanceDocument{
+		Metadata:   layer1.Metadata{Id: "doc", Title: "Doc"},
+		Categories: []layer1.Category{{Id: "1", Title: "Access Control"}},
+	}
+
+	var out strings.Builder
+	if err := WriteFormat(doc, "json", &out); err != nil {
+		t.Fatalf("WriteFormat json failed: %v", err)
+	}
+	var decoded layer1.GuidanceDocument
+	if err := json.Unmarshal([]byte(out.String()), &decoded); err != nil || decoded.Metadata.Id != "doc" {
+		t.Errorf("Expected JSON of the document, got %q (%v)", out.String(), err)
+	}
+
+	out.Reset()
+	if err := WriteFormat(doc, "oscal-catalog", &out); err != nil || !strings.Contains(out.String(), `"catalog"`) {
+		t.Errorf("Expected an OSCAL catalog, got %q (%v)", out.String(), err)
+	}
+
+	if err := WriteFormat(doc, "no-such-format", &out); err == nil || !strings.Contains(err.Error(), "yaml") {
+		t.Errorf("Expected an error listing the available formats, got %v", err)
+	}
+
+	out.Reset()
+	if err := WriteFormat(doc, "markdown", &out); err != nil || !strings.Contains(out.String(), "## 1 Access Control") {
+		t.Errorf("Expected a Markdown heading per category, got %q (%v)", out.String(), err)
+	}
+	out.Reset()
+	doc.Categories[0].Title = "Access <Control>"
+	if err := WriteFormat(doc, "html", &out); err != nil || !strings.Contains(out.String(), "<h2>1 Access &lt;Control&gt;</h2>") {
+		t.Errorf("Expected an escaped HTML heading per category, got %q (%v)", out.String(), err)
+	}
+
+	titles := func(doc *layer1.GuidanceDocument, w io.Writer) error {
+		_, err := io.WriteString(w, doc.Metadata.Title)
+		return err
+	}
+	if err := RegisterFormat("test-titles", titles); err != nil {
+		t.Fatalf("RegisterFormat failed: %v", err)
+	}
+	t.Cleanup(func() { unregisterFormat("test-titles") })
+	if err := RegisterFormat("test-titles", titles); err == nil {
+		t.Error("Expected registering a format twice to fail")
+	}
+	if !slices.Contains(Formats(), "test-titles") {
+		t.Errorf("Expected registered format in %v", Formats())
+	}
+	out.Reset()
+	if err := WriteFormat(doc, "test-titles", &out); err != nil || out.String() != "Doc" {
+		t.Errorf("Expected custom formatter output %q, got %q (%v)", "Doc", out.String(), err)
+	}
+}
@@ -0,0 +1,256 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	oscal "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ossf/gemara/layer1"
+	"github.com/ossf/gemara/layer1/pipeline/storage"
+)
+
+// Formatter writes a Layer-1 document to w in one output format
+type Formatter func(doc *layer1.GuidanceDocument, w io.Writer) error
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]Formatter{}
+)
+
+func init() {
+	mustRegisterFormat("yaml", writeYAML)
+	mustRegisterFormat("yml", writeYAML)
+	mustRegisterFormat("json", writeJSON)
+	mustRegisterFormat("markdown", writeMarkdown)
+	mustRegisterFormat("md", writeMarkdown)
+	mustRegisterFormat("html", writeHTML)
+	mustRegisterFormat("oscal-catalog", writeOSCALCatalog)
+	mustRegisterFormat("oscal-profile", writeOSCALProfile)
+}
+
+// RegisterFormat makes a Formatter available under name. Exporters register
+// themselves from an init function, so the CLI's --format picks them up
+// without being edited. Registering a name twice is an error.
+func RegisterFormat(name string, formatter Formatter) error {
+	if name == "" || formatter == nil {
+		return fmt.Errorf("format registration needs a name and a formatter")
+	}
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	if _, ok := formats[name]; ok {
+		return fmt.Errorf("format already registered: %s", name)
+	}
+	formats[name] = formatter
+	return nil
+}
+
+func mustRegisterFormat(name string, formatter Formatter) {
+	if err := RegisterFormat(name, formatter); err != nil {
+		panic(err)
+	}
+}
+
+// unregisterFormat removes a format, so tests can undo RegisterFormat
+func unregisterFormat(name string) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	delete(formats, name)
+}
+
+// LookupFormat returns the Formatter registered under name
+func LookupFormat(name string) (Formatter, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	formatter, ok := formats[name]
+	return formatter, ok
+}
+
+// Formats returns the registered format names, sorted
+func Formats() []string {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WriteFormat writes doc to w with the Formatter registered under format
+func WriteFormat(doc *layer1.GuidanceDocument, format string, w io.Writer) error {
+	formatter, ok := LookupFormat(format)
+	if !ok {
+		return fmt.Errorf("unsupported format: %s (available: %s)", format, strings.Join(Formats(), ", "))
+	}
+	return formatter(doc, w)
+}
+
+func writeYAML(doc *layer1.GuidanceDocument, w io.Writer) error {
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// writeJSON writes the canonical JSON storage uses for final documents
+func writeJSON(doc *layer1.GuidanceDocument, w io.Writer) error {
+	data, err := storage.MarshalCanonicalJSON(doc)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// writeMarkdown renders the document for reading: a heading per category and
+// guideline, with objectives, parts and recommendations as text. Part text is
+// already Markdown, since tables and code are rendered as Markdown on
+// conversion.
+func writeMarkdown(doc *layer1.GuidanceDocument, w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", doc.Metadata.Title)
+	if details := documentDetails(doc.Metadata); len(details) > 0 {
+		b.WriteString("\n")
+		for _, detail := range details {
+			fmt.Fprintf(&b, "- %s\n", detail)
+		}
+	}
+	if doc.Metadata.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", doc.Metadata.Description)
+	}
+	for _, category := range doc.Categories {
+		fmt.Fprintf(&b, "\n## %s %s\n", category.Id, category.Title)
+		if category.Description != "" {
+			fmt.Fprintf(&b, "\n%s\n", category.Description)
+		}
+		for _, guideline := range category.Guidelines {
+			fmt.Fprintf(&b, "\n### %s %s\n", guideline.Id, guideline.Title)
+			if guideline.Objective != "" {
+				fmt.Fprintf(&b, "\n**Objective:** %s\n", guideline.Objective)
+			}
+			for _, part := range guideline.GuidelineParts {
+				if part.Title != "" {
+					fmt.Fprintf(&b, "\n#### %s %s\n", part.Id, part.Title)
+				} else {
+					fmt.Fprintf(&b, "\n#### %s\n", part.Id)
+				}
+				fmt.Fprintf(&b, "\n%s\n", part.Text)
+				writeMarkdownList(&b, part.Recommendations)
+			}
+			writeMarkdownList(&b, guideline.Recommendations)
+			if len(guideline.SeeAlso) > 0 {
+				fmt.Fprintf(&b, "\nSee also: %s\n", strings.Join(guideline.SeeAlso, ", "))
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownList(b *strings.Builder, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	b.WriteString("\n")
+	for _, item := range items {
+		fmt.Fprintf(b, "- %s\n", item)
+	}
+}
+
+// documentDetails lists the metadata shown under the title of the Markdown
+// and HTML renderings
+func documentDetails(meta layer1.Metadata) []string {
+	var details []string
+	for _, field := range []struct{ label, value string }{
+		{"ID", meta.Id},
+		{"Version", meta.Version},
+		{"Author", meta.Author},
+		{"Published", meta.PublicationDate},
+		{"Document type", string(meta.DocumentType)},
+	} {
+		if field.value != "" {
+			details = append(details, field.label+": "+field.value)
+		}
+	}
+	return details
+}
+
+// writeHTML renders the same structure as writeMarkdown as a standalone HTML
+// page. Part text keeps its line breaks but is not converted from Markdown.
+func writeHTML(doc *layer1.GuidanceDocument, w io.Writer) error {
+	return documentHTML.Execute(w, doc)
+}
+
+var documentHTML = template.Must(template.New("document").Funcs(template.FuncMap{
+	"details": documentDetails,
+	"join":    strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Metadata.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+.text { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>{{.Metadata.Title}}</h1>
+{{with details .Metadata}}<ul>
+{{range .}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}{{with .Metadata.Description}}<p>{{.}}</p>
+{{end}}{{range .Categories}}
+<h2>{{.Id}} {{.Title}}</h2>
+{{with .Description}}<p>{{.}}</p>
+{{end}}{{range .Guidelines}}<h3>{{.Id}} {{.Title}}</h3>
+{{with .Objective}}<p><strong>Objective:</strong> {{.}}</p>
+{{end}}{{range .GuidelineParts}}<h4>{{.Id}}{{with .Title}} {{.}}{{end}}</h4>
+<p class="text">{{.Text}}</p>
+{{with .Recommendations}}<ul>
+{{range .}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}{{end}}{{with .Recommendations}}<ul>
+{{range .}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}{{with .SeeAlso}}<p>See also: {{join . ", "}}</p>
+{{end}}{{end}}{{end}}</body>
+</html>
+`))
+
+func writeOSCALCatalog(doc *layer1.GuidanceDocument, w io.Writer) error {
+	catalog, err := doc.ToOSCALCatalog()
+	if err != nil {
+		return err
+	}
+	return writeOSCAL(oscal.OscalModels{Catalog: &catalog}, w)
+}
+
+// writeOSCALProfile writes a profile importing the document's catalog from
+// "{document-id}-catalog.json", relative to the profile
+func writeOSCALProfile(doc *layer1.GuidanceDocument, w io.Writer) error {
+	profile, err := doc.ToOSCALProfile(doc.Metadata.Id + "-catalog.json")
+	if err != nil {
+		return err
+	}
+	return writeOSCAL(oscal.OscalModels{Profile: &profile}, w)
+}
+
+func writeOSCAL(model oscal.OscalModels, w io.Writer) error {
+	data, err := json.MarshalIndent(model, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
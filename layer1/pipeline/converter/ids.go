@@ -0,0 +1,176 @@
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ossf/gemara/layer1"
+)
+
+// DefaultStripPrefixes are the ID prefixes segmenters are known to add
+var DefaultStripPrefixes = []string{"PCI-DSS-", "REQ-", "Requirement "}
+
+// IDScheme describes the canonical form IDs are rewritten to. The ID is
+// split into tokens on '.', '-', '_' and whitespace, the tokens are joined
+// with Separator and Prefix is prepended: "PCI-DSS-1.1" becomes "REQ-1.1"
+// with Prefix "REQ-" and Separator ".".
+type IDScheme struct {
+	Prefix        string
+	Separator     string   // Defaults to "."
+	StripPrefixes []string // Prefixes removed before tokenizing; defaults to DefaultStripPrefixes
+}
+
+// IDIssue reports an ID or reference that could not be normalized
+type IDIssue struct {
+	Path    string `json:"path" yaml:"path"`
+	ID      string `json:"id" yaml:"id"`
+	Message string `json:"message" yaml:"message"`
+}
+
+func (i IDIssue) String() string {
+	return fmt.Sprintf("%s: %s (%s)", i.Path, i.Message, i.ID)
+}
+
+var idTokenSeparators = regexp.MustCompile(`[.\-_\s]+`)
+
+// Canonical returns the canonical form of an ID under the scheme
+func (s IDScheme) Canonical(id string) string {
+	trimmed := strings.TrimSpace(id)
+	if trimmed == "" {
+		return ""
+	}
+
+	stripPrefixes := s.StripPrefixes
+	if stripPrefixes == nil {
+		stripPrefixes = DefaultStripPrefixes
+	}
+	for _, prefix := range stripPrefixes {
+		if prefix != "" && len(trimmed) > len(prefix) && strings.EqualFold(trimmed[:len(prefix)], prefix) {
+			trimmed = trimmed[len(prefix):]
+			break
+		}
+	}
+	if s.Prefix != "" && strings.HasPrefix(trimmed, s.Prefix) {
+		trimmed = strings.TrimPrefix(trimmed, s.Prefix)
+	}
+
+	separator := s.Separator
+	if separator == "" {
+		separator = "."
+	}
+
+	var tokens []string
+	for _, token := range idTokenSeparators.Split(trimmed, -1) {
+		if token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	if len(tokens) == 0 {
+		return id
+	}
+
+	return s.Prefix + strings.Join(tokens, separator)
+}
+
+// normalizeIDs rewrites every ID in the document to the scheme and updates
+// references (base guidelines and see-also) to match. IDs that would collide
+// keep their original value; references to unknown IDs are left unchanged.
+// Guideline IDs are only unique within a category, so a reference resolves
+// to its own category's guideline first; one matching guidelines with
+// different canonical IDs in several other categories is ambiguous and left
+// unchanged. All three cases are reported.
+func normalizeIDs(doc *layer1.GuidanceDocument, scheme IDScheme) []IDIssue {
+	var issues []IDIssue
+	// Original guideline ID -> canonical, per category and across them
+	rewritten := make([]map[string]string, len(doc.Categories))
+	rewrittenAll := make(map[string][]string)
+
+	rename := func(id, path string, seen map[string]string) string {
+		if id == "" {
+			return id
+		}
+		canonical := scheme.Canonical(id)
+		if other, taken := seen[canonical]; taken && other != id {
+			issues = append(issues, IDIssue{
+				Path:    path,
+				ID:      id,
+				Message: fmt.Sprintf("canonical ID '%s' already used by '%s'; keeping original", canonical, other),
+			})
+			// The kept original is taken too, so nothing is renamed onto it
+			if _, taken := seen[id]; !taken {
+				seen[id] = id
+			}
+			return id
+		}
+		seen[canonical] = id
+		return canonical
+	}
+
+	seenCategories := make(map[string]string)
+	for i := range doc.Categories {
+		cat := &doc.Categories[i]
+		catPath := fmt.Sprintf("categories[%d]", i)
+		cat.Id = rename(cat.Id, catPath+".id", seenCategories)
+
+		rewritten[i] = make(map[string]string)
+		seenGuidelines := make(map[string]string)
+		for j := range cat.Guidelines {
+			guide := &cat.Guidelines[j]
+			guidePath := fmt.Sprintf("%s.guidelines[%d]", catPath, j)
+			original := guide.Id
+			guide.Id = rename(guide.Id, guidePath+".id", seenGuidelines)
+			if original != "" {
+				rewritten[i][original] = guide.Id
+				if !slices.Contains(rewrittenAll[original], guide.Id) {
+					rewrittenAll[original] = append(rewrittenAll[original], guide.Id)
+				}
+			}
+
+			seenParts := make(map[string]string)
+			for k := range guide.GuidelineParts {
+				part := &guide.GuidelineParts[k]
+				part.Id = rename(part.Id, fmt.Sprintf("%s.guideline-parts[%d].id", guidePath, k), seenParts)
+			}
+		}
+	}
+
+	// Rewrite references once every guideline ID is known
+	resolve := func(ref string, category int, path string) string {
+		if canonical, ok := rewritten[category][ref]; ok {
+			return canonical
+		}
+		switch candidates := rewrittenAll[ref]; len(candidates) {
+		case 1:
+			return candidates[0]
+		case 0:
+			issues = append(issues, IDIssue{
+				Path:    path,
+				ID:      ref,
+				Message: "reference does not match any guideline ID; left unchanged",
+			})
+		default:
+			issues = append(issues, IDIssue{
+				Path:    path,
+				ID:      ref,
+				Message: fmt.Sprintf("reference matches guidelines in several categories (%s); left unchanged", strings.Join(candidates, ", ")),
+			})
+		}
+		return ref
+	}
+	for i := range doc.Categories {
+		for j := range doc.Categories[i].Guidelines {
+			guide := &doc.Categories[i].Guidelines[j]
+			guidePath := fmt.Sprintf("categories[%d].guidelines[%d]", i, j)
+			if guide.BaseGuidelineID != "" {
+				guide.BaseGuidelineID = resolve(guide.BaseGuidelineID, i, guidePath+".base-guideline-id")
+			}
+			for k, ref := range guide.SeeAlso {
+				guide.SeeAlso[k] = resolve(ref, i, fmt.Sprintf("%s.see-also[%d]", guidePath, k))
+			}
+		}
+	}
+
+	return issues
+}
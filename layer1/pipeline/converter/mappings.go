@@ -0,0 +1,94 @@
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ossf/gemara/layer1"
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// unspecifiedVersion is the version of a mapping reference whose framework
+// was named without one, since Layer-1 requires a version
+const unspecifiedVersion = "unspecified"
+
+// frameworkVersionPatterns find a version in a framework name: a year
+// ("ISO 27001:2013"), "v8" or "Version 8", "Rev. 5", or a trailing dotted
+// number ("PCI DSS 4.0")
+var frameworkVersionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`:(\d{4})\b`),
+	regexp.MustCompile(`(?i)\bv(?:ersion)?\.?\s*(\d+(?:\.\d+)*)\b`),
+	regexp.MustCompile(`(?i)\brev(?:ision)?\.?\s*(\d+)\b`),
+	regexp.MustCompile(`\s(\d+\.\d+(?:\.\d+)?)$`),
+}
+
+// nonIDChars matches runs of characters that can't appear in a reference ID
+var nonIDChars = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// mappingReferenceID derives a mapping reference ID from a framework name
+// ("ISO/IEC 27001:2013" becomes "ISO-IEC-27001-2013")
+func mappingReferenceID(framework string) string {
+	return strings.Trim(nonIDChars.ReplaceAllString(strings.ToUpper(framework), "-"), "-")
+}
+
+// frameworkVersion returns the version stated in a framework name, if any
+func frameworkVersion(framework string) string {
+	for _, pattern := range frameworkVersionPatterns {
+		if matches := pattern.FindStringSubmatch(framework); matches != nil {
+			return matches[1]
+		}
+	}
+	return ""
+}
+
+// convertMappings turns a guideline's framework references into Layer-1
+// guideline mappings. The source gives no mapping strength, so entries
+// carry the sentence they came from as remarks instead.
+func convertMappings(guide *types.SegmentGuideline) []layer1.Mapping {
+	var mappings []layer1.Mapping
+	for _, segMapping := range guide.Mappings {
+		mapping := layer1.Mapping{
+			ReferenceId: mappingReferenceID(segMapping.Framework),
+			Remarks:     segMapping.Text,
+		}
+		for _, entry := range segMapping.Entries {
+			mapping.Entries = append(mapping.Entries, layer1.MappingEntry{ReferenceId: entry})
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings
+}
+
+// convertMappingReferences lists each framework the guidelines reference
+// once, in order of first reference, as metadata mapping references
+func (c *DefaultConverter) convertMappingReferences(doc *types.SegmentedDocument) []layer1.MappingReference {
+	var refs []layer1.MappingReference
+	seen := map[string]bool{}
+	for _, cat := range doc.Categories {
+		for _, guide := range cat.Guidelines {
+			for _, mapping := range guide.Mappings {
+				id := mappingReferenceID(mapping.Framework)
+				if id == "" || seen[id] {
+					continue
+				}
+				seen[id] = true
+
+				path := fmt.Sprintf("metadata.mapping-references[%d]", len(refs))
+				version := frameworkVersion(mapping.Framework)
+				if version == "" {
+					version = unspecifiedVersion
+					c.report.synthesized(path, fmt.Sprintf("reference to %q; version not stated in the source", mapping.Framework))
+				} else {
+					c.report.synthesized(path, fmt.Sprintf("reference to %q", mapping.Framework))
+				}
+				refs = append(refs, layer1.MappingReference{
+					Id:      id,
+					Title:   mapping.Framework,
+					Version: version,
+				})
+			}
+		}
+	}
+	return refs
+}
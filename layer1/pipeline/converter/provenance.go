@@ -0,0 +1,54 @@
+package converter
+
+import (
+	"fmt"
+
+	"github.com/ossf/gemara/layer1"
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// buildProvenance pairs each element of the converted document with the
+// source blocks the segmenter recorded for it. It runs after ID
+// normalization so elements are listed under their final IDs. Parts the
+// converter synthesized (statements, annotations, tables, links) come from
+// their guideline's content and share its sources.
+func buildProvenance(doc *types.SegmentedDocument, l1 *layer1.GuidanceDocument) *types.Provenance {
+	prov := &types.Provenance{
+		DocumentID:    doc.Metadata.DocumentID,
+		ParsedVersion: doc.Metadata.SourceVersion,
+		Elements:      []types.ElementProvenance{},
+	}
+	add := func(id, kind, path string, sources []types.SourceRef) {
+		prov.Elements = append(prov.Elements, types.ElementProvenance{ID: id, Kind: kind, Path: path, Sources: sources})
+	}
+
+	for i, cat := range l1.Categories {
+		segCat := doc.Categories[i]
+		catPath := fmt.Sprintf("categories[%d]", i)
+		add(cat.Id, "category", catPath, segCat.Sources)
+
+		for j, guide := range cat.Guidelines {
+			segGuide := segCat.Guidelines[j]
+			guidePath := fmt.Sprintf("%s.guidelines[%d]", catPath, j)
+			add(guide.Id, "guideline", guidePath, segGuide.Sources)
+
+			// Each segmented part converts to a part followed by one per
+			// annotation; the rest are synthesized from the guideline
+			var partSources [][]types.SourceRef
+			for _, segPart := range segGuide.Parts {
+				partSources = append(partSources, segPart.Sources)
+				for range segPart.Annotations {
+					partSources = append(partSources, segGuide.Sources)
+				}
+			}
+			for k, part := range guide.GuidelineParts {
+				sources := segGuide.Sources
+				if k < len(partSources) {
+					sources = partSources[k]
+				}
+				add(part.Id, "part", fmt.Sprintf("%s.guideline-parts[%d]", guidePath, k), sources)
+			}
+		}
+	}
+	return prov
+}
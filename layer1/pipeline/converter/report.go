@@ -0,0 +1,106 @@
+package converter
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ossf/gemara/layer1"
+)
+
+// FieldMapping counts segmented fields carried over to a Layer-1 field.
+// Paths use "[]" for list elements, e.g. "categories[].guidelines[].objective".
+type FieldMapping struct {
+	Source string `json:"source" yaml:"source"`
+	Target string `json:"target" yaml:"target"`
+	Count  int    `json:"count" yaml:"count"`
+}
+
+// FieldChange describes a single value the converter dropped, synthesized
+// or transformed rather than copying it as-is
+type FieldChange struct {
+	Path   string `json:"path" yaml:"path"`                         // Segmented path for dropped/transformed values, Layer-1 path for synthesized ones and for values Reverse dropped
+	Target string `json:"target,omitempty" yaml:"target,omitempty"` // Layer-1 path the value ended up at, if any
+	Detail string `json:"detail" yaml:"detail"`
+}
+
+// ConversionReport records what the converter did with each part of a
+// segmented document
+type ConversionReport struct {
+	Mapped      []FieldMapping `json:"mapped" yaml:"mapped"`
+	Dropped     []FieldChange  `json:"dropped,omitempty" yaml:"dropped,omitempty"`
+	Synthesized []FieldChange  `json:"synthesized,omitempty" yaml:"synthesized,omitempty"`
+	Transformed []FieldChange  `json:"transformed,omitempty" yaml:"transformed,omitempty"`
+
+	mappedIndex map[string]int
+}
+
+func newConversionReport() *ConversionReport {
+	return &ConversionReport{mappedIndex: make(map[string]int)}
+}
+
+// mapped counts a field copied from source to target when it has a value
+func (r *ConversionReport) mapped(source, target string, present bool) {
+	if !present {
+		return
+	}
+	key := source + "\x00" + target
+	if i, ok := r.mappedIndex[key]; ok {
+		r.Mapped[i].Count++
+		return
+	}
+	r.mappedIndex[key] = len(r.Mapped)
+	r.Mapped = append(r.Mapped, FieldMapping{Source: source, Target: target, Count: 1})
+}
+
+func (r *ConversionReport) dropped(path, detail string) {
+	r.Dropped = append(r.Dropped, FieldChange{Path: path, Detail: detail})
+}
+
+func (r *ConversionReport) synthesized(path, detail string) {
+	r.Synthesized = append(r.Synthesized, FieldChange{Path: path, Detail: detail})
+}
+
+func (r *ConversionReport) transformed(path, target, detail string) {
+	r.Transformed = append(r.Transformed, FieldChange{Path: path, Target: target, Detail: detail})
+}
+
+// sortMapped orders mappings by source path so reports are stable
+func (r *ConversionReport) sortMapped() {
+	sort.SliceStable(r.Mapped, func(i, j int) bool {
+		return r.Mapped[i].Source < r.Mapped[j].Source
+	})
+	r.mappedIndex = nil
+}
+
+// idPath pairs an ID with its Layer-1 path
+type idPath struct {
+	path string
+	id   string
+}
+
+// collectIDs lists every category, guideline and part ID in document order
+func collectIDs(doc *layer1.GuidanceDocument) []idPath {
+	var ids []idPath
+	for i, cat := range doc.Categories {
+		catPath := fmt.Sprintf("categories[%d]", i)
+		ids = append(ids, idPath{catPath + ".id", cat.Id})
+		for j, guide := range cat.Guidelines {
+			guidePath := fmt.Sprintf("%s.guidelines[%d]", catPath, j)
+			ids = append(ids, idPath{guidePath + ".id", guide.Id})
+			for k, part := range guide.GuidelineParts {
+				ids = append(ids, idPath{fmt.Sprintf("%s.guideline-parts[%d].id", guidePath, k), part.Id})
+			}
+		}
+	}
+	return ids
+}
+
+// recordIDChanges reports IDs rewritten by normalization. before and after
+// come from collectIDs on the same document, so they line up by index.
+func (r *ConversionReport) recordIDChanges(before, after []idPath) {
+	for i := range before {
+		if i < len(after) && before[i].id != after[i].id {
+			r.transformed(before[i].path, before[i].path, fmt.Sprintf("ID normalized from '%s' to '%s'", before[i].id, after[i].id))
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ossf/gemara/layer1"
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// reverseSegmenter names the "segmenter" of a document produced by Reverse
+const reverseSegmenter = "layer1-reverse"
+
+// Reverse maps a Layer-1 document back to a segmented document, so an
+// existing document can be edited and re-enhanced and then converted
+// again. Metadata, revision history, categories, guidelines, parts, rationale
+// and guideline mappings carry over; converting the result forward again
+// yields the same categories, guidelines and parts.
+//
+// The conversion is lossy where segmented documents have no field: imported
+// guidelines and principles, last-modified, exemptions, technology domains,
+// base guideline IDs, principle mappings, see-also lists, mapping strengths
+// and entry remarks, and mapping reference details other than the title
+// are dropped. Each dropped value is listed in Report().Dropped under its
+// Layer-1 path.
+func (c *DefaultConverter) Reverse(doc *layer1.GuidanceDocument) (*types.SegmentedDocument, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("layer-1 document is nil")
+	}
+
+	c.report = newConversionReport()
+	c.provenance = nil
+	c.idIssues = nil
+
+	segmented := &types.SegmentedDocument{
+		Metadata: types.SegmentedMetadata{
+			Segmenter:   reverseSegmenter,
+			SegmentedAt: time.Now(),
+			DocumentID:  doc.Metadata.Id,
+		},
+		DocumentMetadata: c.reverseMetadata(&doc.Metadata),
+		FrontMatter:      doc.FrontMatter,
+		RevisionHistory:  reverseRevisions(doc.Metadata.RevisionHistory),
+		Categories:       make([]types.SegmentCategory, 0, len(doc.Categories)),
+	}
+
+	if len(doc.ImportedGuidelines) > 0 {
+		c.report.dropped("imported-guidelines", fmt.Sprintf("%d imported guideline mappings have no segmented field", len(doc.ImportedGuidelines)))
+	}
+	if len(doc.ImportedPrinciples) > 0 {
+		c.report.dropped("imported-principles", fmt.Sprintf("%d imported principle mappings have no segmented field", len(doc.ImportedPrinciples)))
+	}
+
+	references := make(map[string]layer1.MappingReference, len(doc.Metadata.MappingReferences))
+	for _, ref := range doc.Metadata.MappingReferences {
+		references[ref.Id] = ref
+	}
+
+	for i, cat := range doc.Categories {
+		path := fmt.Sprintf("categories[%d]", i)
+		segCat := types.SegmentCategory{
+			ID:           cat.Id,
+			Title:        cat.Title,
+			Description:  cat.Description,
+			DocumentType: string(cat.DocumentType),
+		}
+		for j, guide := range cat.Guidelines {
+			segCat.Guidelines = append(segCat.Guidelines, c.reverseGuideline(&guide, fmt.Sprintf("%s.guidelines[%d]", path, j), references))
+		}
+		segmented.Categories = append(segmented.Categories, segCat)
+	}
+
+	return segmented, nil
+}
+
+// reverseRevisions maps a Layer-1 revision history back to segmented revisions
+func reverseRevisions(revisions []layer1.Revision) []types.Revision {
+	var history []types.Revision
+	for _, rev := range revisions {
+		history = append(history, types.Revision{
+			Version:     rev.Version,
+			Date:        rev.Date,
+			Description: rev.Description,
+		})
+	}
+	return history
+}
+
+// reverseMetadata maps Layer-1 metadata back to document metadata
+func (c *DefaultConverter) reverseMetadata(meta *layer1.Metadata) types.DocumentMetadata {
+	docMeta := types.DocumentMetadata{
+		ID:              meta.Id,
+		Title:           meta.Title,
+		Description:     meta.Description,
+		Author:          meta.Author,
+		Version:         meta.Version,
+		PublicationDate: meta.PublicationDate,
+		DocumentType:    string(meta.DocumentType),
+	}
+	if meta.Applicability != nil {
+		docMeta.Jurisdictions = meta.Applicability.Jurisdictions
+		docMeta.IndustrySectors = meta.Applicability.IndustrySectors
+		if len(meta.Applicability.TechnologyDomains) > 0 {
+			c.report.dropped("metadata.applicability.technology-domains", "technology domains have no segmented field")
+		}
+	}
+	if meta.LastModified != "" {
+		c.report.dropped("metadata.last-modified", "last-modified has no segmented field")
+	}
+	if len(meta.Exemptions) > 0 {
+		c.report.dropped("metadata.exemptions", "exemptions have no segmented field")
+	}
+
+	// References are rebuilt from the guideline mappings' framework titles
+	for i, ref := range meta.MappingReferences {
+		path := fmt.Sprintf("metadata.mapping-references[%d]", i)
+		var lost []string
+		if mappingReferenceID(ref.Title) != ref.Id {
+			lost = append(lost, "id")
+		}
+		if version := frameworkVersion(ref.Title); version != ref.Version && !(version == "" && ref.Version == unspecifiedVersion) {
+			lost = append(lost, "version")
+		}
+		if ref.Description != "" {
+			lost = append(lost, "description")
+		}
+		if ref.Issuer != "" {
+			lost = append(lost, "issuer")
+		}
+		if ref.Url != "" {
+			lost = append(lost, "url")
+		}
+		if len(lost) > 0 {
+			c.report.dropped(path, fmt.Sprintf("mapping reference %q loses its %s; references are rebuilt from the title", ref.Id, strings.Join(lost, ", ")))
+		}
+	}
+	return docMeta
+}
+
+// reverseGuideline maps a Layer-1 guideline back to a segmented guideline.
+// Parts the forward conversion synthesized (statements, tables, code,
+// links) come back as ordinary parts, so they aren't synthesized twice.
+func (c *DefaultConverter) reverseGuideline(guide *layer1.Guideline, path string, references map[string]layer1.MappingReference) types.SegmentGuideline {
+	segGuide := types.SegmentGuideline{
+		ID:              guide.Id,
+		Title:           guide.Title,
+		Objective:       guide.Objective,
+		Recommendations: guide.Recommendations,
+		Normativity:     types.Normativity(guide.Normativity),
+	}
+	for _, part := range guide.GuidelineParts {
+		segGuide.Parts = append(segGuide.Parts, types.SegmentPart{
+			ID:              part.Id,
+			Title:           part.Title,
+			Text:            part.Text,
+			Recommendations: part.Recommendations,
+			Normativity:     types.Normativity(part.Normativity),
+		})
+	}
+
+	if guide.Rationale != nil {
+		for _, risk := range guide.Rationale.Risks {
+			segGuide.Risks = append(segGuide.Risks, types.RationaleItem{Title: risk.Title, Description: risk.Description})
+		}
+		for _, outcome := range guide.Rationale.Outcomes {
+			segGuide.Outcomes = append(segGuide.Outcomes, types.RationaleItem{Title: outcome.Title, Description: outcome.Description})
+		}
+	}
+
+	for i, mapping := range guide.GuidelineMappings {
+		framework := mapping.ReferenceId
+		if ref, ok := references[mapping.ReferenceId]; ok && ref.Title != "" {
+			framework = ref.Title
+		}
+		segMapping := types.SegmentMapping{Framework: framework, Text: mapping.Remarks}
+		for j, entry := range mapping.Entries {
+			segMapping.Entries = append(segMapping.Entries, entry.ReferenceId)
+			if entry.Strength != 0 || entry.Remarks != "" {
+				c.report.dropped(fmt.Sprintf("%s.guideline-mappings[%d].entries[%d]", path, i, j), "mapping entry strength and remarks have no segmented field")
+			}
+		}
+		segGuide.Mappings = append(segGuide.Mappings, segMapping)
+	}
+
+	if guide.BaseGuidelineID != "" {
+		c.report.dropped(path+".base-guideline-id", "base guideline ID has no segmented field")
+	}
+	if len(guide.PrincipleMappings) > 0 {
+		c.report.dropped(path+".principle-mappings", "principle mappings have no segmented field")
+	}
+	if len(guide.SeeAlso) > 0 {
+		c.report.dropped(path+".see-also", "see-also references have no segmented field")
+	}
+	return segGuide
+}
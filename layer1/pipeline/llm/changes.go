@@ -0,0 +1,348 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// malformedResponseConfidence is the confidence of a review whose response
+// wasn't the JSON the prompt asked for
+const malformedResponseConfidence = 0.1
+
+// segmentationReview is the JSON object segmentationReviewPrompt asks for
+type segmentationReview struct {
+	Confidence  *float64           `json:"confidence"`
+	Issues      []json.RawMessage  `json:"issues"`
+	Suggestions []reviewSuggestion `json:"suggestions"`
+}
+
+// reviewSuggestion is one suggested edit to the segmented document
+type reviewSuggestion struct {
+	Path       string          `json:"path"`
+	Type       string          `json:"type"` // "add", "modify", "remove"
+	NewValue   json.RawMessage `json:"new_value"`
+	Reason     string          `json:"reason"`
+	Confidence *float64        `json:"confidence"`
+}
+
+// extractJSONObject returns the outermost JSON object in an LLM response,
+// which may be wrapped in prose or a code fence
+func extractJSONObject(text string) (string, bool) {
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return "", false
+	}
+	return text[start : end+1], true
+}
+
+// parseSegmentationReview parses a segmentation review response into
+// changes and an overall confidence. Suggestions without a path or with an
+// unknown type are skipped; a response that isn't a JSON object reports
+// ok = false.
+func parseSegmentationReview(text string) ([]types.EnhancementChange, float64, bool) {
+	object, ok := extractJSONObject(text)
+	if !ok {
+		return nil, 0, false
+	}
+	var review segmentationReview
+	if err := json.Unmarshal([]byte(object), &review); err != nil || review.Confidence == nil {
+		return nil, 0, false
+	}
+	confidence := min(max(*review.Confidence, 0), 1)
+
+	changes := []types.EnhancementChange{}
+	for _, suggestion := range review.Suggestions {
+		switch suggestion.Type {
+		case "add", "modify", "remove":
+		default:
+			continue
+		}
+		if strings.TrimSpace(suggestion.Path) == "" {
+			continue
+		}
+		change := types.EnhancementChange{
+			Path:       strings.TrimSpace(suggestion.Path),
+			Type:       suggestion.Type,
+			NewValue:   rawValueString(suggestion.NewValue),
+			Reason:     suggestion.Reason,
+			Confidence: confidence,
+		}
+		if suggestion.Confidence != nil {
+			change.Confidence = min(max(*suggestion.Confidence, 0), 1)
+		}
+		changes = append(changes, change)
+	}
+	return changes, confidence, true
+}
+
+// rawValueString returns a JSON string's text, or other JSON values as-is
+func rawValueString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return ""
+	}
+	return string(raw)
+}
+
+// applyReview fills a segmentation result from the LLM's response: the
+// suggested changes that apply cleanly are recorded and EnhancedData is set
+// to a copy of doc with them applied. A malformed response leaves the copy
+// unchanged and the result at malformedResponseConfidence.
+func applyReview(result *types.EnhancementResult, doc *types.SegmentedDocument, response string) error {
+	changes, confidence, ok := parseSegmentationReview(response)
+	if !ok {
+		confidence = malformedResponseConfidence
+	}
+
+	enhanced, applied, err := applyChanges(doc, changes)
+	if err != nil {
+		return err
+	}
+	result.EnhancedData = enhanced
+	result.Changes = applied
+	result.Confidence = confidence
+	return nil
+}
+
+// ApplyChanges returns a deep copy of doc with the changes applied, leaving
+// doc itself untouched. Paths use the segmented document's JSON field names
+// ("categories[0].guidelines[1].objective"); "add" appends to a list or
+// inserts at an index, "modify" replaces or sets a value (including an empty
+// field the encoding omits), and "remove" deletes one.
+// A change whose path doesn't resolve, or whose value doesn't fit the
+// field, is an error.
+func ApplyChanges(doc *types.SegmentedDocument, changes []types.EnhancementChange) (*types.SegmentedDocument, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy segmented document: %w", err)
+	}
+	for i, change := range changes {
+		updated, _, ok := applyChange(data, change)
+		if !ok {
+			return nil, fmt.Errorf("change %d (%s %s) does not apply to the document", i, change.Type, change.Path)
+		}
+		data = updated
+	}
+	return decodeSegmented(data)
+}
+
+// applyChanges is ApplyChanges for changes from an LLM, which may not all
+// fit the document: those that don't are skipped, and the changes that
+// applied are returned with OldValue filled in from the document
+func applyChanges(doc *types.SegmentedDocument, changes []types.EnhancementChange) (*types.SegmentedDocument, []types.EnhancementChange, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to copy segmented document: %w", err)
+	}
+
+	applied := []types.EnhancementChange{}
+	for _, change := range changes {
+		if updated, change, ok := applyChange(data, change); ok {
+			data = updated
+			applied = append(applied, change)
+		}
+	}
+
+	enhanced, err := decodeSegmented(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enhanced, applied, nil
+}
+
+// applyChange applies one change to an encoded segmented document,
+// reporting whether it applied and filling in the change's OldValue
+func applyChange(data []byte, change types.EnhancementChange) ([]byte, types.EnhancementChange, bool) {
+	steps, err := parseChangePath(change.Path)
+	if err != nil {
+		return nil, change, false
+	}
+	for _, value := range changeValues(change) {
+		var tree interface{}
+		if err := json.Unmarshal(data, &tree); err != nil {
+			return nil, change, false
+		}
+		updated, old, err := updatePath(tree, steps, change.Type, value)
+		if err != nil {
+			return nil, change, false
+		}
+		candidate, err := json.Marshal(updated)
+		if err != nil || !fitsSegmentedDocument(candidate) {
+			continue
+		}
+		if old != nil {
+			change.OldValue = rawValueString(mustMarshal(old))
+		}
+		return candidate, change, true
+	}
+	return nil, change, false
+}
+
+// decodeSegmented decodes an encoded segmented document into a new copy
+func decodeSegmented(data []byte) (*types.SegmentedDocument, error) {
+	var doc types.SegmentedDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to copy segmented document: %w", err)
+	}
+	return &doc, nil
+}
+
+// changeValues returns the values a change may set, in order of preference:
+// a JSON object or list is tried decoded before falling back to plain text
+func changeValues(change types.EnhancementChange) []interface{} {
+	if change.Type == "remove" {
+		return []interface{}{nil}
+	}
+	values := []interface{}{}
+	if trimmed := strings.TrimSpace(change.NewValue); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(trimmed), &decoded); err == nil {
+			values = append(values, decoded)
+		}
+	}
+	return append(values, change.NewValue)
+}
+
+// fitsSegmentedDocument reports whether JSON still decodes as a segmented
+// document, so a change can't put a value of the wrong shape in a field
+func fitsSegmentedDocument(data []byte) bool {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var doc types.SegmentedDocument
+	return dec.Decode(&doc) == nil
+}
+
+func mustMarshal(v interface{}) json.RawMessage {
+	data, _ := json.Marshal(v)
+	return data
+}
+
+// pathStep is one step of a change path: an object key, or a list index
+// when key is empty
+type pathStep struct {
+	key   string
+	index int
+}
+
+// changePathSegment matches one dot-separated segment ("guidelines[1]")
+var changePathSegment = regexp.MustCompile(`^([A-Za-z_]+)((?:\[\d+\])*)$`)
+
+// parseChangePath splits a path such as "categories[0].guidelines[1].title"
+// into steps; a leading "$." is ignored
+func parseChangePath(path string) ([]pathStep, error) {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$.")
+	var steps []pathStep
+	for _, segment := range strings.Split(path, ".") {
+		matches := changePathSegment.FindStringSubmatch(segment)
+		if matches == nil {
+			return nil, fmt.Errorf("invalid path segment %q", segment)
+		}
+		steps = append(steps, pathStep{key: matches[1]})
+		for _, index := range strings.Split(strings.Trim(matches[2], "[]"), "][") {
+			if index == "" {
+				continue
+			}
+			i, err := strconv.Atoi(index)
+			if err != nil {
+				return nil, fmt.Errorf("invalid index in %q", segment)
+			}
+			steps = append(steps, pathStep{index: i})
+		}
+	}
+	return steps, nil
+}
+
+// updatePath applies an add, modify, or remove at the end of steps within a
+// decoded JSON tree, returning the updated node and the value it replaced
+func updatePath(node interface{}, steps []pathStep, op string, value interface{}) (interface{}, interface{}, error) {
+	step := steps[0]
+	if len(steps) > 1 {
+		child, ok := lookupStep(node, step)
+		if !ok {
+			return nil, nil, fmt.Errorf("path not found")
+		}
+		updated, old, err := updatePath(child, steps[1:], op, value)
+		if err != nil {
+			return nil, nil, err
+		}
+		if step.key != "" {
+			node.(map[string]interface{})[step.key] = updated
+		} else {
+			node.([]interface{})[step.index] = updated
+		}
+		return node, old, nil
+	}
+
+	if step.key != "" {
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("not an object")
+		}
+		current, exists := object[step.key]
+		switch {
+		case op == "add" && exists && current != nil:
+			list, ok := current.([]interface{})
+			if !ok {
+				return nil, nil, fmt.Errorf("field already set")
+			}
+			object[step.key] = append(list, value)
+			return object, nil, nil
+		case op == "add" || op == "modify":
+			// Empty fields are omitted from the encoding, so modify may set
+			// a missing key; an unknown one fails the document decode check
+			object[step.key] = value
+		case !exists:
+			return nil, nil, fmt.Errorf("path not found")
+		case op == "remove":
+			delete(object, step.key)
+		}
+		return object, current, nil
+	}
+
+	list, ok := node.([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("not a list")
+	}
+	i := step.index
+	switch {
+	case op == "add" && i <= len(list):
+		list = append(list[:i], append([]interface{}{value}, list[i:]...)...)
+		return list, nil, nil
+	case i >= len(list):
+		return nil, nil, fmt.Errorf("index out of range")
+	case op == "modify":
+		old := list[i]
+		list[i] = value
+		return list, old, nil
+	case op == "remove":
+		old := list[i]
+		return append(list[:i], list[i+1:]...), old, nil
+	}
+	return nil, nil, fmt.Errorf("index out of range")
+}
+
+// lookupStep returns the child of node a step refers to
+func lookupStep(node interface{}, step pathStep) (interface{}, bool) {
+	if step.key != "" {
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		child, ok := object[step.key]
+		return child, ok && child != nil
+	}
+	list, ok := node.([]interface{})
+	if !ok || step.index >= len(list) {
+		return nil, false
+	}
+	return list[step.index], true
+}
@@ -29,11 +29,24 @@ type Enhancer interface {
 // EnhancerBase provides common LLM functionality
 type EnhancerBase struct {
 	config types.LLMConfig
+	retry    retryPolicy // From Options["max_retries"] and Options["retry_base_ms"]
+	jsonMode string      // From Options["json_mode"]
 }
 
-// Configure sets the LLM configuration
+// Configure sets the LLM configuration, rejecting invalid retry and JSON
+// mode options
 func (e *EnhancerBase) Configure(config types.LLMConfig) error {
+	retry, err := parseRetryPolicy(config.Options)
+	if err != nil {
+		return err
+	}
+	jsonMode, err := parseJSONMode(config.Options)
+	if err != nil {
+		return err
+	}
 	e.config = config
+	e.retry = retry
+	e.jsonMode = jsonMode
 	return nil
 }
 
@@ -86,26 +99,31 @@ func (e *MockEnhancer) Name() string {
 
 // EnhanceSegmentation provides mock enhancement
 func (e *MockEnhancer) EnhanceSegmentation(ctx context.Context, doc *types.SegmentedDocument) (*types.EnhancementResult, error) {
+	// Mock: Apply a change to show enhancement happened
+	changes := []types.EnhancementChange{{
+		Path:       "document_metadata.description",
+		Type:       "modify",
+		NewValue:   doc.DocumentMetadata.Description + " (Enhanced)",
+		Reason:     "Mock enhancement for testing",
+		Confidence: 0.95,
+	}}
+	enhanced, applied, err := applyChanges(doc, changes)
+	if err != nil {
+		return nil, err
+	}
+	
 	result := &types.EnhancementResult{
 		OriginalData: doc,
-		EnhancedData: doc,
-		Changes:      []types.EnhancementChange{},
+		EnhancedData: enhanced,
+		Changes:      applied,
 		Confidence:   0.95,
 		Provider:     e.Name(),
 		Model:        "mock",
 		Timestamp:    time.Now(),
+		Prompt:       segmentationReviewPrompt(doc),
+		RawResponse:  `{"confidence": 0.95, "issues": [], "suggestions": []}`,
 	}
 	
-	// Mock: Add a change to show enhancement happened
-	result.Changes = append(result.Changes, types.EnhancementChange{
-		Path:       "metadata.description",
-		Type:       "modify",
-		OldValue:   doc.DocumentMetadata.Description,
-		NewValue:   doc.DocumentMetadata.Description + " (Enhanced)",
-		Reason:     "Mock enhancement for testing",
-		Confidence: 0.95,
-	})
-	
 	return result, nil
 }
 
@@ -139,6 +157,50 @@ func (e *MockEnhancer) EnhanceGuideline(ctx context.Context, guideline *types.Se
 	return result, nil
 }
 
+// FilterChanges removes changes whose confidence is below minConfidence from
+// a result, so only changes the LLM is sure enough about are applied. It
+// returns the number of changes dropped. When a segmented document's or
+// guideline's changes are dropped, EnhancedData is rebuilt from the
+// original with only the kept changes applied. If a kept change no longer
+// applies, e.g. because it edits a guideline a dropped change added,
+// EnhancedData is reset to a copy of the original, no changes are kept, and
+// an error is returned.
+func FilterChanges(result *types.EnhancementResult, minConfidence float64) (int, error) {
+	kept := result.Changes[:0]
+	for _, change := range result.Changes {
+		if change.Confidence >= minConfidence {
+			kept = append(kept, change)
+		}
+	}
+	dropped := len(result.Changes) - len(kept)
+	result.Changes = kept
+
+	if dropped == 0 {
+		return 0, nil
+	}
+	switch original := result.OriginalData.(type) {
+	case *types.SegmentedDocument:
+		enhanced, applied, err := applyChanges(original, kept)
+		if err == nil && len(applied) < len(kept) {
+			err = fmt.Errorf("%d of the kept changes depend on dropped ones", len(kept)-len(applied))
+		}
+		if err != nil {
+			result.Changes = []types.EnhancementChange{}
+			if unchanged, copyErr := ApplyChanges(original, nil); copyErr == nil {
+				result.EnhancedData = unchanged
+			} else {
+				result.EnhancedData = nil
+			}
+			return dropped, fmt.Errorf("failed to reapply changes above confidence %.2f, keeping the original: %w", minConfidence, err)
+		}
+		result.EnhancedData = enhanced
+		result.Changes = applied
+	case *types.SegmentGuideline:
+		result.EnhancedData = applyGuidelineChanges(original, kept)
+	}
+	return dropped, nil
+}
+
 // PromptTemplates contains prompts for different enhancement tasks
 type PromptTemplates struct {
 	MetadataValidation   string
@@ -181,9 +243,10 @@ Tasks:
 4. Suggest better title if current one is unclear
 
 Respond with a JSON object containing:
-- enhanced_guideline: improved guideline structure
 - confidence: 0-1 score
-- changes: list of changes made`,
+- title: the improved title
+- objective: the guideline's objective
+- recommendations: list of key recommendations`,
 
 	SegmentationReview: `Review the document segmentation:
 
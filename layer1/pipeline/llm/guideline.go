@@ -0,0 +1,118 @@
+package llm
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// defaultGuidelineConfidence is the confidence of a guideline review that
+// doesn't score itself
+const defaultGuidelineConfidence = 0.8
+
+// guidelineReview is the JSON object guidelineReviewPrompt asks for
+type guidelineReview struct {
+	Confidence      *float64 `json:"confidence"`
+	Title           string   `json:"title"`
+	Objective       string   `json:"objective"`
+	Recommendations []string `json:"recommendations"`
+}
+
+// guidelinePath is the change path of a guideline field
+func guidelinePath(guideline *types.SegmentGuideline, field string) string {
+	return "guideline." + guideline.ID + "." + field
+}
+
+// parseGuidelineReview parses a guideline review response into changes to
+// the guideline: a missing objective is added, a different title or
+// objective is modified, and each recommendation the guideline doesn't
+// already have is added. A response that isn't a JSON object reports
+// ok = false.
+func parseGuidelineReview(guideline *types.SegmentGuideline, text string) ([]types.EnhancementChange, float64, bool) {
+	object, ok := extractJSONObject(text)
+	if !ok {
+		return nil, 0, false
+	}
+	var review guidelineReview
+	if err := json.Unmarshal([]byte(object), &review); err != nil {
+		return nil, 0, false
+	}
+	confidence := defaultGuidelineConfidence
+	if review.Confidence != nil {
+		confidence = min(max(*review.Confidence, 0), 1)
+	}
+
+	changes := []types.EnhancementChange{}
+	field := func(name, old, value, reason string) {
+		value = strings.TrimSpace(value)
+		if value == "" || value == old {
+			return
+		}
+		change := types.EnhancementChange{
+			Path:       guidelinePath(guideline, name),
+			Type:       "modify",
+			OldValue:   old,
+			NewValue:   value,
+			Reason:     reason,
+			Confidence: confidence,
+		}
+		if old == "" {
+			change.Type = "add"
+			change.Reason = "Missing " + name + " extracted by LLM"
+		}
+		changes = append(changes, change)
+	}
+	field("title", guideline.Title, review.Title, "Title clarified by LLM")
+	field("objective", guideline.Objective, review.Objective, "Objective rewritten by LLM")
+
+	seen := slices.Clone(guideline.Recommendations)
+	for _, recommendation := range review.Recommendations {
+		recommendation = strings.TrimSpace(recommendation)
+		if recommendation == "" || slices.Contains(seen, recommendation) {
+			continue
+		}
+		seen = append(seen, recommendation)
+		changes = append(changes, types.EnhancementChange{
+			Path:       guidelinePath(guideline, "recommendations"),
+			Type:       "add",
+			NewValue:   recommendation,
+			Reason:     "Recommendation identified by LLM",
+			Confidence: confidence,
+		})
+	}
+	return changes, confidence, true
+}
+
+// applyGuidelineChanges returns a copy of guideline with changes from
+// parseGuidelineReview applied, leaving guideline itself untouched
+func applyGuidelineChanges(guideline *types.SegmentGuideline, changes []types.EnhancementChange) *types.SegmentGuideline {
+	enhanced := *guideline
+	enhanced.Recommendations = slices.Clone(guideline.Recommendations)
+	for _, change := range changes {
+		switch change.Path {
+		case guidelinePath(guideline, "title"):
+			enhanced.Title = change.NewValue
+		case guidelinePath(guideline, "objective"):
+			enhanced.Objective = change.NewValue
+		case guidelinePath(guideline, "recommendations"):
+			enhanced.Recommendations = append(enhanced.Recommendations, change.NewValue)
+		}
+	}
+	return &enhanced
+}
+
+// applyGuidelineReview fills a guideline result from the LLM's response:
+// EnhancedData is set to a copy of guideline with the returned title,
+// objective and recommendations applied. A malformed response leaves the
+// copy unchanged and the result at malformedResponseConfidence.
+func applyGuidelineReview(result *types.EnhancementResult, guideline *types.SegmentGuideline, response string) {
+	changes, confidence, ok := parseGuidelineReview(guideline, response)
+	if !ok {
+		changes, confidence = []types.EnhancementChange{}, malformedResponseConfidence
+	}
+	result.EnhancedData = applyGuidelineChanges(guideline, changes)
+	result.Changes = changes
+	result.Confidence = confidence
+}
@@ -0,0 +1,82 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JSON mode settings, selected via LLMConfig.Options["json_mode"]. In JSON
+// mode the provider's API constrains the response to a JSON object:
+// OpenAI's response_format, or a forced tool call for Anthropic. Without
+// it, the JSON object is extracted from the response text, which may wrap
+// it in prose or a code fence.
+const (
+	JSONModeAuto = "auto" // Use JSON mode for models known to support it (default)
+	JSONModeOn   = "on"   // Always use JSON mode
+	JSONModeOff  = "off"  // Never use JSON mode
+)
+
+// openAIJSONModels lists the model prefixes supporting OpenAI's
+// response_format json_object
+var openAIJSONModels = []string{
+	"gpt-4o", "gpt-4.1", "gpt-4.5", "gpt-4-turbo", "gpt-4-1106", "gpt-4-0125", "gpt-5",
+	"gpt-3.5-turbo-1106", "gpt-3.5-turbo-0125", "o3", "o4",
+}
+
+// anthropicToolName names the tool the Anthropic enhancer forces the model
+// to call, whose input is the response
+const anthropicToolName = "respond"
+
+// parseJSONMode reads the JSON mode setting from LLMConfig.Options
+func parseJSONMode(options map[string]string) (string, error) {
+	switch mode := options["json_mode"]; mode {
+	case "":
+		return JSONModeAuto, nil
+	case JSONModeAuto, JSONModeOn, JSONModeOff:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid json_mode %q: use auto, on or off", mode)
+	}
+}
+
+// useJSONMode reports whether calls should use the provider's JSON mode,
+// given whether the configured model is known to support it
+func (e *EnhancerBase) useJSONMode(supported bool) bool {
+	switch e.jsonMode {
+	case JSONModeOn:
+		return true
+	case JSONModeOff:
+		return false
+	default:
+		return supported
+	}
+}
+
+// openAISupportsJSONMode reports whether an OpenAI model is known to
+// support response_format json_object
+func openAISupportsJSONMode(model string) bool {
+	for _, prefix := range openAIJSONModels {
+		if strings.HasPrefix(model, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// anthropicSupportsJSONMode reports whether an Anthropic model supports
+// tool use, which Claude 2 and Claude Instant models don't
+func anthropicSupportsJSONMode(model string) bool {
+	return strings.HasPrefix(model, "claude-") &&
+		!strings.HasPrefix(model, "claude-2") && !strings.HasPrefix(model, "claude-instant")
+}
+
+// anthropicResponseTool is the tool whose input carries the response in
+// JSON mode; any JSON object is accepted, as the prompt describes its shape
+func anthropicResponseTool() AnthropicTool {
+	return AnthropicTool{
+		Name:        anthropicToolName,
+		Description: "Return your response as a JSON object with the fields requested.",
+		InputSchema: json.RawMessage(`{"type":"object"}`),
+	}
+}
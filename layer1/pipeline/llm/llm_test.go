@@ -2,6 +2,12 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -63,6 +69,17 @@ func TestMockEnhancerSegmentation(t *testing.T) {
 	if len(result.Changes) == 0 {
 		t.Error("Expected at least one change")
 	}
+	
+	enhanced, ok := result.EnhancedData.(*types.SegmentedDocument)
+	if !ok || enhanced == doc {
+		t.Fatalf("Expected a separate enhanced document, got %+v", result.EnhancedData)
+	}
+	if enhanced.DocumentMetadata.Description != "Original description (Enhanced)" {
+		t.Errorf("Expected the change applied to the enhanced document, got %q", enhanced.DocumentMetadata.Description)
+	}
+	if doc.DocumentMetadata.Description != "Original description" || result.Changes[0].OldValue != "Original description" {
+		t.Errorf("Expected the original description kept, got %q (old value %q)", doc.DocumentMetadata.Description, result.Changes[0].OldValue)
+	}
 }
 
 func TestMockEnhancerMetadata(t *testing.T) {
@@ -212,6 +229,75 @@ func TestOpenAIEnhancerCreation(t *testing.T) {
 	}
 }
 
+func TestOpenAIEnhancerRecordsExchange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"choices": [{"message": {"role": "assistant", "content": "{\"confidence\": 0.9}"}}],
+			"usage": {"prompt_tokens": 120, "completion_tokens": 8, "total_tokens": 128}
+		}`))
+	}))
+	defer server.Close()
+	
+	enhancer, err := NewOpenAIEnhancer(types.LLMConfig{
+		Provider: "openai",
+		APIKey:   "test-key",
+		Endpoint: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create OpenAI enhancer: %v", err)
+	}
+	
+	doc := &types.SegmentedDocument{
+		DocumentMetadata: types.DocumentMetadata{Title: "Audit Doc"},
+	}
+	result, err := enhancer.EnhanceSegmentation(context.Background(), doc)
+	if err != nil {
+		t.Fatalf("EnhanceSegmentation failed: %v", err)
+	}
+	
+	if result.Prompt != segmentationReviewPrompt(doc) {
+		t.Errorf("Expected prompt to be recorded, got %q", result.Prompt)
+	}
+	if result.RawResponse != `{"confidence": 0.9}` {
+		t.Errorf("Expected raw response to be recorded, got %q", result.RawResponse)
+	}
+	if result.Usage == nil || result.Usage.PromptTokens != 120 || result.Usage.CompletionTokens != 8 || result.Usage.TotalTokens != 128 {
+		t.Errorf("Unexpected token usage: %+v", result.Usage)
+	}
+	// gpt-4: 120 prompt tokens at $0.03/1K plus 8 completion tokens at $0.06/1K
+	if math.Abs(result.CostEstimate-0.00408) > 1e-9 {
+		t.Errorf("Expected cost estimate 0.00408, got %v", result.CostEstimate)
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	usage := &types.TokenUsage{PromptTokens: 2000, CompletionTokens: 1000, TotalTokens: 3000}
+	
+	tests := []struct {
+		model string
+		cost  float64
+		ok    bool
+	}{
+		{"gpt-4o", 0.015, true},
+		{"gpt-4o-2024-08-06", 0.015, true},
+		{"gpt-4o-mini-2024-07-18", 0.0009, true},
+		{"claude-3-haiku-20240307", 0.00175, true},
+		{"gpt-4oops", 0, false},
+		{"unknown-model", 0, false},
+	}
+	for _, tt := range tests {
+		cost, ok := EstimateCost(tt.model, usage)
+		if ok != tt.ok || math.Abs(cost-tt.cost) > 1e-9 {
+			t.Errorf("EstimateCost(%q) = %v, %v; want %v, %v", tt.model, cost, ok, tt.cost, tt.ok)
+		}
+	}
+	
+	if cost, ok := EstimateCost("gpt-4", nil); ok || cost != 0 {
+		t.Errorf("Expected no estimate without usage, got %v, %v", cost, ok)
+	}
+}
+
 func TestAnthropicEnhancerCreation(t *testing.T) {
 	config := types.LLMConfig{
 		Provider: "anthropic",
@@ -229,6 +315,209 @@ func TestAnthropicEnhancerCreation(t *testing.T) {
 	}
 }
 
+func TestAnthropicEnhancer(t *testing.T) {
+	var apiKey, version string
+	var request AnthropicRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey, version = r.Header.Get("x-api-key"), r.Header.Get("anthropic-version")
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"content": [{"type": "text", "text": "Review:\n{\"confidence\": 0.72, \"issues\": [], \"suggestions\": [{\"path\": \"document_metadata.title\", \"type\": \"modify\", \"new_value\": \"Audit Document\", \"reason\": \"Full title\"}]}"}],
+			"usage": {"input_tokens": 90, "output_tokens": 10}
+		}`))
+	}))
+	defer server.Close()
+	
+	enhancer, err := NewAnthropicEnhancer(types.LLMConfig{
+		Provider: "anthropic",
+		APIKey:   "test-key",
+		Endpoint: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create Anthropic enhancer: %v", err)
+	}
+	
+	doc := &types.SegmentedDocument{
+		DocumentMetadata: types.DocumentMetadata{Title: "Audit Doc"},
+	}
+	result, err := enhancer.EnhanceSegmentation(context.Background(), doc)
+	if err != nil {
+		t.Fatalf("EnhanceSegmentation failed: %v", err)
+	}
+	
+	if apiKey != "test-key" || version == "" || request.System == "" {
+		t.Errorf("Unexpected request: key %q, version %q, system %q", apiKey, version, request.System)
+	}
+	if result.Provider != enhancer.Name() {
+		t.Errorf("Expected provider %s, got %s", enhancer.Name(), result.Provider)
+	}
+	if result.Confidence != 0.72 || len(result.Changes) != 1 || result.Changes[0].Confidence != 0.72 {
+		t.Errorf("Expected confidence 0.72 from the response, got %v (changes: %+v)", result.Confidence, result.Changes)
+	}
+	if enhanced := result.EnhancedData.(*types.SegmentedDocument); enhanced.DocumentMetadata.Title != "Audit Document" || doc.DocumentMetadata.Title != "Audit Doc" {
+		t.Errorf("Expected the title change applied to a copy, got %q (original %q)", enhanced.DocumentMetadata.Title, doc.DocumentMetadata.Title)
+	}
+	if result.Prompt != segmentationReviewPrompt(doc) {
+		t.Errorf("Expected prompt to be recorded, got %q", result.Prompt)
+	}
+	if result.Usage == nil || result.Usage.TotalTokens != 100 {
+		t.Errorf("Unexpected token usage: %+v", result.Usage)
+	}
+	
+	meta := &types.DocumentMetadata{Title: "Audit Doc"}
+	if result, err := enhancer.ValidateMetadata(context.Background(), meta); err != nil || result.OriginalData != meta {
+		t.Errorf("ValidateMetadata failed: %v", err)
+	}
+	guideline := &types.SegmentGuideline{ID: "AC-1", Title: "Policy"}
+	if result, err := enhancer.EnhanceGuideline(context.Background(), guideline); err != nil || result.OriginalData != guideline || len(result.Changes) != 0 {
+		t.Errorf("EnhanceGuideline failed: %v", err)
+	}
+}
+
+func TestJSONMode(t *testing.T) {
+	var openAIRequest OpenAIRequest
+	openAI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		openAIRequest = OpenAIRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&openAIRequest)
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "{\"confidence\": 0.9}"}}]}`))
+	}))
+	defer openAI.Close()
+	
+	for _, tt := range []struct {
+		model, mode string
+		want        bool
+	}{
+		{"gpt-4o-mini", "", true},
+		{"gpt-4", "", false},
+		{"gpt-4", JSONModeOn, true},
+		{"gpt-4o", JSONModeOff, false},
+	} {
+		enhancer, err := NewOpenAIEnhancer(types.LLMConfig{Model: tt.model, Endpoint: openAI.URL, Options: map[string]string{"json_mode": tt.mode}})
+		if err != nil {
+			t.Fatalf("Failed to create OpenAI enhancer: %v", err)
+		}
+		if _, err := enhancer.EnhanceSegmentation(context.Background(), &types.SegmentedDocument{}); err != nil {
+			t.Fatalf("EnhanceSegmentation failed: %v", err)
+		}
+		if got := openAIRequest.ResponseFormat != nil && openAIRequest.ResponseFormat.Type == "json_object"; got != tt.want {
+			t.Errorf("%s with json_mode %q: expected response_format %v, got %+v", tt.model, tt.mode, tt.want, openAIRequest.ResponseFormat)
+		}
+	}
+	
+	// Anthropic answers through the forced tool call
+	var anthropicRequest AnthropicRequest
+	anthropic := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&anthropicRequest)
+		_, _ = w.Write([]byte(`{"content": [{"type": "tool_use", "name": "respond", "input": {"confidence": 0.6, "title": "Account Policy"}}]}`))
+	}))
+	defer anthropic.Close()
+	
+	enhancer, err := NewAnthropicEnhancer(types.LLMConfig{Endpoint: anthropic.URL})
+	if err != nil {
+		t.Fatalf("Failed to create Anthropic enhancer: %v", err)
+	}
+	result, err := enhancer.EnhanceGuideline(context.Background(), &types.SegmentGuideline{ID: "AC-1", Title: "Policy"})
+	if err != nil {
+		t.Fatalf("EnhanceGuideline failed: %v", err)
+	}
+	if len(anthropicRequest.Tools) != 1 || anthropicRequest.ToolChoice == nil || anthropicRequest.ToolChoice.Name != anthropicRequest.Tools[0].Name {
+		t.Errorf("Expected a forced tool call, got tools %+v and choice %+v", anthropicRequest.Tools, anthropicRequest.ToolChoice)
+	}
+	if result.Confidence != 0.6 || len(result.Changes) != 1 || result.Changes[0].NewValue != "Account Policy" {
+		t.Errorf("Expected the tool input as response, got %v %+v", result.Confidence, result.Changes)
+	}
+	
+	if _, err := NewAnthropicEnhancer(types.LLMConfig{Options: map[string]string{"json_mode": "always"}}); err == nil {
+		t.Error("Expected an invalid json_mode to be rejected")
+	}
+}
+
+func TestEnhanceGuideline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"choices": [{"message": {"role": "assistant", "content": "{\"confidence\": 0.7, \"title\": \"Access Control Policy\", \"objective\": \"Limit access to authorized users\", \"recommendations\": [\"Review access yearly\", \"Use MFA\"]}"}}]
+		}`))
+	}))
+	defer server.Close()
+	
+	enhancer, err := NewOpenAIEnhancer(types.LLMConfig{
+		Provider: "openai",
+		APIKey:   "test-key",
+		Endpoint: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create OpenAI enhancer: %v", err)
+	}
+	
+	guideline := &types.SegmentGuideline{ID: "AC-1", Title: "Policy", Recommendations: []string{"Use MFA"}}
+	result, err := enhancer.EnhanceGuideline(context.Background(), guideline)
+	if err != nil {
+		t.Fatalf("EnhanceGuideline failed: %v", err)
+	}
+	
+	enhanced := result.EnhancedData.(*types.SegmentGuideline)
+	if enhanced == guideline || enhanced.Title != "Access Control Policy" || enhanced.Objective != "Limit access to authorized users" {
+		t.Errorf("Unexpected enhanced guideline: %+v", enhanced)
+	}
+	if len(enhanced.Recommendations) != 2 || enhanced.Recommendations[1] != "Review access yearly" {
+		t.Errorf("Expected the new recommendation appended, got %v", enhanced.Recommendations)
+	}
+	if guideline.Title != "Policy" || guideline.Objective != "" || len(guideline.Recommendations) != 1 {
+		t.Errorf("Original guideline was modified: %+v", guideline)
+	}
+	
+	want := []types.EnhancementChange{
+		{Path: "guideline.AC-1.title", Type: "modify", OldValue: "Policy", NewValue: "Access Control Policy"},
+		{Path: "guideline.AC-1.objective", Type: "add", NewValue: "Limit access to authorized users"},
+		{Path: "guideline.AC-1.recommendations", Type: "add", NewValue: "Review access yearly"},
+	}
+	if len(result.Changes) != len(want) {
+		t.Fatalf("Expected %d changes, got %+v", len(want), result.Changes)
+	}
+	for i, change := range result.Changes {
+		if change.Path != want[i].Path || change.Type != want[i].Type || change.OldValue != want[i].OldValue || change.NewValue != want[i].NewValue || change.Confidence != 0.7 {
+			t.Errorf("Change %d: got %+v, want %+v", i, change, want[i])
+		}
+	}
+	
+	if dropped, err := FilterChanges(result, 0.9); err != nil || dropped != 3 || result.EnhancedData.(*types.SegmentGuideline).Title != "Policy" {
+		t.Errorf("Expected filtering to rebuild the unchanged guideline, dropped %d (%v)", dropped, err)
+	}
+}
+
+func TestEnhanceGuidelineMalformedResponse(t *testing.T) {
+	guideline := &types.SegmentGuideline{ID: "AC-1", Title: "Policy"}
+	result := &types.EnhancementResult{OriginalData: guideline}
+	applyGuidelineReview(result, guideline, "I improved the guideline.")
+	
+	if result.Confidence != malformedResponseConfidence || len(result.Changes) != 0 {
+		t.Errorf("Expected no changes at low confidence, got %v and %+v", result.Confidence, result.Changes)
+	}
+	if enhanced := result.EnhancedData.(*types.SegmentGuideline); enhanced.Title != "Policy" {
+		t.Errorf("Expected an unchanged guideline, got %+v", enhanced)
+	}
+}
+
+func TestResponseConfidence(t *testing.T) {
+	tests := []struct {
+		text string
+		want float64
+	}{
+		{`{"confidence": 0.6}`, 0.6},
+		{"```json\n{\"confidence\": 0.9, \"issues\": []}\n```", 0.9},
+		{`{"confidence": 7}`, 1},
+		{`{"issues": []}`, 0.5},
+		{"No JSON here", 0.5},
+	}
+	for _, tt := range tests {
+		if got := responseConfidence(tt.text, 0.5); got != tt.want {
+			t.Errorf("responseConfidence(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
 func TestEnhancementResultStructure(t *testing.T) {
 	result := &types.EnhancementResult{
 		OriginalData: "original",
@@ -258,6 +547,66 @@ func TestEnhancementResultStructure(t *testing.T) {
 	}
 }
 
+func TestFilterChanges(t *testing.T) {
+	result := &types.EnhancementResult{
+		Changes: []types.EnhancementChange{
+			{Path: "categories[0].title", Confidence: 0.9},
+			{Path: "categories[1].title", Confidence: 0.4},
+			{Path: "categories[2].title", Confidence: 0.7},
+		},
+	}
+	
+	if dropped, err := FilterChanges(result, 0.7); err != nil || dropped != 1 {
+		t.Errorf("Expected 1 change dropped, got %d (%v)", dropped, err)
+	}
+	if len(result.Changes) != 2 || result.Changes[0].Path != "categories[0].title" || result.Changes[1].Path != "categories[2].title" {
+		t.Errorf("Expected the 0.9 and 0.7 changes to be kept, got %+v", result.Changes)
+	}
+	
+	if dropped, _ := FilterChanges(result, 0); dropped != 0 {
+		t.Errorf("Expected no changes dropped at threshold 0, got %d", dropped)
+	}
+	
+	// Dropping a segmentation change rebuilds the enhanced document without it
+	doc := &types.SegmentedDocument{Categories: []types.SegmentCategory{{ID: "1", Title: "Access"}}}
+	changes := []types.EnhancementChange{
+		{Path: "categories[0].title", Type: "modify", NewValue: "Access Control", Confidence: 0.9},
+		{Path: "categories[0].description", Type: "modify", NewValue: "Guess", Confidence: 0.3},
+	}
+	enhanced, err := ApplyChanges(doc, changes)
+	if err != nil {
+		t.Fatalf("ApplyChanges failed: %v", err)
+	}
+	result = &types.EnhancementResult{OriginalData: doc, EnhancedData: enhanced, Changes: changes}
+	if dropped, err := FilterChanges(result, 0.5); err != nil || dropped != 1 {
+		t.Errorf("Expected 1 change dropped, got %d (%v)", dropped, err)
+	}
+	category := result.EnhancedData.(*types.SegmentedDocument).Categories[0]
+	if category.Title != "Access Control" || category.Description != "" {
+		t.Errorf("Expected only the kept change applied, got %+v", category)
+	}
+	
+	// A kept change that depends on a dropped one fails the filter, which
+	// keeps the original rather than the unfiltered document
+	doc.Categories[0].Guidelines = []types.SegmentGuideline{{ID: "1.1", Title: "Passwords"}}
+	changes = []types.EnhancementChange{
+		{Path: "categories[0].guidelines", Type: "add", NewValue: `{"id": "1.2", "title": "Accounts"}`, Confidence: 0.3},
+		{Path: "categories[0].guidelines[1].title", Type: "modify", NewValue: "User Accounts", Confidence: 0.9},
+		{Path: "categories[0].title", Type: "modify", NewValue: "Access Control", Confidence: 0.9},
+	}
+	if enhanced, err = ApplyChanges(doc, changes); err != nil {
+		t.Fatalf("ApplyChanges failed: %v", err)
+	}
+	result = &types.EnhancementResult{OriginalData: doc, EnhancedData: enhanced, Changes: changes}
+	if _, err := FilterChanges(result, 0.5); err == nil {
+		t.Error("Expected an error when a kept change no longer applies")
+	}
+	unchanged := result.EnhancedData.(*types.SegmentedDocument)
+	if unchanged == doc || len(unchanged.Categories[0].Guidelines) != 1 || unchanged.Categories[0].Title != "Access" || len(result.Changes) != 0 {
+		t.Errorf("Expected a copy of the original with no changes, got %+v and %+v", unchanged.Categories[0], result.Changes)
+	}
+}
+
 func TestDefaultPrompts(t *testing.T) {
 	if DefaultPrompts.MetadataValidation == "" {
 		t.Error("MetadataValidation prompt is empty")
@@ -282,3 +631,309 @@ func contains(s, substr string) bool {
 }
 
 
+
+func TestParseSegmentationReview(t *testing.T) {
+	response := "```json\n" + `{
+		"confidence": 0.8,
+		"issues": ["Guideline 1.1 has no title"],
+		"suggestions": [
+			{"path": "categories[0].guidelines[0].title", "type": "modify", "new_value": "Passwords", "reason": "Missing title"},
+			{"path": "categories[0].guidelines[0].recommendations", "type": "add", "new_value": "Rotate keys", "confidence": 0.6},
+			{"path": "categories[1]", "type": "rename", "new_value": "x"},
+			{"type": "remove"}
+		]
+	}` + "\n```"
+	
+	changes, confidence, ok := parseSegmentationReview(response)
+	if !ok || confidence != 0.8 {
+		t.Fatalf("Expected a review with confidence 0.8, got %v (ok %v)", confidence, ok)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %+v", changes)
+	}
+	if changes[0].NewValue != "Passwords" || changes[0].Confidence != 0.8 || changes[0].Reason != "Missing title" {
+		t.Errorf("Unexpected first change: %+v", changes[0])
+	}
+	if changes[1].Type != "add" || changes[1].Confidence != 0.6 {
+		t.Errorf("Unexpected second change: %+v", changes[1])
+	}
+	
+	for _, malformed := range []string{"No issues found.", `{"confidence": "high"}`, `{"issues": []}`} {
+		if _, _, ok := parseSegmentationReview(malformed); ok {
+			t.Errorf("Expected %q to be rejected", malformed)
+		}
+	}
+}
+
+func TestApplyChanges(t *testing.T) {
+	doc := &types.SegmentedDocument{
+		Categories: []types.SegmentCategory{{
+			ID:         "1",
+			Title:      "Access",
+			Guidelines: []types.SegmentGuideline{{ID: "1.1", Title: "Passwords"}, {ID: "1.2", Title: "Sessions"}},
+		}},
+	}
+	
+	// An empty objective is omitted from the encoding, but modify can still
+	// fill it; a field the guideline can't have is an error
+	if _, err := ApplyChanges(doc, []types.EnhancementChange{
+		{Path: "categories[0].guidelines[1].objectiv", Type: "modify", NewValue: "Limit session length"},
+	}); err == nil {
+		t.Error("Expected an error modifying a field the guideline doesn't have")
+	}
+	
+	enhanced, err := ApplyChanges(doc, []types.EnhancementChange{
+		{Path: "categories[0].guidelines[1].objective", Type: "modify", NewValue: "Limit session length"},
+		{Path: "categories[0].title", Type: "modify", NewValue: "Access Control"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyChanges failed: %v", err)
+	}
+	if enhanced == doc || enhanced.Categories[0].Title != "Access Control" || enhanced.Categories[0].Guidelines[1].Objective != "Limit session length" {
+		t.Errorf("Expected the changes applied to a copy, got %+v", enhanced.Categories[0])
+	}
+	if doc.Categories[0].Title != "Access" || doc.Categories[0].Guidelines[1].Objective != "" {
+		t.Errorf("Expected the original document to be unchanged, got %+v", doc.Categories[0])
+	}
+	
+	if _, err := ApplyChanges(doc, []types.EnhancementChange{{Path: "categories[5].title", Type: "modify", NewValue: "Missing"}}); err == nil {
+		t.Error("Expected an error for a path that doesn't resolve")
+	}
+}
+
+func TestApplyChangesSkipsInvalid(t *testing.T) {
+	doc := &types.SegmentedDocument{
+		Categories: []types.SegmentCategory{{
+			ID:    "1",
+			Title: "Access",
+			Guidelines: []types.SegmentGuideline{
+				{ID: "1.1", Title: "", Recommendations: []string{"Use MFA"}},
+				{ID: "1.2", Title: "Sessions"},
+			},
+		}},
+	}
+	
+	enhanced, applied, err := applyChanges(doc, []types.EnhancementChange{
+		{Path: "categories[0].guidelines[0].title", Type: "modify", NewValue: "Passwords"},
+		{Path: "$.categories[0].guidelines[0].recommendations", Type: "add", NewValue: "Rotate keys"},
+		{Path: "categories[0].guidelines[1]", Type: "remove"},
+		{Path: "categories[0].guidelines", Type: "add", NewValue: `{"id": "1.3", "title": "Logging"}`},
+		{Path: "categories[0].title", Type: "modify", NewValue: `["not", "a", "title"]`},
+		{Path: "categories[3].title", Type: "modify", NewValue: "Missing"},
+		{Path: "categories[0].owner", Type: "add", NewValue: "Unknown field"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyChanges failed: %v", err)
+	}
+	
+	if len(applied) != 5 {
+		t.Fatalf("Expected 5 changes applied, got %+v", applied)
+	}
+	if applied[2].OldValue == "" || applied[4].OldValue != "Access" {
+		t.Errorf("Expected old values to be recorded, got %+v", applied)
+	}
+	category := enhanced.Categories[0]
+	if category.Title != `["not", "a", "title"]` {
+		t.Errorf("Expected a list value to fall back to text for a string field, got %q", category.Title)
+	}
+	if len(category.Guidelines) != 2 || category.Guidelines[0].Title != "Passwords" || category.Guidelines[1].ID != "1.3" {
+		t.Errorf("Unexpected guidelines: %+v", category.Guidelines)
+	}
+	if len(category.Guidelines[0].Recommendations) != 2 {
+		t.Errorf("Expected an added recommendation, got %v", category.Guidelines[0].Recommendations)
+	}
+	
+	// The original document is untouched
+	if doc.Categories[0].Title != "Access" || doc.Categories[0].Guidelines[0].Title != "" || len(doc.Categories[0].Guidelines) != 2 {
+		t.Errorf("Expected the original document to be unchanged, got %+v", doc.Categories[0])
+	}
+	enhanced.Categories[0].Guidelines[0].Recommendations[0] = "Changed"
+	if doc.Categories[0].Guidelines[0].Recommendations[0] != "Use MFA" {
+		t.Error("Expected the enhanced document not to share memory with the original")
+	}
+}
+
+func TestEnhanceSegmentationMalformedResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "The segmentation looks fine."}}]}`))
+	}))
+	defer server.Close()
+	
+	enhancer, err := NewOpenAIEnhancer(types.LLMConfig{Provider: "openai", APIKey: "test-key", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create OpenAI enhancer: %v", err)
+	}
+	
+	doc := &types.SegmentedDocument{DocumentMetadata: types.DocumentMetadata{Title: "Audit Doc"}}
+	result, err := enhancer.EnhanceSegmentation(context.Background(), doc)
+	if err != nil {
+		t.Fatalf("EnhanceSegmentation failed: %v", err)
+	}
+	if result.Confidence != malformedResponseConfidence || len(result.Changes) != 0 {
+		t.Errorf("Expected a low-confidence result with no changes, got %v with %+v", result.Confidence, result.Changes)
+	}
+	if enhanced, ok := result.EnhancedData.(*types.SegmentedDocument); !ok || enhanced == doc || enhanced.DocumentMetadata.Title != "Audit Doc" {
+		t.Errorf("Expected an unchanged copy of the document, got %+v", result.EnhancedData)
+	}
+}
+
+func TestProviderRetries(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error": {"message": "overloaded"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "{\"confidence\": 0.9}"}}]}`))
+	}))
+	defer server.Close()
+	
+	enhancer, err := NewOpenAIEnhancer(types.LLMConfig{
+		Provider: "openai",
+		APIKey:   "test-key",
+		Endpoint: server.URL,
+		Options:  map[string]string{"retry_base_ms": "1"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create OpenAI enhancer: %v", err)
+	}
+	
+	result, err := enhancer.EnhanceSegmentation(context.Background(), &types.SegmentedDocument{})
+	if err != nil {
+		t.Fatalf("Expected success after retries, got %v", err)
+	}
+	if calls != 3 || result.Confidence != 0.9 {
+		t.Errorf("Expected 3 calls and the final response, got %d calls and confidence %v", calls, result.Confidence)
+	}
+	
+	// Running out of retries reports the last error
+	calls = 0
+	enhancer.retry.maxRetries = 1
+	if _, err := enhancer.EnhanceSegmentation(context.Background(), &types.SegmentedDocument{}); err == nil || !contains(err.Error(), "overloaded") || calls != 2 {
+		t.Errorf("Expected failure after 2 attempts, got %v (%d calls)", err, calls)
+	}
+}
+
+func TestProviderNoRetryOnClientError(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"type": "error", "error": {"type": "authentication_error", "message": "invalid x-api-key"}}`))
+	}))
+	defer server.Close()
+	
+	enhancer, err := NewAnthropicEnhancer(types.LLMConfig{
+		Provider: "anthropic",
+		APIKey:   "bad-key",
+		Endpoint: server.URL,
+		Options:  map[string]string{"retry_base_ms": "1"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create Anthropic enhancer: %v", err)
+	}
+	
+	_, err = enhancer.EnhanceSegmentation(context.Background(), &types.SegmentedDocument{})
+	if err == nil || !contains(err.Error(), "invalid x-api-key") || !contains(err.Error(), "401") {
+		t.Errorf("Expected the authentication error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single attempt, got %d", calls)
+	}
+}
+
+func TestProviderRetryCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	
+	enhancer, err := NewOpenAIEnhancer(types.LLMConfig{Provider: "openai", APIKey: "test-key", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create OpenAI enhancer: %v", err)
+	}
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = enhancer.EnhanceSegmentation(ctx, &types.SegmentedDocument{})
+	if err == nil || !contains(err.Error(), "429") {
+		t.Errorf("Expected the rate limit error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected cancellation to stop waiting out Retry-After, took %v", elapsed)
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	policy, err := parseRetryPolicy(map[string]string{"max_retries": "5", "retry_base_ms": "100"})
+	if err != nil || policy.maxRetries != 5 || policy.base != 100*time.Millisecond {
+		t.Errorf("Unexpected policy %+v (err %v)", policy, err)
+	}
+	if policy, _ := parseRetryPolicy(nil); policy.maxRetries != defaultMaxRetries || policy.base != defaultRetryBase {
+		t.Errorf("Expected defaults, got %+v", policy)
+	}
+	for _, options := range []map[string]string{{"max_retries": "-1"}, {"retry_base_ms": "soon"}} {
+		if _, err := NewOpenAIEnhancer(types.LLMConfig{Provider: "openai", Options: options}); err == nil {
+			t.Errorf("Expected %v to be rejected", options)
+		}
+	}
+	
+	for n := 0; n < 4; n++ {
+		want := policy.base << n
+		if delay := policy.backoff(n); delay < want/2 || delay > want {
+			t.Errorf("backoff(%d) = %v, want between %v and %v", n, delay, want/2, want)
+		}
+	}
+	if delay := policy.backoff(40); delay > maxRetryDelay {
+		t.Errorf("Expected backoff capped at %v, got %v", maxRetryDelay, delay)
+	}
+	
+	if wait, ok := retryAfter("2"); !ok || wait != 2*time.Second {
+		t.Errorf("Expected a 2s Retry-After, got %v", wait)
+	}
+	if wait, ok := retryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)); !ok || wait < 59*time.Minute {
+		t.Errorf("Expected an HTTP-date Retry-After about an hour out, got %v", wait)
+	}
+	if _, ok := retryAfter("soon"); ok {
+		t.Error("Expected an invalid Retry-After to be ignored")
+	}
+}
+
+func TestCheckRetention(t *testing.T) {
+	original := &types.SegmentedDocument{Categories: []types.SegmentCategory{
+		{ID: "1", Guidelines: []types.SegmentGuideline{{ID: "1.1"}, {ID: "1.2"}}},
+		{ID: "2", Guidelines: []types.SegmentGuideline{{ID: "2.1"}}},
+	}}
+	
+	enhanced, err := ApplyChanges(original, []types.EnhancementChange{
+		{Path: "categories[0].guidelines[0].title", Type: "modify", NewValue: "Passwords"},
+		{Path: "categories[1].guidelines", Type: "add", NewValue: `{"id": "2.2", "title": "Logging"}`},
+	})
+	if err != nil {
+		t.Fatalf("ApplyChanges failed: %v", err)
+	}
+	if err := CheckRetention(original, enhanced); err != nil {
+		t.Errorf("Expected edits and additions to pass, got %v", err)
+	}
+	
+	enhanced, err = ApplyChanges(original, []types.EnhancementChange{
+		{Path: "categories[0].guidelines[1]", Type: "remove"},
+		{Path: "categories[1]", Type: "remove"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyChanges failed: %v", err)
+	}
+	err = CheckRetention(original, enhanced)
+	if !errors.Is(err, ErrContentRemoved) {
+		t.Fatalf("Expected ErrContentRemoved, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "categories 2; guidelines 1.2, 2.1") {
+		t.Errorf("Expected the missing IDs in the error, got %q", err)
+	}
+}
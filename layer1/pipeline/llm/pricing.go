@@ -0,0 +1,61 @@
+package llm
+
+import (
+	"strings"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// ModelPrice is a model's price in USD per 1,000 tokens
+type ModelPrice struct {
+	PromptPer1K     float64
+	CompletionPer1K float64
+}
+
+// ModelPrices holds list prices used for cost estimates, keyed by model.
+// A dated model name ("gpt-4o-2024-08-06") uses the price of its base name.
+// Prices change; add or override entries to match your agreement.
+var ModelPrices = map[string]ModelPrice{
+	"gpt-4":                      {PromptPer1K: 0.03, CompletionPer1K: 0.06},
+	"gpt-4-turbo":                {PromptPer1K: 0.01, CompletionPer1K: 0.03},
+	"gpt-4o":                     {PromptPer1K: 0.0025, CompletionPer1K: 0.01},
+	"gpt-4o-mini":                {PromptPer1K: 0.00015, CompletionPer1K: 0.0006},
+	"gpt-3.5-turbo":              {PromptPer1K: 0.0005, CompletionPer1K: 0.0015},
+	"claude-3-opus-20240229":     {PromptPer1K: 0.015, CompletionPer1K: 0.075},
+	"claude-3-sonnet-20240229":   {PromptPer1K: 0.003, CompletionPer1K: 0.015},
+	"claude-3-5-sonnet-20240620": {PromptPer1K: 0.003, CompletionPer1K: 0.015},
+	"claude-3-haiku-20240307":    {PromptPer1K: 0.00025, CompletionPer1K: 0.00125},
+}
+
+// EstimateCost estimates the USD cost of a call from its token usage. It
+// reports false when the provider didn't return usage or the model has no
+// price, rather than guessing.
+func EstimateCost(model string, usage *types.TokenUsage) (float64, bool) {
+	if usage == nil {
+		return 0, false
+	}
+	price, ok := modelPrice(model)
+	if !ok {
+		return 0, false
+	}
+	return float64(usage.PromptTokens)/1000*price.PromptPer1K +
+		float64(usage.CompletionTokens)/1000*price.CompletionPer1K, true
+}
+
+// modelPrice looks up a model's price, falling back to the longest priced
+// name it extends ("gpt-4-turbo" for "gpt-4-turbo-2024-04-09")
+func modelPrice(model string) (ModelPrice, bool) {
+	if price, ok := ModelPrices[model]; ok {
+		return price, true
+	}
+	var best string
+	for name := range ModelPrices {
+		if strings.HasPrefix(model, name+"-") && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return ModelPrice{}, false
+	}
+	return ModelPrices[best], true
+}
@@ -5,12 +5,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
 	"github.com/ossf/gemara/layer1/pipeline/types"
 )
 
+// systemPrompt sets the reviewer role for every provider
+const systemPrompt = "You are an expert in compliance frameworks and security standards. Your task is to analyze and improve structured data extracted from PDF documents."
+
 // OpenAIEnhancer uses OpenAI API for enhancement
 type OpenAIEnhancer struct {
 	EnhancerBase
@@ -56,6 +60,14 @@ type OpenAIRequest struct {
 	Messages    []OpenAIMessage `json:"messages"`
 	Temperature float64         `json:"temperature"`
 	MaxTokens   int             `json:"max_tokens"`
+	
+	// ResponseFormat constrains the response to a JSON object in JSON mode
+	ResponseFormat *OpenAIResponseFormat `json:"response_format,omitempty"`
+}
+
+// OpenAIResponseFormat selects the response format, e.g. "json_object"
+type OpenAIResponseFormat struct {
+	Type string `json:"type"`
 }
 
 // OpenAIMessage represents a chat message
@@ -69,81 +81,98 @@ type OpenAIResponse struct {
 	Choices []struct {
 		Message OpenAIMessage `json:"message"`
 	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+	} `json:"usage,omitempty"`
 	Error *struct {
 		Message string `json:"message"`
 		Type    string `json:"type"`
 	} `json:"error,omitempty"`
 }
 
+// llmResponse is the text and token usage returned by a provider call
+type llmResponse struct {
+	Text  string
+	Usage *types.TokenUsage
+}
+
+// record copies the raw exchange onto an enhancement result for auditing,
+// estimating its cost from the result's model
+func (r *llmResponse) record(result *types.EnhancementResult, prompt string) {
+	result.Prompt = prompt
+	result.RawResponse = r.Text
+	result.Usage = r.Usage
+	result.CostEstimate, _ = EstimateCost(result.Model, r.Usage)
+}
+
 // callOpenAI makes a request to the OpenAI API
-func (e *OpenAIEnhancer) callOpenAI(ctx context.Context, prompt string) (string, error) {
+func (e *OpenAIEnhancer) callOpenAI(ctx context.Context, prompt string) (*llmResponse, error) {
 	req := OpenAIRequest{
 		Model: e.config.Model,
 		Messages: []OpenAIMessage{
-			{Role: "system", Content: "You are an expert in compliance frameworks and security standards. Your task is to analyze and improve structured data extracted from PDF documents."},
+			{Role: "system", Content: systemPrompt},
 			{Role: "user", Content: prompt},
 		},
 		Temperature: e.config.Temperature,
 		MaxTokens:   e.config.MaxTokens,
 	}
+	if e.useJSONMode(openAISupportsJSONMode(e.config.Model)) {
+		req.ResponseFormat = &OpenAIResponseFormat{Type: "json_object"}
+	}
 	
 	jsonData, err := json.Marshal(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 	
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", e.config.Endpoint, bytes.NewReader(jsonData))
+	resp, err := doWithRetry(ctx, e.client, e.retry, "OpenAI", func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", e.config.Endpoint, bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Authorization", "Bearer "+e.config.APIKey)
+		return httpReq, nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
+	defer func() { _ = resp.Body.Close() }()
 	
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+e.config.APIKey)
-	
-	resp, err := e.client.Do(httpReq)
-	if err != nil {
-		return "", fmt.Errorf("API request failed: %w", err)
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return nil, fmt.Errorf("OpenAI API error (HTTP %d): %s", resp.StatusCode, apiErrorMessage(body))
 	}
-	defer func() { _ = resp.Body.Close() }()
 	
 	var openAIResp OpenAIResponse
 	if err := json.NewDecoder(resp.Body).Decode(&openAIResp); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 	
 	if openAIResp.Error != nil {
-		return "", fmt.Errorf("OpenAI API error: %s", openAIResp.Error.Message)
+		return nil, fmt.Errorf("OpenAI API error: %s", openAIResp.Error.Message)
 	}
 	
 	if len(openAIResp.Choices) == 0 {
-		return "", fmt.Errorf("no response from OpenAI")
+		return nil, fmt.Errorf("no response from OpenAI")
 	}
 	
-	return openAIResp.Choices[0].Message.Content, nil
+	response := &llmResponse{Text: openAIResp.Choices[0].Message.Content}
+	if openAIResp.Usage != nil {
+		response.Usage = &types.TokenUsage{
+			PromptTokens:     openAIResp.Usage.PromptTokens,
+			CompletionTokens: openAIResp.Usage.CompletionTokens,
+			TotalTokens:      openAIResp.Usage.TotalTokens,
+		}
+	}
+	return response, nil
 }
 
 // EnhanceSegmentation improves segmentation results
 func (e *OpenAIEnhancer) EnhanceSegmentation(ctx context.Context, doc *types.SegmentedDocument) (*types.EnhancementResult, error) {
-	// Build prompt
-	prompt := fmt.Sprintf(`Review this document segmentation and suggest improvements:
-
-Document: %s
-Categories: %d
-Total Guidelines: %d
-
-Please analyze:
-1. Are all categories properly identified?
-2. Are guidelines correctly nested?
-3. Should any guidelines be merged or split?
-4. Are IDs formatted consistently?
-
-Respond with JSON containing:
-- confidence: 0-1 score
-- issues: list of identified problems
-- suggestions: list of improvements`,
-		doc.DocumentMetadata.Title,
-		len(doc.Categories),
-		e.countGuidelines(doc))
+	prompt := segmentationReviewPrompt(doc)
 	
 	response, err := e.callOpenAI(ctx, prompt)
 	if err != nil {
@@ -153,44 +182,22 @@ Respond with JSON containing:
 	// Parse response and create enhancement result
 	result := &types.EnhancementResult{
 		OriginalData: doc,
-		EnhancedData: doc,
-		Changes:      []types.EnhancementChange{},
-		Confidence:   0.8,
 		Provider:     e.Name(),
 		Model:        e.config.Model,
 		Timestamp:    time.Now(),
 	}
+	response.record(result, prompt)
 	
-	// TODO: Parse JSON response and extract actual changes
-	// For now, just return the response as a change
-	result.Changes = append(result.Changes, types.EnhancementChange{
-		Path:       "segmentation",
-		Type:       "modify",
-		NewValue:   response,
-		Reason:     "LLM analysis",
-		Confidence: 0.8,
-	})
+	if err := applyReview(result, doc, response.Text); err != nil {
+		return nil, err
+	}
 	
 	return result, nil
 }
 
 // ValidateMetadata validates and enriches metadata
 func (e *OpenAIEnhancer) ValidateMetadata(ctx context.Context, meta *types.DocumentMetadata) (*types.EnhancementResult, error) {
-	prompt := fmt.Sprintf(`Review and improve this document metadata:
-
-Title: %s
-Author: %s
-Version: %s
-Description: %s
-
-Tasks:
-1. Validate accuracy
-2. Improve description if generic
-3. Suggest document type (Standard/Regulation/Framework/Best Practice)
-4. Identify applicable jurisdictions and industry sectors
-
-Respond with JSON containing validated and enhanced metadata.`,
-		meta.Title, meta.Author, meta.Version, meta.Description)
+	prompt := metadataReviewPrompt(meta)
 	
 	response, err := e.callOpenAI(ctx, prompt)
 	if err != nil {
@@ -206,11 +213,12 @@ Respond with JSON containing validated and enhanced metadata.`,
 		Model:        e.config.Model,
 		Timestamp:    time.Now(),
 	}
+	response.record(result, prompt)
 	
 	result.Changes = append(result.Changes, types.EnhancementChange{
 		Path:       "metadata",
 		Type:       "modify",
-		NewValue:   response,
+		NewValue:   response.Text,
 		Reason:     "LLM validation",
 		Confidence: 0.85,
 	})
@@ -220,20 +228,7 @@ Respond with JSON containing validated and enhanced metadata.`,
 
 // EnhanceGuideline improves individual guideline quality
 func (e *OpenAIEnhancer) EnhanceGuideline(ctx context.Context, guideline *types.SegmentGuideline) (*types.EnhancementResult, error) {
-	prompt := fmt.Sprintf(`Improve this guideline:
-
-ID: %s
-Title: %s
-Objective: %s
-
-Tasks:
-1. Extract clear objective if missing
-2. Identify key recommendations
-3. Suggest if should be split into parts
-4. Improve title clarity
-
-Respond with enhanced guideline structure.`,
-		guideline.ID, guideline.Title, guideline.Objective)
+	prompt := guidelineReviewPrompt(guideline)
 	
 	response, err := e.callOpenAI(ctx, prompt)
 	if err != nil {
@@ -242,27 +237,108 @@ Respond with enhanced guideline structure.`,
 	
 	result := &types.EnhancementResult{
 		OriginalData: guideline,
-		EnhancedData: guideline,
-		Changes:      []types.EnhancementChange{},
-		Confidence:   0.8,
 		Provider:     e.Name(),
 		Model:        e.config.Model,
 		Timestamp:    time.Now(),
 	}
-	
-	result.Changes = append(result.Changes, types.EnhancementChange{
-		Path:       "guideline." + guideline.ID,
-		Type:       "modify",
-		NewValue:   response,
-		Reason:     "LLM enhancement",
-		Confidence: 0.8,
-	})
+	response.record(result, prompt)
+	applyGuidelineReview(result, guideline, response.Text)
 	
 	return result, nil
 }
 
+// segmentationReviewPrompt builds the prompt asking an LLM to review a
+// document's segmentation
+func segmentationReviewPrompt(doc *types.SegmentedDocument) string {
+	return fmt.Sprintf(`Review this document segmentation and suggest improvements:
+
+Document: %s
+Categories: %d
+Total Guidelines: %d
+
+Please analyze:
+1. Are all categories properly identified?
+2. Are guidelines correctly nested?
+3. Should any guidelines be merged or split?
+4. Are IDs formatted consistently?
+
+Respond with JSON containing:
+- confidence: 0-1 score
+- issues: list of identified problems
+- suggestions: list of improvements, each an object with:
+  - path: field to change, using the JSON field names of the segmentation
+    (e.g. "categories[0].guidelines[1].title")
+  - type: "add", "modify", or "remove"
+  - new_value: the value to set (omit for "remove")
+  - reason: why the change is needed
+  - confidence: 0-1 score for this change`,
+		doc.DocumentMetadata.Title,
+		len(doc.Categories),
+		countGuidelines(doc))
+}
+
+// metadataReviewPrompt builds the prompt asking an LLM to validate and
+// enrich document metadata
+func metadataReviewPrompt(meta *types.DocumentMetadata) string {
+	return fmt.Sprintf(`Review and improve this document metadata:
+
+Title: %s
+Author: %s
+Version: %s
+Description: %s
+
+Tasks:
+1. Validate accuracy
+2. Improve description if generic
+3. Suggest document type (Standard/Regulation/Framework/Best Practice)
+4. Identify applicable jurisdictions and industry sectors
+
+Respond with JSON containing validated and enhanced metadata.`,
+		meta.Title, meta.Author, meta.Version, meta.Description)
+}
+
+// guidelineReviewPrompt builds the prompt asking an LLM to improve a
+// guideline
+func guidelineReviewPrompt(guideline *types.SegmentGuideline) string {
+	return fmt.Sprintf(`Improve this guideline:
+
+ID: %s
+Title: %s
+Objective: %s
+
+Tasks:
+1. Extract clear objective if missing
+2. Identify key recommendations
+3. Suggest if should be split into parts
+4. Improve title clarity
+
+Respond with JSON containing:
+- confidence: 0-1 score
+- title: the improved title
+- objective: the guideline's objective, extracted from its text if missing
+- recommendations: list of key recommendations, one sentence each`,
+		guideline.ID, guideline.Title, guideline.Objective)
+}
+
+// responseConfidence reads the "confidence" score from a JSON object in an
+// LLM response, which may be wrapped in prose or a code fence, returning
+// fallback when there is none
+func responseConfidence(text string, fallback float64) float64 {
+	object, ok := extractJSONObject(text)
+	if !ok {
+		return fallback
+	}
+	var scored struct {
+		Confidence *float64 `json:"confidence"`
+	}
+	if err := json.Unmarshal([]byte(object), &scored); err != nil || scored.Confidence == nil {
+		return fallback
+	}
+	return min(max(*scored.Confidence, 0), 1)
+}
+
 // countGuidelines counts total guidelines in document
-func (e *OpenAIEnhancer) countGuidelines(doc *types.SegmentedDocument) int {
+func countGuidelines(doc *types.SegmentedDocument) int {
 	count := 0
 	for _, cat := range doc.Categories {
 		count += len(cat.Guidelines)
@@ -312,9 +388,28 @@ func (e *AnthropicEnhancer) Name() string {
 // AnthropicRequest represents an Anthropic API request
 type AnthropicRequest struct {
 	Model       string              `json:"model"`
+	System      string              `json:"system,omitempty"`
 	Messages    []AnthropicMessage  `json:"messages"`
 	MaxTokens   int                 `json:"max_tokens"`
 	Temperature float64             `json:"temperature"`
+	
+	// Tools and ToolChoice force a call to the response tool in JSON mode
+	Tools      []AnthropicTool      `json:"tools,omitempty"`
+	ToolChoice *AnthropicToolChoice `json:"tool_choice,omitempty"`
+}
+
+// AnthropicTool describes a tool the model can call
+type AnthropicTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+// AnthropicToolChoice selects how the model uses tools; type "tool" forces
+// a call to the named tool
+type AnthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
 }
 
 // AnthropicMessage represents a message
@@ -326,8 +421,14 @@ type AnthropicMessage struct {
 // AnthropicResponse represents an Anthropic API response
 type AnthropicResponse struct {
 	Content []struct {
-		Text string `json:"text"`
+		Type  string          `json:"type"`
+		Text  string          `json:"text"`
+		Input json.RawMessage `json:"input,omitempty"` // Tool call arguments, for "tool_use" blocks
 	} `json:"content"`
+	Usage *struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage,omitempty"`
 	Error *struct {
 		Message string `json:"message"`
 		Type    string `json:"type"`
@@ -335,66 +436,150 @@ type AnthropicResponse struct {
 }
 
 // callAnthropic makes a request to the Anthropic API
-//
-//nolint:unused // Reserved for future Anthropic integration
-func (e *AnthropicEnhancer) callAnthropic(ctx context.Context, prompt string) (string, error) {
+func (e *AnthropicEnhancer) callAnthropic(ctx context.Context, prompt string) (*llmResponse, error) {
 	req := AnthropicRequest{
-		Model: e.config.Model,
+		Model:  e.config.Model,
+		System: systemPrompt,
 		Messages: []AnthropicMessage{
 			{Role: "user", Content: prompt},
 		},
 		MaxTokens:   e.config.MaxTokens,
 		Temperature: e.config.Temperature,
 	}
+	if e.useJSONMode(anthropicSupportsJSONMode(e.config.Model)) {
+		req.Tools = []AnthropicTool{anthropicResponseTool()}
+		req.ToolChoice = &AnthropicToolChoice{Type: "tool", Name: anthropicToolName}
+	}
 	
 	jsonData, err := json.Marshal(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
-	}
-	
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", e.config.Endpoint, bytes.NewReader(jsonData))
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	
+	resp, err := doWithRetry(ctx, e.client, e.retry, "anthropic", func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", e.config.Endpoint, bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("x-api-key", e.config.APIKey)
+		httpReq.Header.Set("anthropic-version", "2023-06-01")
+		return httpReq, nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
+	defer func() { _ = resp.Body.Close() }()
 	
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("x-api-key", e.config.APIKey)
-	httpReq.Header.Set("anthropic-version", "2023-06-01")
-	
-	resp, err := e.client.Do(httpReq)
-	if err != nil {
-		return "", fmt.Errorf("API request failed: %w", err)
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return nil, fmt.Errorf("anthropic API error (HTTP %d): %s", resp.StatusCode, apiErrorMessage(body))
 	}
-	defer func() { _ = resp.Body.Close() }()
 	
 	var anthropicResp AnthropicResponse
 	if err := json.NewDecoder(resp.Body).Decode(&anthropicResp); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 	
 	if anthropicResp.Error != nil {
-		return "", fmt.Errorf("anthropic API error: %s", anthropicResp.Error.Message)
+		return nil, fmt.Errorf("anthropic API error: %s", anthropicResp.Error.Message)
 	}
 	
 	if len(anthropicResp.Content) == 0 {
-		return "", fmt.Errorf("no response from Anthropic")
+		return nil, fmt.Errorf("no response from Anthropic")
 	}
 	
-	return anthropicResp.Content[0].Text, nil
+	// In JSON mode the response is the response tool's input
+	response := &llmResponse{Text: anthropicResp.Content[0].Text}
+	for _, block := range anthropicResp.Content {
+		if block.Type == "tool_use" && block.Input != nil {
+			response.Text = string(block.Input)
+			break
+		}
+	}
+	if anthropicResp.Usage != nil {
+		response.Usage = &types.TokenUsage{
+			PromptTokens:     anthropicResp.Usage.InputTokens,
+			CompletionTokens: anthropicResp.Usage.OutputTokens,
+			TotalTokens:      anthropicResp.Usage.InputTokens + anthropicResp.Usage.OutputTokens,
+		}
+	}
+	return response, nil
 }
 
-// EnhanceSegmentation, ValidateMetadata, and EnhanceGuideline follow similar patterns to OpenAI
+// EnhanceSegmentation improves segmentation results
 func (e *AnthropicEnhancer) EnhanceSegmentation(ctx context.Context, doc *types.SegmentedDocument) (*types.EnhancementResult, error) {
-	// Similar implementation using callAnthropic instead of callOpenAI
-	return nil, fmt.Errorf("not implemented yet")
+	prompt := segmentationReviewPrompt(doc)
+	
+	response, err := e.callAnthropic(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	
+	result := &types.EnhancementResult{
+		OriginalData: doc,
+		Provider:     e.Name(),
+		Model:        e.config.Model,
+		Timestamp:    time.Now(),
+	}
+	response.record(result, prompt)
+	
+	if err := applyReview(result, doc, response.Text); err != nil {
+		return nil, err
+	}
+	
+	return result, nil
 }
 
+// ValidateMetadata validates and enriches metadata
 func (e *AnthropicEnhancer) ValidateMetadata(ctx context.Context, meta *types.DocumentMetadata) (*types.EnhancementResult, error) {
-	return nil, fmt.Errorf("not implemented yet")
+	prompt := metadataReviewPrompt(meta)
+	
+	response, err := e.callAnthropic(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	
+	confidence := responseConfidence(response.Text, 0.85)
+	result := &types.EnhancementResult{
+		OriginalData: meta,
+		EnhancedData: meta,
+		Changes:      []types.EnhancementChange{},
+		Confidence:   confidence,
+		Provider:     e.Name(),
+		Model:        e.config.Model,
+		Timestamp:    time.Now(),
+	}
+	response.record(result, prompt)
+	
+	result.Changes = append(result.Changes, types.EnhancementChange{
+		Path:       "metadata",
+		Type:       "modify",
+		NewValue:   response.Text,
+		Reason:     "LLM validation",
+		Confidence: confidence,
+	})
+	
+	return result, nil
 }
 
+// EnhanceGuideline improves individual guideline quality
 func (e *AnthropicEnhancer) EnhanceGuideline(ctx context.Context, guideline *types.SegmentGuideline) (*types.EnhancementResult, error) {
-	return nil, fmt.Errorf("not implemented yet")
+	prompt := guidelineReviewPrompt(guideline)
+	
+	response, err := e.callAnthropic(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	
+	result := &types.EnhancementResult{
+		OriginalData: guideline,
+		Provider:     e.Name(),
+		Model:        e.config.Model,
+		Timestamp:    time.Now(),
+	}
+	response.record(result, prompt)
+	applyGuidelineReview(result, guideline, response.Text)
+	
+	return result, nil
 }
-
-
@@ -0,0 +1,53 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// ErrContentRemoved is returned by CheckRetention when an enhanced document
+// is missing categories or guidelines the original had
+var ErrContentRemoved = errors.New("enhancement removed content")
+
+// CheckRetention verifies that an enhanced document keeps every category
+// and guideline of the original, matched by ID, so content an LLM drops
+// (or renames out of recognition) is caught before the enhanced version
+// replaces the original. Added content and edits to kept elements are
+// allowed. The error wraps ErrContentRemoved and names the missing IDs.
+func CheckRetention(original, enhanced *types.SegmentedDocument) error {
+	categories := map[string]bool{}
+	guidelines := map[string]bool{}
+	for _, category := range enhanced.Categories {
+		categories[category.ID] = true
+		for _, guideline := range category.Guidelines {
+			guidelines[guideline.ID] = true
+		}
+	}
+
+	var missingCategories, missingGuidelines []string
+	for _, category := range original.Categories {
+		if !categories[category.ID] {
+			missingCategories = append(missingCategories, category.ID)
+		}
+		for _, guideline := range category.Guidelines {
+			if !guidelines[guideline.ID] {
+				missingGuidelines = append(missingGuidelines, guideline.ID)
+			}
+		}
+	}
+
+	var missing []string
+	if len(missingCategories) > 0 {
+		missing = append(missing, fmt.Sprintf("categories %s", strings.Join(missingCategories, ", ")))
+	}
+	if len(missingGuidelines) > 0 {
+		missing = append(missing, fmt.Sprintf("guidelines %s", strings.Join(missingGuidelines, ", ")))
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrContentRemoved, strings.Join(missing, "; "))
+}
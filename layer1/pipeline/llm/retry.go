@@ -0,0 +1,146 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Retry defaults, overridden by LLMConfig.Options["max_retries"] and
+// Options["retry_base_ms"]
+const (
+	defaultMaxRetries = 3
+	defaultRetryBase  = 500 * time.Millisecond
+
+	// maxRetryDelay caps the backoff between attempts; a longer Retry-After
+	// from the provider is still honored
+	maxRetryDelay = 30 * time.Second
+)
+
+// retryPolicy controls how failed provider calls are retried
+type retryPolicy struct {
+	maxRetries int           // Retries after the first attempt
+	base       time.Duration // Backoff before the first retry, doubled for each one after
+}
+
+// parseRetryPolicy reads the retry settings from LLMConfig.Options
+func parseRetryPolicy(options map[string]string) (retryPolicy, error) {
+	policy := retryPolicy{maxRetries: defaultMaxRetries, base: defaultRetryBase}
+	if value, ok := options["max_retries"]; ok {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return policy, fmt.Errorf("invalid max_retries %q: must be a non-negative integer", value)
+		}
+		policy.maxRetries = n
+	}
+	if value, ok := options["retry_base_ms"]; ok {
+		ms, err := strconv.Atoi(value)
+		if err != nil || ms < 0 {
+			return policy, fmt.Errorf("invalid retry_base_ms %q: must be a non-negative integer", value)
+		}
+		policy.base = time.Duration(ms) * time.Millisecond
+	}
+	return policy, nil
+}
+
+// backoff returns the delay before retry n (0 for the first retry): the
+// base doubled n times, capped at maxRetryDelay, with the upper half
+// jittered so concurrent clients don't retry in lockstep
+func (p retryPolicy) backoff(n int) time.Duration {
+	delay := p.base
+	for i := 0; i < n && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxRetryDelay)
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// retryableStatus reports whether a response status is worth retrying:
+// timeouts, rate limits and server errors. Other errors, such as a bad
+// request or failed authentication, won't succeed on a retry.
+func retryableStatus(status int) bool {
+	return status == http.StatusRequestTimeout || status == http.StatusTooManyRequests || status >= 500
+}
+
+// retryAfter reads a Retry-After header given in seconds or as an HTTP date
+func retryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(strings.TrimSpace(header)); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+// apiErrorMessage reads the message of a provider's JSON error body
+// ({"error": {"message": ...}}), falling back to the raw body
+func apiErrorMessage(body []byte) string {
+	var parsed struct {
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &parsed); err == nil && parsed.Error != nil && parsed.Error.Message != "" {
+		return parsed.Error.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// doWithRetry sends a request built by newRequest, retrying transport
+// errors and retryable statuses with exponential backoff until the retries
+// run out or ctx is done. A Retry-After header sets the wait before the
+// next attempt. Any other response, including non-retryable errors, is
+// returned to the caller to decode.
+func doWithRetry(ctx context.Context, client *http.Client, policy retryPolicy, provider string, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		var wait time.Duration
+		resp, err := client.Do(req)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("API request failed: %w", ctx.Err())
+			}
+			err = fmt.Errorf("API request failed: %w", err)
+		case retryableStatus(resp.StatusCode):
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+			_ = resp.Body.Close()
+			err = fmt.Errorf("%s API error (HTTP %d): %s", provider, resp.StatusCode, apiErrorMessage(body))
+			wait, _ = retryAfter(resp.Header.Get("Retry-After"))
+		default:
+			return resp, nil
+		}
+
+		if attempt >= policy.maxRetries {
+			if attempt > 0 {
+				return nil, fmt.Errorf("%w (after %d attempts)", err, attempt+1)
+			}
+			return nil, err
+		}
+		if wait == 0 {
+			wait = policy.backoff(attempt)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w (retry canceled: %v)", err, ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
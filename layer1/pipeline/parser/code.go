@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// minCodeIndent is how much deeper than the page's text margin a line must
+// be indented to be read as part of a code example
+const minCodeIndent = 4
+
+var (
+	// Matches a shell prompt or comment opening a line ("$ sudo ...", "# chmod ...")
+	codePromptRegex = regexp.MustCompile(`^[$#]\s+\S`)
+
+	// Matches lines that read as code or configuration rather than prose:
+	// assignments ("PermitRootLogin = no"), command-line flags, absolute
+	// paths, markup tags, and braces opening or closing a line
+	codeLineRegex = regexp.MustCompile(`^[$#]\s+\S|^[\w.\[\]-]+\s*=\s*\S|^[\w.-]+(\s+\S+)*\s+--?[A-Za-z]|(^|\s)/[\w.-]+/|^</?[A-Za-z][^>]*>|[{}]\s*$|^}`)
+)
+
+// pageMargins returns, for each line, the indentation of the least indented
+// non-empty line on its page, so indentation can be measured against the
+// margin layout-mode text is shifted by
+func pageMargins(lines []string) []int {
+	margins := make([]int, len(lines))
+	start := 0
+	flush := func(end int) {
+		margin := -1
+		for _, line := range lines[start:end] {
+			if line = strings.TrimLeft(line, "\f"); strings.TrimSpace(line) == "" {
+				continue
+			}
+			if indent := lineIndent(line); margin < 0 || indent < margin {
+				margin = indent
+			}
+		}
+		for k := start; k < end; k++ {
+			margins[k] = max(margin, 0)
+		}
+		start = end
+	}
+	for i, line := range lines {
+		if strings.Contains(line, "\f") {
+			flush(i)
+		}
+	}
+	flush(len(lines))
+	return margins
+}
+
+// collectCode reads a code example from the start of lines: consecutive
+// non-empty lines indented at least minCodeIndent past margin, up to the
+// last that looks like code, at least half of which look like code. A lone
+// line counts only when it opens with a shell prompt. It returns nil when
+// there is no example, along with the number of lines consumed; the block
+// keeps the lines' relative indentation.
+func collectCode(lines []string, margin int) (*types.Block, int) {
+	var code []string
+	codeLike, end := 0, 0
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if line == "" || strings.Contains(line, "\f") || lineIndent(line) < margin+minCodeIndent {
+			break
+		}
+		code = append(code, line)
+		if codeLineRegex.MatchString(strings.TrimSpace(line)) {
+			codeLike++
+			end = len(code)
+		}
+	}
+
+	// Indented prose running on after the example isn't part of it
+	code = code[:end]
+	n := len(code)
+	if n == 0 || codeLike*2 < n || (n == 1 && !codePromptRegex.MatchString(strings.TrimSpace(code[0]))) {
+		return nil, 0
+	}
+
+	indent := lineIndent(code[0])
+	for _, line := range code[1:] {
+		indent = min(indent, lineIndent(line))
+	}
+	for k, line := range code {
+		code[k] = strings.ReplaceAll(line, "\t", "    ")[indent:]
+	}
+	return &types.Block{Type: types.BlockTypeCode, Text: strings.Join(code, "\n")}, n
+}
+
+// lineIndent returns a line's indentation, counting a tab as four spaces
+func lineIndent(line string) int {
+	expanded := strings.ReplaceAll(line, "\t", "    ")
+	return len(expanded) - len(strings.TrimLeft(expanded, " "))
+}
@@ -0,0 +1,313 @@
+package parser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Column modes, selected via ParserConfig.Options["columns"]. When set, the
+// simple parser reads word positions from pdftotext -bbox-layout and orders
+// blocks column by column instead of using pdftotext_mode. Text files, which
+// carry no positions, are split at the whitespace gutter between columns.
+const (
+	ColumnsAuto = "auto" // Detect one or two columns per page
+	ColumnsOne  = "1"    // Read blocks top to bottom
+	ColumnsTwo  = "2"    // Read the left column, then the right one
+)
+
+// gutterTolerance is how far, as a fraction of the page width, a block may
+// cross the page's center line and still belong to one column
+const gutterTolerance = 0.02
+
+// minColumnBlocks is how many blocks each side needs for auto mode to treat
+// a page as two columns
+const minColumnBlocks = 2
+
+// minGutterWidth is how many spaces a line needs at a candidate gutter for
+// its text to be split there
+const minGutterWidth = 2
+
+// minColumnRows is how many lines need text on both sides of the gutter for
+// auto mode to treat a text page as two columns
+const minColumnRows = 3
+
+// bboxDocument is the XHTML written by pdftotext -bbox-layout
+type bboxDocument struct {
+	Pages []bboxPage `xml:"body>doc>page"`
+}
+
+type bboxPage struct {
+	Width  float64     `xml:"width,attr"`
+	Blocks []bboxBlock `xml:"flow>block"`
+}
+
+type bboxBlock struct {
+	XMin  float64    `xml:"xMin,attr"`
+	YMin  float64    `xml:"yMin,attr"`
+	XMax  float64    `xml:"xMax,attr"`
+	YMax  float64    `xml:"yMax,attr"`
+	Lines []bboxLine `xml:"line"`
+}
+
+type bboxLine struct {
+	Words []string `xml:"word"`
+}
+
+// columnsMode returns the configured column mode, or "" when column-aware
+// extraction is off
+func (p *SimpleParser) columnsMode() string {
+	return p.config.Options["columns"]
+}
+
+// runColumnExtraction extracts text in reading order using word positions,
+// so multi-column pages don't interleave their columns. pdftotext builds
+// without -bbox-layout fall back to splitting layout text at the gutter.
+func (p *SimpleParser) runColumnExtraction(filePath string) (string, error) {
+	output, err := p.runPdftotext(filePath, "bbox-layout")
+	if err != nil {
+		layoutText, layoutErr := p.runPdftotext(filePath, PdftotextModeLayout)
+		if layoutErr != nil {
+			return "", err
+		}
+		return orderLayoutColumns(layoutText, p.columnsMode()), nil
+	}
+	return orderColumnText(output, p.columnsMode())
+}
+
+// orderColumnText converts pdftotext -bbox-layout output to plain text, one
+// paragraph per block and pages separated by form feeds, with blocks in
+// column reading order
+func orderColumnText(bboxXHTML, mode string) (string, error) {
+	var doc bboxDocument
+	if err := xml.Unmarshal([]byte(bboxXHTML), &doc); err != nil {
+		return "", fmt.Errorf("failed to read pdftotext bbox output: %w", err)
+	}
+
+	var sb strings.Builder
+	for i, page := range doc.Pages {
+		if i > 0 {
+			sb.WriteString("\f")
+		}
+		writeBlocks(&sb, orderBlocks(page, mode))
+	}
+	return sb.String(), nil
+}
+
+// writeBlocks writes blocks as text, a line per line and a blank line after
+// each block
+func writeBlocks(sb *strings.Builder, blocks []bboxBlock) {
+	for _, block := range blocks {
+		for _, line := range block.Lines {
+			sb.WriteString(strings.Join(line.Words, " "))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// orderBlocks returns a page's blocks in reading order. On two-column pages
+// blocks spanning the gutter (titles, wide tables) split the page into bands;
+// within each band the left column is read before the right one.
+func orderBlocks(page bboxPage, mode string) []bboxBlock {
+	blocks := append([]bboxBlock(nil), page.Blocks...)
+	sort.SliceStable(blocks, func(i, j int) bool {
+		if blocks[i].YMin != blocks[j].YMin {
+			return blocks[i].YMin < blocks[j].YMin
+		}
+		return blocks[i].XMin < blocks[j].XMin
+	})
+
+	if mode == ColumnsOne || page.Width <= 0 {
+		return blocks
+	}
+	if mode == ColumnsAuto && !isTwoColumn(page) {
+		return blocks
+	}
+
+	var ordered, left, right []bboxBlock
+	flush := func() {
+		ordered = append(ordered, left...)
+		ordered = append(ordered, right...)
+		left, right = nil, nil
+	}
+	for _, block := range blocks {
+		switch columnOf(block, page.Width) {
+		case 0:
+			left = append(left, block)
+		case 1:
+			right = append(right, block)
+		default:
+			flush()
+			ordered = append(ordered, block)
+		}
+	}
+	flush()
+	return ordered
+}
+
+// isTwoColumn reports whether enough blocks sit wholly on each side of the
+// page's center line for the page to be laid out in two columns
+func isTwoColumn(page bboxPage) bool {
+	var left, right int
+	for _, block := range page.Blocks {
+		switch columnOf(block, page.Width) {
+		case 0:
+			left++
+		case 1:
+			right++
+		}
+	}
+	return left >= minColumnBlocks && right >= minColumnBlocks
+}
+
+// columnOf returns 0 for a block in the left column, 1 for the right column
+// and -1 for a block spanning both
+func columnOf(block bboxBlock, pageWidth float64) int {
+	center := pageWidth / 2
+	tolerance := pageWidth * gutterTolerance
+	switch {
+	case block.XMax <= center+tolerance:
+		return 0
+	case block.XMin >= center-tolerance:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// orderLayoutColumns reorders layout-mode text (pages separated by form
+// feeds) into column reading order, using the horizontal position of the
+// whitespace gap running down each page as the gutter
+func orderLayoutColumns(text, mode string) string {
+	if mode == "" || mode == ColumnsOne {
+		return text
+	}
+	pages := strings.Split(text, "\f")
+	for i, page := range pages {
+		pages[i] = orderPageColumns(page, mode)
+	}
+	return strings.Join(pages, "\f")
+}
+
+// orderPageColumns splits a page's lines at its gutter and orders them
+// like pdftotext -bbox-layout blocks, see layoutPage
+func orderPageColumns(page, mode string) string {
+	lines := strings.Split(page, "\n")
+	gutter := findGutter(lines, mode)
+	if gutter < 0 {
+		return page
+	}
+	var sb strings.Builder
+	writeBlocks(&sb, orderBlocks(layoutPage(lines, gutter), ColumnsTwo))
+	return sb.String()
+}
+
+// layoutPage lays a page of layout text out as blocks, in character columns
+// and line numbers, with the gutter at the page's center. Lines on the same
+// side of the gutter form a block until a blank line; a side left empty on a
+// line doesn't end its block. A line crossing the gutter is a block of its
+// own, spanning the page.
+func layoutPage(lines []string, gutter int) bboxPage {
+	page := bboxPage{Width: float64(2 * gutter)}
+	var open [2]*bboxBlock
+	closeBlocks := func() {
+		for side, block := range open {
+			if block != nil {
+				page.Blocks = append(page.Blocks, *block)
+				open[side] = nil
+			}
+		}
+	}
+	add := func(side, y int, text string) {
+		if text == "" {
+			return
+		}
+		if open[side] == nil {
+			open[side] = &bboxBlock{XMin: float64(side * gutter), XMax: float64((side + 1) * gutter), YMin: float64(y)}
+		}
+		open[side].YMax = float64(y)
+		open[side].Lines = append(open[side].Lines, bboxLine{Words: []string{text}})
+	}
+
+	for y, line := range lines {
+		runes := []rune(strings.ReplaceAll(line, "\t", "    "))
+		switch {
+		case strings.TrimSpace(line) == "":
+			closeBlocks()
+		case len(runes) <= gutter:
+			add(0, y, strings.TrimRight(string(runes), " "))
+		case isGap(runes, gutter):
+			add(0, y, strings.TrimRight(string(runes[:gutter]), " "))
+			add(1, y, strings.TrimSpace(string(runes[gutter:])))
+		default:
+			closeBlocks()
+			page.Blocks = append(page.Blocks, bboxBlock{
+				XMax:  page.Width,
+				YMin:  float64(y),
+				YMax:  float64(y),
+				Lines: []bboxLine{{Words: []string{line}}},
+			})
+		}
+	}
+	closeBlocks()
+	return page
+}
+
+// findGutter returns the character column of the gap between two columns of
+// text, or -1 if the page isn't laid out in columns. Candidates lie in the
+// middle of the page; the one with the most lines having text on both sides
+// of a gap wins, and in auto mode it must also be crossed by few lines.
+func findGutter(lines []string, mode string) int {
+	rows := make([][]rune, len(lines))
+	width := 0
+	for i, line := range lines {
+		rows[i] = []rune(strings.ReplaceAll(line, "\t", "    "))
+		width = max(width, len(rows[i]))
+	}
+
+	best, bestSplit, bestCross := -1, 0, 0
+	for x := width * 3 / 10; x < width*7/10; x++ {
+		split, cross := 0, 0
+		for _, row := range rows {
+			if len(row) <= x {
+				continue
+			}
+			if !isGap(row, x) {
+				cross++
+				continue
+			}
+			if strings.TrimSpace(string(row[:x])) != "" && strings.TrimSpace(string(row[x:])) != "" {
+				split++
+			}
+		}
+		if split > bestSplit || (split == bestSplit && cross < bestCross) {
+			best, bestSplit, bestCross = x, split, cross
+		}
+	}
+
+	if best < 0 || bestSplit == 0 {
+		return -1
+	}
+	if mode == ColumnsAuto && (bestSplit < minColumnRows || bestCross*2 > bestSplit) {
+		return -1
+	}
+	return best
+}
+
+// isGap reports whether row has a run of at least minGutterWidth spaces
+// covering column x
+func isGap(row []rune, x int) bool {
+	if row[x] != ' ' {
+		return false
+	}
+	start, end := x, x
+	for start > 0 && row[start-1] == ' ' {
+		start--
+	}
+	for end < len(row) && row[end] == ' ' {
+		end++
+	}
+	return end-start >= minGutterWidth
+}
@@ -1,11 +1,15 @@
 package parser
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"math"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"time"
 
 	"github.com/ossf/gemara/layer1/pipeline/types"
@@ -68,6 +72,7 @@ type DoclingTextItem struct {
 	Level      int           `json:"level,omitempty"`
 	Marker     string        `json:"marker,omitempty"`
 	Enumerated bool          `json:"enumerated,omitempty"`
+	Hyperlink  string        `json:"hyperlink,omitempty"`
 }
 
 // DoclingProv contains provenance info (page/bbox)
@@ -89,6 +94,7 @@ type DoclingTable struct {
 	SelfRef string        `json:"self_ref"`
 	Label   string        `json:"label"`
 	Prov    []DoclingProv `json:"prov"`
+	Rows    [][]string    `json:"rows,omitempty"` // Cell text, row by row
 }
 
 // DoclingPageInfo contains page dimensions
@@ -111,12 +117,37 @@ func (p *DoclingParser) Parse(filePath string) (*types.ParsedDocument, error) {
 		return nil, fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
-	// Run the Python script
+	if err := p.checkInputSize(absPath); err != nil {
+		return nil, err
+	}
+
+	// Run the Python script, capping how much of its output is buffered
 	cmd := exec.Command("python3", p.scriptPath, absPath)
-	output, err := cmd.Output()
+	if password := p.pdfPassword(); password != "" {
+		// Passed through the environment to keep it out of the process list
+		cmd.Env = append(os.Environ(), pdfPasswordEnv+"="+password)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("docling conversion failed: %s", string(exitErr.Stderr))
+		return nil, fmt.Errorf("failed to run docling: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run docling: %w", err)
+	}
+	output, readErr := p.readLimited(stdout, "docling output")
+	if readErr != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil, fmt.Errorf("failed to read docling output: %w", readErr)
+	}
+	if err := cmd.Wait(); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			if pwErr := passwordError(filePath, stderr.String()+string(output)); pwErr != nil {
+				return nil, pwErr
+			}
+			return nil, fmt.Errorf("docling conversion failed: %s", stderr.String())
 		}
 		return nil, fmt.Errorf("failed to run docling: %w", err)
 	}
@@ -132,6 +163,9 @@ func (p *DoclingParser) Parse(filePath string) (*types.ParsedDocument, error) {
 		for _, e := range resp.Errors {
 			errMsgs += e.ErrorMessage + "; "
 		}
+		if pwErr := passwordError(filePath, errMsgs); pwErr != nil {
+			return nil, pwErr
+		}
 		return nil, fmt.Errorf("docling conversion failed: %s", errMsgs)
 	}
 
@@ -166,12 +200,15 @@ func (p *DoclingParser) convertDocument(filePath string, docling *DoclingDocumen
 		pageBlocks[pageNo] = append(pageBlocks[pageNo], block)
 	}
 
-	// Convert tables
-	for _, table := range docling.Tables {
+	// Convert tables, joining those that continue across a page break
+	for _, table := range mergeContinuedTables(docling.Tables) {
 		block := types.Block{
 			Type: types.BlockTypeTable,
 			Text: "[Table]",
 		}
+		if len(table.Rows) > 0 {
+			block.TableData = &types.TableData{Rows: table.Rows}
+		}
 
 		pageNo := 1
 		if len(table.Prov) > 0 {
@@ -210,9 +247,62 @@ func (p *DoclingParser) convertDocument(filePath string, docling *DoclingDocumen
 		doc.Pages = append(doc.Pages, page)
 	}
 
+	p.normalizeListMarkers(doc.Pages)
 	return doc
 }
 
+// tableEdgeTolerance is how far, as a fraction of the table's width, the
+// left and right edges of a continuation may drift from the table it continues
+const tableEdgeTolerance = 0.05
+
+// mergeContinuedTables joins tables that docling split at a page break. A
+// table continues the previous one when it starts on the next page, its left
+// and right edges line up with it, and, when both carry cell data, it has the
+// same number of columns. The merged table keeps the first part's position;
+// a repeated header row on the continuation is dropped.
+func mergeContinuedTables(tables []DoclingTable) []DoclingTable {
+	var merged []DoclingTable
+	for _, table := range tables {
+		if n := len(merged); n > 0 && continuesTable(merged[n-1], table) {
+			prev := &merged[n-1]
+			rows := table.Rows
+			if len(prev.Rows) > 0 && len(rows) > 0 && slices.Equal(prev.Rows[0], rows[0]) {
+				rows = rows[1:]
+			}
+			prev.Rows = append(prev.Rows, rows...)
+			prev.Prov = append(prev.Prov, table.Prov...)
+			continue
+		}
+		table.Rows = append([][]string(nil), table.Rows...)
+		table.Prov = append([]DoclingProv(nil), table.Prov...)
+		merged = append(merged, table)
+	}
+	return merged
+}
+
+// continuesTable reports whether next looks like the continuation of prev on
+// the following page
+func continuesTable(prev, next DoclingTable) bool {
+	if len(prev.Prov) == 0 || len(next.Prov) == 0 {
+		return false
+	}
+	last := prev.Prov[len(prev.Prov)-1]
+	first := next.Prov[0]
+	if first.PageNo != last.PageNo+1 {
+		return false
+	}
+
+	tolerance := (last.BBox.R - last.BBox.L) * tableEdgeTolerance
+	if math.Abs(first.BBox.L-last.BBox.L) > tolerance || math.Abs(first.BBox.R-last.BBox.R) > tolerance {
+		return false
+	}
+
+	if len(prev.Rows) > 0 && len(next.Rows) > 0 {
+		return len(prev.Rows[0]) == len(next.Rows[0])
+	}
+	return true
+}
+
 // convertTextItem converts a DoclingTextItem to a Block
 func (p *DoclingParser) convertTextItem(item *DoclingTextItem) types.Block {
 	block := types.Block{
@@ -251,6 +341,11 @@ func (p *DoclingParser) convertTextItem(item *DoclingTextItem) types.Block {
 		block.Type = types.BlockTypeParagraph
 	}
 
+	// Keep hyperlink annotations so the converter can preserve them
+	if item.Hyperlink != "" {
+		block.Links = []types.Link{{Text: item.Text, URL: item.Hyperlink}}
+	}
+
 	// Add bounding box if available
 	if len(item.Prov) > 0 {
 		prov := item.Prov[0]
@@ -6,16 +6,52 @@ Outputs JSON to stdout.
 """
 
 import json
+import os
 import sys
 from pathlib import Path
 
+# Set by the Go parser from ParserConfig.Options["pdf_password"]
+PASSWORD_ENV = "GEMARA_PDF_PASSWORD"
 
-def convert_pdf(input_path: str) -> dict:
-    """Convert a PDF file using docling and return structured data."""
+
+def new_converter(password: str):
+    """Create a DocumentConverter, opening encrypted PDFs with password."""
     from docling.document_converter import DocumentConverter
 
-    converter = DocumentConverter()
-    result = converter.convert(input_path)
+    if not password:
+        return DocumentConverter()
+
+    try:
+        from docling.datamodel.backend_options import PdfBackendOptions
+        from docling.datamodel.base_models import InputFormat
+        from docling.document_converter import PdfFormatOption
+        from pydantic import SecretStr
+    except ImportError as e:
+        raise RuntimeError(f"installed docling does not support PDF passwords: {e}")
+
+    options = PdfFormatOption(backend_options=PdfBackendOptions(password=SecretStr(password)))
+    return DocumentConverter(format_options={InputFormat.PDF: options})
+
+
+def table_rows(table) -> list:
+    """Return a table's cell text row by row, or [] when docling has no grid."""
+    data = getattr(table, "data", None)
+    grid = getattr(data, "grid", None) if data is not None else None
+    if not grid:
+        return []
+    return [[getattr(cell, "text", "") for cell in row] for row in grid]
+
+
+def convert_pdf(input_path: str) -> dict:
+    """Convert a PDF file using docling and return structured data."""
+    converter = new_converter(os.environ.get(PASSWORD_ENV, ""))
+    try:
+        result = converter.convert(input_path)
+    except Exception as e:
+        # Normalize the backend's wording so the Go side can detect it
+        if "password" in str(e).lower():
+            raise RuntimeError(f"Incorrect password: {e}")
+        raise
     doc = result.document
 
     # Build output structure matching what the Go parser expects
@@ -62,6 +98,10 @@ def convert_pdf(input_path: str) -> dict:
         if hasattr(item, "enumerated"):
             text_item["enumerated"] = item.enumerated
 
+        # Add hyperlink annotations (external URLs or internal references)
+        if getattr(item, "hyperlink", None):
+            text_item["hyperlink"] = str(item.hyperlink)
+
         output["document"]["texts"].append(text_item)
 
     # Extract tables
@@ -84,6 +124,10 @@ def convert_pdf(input_path: str) -> dict:
                     }
                 table_item["prov"].append(prov_item)
 
+        rows = table_rows(table)
+        if rows:
+            table_item["rows"] = rows
+
         output["document"]["tables"].append(table_item)
 
     # Extract page info
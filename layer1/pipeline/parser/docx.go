@@ -0,0 +1,552 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// Parts of a DOCX package read by the parser
+const (
+	docxDocumentPart  = "word/document.xml"
+	docxStylesPart    = "word/styles.xml"
+	docxNumberingPart = "word/numbering.xml"
+)
+
+// docxMaxHeadingLevel is the deepest Word heading style mapped to a heading
+const docxMaxHeadingLevel = 6
+
+// DOCXParser reads Word documents directly, mapping paragraph styles to
+// block types instead of recovering structure from a rendered PDF
+type DOCXParser struct {
+	ParserBase
+}
+
+// NewDOCXParser creates a new DOCX parser
+func NewDOCXParser(config types.ParserConfig) (*DOCXParser, error) {
+	parser := &DOCXParser{}
+	if err := parser.Configure(config); err != nil {
+		return nil, err
+	}
+	return parser, nil
+}
+
+// Name returns the parser name
+func (p *DOCXParser) Name() string {
+	return "docx"
+}
+
+// Parse extracts content from a .docx file
+func (p *DOCXParser) Parse(filePath string) (*types.ParsedDocument, error) {
+	if err := p.checkInputSize(filePath); err != nil {
+		return nil, err
+	}
+
+	archive, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open docx: %w", err)
+	}
+	defer archive.Close()
+
+	document, err := p.readPart(&archive.Reader, docxDocumentPart)
+	if err != nil {
+		return nil, err
+	}
+	if document == nil {
+		return nil, fmt.Errorf("failed to open docx: %s not found", docxDocumentPart)
+	}
+
+	// Styles and numbering are optional parts
+	styles := docxStyles{}
+	if data, err := p.readPart(&archive.Reader, docxStylesPart); err != nil {
+		return nil, err
+	} else if data != nil {
+		if styles, err = parseDOCXStyles(data); err != nil {
+			return nil, err
+		}
+	}
+	numbering := &docxNumbering{}
+	if data, err := p.readPart(&archive.Reader, docxNumberingPart); err != nil {
+		return nil, err
+	} else if data != nil {
+		if err := xml.Unmarshal(data, numbering); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", docxNumberingPart, err)
+		}
+	}
+
+	pages, err := convertDOCX(document, styles, numbering)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &types.ParsedDocument{
+		Metadata: types.ParsedMetadata{
+			SourceFile: filePath,
+			Parser:     "docx-v1.0",
+			ParsedAt:   time.Now(),
+		},
+		Pages: pages,
+	}
+	p.normalizeListMarkers(doc.Pages)
+	return doc, nil
+}
+
+// readPart reads a part of the package, returning nil if it doesn't exist.
+// Parts are read through the size limit, so a small archive can't expand
+// into an oversized document.
+func (p *DOCXParser) readPart(archive *zip.Reader, name string) ([]byte, error) {
+	f, err := archive.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer f.Close()
+
+	data, err := p.readLimited(f, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return data, nil
+}
+
+// docxVal is an element whose value is held in its w:val attribute
+type docxVal struct {
+	Val string `xml:"val,attr"`
+}
+
+// docxStyle is a paragraph style from styles.xml
+type docxStyle struct {
+	ID           string   `xml:"styleId,attr"`
+	Name         docxVal  `xml:"name"`
+	BasedOn      docxVal  `xml:"basedOn"`
+	OutlineLevel *docxVal `xml:"pPr>outlineLvl"`
+}
+
+// docxStyles maps style IDs to styles
+type docxStyles map[string]docxStyle
+
+// parseDOCXStyles reads the style definitions from styles.xml
+func parseDOCXStyles(data []byte) (docxStyles, error) {
+	var parsed struct {
+		Styles []docxStyle `xml:"style"`
+	}
+	if err := xml.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", docxStylesPart, err)
+	}
+	styles := make(docxStyles, len(parsed.Styles))
+	for _, style := range parsed.Styles {
+		styles[style.ID] = style
+	}
+	return styles, nil
+}
+
+// blockType maps a paragraph style to a block type, with the heading level
+// for headings. Built-in styles are recognized by name, so localized style
+// IDs ("berschrift1") still map; custom styles fall back to the outline
+// level they or the styles they are based on declare.
+func (s docxStyles) blockType(styleID string) (types.BlockType, int) {
+	for depth := 0; styleID != "" && depth < 10; depth++ {
+		style, ok := s[styleID]
+		name := styleID
+		if ok && style.Name.Val != "" {
+			name = style.Name.Val
+		}
+		name = strings.ToLower(strings.ReplaceAll(name, " ", ""))
+
+		switch {
+		case name == "title":
+			return types.BlockTypeHeading, 1
+		case strings.HasPrefix(name, "heading"):
+			if level, err := strconv.Atoi(strings.TrimPrefix(name, "heading")); err == nil && level >= 1 && level <= docxMaxHeadingLevel {
+				return types.BlockTypeHeading, level
+			}
+		case name == "listparagraph":
+			return types.BlockTypeList, 0
+		case name == "caption":
+			return types.BlockTypeCaption, 0
+		case name == "footnotetext":
+			return types.BlockTypeFootnote, 0
+		}
+
+		if !ok {
+			break
+		}
+		if style.OutlineLevel != nil {
+			if level, err := strconv.Atoi(style.OutlineLevel.Val); err == nil && level < docxMaxHeadingLevel {
+				return types.BlockTypeHeading, level + 1
+			}
+		}
+		styleID = style.BasedOn.Val
+	}
+	return types.BlockTypeParagraph, 0
+}
+
+// docxNumbering holds the list definitions from numbering.xml
+type docxNumbering struct {
+	AbstractNums []docxAbstractNum `xml:"abstractNum"`
+	Nums         []docxNum         `xml:"num"`
+
+	counters map[string][]int // Current count per numId and level
+}
+
+type docxAbstractNum struct {
+	ID     string      `xml:"abstractNumId,attr"`
+	Levels []docxLevel `xml:"lvl"`
+}
+
+type docxNum struct {
+	ID         string  `xml:"numId,attr"`
+	AbstractID docxVal `xml:"abstractNumId"`
+}
+
+type docxLevel struct {
+	Ilvl    int     `xml:"ilvl,attr"`
+	Start   docxVal `xml:"start"`
+	NumFmt  docxVal `xml:"numFmt"`
+	LvlText docxVal `xml:"lvlText"`
+}
+
+// start returns the level's first number (default: 1)
+func (l docxLevel) start() int {
+	if start, err := strconv.Atoi(l.Start.Val); err == nil {
+		return start
+	}
+	return 1
+}
+
+// levels returns the level definitions of a list
+func (n *docxNumbering) levels(numID string) []docxLevel {
+	for _, num := range n.Nums {
+		if num.ID != numID {
+			continue
+		}
+		for _, abstract := range n.AbstractNums {
+			if abstract.ID == num.AbstractID.Val {
+				return abstract.Levels
+			}
+		}
+	}
+	return nil
+}
+
+// next advances a list's counter at ilvl, restarting deeper levels, and
+// returns the rendered marker and whether the list is ordered
+func (n *docxNumbering) next(numID string, ilvl int) (string, bool) {
+	levels := n.levels(numID)
+	level := func(i int) docxLevel {
+		for _, l := range levels {
+			if l.Ilvl == i {
+				return l
+			}
+		}
+		return docxLevel{Ilvl: i, NumFmt: docxVal{Val: "bullet"}}
+	}
+
+	if n.counters == nil {
+		n.counters = make(map[string][]int)
+	}
+	counts := n.counters[numID]
+	for len(counts) <= ilvl {
+		counts = append(counts, level(len(counts)).start()-1)
+	}
+	counts[ilvl]++
+	for i := ilvl + 1; i < len(counts); i++ {
+		counts[i] = level(i).start() - 1
+	}
+	n.counters[numID] = counts
+
+	current := level(ilvl)
+	switch current.NumFmt.Val {
+	case "bullet":
+		return "•", false
+	case "none":
+		return "", true
+	}
+
+	marker := current.LvlText.Val
+	if marker == "" {
+		marker = "%" + strconv.Itoa(ilvl+1) + "."
+	}
+	for i := ilvl; i >= 0; i-- {
+		marker = strings.ReplaceAll(marker, "%"+strconv.Itoa(i+1), formatDOCXNumber(max(counts[i], 1), level(i).NumFmt.Val))
+	}
+	return marker, true
+}
+
+// formatDOCXNumber renders a list counter in a Word number format
+func formatDOCXNumber(n int, format string) string {
+	switch format {
+	case "lowerLetter", "upperLetter":
+		letter := strings.Repeat(string(rune('a'+(n-1)%26)), (n-1)/26+1)
+		if format == "upperLetter" {
+			return strings.ToUpper(letter)
+		}
+		return letter
+	case "lowerRoman":
+		return strings.ToLower(toRoman(n))
+	case "upperRoman":
+		return toRoman(n)
+	default:
+		return strconv.Itoa(n)
+	}
+}
+
+// toRoman renders n as an upper-case roman numeral
+func toRoman(n int) string {
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	numerals := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+	var sb strings.Builder
+	for i, v := range values {
+		for n >= v {
+			sb.WriteString(numerals[i])
+			n -= v
+		}
+	}
+	return sb.String()
+}
+
+// docxParagraph is the style, numbering, and text of a w:p element
+type docxParagraph struct {
+	style       string
+	outline     int // Direct outline level, or -1
+	numID       string
+	ilvl        int
+	text        string
+	breakBefore bool // Starts on a new page
+	pageBreak   bool // Contains an explicit page break
+}
+
+// convertDOCX converts document.xml to pages of blocks. Word documents
+// have no fixed pages, so only explicit page breaks start a new one.
+func convertDOCX(document []byte, styles docxStyles, numbering *docxNumbering) ([]types.Page, error) {
+	pages := []types.Page{{PageNumber: 1, Blocks: []types.Block{}}}
+	add := func(block types.Block) {
+		page := &pages[len(pages)-1]
+		page.Blocks = append(page.Blocks, block)
+	}
+	newPage := func() {
+		pages = append(pages, types.Page{PageNumber: len(pages) + 1, Blocks: []types.Block{}})
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(document))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", docxDocumentPart, err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		// Other elements (body, content controls) are walked into, so
+		// paragraphs and tables nested in them are still found
+		switch start.Name.Local {
+		case "p":
+			para, err := decodeDOCXParagraph(decoder)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", docxDocumentPart, err)
+			}
+			if para.breakBefore {
+				newPage()
+			}
+			if block, ok := para.block(styles, numbering); ok {
+				add(block)
+			}
+			if para.pageBreak {
+				newPage()
+			}
+		case "tbl":
+			rows, err := decodeDOCXTable(decoder)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", docxDocumentPart, err)
+			}
+			if len(rows) > 0 {
+				add(types.Block{
+					Type:      types.BlockTypeTable,
+					Text:      tableBlockText,
+					TableData: &types.TableData{Rows: rows},
+				})
+			}
+		}
+	}
+
+	// Drop pages left empty by trailing or consecutive breaks
+	kept := pages[:0]
+	for _, page := range pages {
+		if len(page.Blocks) > 0 {
+			page.PageNumber = len(kept) + 1
+			kept = append(kept, page)
+		}
+	}
+	return kept, nil
+}
+
+// block converts a paragraph to a block, or reports false if it has no text
+func (para docxParagraph) block(styles docxStyles, numbering *docxNumbering) (types.Block, bool) {
+	text := strings.TrimSpace(para.text)
+	if text == "" {
+		return types.Block{}, false
+	}
+
+	blockType, level := styles.blockType(para.style)
+	if para.outline >= 0 && para.outline < docxMaxHeadingLevel {
+		blockType, level = types.BlockTypeHeading, para.outline+1
+	}
+
+	marker, ordered := "", false
+	if para.numID != "" && para.numID != "0" {
+		marker, ordered = numbering.next(para.numID, para.ilvl)
+		if blockType == types.BlockTypeParagraph {
+			blockType = types.BlockTypeList
+		}
+	}
+
+	block := types.Block{Type: blockType, Text: text}
+	switch blockType {
+	case types.BlockTypeHeading:
+		// Keep automatic numbering so numbered-heading patterns match
+		if ordered && marker != "" {
+			block.Text = marker + " " + text
+		}
+		block.Level = level
+		block.FontWeight = "bold"
+	case types.BlockTypeList:
+		listType := "unordered"
+		if ordered {
+			listType = "ordered"
+		}
+		block.ListItem = &types.ListItem{
+			Level:  para.ilvl + 1,
+			Marker: marker,
+			Type:   listType,
+		}
+	}
+	return block, true
+}
+
+// decodeDOCXParagraph reads a w:p element whose start tag was just read,
+// collecting its properties and the text of its runs
+func decodeDOCXParagraph(decoder *xml.Decoder) (docxParagraph, error) {
+	para := docxParagraph{outline: -1}
+	var text strings.Builder
+	for depth := 1; depth > 0; {
+		token, err := decoder.Token()
+		if err != nil {
+			return para, err
+		}
+		switch t := token.(type) {
+		case xml.EndElement:
+			depth--
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				var s string
+				if err := decoder.DecodeElement(&s, &t); err != nil {
+					return para, err
+				}
+				text.WriteString(s)
+				continue
+			case "del", "delText", "instrText":
+				// Tracked deletions and field codes aren't visible text
+				if err := decoder.Skip(); err != nil {
+					return para, err
+				}
+				continue
+			case "pStyle":
+				para.style = docxAttr(t, "val")
+			case "outlineLvl":
+				if level, err := strconv.Atoi(docxAttr(t, "val")); err == nil {
+					para.outline = level
+				}
+			case "pageBreakBefore":
+				val := docxAttr(t, "val")
+				para.breakBefore = val != "0" && val != "false"
+			case "numId":
+				para.numID = docxAttr(t, "val")
+			case "ilvl":
+				para.ilvl, _ = strconv.Atoi(docxAttr(t, "val"))
+			case "tab":
+				text.WriteString(" ")
+			case "br", "cr":
+				if docxAttr(t, "type") == "page" {
+					para.pageBreak = true
+				} else {
+					text.WriteString(" ")
+				}
+			}
+			depth++
+		}
+	}
+	para.text = strings.Join(strings.Fields(text.String()), " ")
+	return para, nil
+}
+
+// decodeDOCXTable reads a w:tbl element whose start tag was just read into
+// rows of cell text. Paragraphs within a cell are joined with spaces, and
+// nested tables are skipped.
+func decodeDOCXTable(decoder *xml.Decoder) ([][]string, error) {
+	var rows [][]string
+	var row []string
+	var cell []string
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "tr":
+				row = nil
+			case "tc":
+				cell = nil
+			case "p":
+				para, err := decodeDOCXParagraph(decoder)
+				if err != nil {
+					return nil, err
+				}
+				if text := strings.TrimSpace(para.text); text != "" {
+					cell = append(cell, text)
+				}
+			case "tbl":
+				if err := decoder.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "tc":
+				row = append(row, strings.Join(cell, " "))
+			case "tr":
+				rows = append(rows, row)
+			case "tbl":
+				return rows, nil
+			}
+		}
+	}
+}
+
+// docxAttr returns the value of an element's attribute by local name
+func docxAttr(start xml.StartElement, name string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// LoadParsedDocument reads a ParsedDocument written as JSON by an external
+// extraction tool, so its blocks can be segmented without running one of
+// our parsers. Fields that aren't part of ParsedDocument are rejected
+// rather than silently dropped, and the document must pass
+// ParsedDocument.Validate. Missing source file, parser, and parse time
+// metadata are filled in.
+func LoadParsedDocument(path string) (*types.ParsedDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parsed document: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var doc types.ParsedDocument
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid parsed document JSON: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid parsed document JSON: unexpected data after the document")
+	}
+	if err := doc.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid parsed document: %w", err)
+	}
+
+	if doc.Metadata.SourceFile == "" {
+		doc.Metadata.SourceFile = path
+	}
+	if doc.Metadata.Parser == "" {
+		doc.Metadata.Parser = "external"
+	}
+	if doc.Metadata.ParsedAt.IsZero() {
+		doc.Metadata.ParsedAt = time.Now()
+	}
+	return &doc, nil
+}
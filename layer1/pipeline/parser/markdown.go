@@ -0,0 +1,290 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+var (
+	// Matches ATX headings ("## Access Control", "### 1.1 Passwords ###")
+	mdHeadingRegex = regexp.MustCompile(`^ {0,3}(#{1,6})(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+
+	// Matches setext heading underlines ("=====" for level 1, "-----" for level 2)
+	mdSetextRegex = regexp.MustCompile(`^ {0,3}(=+|-+)\s*$`)
+
+	// Matches the opening or closing fence of a code block
+	mdFenceRegex = regexp.MustCompile("^ {0,3}(```+|~~~+)\\s*([^`\\s]*)")
+
+	// Matches list items, capturing indentation, marker, and text
+	mdListRegex = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+
+	// Matches thematic breaks ("---", "***", "___")
+	mdRuleRegex = regexp.MustCompile(`^ {0,3}(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+
+	// Matches the delimiter row of a pipe table ("| --- | :---: |")
+	mdTableDelimiterRegex = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+
+	// Matches inline links and images ("[text](url)", "![alt](src)")
+	mdLinkRegex = regexp.MustCompile(`!?\[([^\]]*)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+
+	// Matches emphasis and inline code markers
+	mdEmphasisRegex = regexp.MustCompile("\\*\\*|__|`")
+
+	// Matches HTML comments on a single line
+	mdCommentRegex = regexp.MustCompile(`<!--.*?-->`)
+)
+
+// MarkdownParser reads Markdown documents such as standards published on
+// the web, keeping their heading structure without a PDF round trip
+type MarkdownParser struct {
+	ParserBase
+}
+
+// NewMarkdownParser creates a new Markdown parser
+func NewMarkdownParser(config types.ParserConfig) (*MarkdownParser, error) {
+	parser := &MarkdownParser{}
+	if err := parser.Configure(config); err != nil {
+		return nil, err
+	}
+	return parser, nil
+}
+
+// Name returns the parser name
+func (p *MarkdownParser) Name() string {
+	return "markdown"
+}
+
+// Parse extracts content from a Markdown file
+func (p *MarkdownParser) Parse(filePath string) (*types.ParsedDocument, error) {
+	if err := p.checkInputSize(filePath); err != nil {
+		return nil, err
+	}
+	content, err := p.readTextFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &types.ParsedDocument{
+		Metadata: types.ParsedMetadata{
+			SourceFile: filePath,
+			Parser:     "markdown-v1.0",
+			ParsedAt:   time.Now(),
+		},
+		Pages: paginateSections(parseMarkdown(string(content))),
+	}
+	p.normalizeListMarkers(doc.Pages)
+	return doc, nil
+}
+
+// parseMarkdown converts Markdown text into blocks
+func parseMarkdown(content string) []types.Block {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	lines = skipFrontMatter(lines)
+
+	var blocks []types.Block
+	var paragraph []string
+	var list *types.Block
+	flush := func() {
+		if list != nil {
+			list.Text, list.Links = mdInline(list.Text)
+			blocks = append(blocks, *list)
+			list = nil
+		}
+		if len(paragraph) > 0 {
+			text, links := mdInline(strings.Join(paragraph, " "))
+			blocks = append(blocks, types.Block{Type: types.BlockTypeParagraph, Text: text, Links: links})
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := mdCommentRegex.ReplaceAllString(lines[i], "")
+
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+
+		// Fenced code runs to the matching fence or the end of the document
+		if matches := mdFenceRegex.FindStringSubmatch(line); matches != nil {
+			flush()
+			fence := matches[1]
+			var code []string
+			for i++; i < len(lines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+					break
+				}
+				code = append(code, lines[i])
+			}
+			blocks = append(blocks, types.Block{Type: types.BlockTypeCode, Text: strings.Join(code, "\n")})
+			continue
+		}
+
+		if matches := mdHeadingRegex.FindStringSubmatch(line); matches != nil {
+			flush()
+			text, links := mdInline(matches[2])
+			blocks = append(blocks, types.Block{
+				Type:       types.BlockTypeHeading,
+				Level:      len(matches[1]),
+				Text:       text,
+				FontWeight: "bold",
+				Links:      links,
+			})
+			continue
+		}
+
+		// A setext underline turns the paragraph above it into a heading
+		if matches := mdSetextRegex.FindStringSubmatch(line); matches != nil && len(paragraph) > 0 && list == nil {
+			text, links := mdInline(strings.Join(paragraph, " "))
+			paragraph = nil
+			level := 1
+			if matches[1][0] == '-' {
+				level = 2
+			}
+			blocks = append(blocks, types.Block{Type: types.BlockTypeHeading, Level: level, Text: text, FontWeight: "bold", Links: links})
+			continue
+		}
+
+		if mdRuleRegex.MatchString(line) {
+			flush()
+			continue
+		}
+
+		if table, n := collectPipeTable(lines[i:]); table != nil {
+			flush()
+			blocks = append(blocks, types.Block{Type: types.BlockTypeTable, Text: tableBlockText, TableData: table})
+			i += n - 1
+			continue
+		}
+
+		if matches := mdListRegex.FindStringSubmatch(line); matches != nil {
+			flush()
+			listType := "unordered"
+			if matches[2][0] >= '0' && matches[2][0] <= '9' {
+				listType = "ordered"
+			}
+			list = &types.Block{
+				Type: types.BlockTypeList,
+				Text: strings.TrimSpace(matches[3]),
+				ListItem: &types.ListItem{
+					Level:  len(strings.ReplaceAll(matches[1], "\t", "    "))/2 + 1,
+					Marker: matches[2],
+					Type:   listType,
+				},
+			}
+			continue
+		}
+
+		// Lazy continuation lines extend the open list item or paragraph
+		text := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), ">"))
+		if list != nil {
+			list.Text += " " + text
+			continue
+		}
+		if text != "" {
+			paragraph = append(paragraph, text)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// skipFrontMatter drops a leading YAML front matter block
+func skipFrontMatter(lines []string) []string {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return lines
+	}
+	for i := 1; i < len(lines); i++ {
+		if t := strings.TrimSpace(lines[i]); t == "---" || t == "..." {
+			return lines[i+1:]
+		}
+	}
+	return lines
+}
+
+// collectPipeTable reads a pipe table (a header row, a delimiter row, and
+// body rows) from the start of lines, returning nil if there isn't one,
+// along with the number of lines consumed
+func collectPipeTable(lines []string) (*types.TableData, int) {
+	if len(lines) < 2 || !strings.Contains(lines[0], "|") || !mdTableDelimiterRegex.MatchString(lines[1]) {
+		return nil, 0
+	}
+
+	table := &types.TableData{Rows: [][]string{pipeCells(lines[0])}}
+	n := 2
+	for ; n < len(lines); n++ {
+		if strings.TrimSpace(lines[n]) == "" || !strings.Contains(lines[n], "|") {
+			break
+		}
+		table.Rows = append(table.Rows, pipeCells(lines[n]))
+	}
+	return table, n
+}
+
+// pipeCells splits a pipe table row into its cell text
+func pipeCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+	cells := strings.Split(line, "|")
+	for i, cell := range cells {
+		cells[i], _ = mdInline(cell)
+	}
+	return cells
+}
+
+// mdInline strips inline markup, returning the plain text and the links it
+// contained; link text is kept in place of the link
+func mdInline(text string) (string, []types.Link) {
+	var links []types.Link
+	text = mdLinkRegex.ReplaceAllStringFunc(text, func(match string) string {
+		parts := mdLinkRegex.FindStringSubmatch(match)
+		if !strings.HasPrefix(match, "!") {
+			links = append(links, types.Link{Text: parts[1], URL: parts[2]})
+		}
+		return parts[1]
+	})
+	text = mdEmphasisRegex.ReplaceAllString(text, "")
+	return strings.Join(strings.Fields(text), " "), links
+}
+
+// paginateSections puts each top-level section on its own page, so the
+// segmenter sees section boundaries as it would page breaks in a PDF. The
+// top level is the shallowest heading level used more than once; anything
+// before the first section, such as the document title, stays with it.
+func paginateSections(blocks []types.Block) []types.Page {
+	counts := make(map[int]int)
+	for _, block := range blocks {
+		if block.Type == types.BlockTypeHeading {
+			counts[block.Level]++
+		}
+	}
+	top := 0
+	for level := 1; level <= 6; level++ {
+		if counts[level] > 1 {
+			top = level
+			break
+		}
+	}
+
+	var pages []types.Page
+	current := types.Page{PageNumber: 1, Blocks: []types.Block{}}
+	inSection := false
+	for _, block := range blocks {
+		if top > 0 && block.Type == types.BlockTypeHeading && block.Level == top {
+			if inSection {
+				pages = append(pages, current)
+				current = types.Page{PageNumber: len(pages) + 1, Blocks: []types.Block{}}
+			}
+			inSection = true
+		}
+		current.Blocks = append(current.Blocks, block)
+	}
+	if len(current.Blocks) > 0 {
+		pages = append(pages, current)
+	}
+	return pages
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// normalizeListMarkers sets ListItem.MarkerKind on every list block unless
+// ParserConfig.Options["normalize_list_markers"] is "false". Markers are
+// classified with types.ClassifyMarker; a single letter that could be roman
+// ("i", "v", "x") takes the kind of the previous marker at the same level
+// when that marker is the letter just before it or a roman numeral.
+func (p *ParserBase) normalizeListMarkers(pages []types.Page) {
+	if p.config.Options["normalize_list_markers"] == "false" {
+		return
+	}
+
+	// Previous marker at each nesting level, reset by non-list blocks
+	previous := make(map[int]string)
+	for i := range pages {
+		for j := range pages[i].Blocks {
+			block := &pages[i].Blocks[j]
+			if block.Type != types.BlockTypeList || block.ListItem == nil {
+				clear(previous)
+				continue
+			}
+
+			item := block.ListItem
+			item.MarkerKind = markerKind(item, previous[item.Level])
+			previous[item.Level] = item.Marker
+		}
+	}
+}
+
+// markerKind classifies a list item's marker given the marker before it at
+// the same level
+func markerKind(item *types.ListItem, previous string) types.MarkerKind {
+	if item.Marker == "" {
+		if item.Type == "unordered" {
+			return types.MarkerKindBullet
+		}
+		return ""
+	}
+
+	kind := types.ClassifyMarker(item.Marker)
+	letter, ok := markerLetter(item.Marker)
+	if !ok || previous == "" {
+		return kind
+	}
+
+	switch letter {
+	case 'i', 'v', 'x', 'I', 'V', 'X':
+		prevKind := types.ClassifyMarker(previous)
+		if prevKind == types.MarkerKindRoman {
+			return types.MarkerKindRoman
+		}
+		if prev, ok := markerLetter(previous); ok && prev+1 == letter {
+			return prevKind
+		}
+	}
+	return kind
+}
+
+// markerLetter returns the letter of a single-letter marker such as "b." or
+// "(C)"
+func markerLetter(marker string) (byte, bool) {
+	var letter byte
+	for i := 0; i < len(marker); i++ {
+		c := marker[i]
+		switch {
+		case c == '(' || c == ')' || c == '[' || c == ']' || c == '.' || c == ':' || c == ' ':
+		case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			if letter != 0 {
+				return 0, false
+			}
+			letter = c
+		default:
+			return 0, false
+		}
+	}
+	return letter, letter != 0
+}
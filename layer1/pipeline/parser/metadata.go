@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// maxMetadataTitleLength bounds the first-page line taken as a title, so a
+// page that opens with body text doesn't yield a paragraph-long title
+const maxMetadataTitleLength = 150
+
+var (
+	// Matches a version on the first page ("Version 3.2.1", "v4.0", "Revision 5")
+	metadataVersionRegex = regexp.MustCompile(`(?i)\b(?:version|revision|rev\.?)\s*:?\s*v?(\d+(?:\.\d+)*)\b|\bv(\d+\.\d+(?:\.\d+)*)\b`)
+
+	// Matches an author line on the first page ("By: Jane Doe", "Author: PCI SSC")
+	metadataAuthorRegex = regexp.MustCompile(`(?i)^(?:by|authors?|prepared by|published by)\s*:?\s+(.+)$`)
+
+	// Matches a publication date on the first page ("May 2018", "April 29, 2016")
+	metadataDateRegex = regexp.MustCompile(`(?i)\b((?:jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.?\s+(?:\d{1,2},?\s+)?\d{4})\b`)
+
+	// Matches document titles generated by authoring tools rather than set
+	// by the author ("Microsoft Word - policy.docx", "untitled")
+	placeholderTitleRegex = regexp.MustCompile(`(?i)^(microsoft \w+ - |untitled)|\.(docx?|pdf|odt|rtf)$`)
+)
+
+// ExtractMetadataOnly reads a PDF's title, author, version, and date
+// without structuring the document. The document information dictionary
+// (via pdfinfo) is preferred; fields it lacks, or whose title is an
+// authoring tool placeholder, are filled from a scan of the first page.
+// Fields that can't be found are left empty.
+func ExtractMetadataOnly(filePath string) (types.DocumentMetadata, error) {
+	meta := types.DocumentMetadata{
+		ID: strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath)),
+	}
+
+	if _, err := exec.LookPath("pdfinfo"); err != nil {
+		return meta, fmt.Errorf("pdfinfo not found (install poppler-utils): %w", err)
+	}
+	info, err := runPopplerTool(filePath, "pdfinfo", "-isodates", filePath)
+	if err != nil {
+		return meta, err
+	}
+	fields := parsePdfinfo(info)
+	meta.Title = fields["Title"]
+	if placeholderTitleRegex.MatchString(meta.Title) {
+		meta.Title = ""
+	}
+	meta.Author = fields["Author"]
+	meta.PublicationDate, _, _ = strings.Cut(fields["CreationDate"], "T")
+
+	if meta.Title != "" && meta.Author != "" && meta.PublicationDate != "" && meta.Version != "" {
+		return meta, nil
+	}
+	if _, err := exec.LookPath("pdftotext"); err != nil {
+		return meta, fmt.Errorf("pdftotext not found (install poppler-utils): %w", err)
+	}
+	firstPage, err := runPopplerTool(filePath, "pdftotext", "-f", "1", "-l", "1", "-layout", filePath, "-")
+	if err != nil {
+		return meta, err
+	}
+	scanFirstPage(firstPage, &meta)
+	return meta, nil
+}
+
+// runPopplerTool runs a poppler command on filePath, returning its output
+func runPopplerTool(filePath, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if pwErr := passwordError(filePath, stderr.String()); pwErr != nil {
+			return "", pwErr
+		}
+		return "", fmt.Errorf("%s failed: %w (stderr: %s)", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// parsePdfinfo parses pdfinfo's "Key:   value" lines
+func parsePdfinfo(output string) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		if value = strings.TrimSpace(value); value != "" {
+			fields[strings.TrimSpace(key)] = value
+		}
+	}
+	return fields
+}
+
+// scanFirstPage fills the metadata fields still empty from the first page's
+// text: the first substantial line is taken as the title, and the version,
+// author, and date from the first lines matching their patterns
+func scanFirstPage(text string, meta *types.DocumentMetadata) {
+	for _, line := range strings.Split(text, "\n") {
+		line = normalizeWhitespace(line)
+		if line == "" || isPageHeaderFooter(line) {
+			continue
+		}
+
+		if meta.Title == "" && len(line) <= maxMetadataTitleLength && strings.ContainsFunc(line, unicode.IsLetter) {
+			meta.Title = line
+			continue
+		}
+		if meta.Version == "" {
+			if matches := metadataVersionRegex.FindStringSubmatch(line); matches != nil {
+				meta.Version = matches[1] + matches[2]
+			}
+		}
+		if meta.Author == "" {
+			if matches := metadataAuthorRegex.FindStringSubmatch(line); matches != nil {
+				meta.Author = strings.TrimSpace(matches[1])
+			}
+		}
+		if meta.PublicationDate == "" {
+			if matches := metadataDateRegex.FindStringSubmatch(line); matches != nil {
+				meta.PublicationDate = matches[1]
+			}
+		}
+	}
+}
@@ -1,7 +1,11 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/ossf/gemara/layer1/pipeline/types"
 )
@@ -25,11 +29,29 @@ func NewParser(config types.ParserConfig) (Parser, error) {
 		return NewDoclingParser(config)
 	case "simple":
 		return NewSimpleParser(config)
+	case "pymupdf":
+		return NewPyMuPDFParser(config)
+	case "docx":
+		return NewDOCXParser(config)
+	case "markdown":
+		return NewMarkdownParser(config)
 	default:
 		return nil, fmt.Errorf("unsupported parser provider: %s", config.Provider)
 	}
 }
 
+// DefaultMaxBytes is the input and extracted-text size limit used when
+// ParserConfig.MaxBytes is zero
+const DefaultMaxBytes int64 = 256 << 20 // 256MB
+
+// ErrDocumentTooLarge is returned when an input file or the text extracted
+// from it exceeds the configured size limit
+var ErrDocumentTooLarge = errors.New("document too large")
+
+// ErrIncorrectPassword is returned when a PDF is encrypted and no password,
+// or the wrong one, was given via ParserConfig.Options["pdf_password"]
+var ErrIncorrectPassword = errors.New("incorrect or missing PDF password")
+
 // ParserBase provides common functionality for all parsers
 type ParserBase struct {
 	config types.ParserConfig
@@ -46,3 +68,83 @@ func (p *ParserBase) GetConfig() types.ParserConfig {
 	return p.config
 }
 
+
+// pdfPasswordEnv carries the PDF password to the Python helper scripts
+const pdfPasswordEnv = "GEMARA_PDF_PASSWORD"
+
+// pdfPassword returns the configured password for encrypted PDFs
+func (p *ParserBase) pdfPassword() string {
+	return p.config.Options["pdf_password"]
+}
+
+// passwordError reports whether a tool's error output means the PDF could
+// not be opened with the given password, returning ErrIncorrectPassword if so
+func passwordError(filePath, output string) error {
+	if !strings.Contains(strings.ToLower(output), "incorrect password") {
+		return nil
+	}
+	return fmt.Errorf("%w: %s (set the pdf_password parser option)", ErrIncorrectPassword, filePath)
+}
+
+// maxBytes returns the configured size limit; a negative MaxBytes disables it
+func (p *ParserBase) maxBytes() int64 {
+	switch {
+	case p.config.MaxBytes == 0:
+		return DefaultMaxBytes
+	case p.config.MaxBytes < 0:
+		return 0
+	default:
+		return p.config.MaxBytes
+	}
+}
+
+// checkInputSize stats the input before parsing so oversized files are
+// rejected without being read
+func (p *ParserBase) checkInputSize(filePath string) error {
+	limit := p.maxBytes()
+	if limit == 0 {
+		return nil
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to stat input: %w", err)
+	}
+	if info.Size() > limit {
+		return fmt.Errorf("%w: %s is %d bytes (limit %d)", ErrDocumentTooLarge, filePath, info.Size(), limit)
+	}
+	return nil
+}
+
+// readLimited reads all of r, failing with ErrDocumentTooLarge instead of
+// buffering more than the configured limit
+func (p *ParserBase) readLimited(r io.Reader, what string) ([]byte, error) {
+	limit := p.maxBytes()
+	if limit == 0 {
+		return io.ReadAll(r)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: %s exceeds %d bytes", ErrDocumentTooLarge, what, limit)
+	}
+	return data, nil
+}
+
+// readTextFile reads a text file within the configured size limit
+func (p *ParserBase) readTextFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read text file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	content, err := p.readLimited(f, "extracted text")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read text file: %w", err)
+	}
+	return content, nil
+}
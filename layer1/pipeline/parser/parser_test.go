@@ -1,8 +1,12 @@
 package parser
 
 import (
+	"archive/zip"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/ossf/gemara/layer1/pipeline/types"
@@ -103,6 +107,9 @@ func TestParserFactory(t *testing.T) {
 	}{
 		{"simple", false},
 		{"docling", false},
+		{"pymupdf", false},
+		{"docx", false},
+		{"markdown", false},
 		{"invalid", true},
 	}
 	
@@ -120,3 +127,1117 @@ func TestParserFactory(t *testing.T) {
 	}
 }
 
+
+func TestSimpleParserPdftotextMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		wantErr bool
+	}{
+		{"", false},
+		{PdftotextModeLayout, false},
+		{PdftotextModeRaw, false},
+		{PdftotextModeAuto, false},
+		{"columns", true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			config := types.ParserConfig{
+				Provider: "simple",
+				Options:  map[string]string{"pdftotext_mode": tt.mode},
+			}
+			
+			_, err := NewSimpleParser(config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewSimpleParser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSimpleParserHeadingPatterns(t *testing.T) {
+	content := `Access Control Policy
+
+Users must be authenticated.
+`
+	
+	// Title-case headings are paragraphs by default
+	parser, err := NewSimpleParser(types.ParserConfig{Provider: "simple"})
+	if err != nil {
+		t.Fatalf("NewSimpleParser() error = %v", err)
+	}
+	blocks := parser.parseTextContent(content)[0].Blocks
+	if blocks[0].Type != types.BlockTypeParagraph {
+		t.Errorf("Expected paragraph without custom patterns, got %+v", blocks[0])
+	}
+	
+	parser, err = NewSimpleParser(types.ParserConfig{
+		Provider: "simple",
+		Options:  map[string]string{"heading_patterns": `^Appendix [A-Z], ^(?:[A-Z][a-z]+ )+Policy$`},
+	})
+	if err != nil {
+		t.Fatalf("NewSimpleParser() error = %v", err)
+	}
+	blocks = parser.parseTextContent(content)[0].Blocks
+	if blocks[0].Type != types.BlockTypeHeading || blocks[0].Text != "Access Control Policy" {
+		t.Errorf("Expected custom heading, got %+v", blocks[0])
+	}
+	// Defaults still apply
+	if !parser.isHeading("1.1 Establish standards") {
+		t.Error("Expected default heading pattern to still match")
+	}
+	
+	_, err = NewSimpleParser(types.ParserConfig{
+		Provider: "simple",
+		Options:  map[string]string{"heading_patterns": "^Section (unclosed"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "heading_patterns") {
+		t.Errorf("Expected invalid pattern error, got %v", err)
+	}
+}
+
+func TestNormalizeListMarkers(t *testing.T) {
+	list := func(marker, listType string) types.Block {
+		return types.Block{Type: types.BlockTypeList, ListItem: &types.ListItem{Level: 1, Marker: marker, Type: listType}}
+	}
+	pages := []types.Page{{Blocks: []types.Block{
+		list("1.", "ordered"),
+		list("h.", "ordered"),
+		list("i.", "ordered"), // Follows "h.": alphabetic
+		{Type: types.BlockTypeParagraph, Text: "Break"},
+		list("i.", "ordered"), // Starts a list: roman
+		list("iv.", "ordered"),
+		list("v.", "ordered"), // Follows a roman numeral
+		list("•", "unordered"),
+		list("", "unordered"),
+	}}}
+	want := []types.MarkerKind{
+		types.MarkerKindDecimal,
+		types.MarkerKindLowerAlpha,
+		types.MarkerKindLowerAlpha,
+		"",
+		types.MarkerKindRoman,
+		types.MarkerKindRoman,
+		types.MarkerKindRoman,
+		types.MarkerKindBullet,
+		types.MarkerKindBullet,
+	}
+	
+	p := &ParserBase{}
+	p.normalizeListMarkers(pages)
+	for i, block := range pages[0].Blocks {
+		if block.ListItem == nil {
+			continue
+		}
+		if block.ListItem.MarkerKind != want[i] {
+			t.Errorf("block %d (%q): MarkerKind = %q, want %q", i, block.ListItem.Marker, block.ListItem.MarkerKind, want[i])
+		}
+	}
+	
+	// Normalization can be turned off
+	off := []types.Page{{Blocks: []types.Block{list("1.", "ordered")}}}
+	p = &ParserBase{config: types.ParserConfig{Options: map[string]string{"normalize_list_markers": "false"}}}
+	p.normalizeListMarkers(off)
+	if kind := off[0].Blocks[0].ListItem.MarkerKind; kind != "" {
+		t.Errorf("Expected no MarkerKind when disabled, got %q", kind)
+	}
+}
+
+func TestSimpleParserMaxBytes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.txt")
+	if err := os.WriteFile(path, []byte(strings.Repeat("Requirement text\n", 100)), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		maxBytes int64
+		wantErr  bool
+	}{
+		{"default limit", 0, false},
+		{"unlimited", -1, false},
+		{"under limit", 4096, false},
+		{"over limit", 64, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewSimpleParser(types.ParserConfig{Provider: "simple", MaxBytes: tt.maxBytes})
+			if err != nil {
+				t.Fatalf("NewSimpleParser() error = %v", err)
+			}
+
+			_, err = p.ParseTextFile(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTextFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrDocumentTooLarge) {
+				t.Errorf("ParseTextFile() error = %v, want ErrDocumentTooLarge", err)
+			}
+		})
+	}
+}
+
+func TestReadLimited(t *testing.T) {
+	p := &ParserBase{config: types.ParserConfig{MaxBytes: 8}}
+
+	if _, err := p.readLimited(strings.NewReader("12345678"), "output"); err != nil {
+		t.Errorf("readLimited() at limit error = %v", err)
+	}
+	if _, err := p.readLimited(strings.NewReader("123456789"), "output"); !errors.Is(err, ErrDocumentTooLarge) {
+		t.Errorf("readLimited() over limit error = %v, want ErrDocumentTooLarge", err)
+	}
+}
+
+func TestPDFPassword(t *testing.T) {
+	p, err := NewSimpleParser(types.ParserConfig{Provider: "simple"})
+	if err != nil {
+		t.Fatalf("NewSimpleParser() error = %v", err)
+	}
+	if args := p.passwordArgs(); args != nil {
+		t.Errorf("passwordArgs() without password = %v, want none", args)
+	}
+
+	p, err = NewSimpleParser(types.ParserConfig{Provider: "simple", Options: map[string]string{"pdf_password": "s3cret"}})
+	if err != nil {
+		t.Fatalf("NewSimpleParser() error = %v", err)
+	}
+	want := []string{"-upw", "s3cret", "-opw", "s3cret"}
+	if args := p.passwordArgs(); strings.Join(args, " ") != strings.Join(want, " ") {
+		t.Errorf("passwordArgs() = %v, want %v", args, want)
+	}
+
+	if err := passwordError("doc.pdf", "Command Line Error: Incorrect password\n"); !errors.Is(err, ErrIncorrectPassword) {
+		t.Errorf("passwordError() = %v, want ErrIncorrectPassword", err)
+	}
+	if err := passwordError("doc.pdf", "Syntax Error: Couldn't find trailer dictionary"); err != nil {
+		t.Errorf("passwordError() for unrelated failure = %v, want nil", err)
+	}
+}
+
+func TestPyMuPDFConvertDocument(t *testing.T) {
+	p := &PyMuPDFParser{}
+	resp := &PyMuPDFResponse{
+		Status: "success",
+		Pages: []PyMuPDFPage{
+			{PageNo: 1, Blocks: []PyMuPDFBlock{
+				{Text: "1. Access Control", FontSize: 16, FontName: "Helvetica-Bold", Bold: true},
+				{Text: "1.1 Passwords", FontSize: 13.02},
+				{Text: "Administrators must enforce strong passwords for all accounts.", FontSize: 10, FontName: "Helvetica"},
+			}},
+			{PageNo: 2, Blocks: []PyMuPDFBlock{
+				{Text: "• Rotate credentials after an incident.", FontSize: 10, BBox: types.BBox{X1: 72, Y1: 90, X2: 300, Y2: 102}},
+				{Text: "Passwords are never stored in plain text by any system component.", FontSize: 10},
+			}},
+		},
+	}
+
+	doc := p.convertDocument("test.pdf", resp)
+
+	if len(doc.Pages) != 2 || doc.Pages[1].PageNumber != 2 {
+		t.Fatalf("Expected pages 1 and 2 from page indices, got %+v", doc.Pages)
+	}
+	first := doc.Pages[0].Blocks
+	if first[0].Type != types.BlockTypeHeading || first[0].Level != 1 || first[0].FontWeight != "bold" {
+		t.Errorf("Expected bold level-1 heading, got %+v", first[0])
+	}
+	if first[1].Type != types.BlockTypeHeading || first[1].Level != 2 {
+		t.Errorf("Expected level-2 heading, got %+v", first[1])
+	}
+	if first[2].Type != types.BlockTypeParagraph || first[2].FontSize != 10 || first[2].FontName != "Helvetica" {
+		t.Errorf("Expected body paragraph with font info, got %+v", first[2])
+	}
+	list := doc.Pages[1].Blocks[0]
+	if list.Type != types.BlockTypeList || list.ListItem == nil || list.Text != "Rotate credentials after an incident." {
+		t.Errorf("Expected bulleted list item, got %+v", list)
+	}
+	if list.BBox == nil || list.BBox.X1 != 72 || list.BBox.Y2 != 102 {
+		t.Errorf("Expected bounding box to be kept, got %+v", list.BBox)
+	}
+}
+
+func TestDoclingHyperlinks(t *testing.T) {
+	p := &DoclingParser{}
+
+	block := p.convertTextItem(&DoclingTextItem{
+		Label:     "text",
+		Text:      "CIS Benchmarks",
+		Hyperlink: "https://www.cisecurity.org/cis-benchmarks",
+	})
+	if len(block.Links) != 1 || block.Links[0].URL != "https://www.cisecurity.org/cis-benchmarks" || block.Links[0].Text != "CIS Benchmarks" {
+		t.Errorf("Expected hyperlink to be preserved, got %+v", block.Links)
+	}
+
+	block = p.convertTextItem(&DoclingTextItem{Label: "text", Text: "No links here"})
+	if block.Links != nil {
+		t.Errorf("Expected no links, got %+v", block.Links)
+	}
+}
+
+func TestDoclingContinuedTables(t *testing.T) {
+	p := &DoclingParser{}
+	prov := func(page int, l, r float64) []DoclingProv {
+		return []DoclingProv{{PageNo: page, BBox: DoclingBBox{L: l, T: 700, R: r, B: 100}}}
+	}
+	header := []string{"Control", "Requirement"}
+
+	resp := &DoclingDocument{
+		Tables: []DoclingTable{
+			{Prov: prov(1, 72, 540), Rows: [][]string{header, {"AC-1", "Policy"}}},
+			// Continues on the next page, repeating the header
+			{Prov: prov(2, 73, 539), Rows: [][]string{header, {"AC-2", "Accounts"}}},
+			// Same page as the previous part: a separate table
+			{Prov: prov(2, 72, 540), Rows: [][]string{{"A", "B"}}},
+			// Next page but a different width
+			{Prov: prov(3, 150, 400), Rows: [][]string{{"C", "D"}}},
+			// Next page and aligned but with a different column count
+			{Prov: prov(4, 150, 400), Rows: [][]string{{"E", "F", "G"}}},
+		},
+	}
+
+	doc := p.convertDocument("test.pdf", resp)
+	var tables []types.Block
+	for _, page := range doc.Pages {
+		for _, block := range page.Blocks {
+			if block.Type == types.BlockTypeTable {
+				tables = append(tables, block)
+			}
+		}
+	}
+	if len(tables) != 4 {
+		t.Fatalf("Expected 4 tables, got %d", len(tables))
+	}
+	if len(doc.Pages[0].Blocks) != 1 {
+		t.Fatalf("Expected the merged table on page 1, got %+v", doc.Pages[0].Blocks)
+	}
+	merged := doc.Pages[0].Blocks[0]
+	if merged.TableData == nil || len(merged.TableData.Rows) != 3 {
+		t.Fatalf("Expected 3 rows in the merged table, got %+v", merged.TableData)
+	}
+	if merged.TableData.Rows[2][0] != "AC-2" {
+		t.Errorf("Expected continuation rows after the first part, got %v", merged.TableData.Rows)
+	}
+	if merged.BBox == nil || merged.BBox.X1 != 72 {
+		t.Errorf("Expected the first part's bounding box, got %+v", merged.BBox)
+	}
+
+	// Tables without cell data merge on position alone
+	merged2 := mergeContinuedTables([]DoclingTable{{Prov: prov(5, 72, 540)}, {Prov: prov(6, 72, 540)}})
+	if len(merged2) != 1 || len(merged2[0].Prov) != 2 {
+		t.Errorf("Expected tables without rows to merge, got %+v", merged2)
+	}
+}
+
+// writeDOCX writes a minimal .docx package with the given parts
+func writeDOCX(t *testing.T, parts map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.docx")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create docx: %v", err)
+	}
+	defer f.Close()
+	
+	w := zip.NewWriter(f)
+	for name, content := range parts {
+		part, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("failed to add %s: %v", name, err)
+		}
+		if _, err := part.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to write docx: %v", err)
+	}
+	return path
+}
+
+func TestDOCXParser(t *testing.T) {
+	const w = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"`
+	styles := `<w:styles ` + w + `>
+  <w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/></w:style>
+  <w:style w:type="paragraph" w:styleId="berschrift1"><w:name w:val="heading 1"/></w:style>
+  <w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/></w:style>
+  <w:style w:type="paragraph" w:styleId="ListParagraph"><w:name w:val="List Paragraph"/></w:style>
+  <w:style w:type="paragraph" w:styleId="ControlTitle"><w:name w:val="Control Title"/><w:basedOn w:val="Normal"/><w:pPr><w:outlineLvl w:val="2"/></w:pPr></w:style>
+</w:styles>`
+	numbering := `<w:numbering ` + w + `>
+  <w:abstractNum w:abstractNumId="0">
+    <w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="decimal"/><w:lvlText w:val="%1."/></w:lvl>
+    <w:lvl w:ilvl="1"><w:start w:val="1"/><w:numFmt w:val="lowerLetter"/><w:lvlText w:val="%2)"/></w:lvl>
+  </w:abstractNum>
+  <w:abstractNum w:abstractNumId="1">
+    <w:lvl w:ilvl="0"><w:numFmt w:val="bullet"/><w:lvlText w:val=""/></w:lvl>
+  </w:abstractNum>
+  <w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num>
+  <w:num w:numId="2"><w:abstractNumId w:val="1"/></w:num>
+</w:numbering>`
+	para := func(style, text string) string {
+		return `<w:p><w:pPr><w:pStyle w:val="` + style + `"/></w:pPr><w:r><w:t>` + text + `</w:t></w:r></w:p>`
+	}
+	item := func(numID, ilvl, text string) string {
+		return `<w:p><w:pPr><w:pStyle w:val="ListParagraph"/><w:numPr><w:ilvl w:val="` + ilvl + `"/><w:numId w:val="` + numID + `"/></w:numPr></w:pPr><w:r><w:t>` + text + `</w:t></w:r></w:p>`
+	}
+	document := `<w:document ` + w + `><w:body>` +
+		para("Title", "Information Security Policy") +
+		para("berschrift1", "Access Control") +
+		`<w:p><w:r><w:t xml:space="preserve">Access is granted </w:t></w:r><w:r><w:t>on least privilege.</w:t></w:r>` +
+		`<w:r><w:br w:type="page"/></w:r></w:p>` +
+		para("Heading2", "Passwords") +
+		item("1", "0", "Use a password manager.") +
+		item("1", "1", "Generate unique passwords.") +
+		item("1", "1", "Never reuse them.") +
+		item("1", "0", "Rotate after compromise.") +
+		item("2", "0", "Applies to contractors.") +
+		para("ControlTitle", "Review") +
+		`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>Control</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Owner</w:t></w:r></w:p></w:tc></w:tr>` +
+		`<w:tr><w:tc><w:p><w:r><w:t>AC-1</w:t></w:r></w:p><w:p><w:r><w:t>(annual)</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>CISO</w:t></w:r></w:p></w:tc></w:tr></w:tbl>` +
+		`<w:p/><w:sectPr/></w:body></w:document>`
+	
+	path := writeDOCX(t, map[string]string{
+		"word/document.xml":  document,
+		"word/styles.xml":    styles,
+		"word/numbering.xml": numbering,
+	})
+	
+	p, err := NewParser(types.ParserConfig{Provider: "docx"})
+	if err != nil {
+		t.Fatalf("NewParser() error = %v", err)
+	}
+	doc, err := p.Parse(path)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if doc.Metadata.Parser != "docx-v1.0" {
+		t.Errorf("Expected docx parser metadata, got %s", doc.Metadata.Parser)
+	}
+	if len(doc.Pages) != 2 {
+		t.Fatalf("Expected the page break to start page 2, got %d pages", len(doc.Pages))
+	}
+	
+	first := doc.Pages[0].Blocks
+	if len(first) != 3 {
+		t.Fatalf("Expected 3 blocks on page 1, got %+v", first)
+	}
+	if first[0].Type != types.BlockTypeHeading || first[0].Level != 1 {
+		t.Errorf("Expected title as level 1 heading, got %+v", first[0])
+	}
+	if first[1].Type != types.BlockTypeHeading || first[1].Level != 1 || first[1].Text != "Access Control" {
+		t.Errorf("Expected localized heading style to map by name, got %+v", first[1])
+	}
+	if first[2].Type != types.BlockTypeParagraph || first[2].Text != "Access is granted on least privilege." {
+		t.Errorf("Expected runs joined into a paragraph, got %+v", first[2])
+	}
+	
+	second := doc.Pages[1].Blocks
+	if len(second) != 8 {
+		t.Fatalf("Expected 8 blocks on page 2, got %+v", second)
+	}
+	if second[0].Type != types.BlockTypeHeading || second[0].Level != 2 {
+		t.Errorf("Expected level 2 heading, got %+v", second[0])
+	}
+	wantItems := []struct {
+		marker   string
+		level    int
+		listType string
+		kind     types.MarkerKind
+	}{
+		{"1.", 1, "ordered", types.MarkerKindDecimal},
+		{"a)", 2, "ordered", types.MarkerKindLowerAlpha},
+		{"b)", 2, "ordered", types.MarkerKindLowerAlpha},
+		{"2.", 1, "ordered", types.MarkerKindDecimal},
+		{"•", 1, "unordered", types.MarkerKindBullet},
+	}
+	for i, want := range wantItems {
+		block := second[i+1]
+		if block.Type != types.BlockTypeList || block.ListItem == nil {
+			t.Errorf("item %d: expected list block, got %+v", i, block)
+			continue
+		}
+		got := block.ListItem
+		if got.Marker != want.marker || got.Level != want.level || got.Type != want.listType || got.MarkerKind != want.kind {
+			t.Errorf("item %d: got %+v, want %+v", i, *got, want)
+		}
+	}
+	if second[6].Type != types.BlockTypeHeading || second[6].Level != 3 {
+		t.Errorf("Expected custom style's outline level to make a heading, got %+v", second[6])
+	}
+	table := second[7]
+	if table.Type != types.BlockTypeTable || table.TableData == nil {
+		t.Fatalf("Expected table block, got %+v", table)
+	}
+	wantRows := [][]string{{"Control", "Owner"}, {"AC-1 (annual)", "CISO"}}
+	if fmt.Sprint(table.TableData.Rows) != fmt.Sprint(wantRows) {
+		t.Errorf("Rows = %q, want %q", table.TableData.Rows, wantRows)
+	}
+}
+
+func TestDOCXParserErrors(t *testing.T) {
+	p, err := NewDOCXParser(types.ParserConfig{Provider: "docx"})
+	if err != nil {
+		t.Fatalf("NewDOCXParser() error = %v", err)
+	}
+	
+	// Not a zip archive
+	notZip := filepath.Join(t.TempDir(), "plain.docx")
+	if err := os.WriteFile(notZip, []byte("not a docx"), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	if _, err := p.Parse(notZip); err == nil {
+		t.Error("Expected error for a non-zip file")
+	}
+	
+	// Missing main document part
+	if _, err := p.Parse(writeDOCX(t, map[string]string{"word/styles.xml": "<styles/>"})); err == nil || !strings.Contains(err.Error(), "word/document.xml") {
+		t.Errorf("Expected missing document error, got %v", err)
+	}
+	
+	// Parts are read through the size limit
+	p, _ = NewDOCXParser(types.ParserConfig{Provider: "docx", MaxBytes: 4096})
+	big := "<document><body><p><r><t>" + strings.Repeat("x", 8192) + "</t></r></p></body></document>"
+	if _, err := p.Parse(writeDOCX(t, map[string]string{"word/document.xml": big})); !errors.Is(err, ErrDocumentTooLarge) {
+		t.Errorf("Expected ErrDocumentTooLarge, got %v", err)
+	}
+}
+
+func TestPickStructuredPages(t *testing.T) {
+	parser, err := NewSimpleParser(types.ParserConfig{Provider: "simple"})
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	
+	// Two-column layout interleaves the columns, hiding the headings
+	layoutText := `1. Access Control        2. Audit Logging
+Users must be            Events must be
+authenticated.           recorded.
+`
+	rawText := `1. Access Control
+
+Users must be authenticated.
+
+2. Audit Logging
+
+Events must be recorded.
+`
+	
+	pages, mode := parser.pickStructuredPages(layoutText, rawText)
+	if mode != PdftotextModeRaw {
+		t.Errorf("Expected raw mode to be chosen, got %s", mode)
+	}
+	if len(pages) == 0 {
+		t.Fatal("Expected parsed pages")
+	}
+	
+	// Equal structure prefers layout
+	_, mode = parser.pickStructuredPages(rawText, rawText)
+	if mode != PdftotextModeLayout {
+		t.Errorf("Expected layout mode on tie, got %s", mode)
+	}
+}
+
+func TestParseRevisionTable(t *testing.T) {
+	parser, err := NewSimpleParser(types.ParserConfig{Provider: "simple"})
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	
+	content := `Document Changes
+
+Date            Version     Description
+October 2008    1.2         To introduce PCI DSS v1.2 as "PCI DSS Requirements
+                            and Security Assessment Procedures."
+
+May 2018        3.2.1       Updated to reflect minor corrections.
+
+Introduction
+
+This document provides the PCI DSS requirements.
+`
+	
+	pages := parser.parseTextContent(content)
+	if len(pages) == 0 {
+		t.Fatal("Expected parsed pages")
+	}
+	
+	var table *types.TableData
+	for _, block := range pages[0].Blocks {
+		if block.Type == types.BlockTypeTable && block.Text == types.RevisionTableTitle {
+			table = block.TableData
+		}
+	}
+	if table == nil {
+		t.Fatalf("Expected a revision table block, got %+v", pages[0].Blocks)
+	}
+	if len(table.Rows) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d: %v", len(table.Rows), table.Rows)
+	}
+	if got := table.Rows[1]; got[0] != "October 2008" || got[1] != "1.2" || got[2] != `To introduce PCI DSS v1.2 as "PCI DSS Requirements and Security Assessment Procedures."` {
+		t.Errorf("Unexpected first row: %q", got)
+	}
+	
+	// Content after the table is parsed normally
+	last := pages[0].Blocks[len(pages[0].Blocks)-1]
+	if last.Type != types.BlockTypeParagraph || last.Text != "This document provides the PCI DSS requirements." {
+		t.Errorf("Expected trailing paragraph, got %+v", last)
+	}
+}
+
+func TestParseAlignedTable(t *testing.T) {
+	parser, err := NewSimpleParser(types.ParserConfig{Provider: "simple"})
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	
+	content := `The following procedures apply.
+Requirement        Testing Procedures             Guidance
+Firewall config    Inspect the configuration      Reduces exposure
+Router config      Interview personnel            Limits access
+
+Administrators review the rules    quarterly.
+`
+	
+	pages := parser.parseTextContent(content)
+	if len(pages) == 0 {
+		t.Fatal("Expected parsed pages")
+	}
+	blocks := pages[0].Blocks
+	if len(blocks) != 3 {
+		t.Fatalf("Expected paragraph, table and paragraph, got %+v", blocks)
+	}
+	
+	if blocks[0].Type != types.BlockTypeParagraph || blocks[0].Text != "The following procedures apply." {
+		t.Errorf("Expected leading paragraph, got %+v", blocks[0])
+	}
+	table := blocks[1]
+	if table.Type != types.BlockTypeTable || table.TableData == nil || len(table.TableData.Rows) != 3 {
+		t.Fatalf("Expected a 3-row table, got %+v", table)
+	}
+	if got := table.TableData.Rows[1]; len(got) != 3 || got[0] != "Firewall config" || got[2] != "Reduces exposure" {
+		t.Errorf("Unexpected first body row: %q", got)
+	}
+	// A single line with a column gap is not a table
+	if blocks[2].Type != types.BlockTypeParagraph || blocks[2].Text != "Administrators review the rules quarterly." {
+		t.Errorf("Expected trailing paragraph, got %+v", blocks[2])
+	}
+}
+
+func TestParseCodeBlocks(t *testing.T) {
+	parser, err := NewSimpleParser(types.ParserConfig{Provider: "simple"})
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	
+	content := `  Disable root login in the SSH daemon configuration:
+      # /etc/ssh/sshd_config
+      PermitRootLogin = no
+        MaxAuthTries = 4
+  Then restart the service:
+      $ sudo systemctl restart sshd
+      Administrators should confirm the change
+      with the change board before rollout.
+`
+	
+	pages := parser.parseTextContent(content)
+	if len(pages) == 0 {
+		t.Fatal("Expected parsed pages")
+	}
+	blocks := pages[0].Blocks
+	if len(blocks) != 5 {
+		t.Fatalf("Expected paragraph, code, paragraph, code and paragraph, got %+v", blocks)
+	}
+	
+	if blocks[1].Type != types.BlockTypeCode || blocks[1].Text != "# /etc/ssh/sshd_config\nPermitRootLogin = no\n  MaxAuthTries = 4" {
+		t.Errorf("Expected the configuration kept verbatim, got %+v", blocks[1])
+	}
+	if blocks[2].Type != types.BlockTypeParagraph || blocks[2].Text != "Then restart the service:" {
+		t.Errorf("Expected paragraph between examples, got %+v", blocks[2])
+	}
+	if blocks[3].Type != types.BlockTypeCode || blocks[3].Text != "$ sudo systemctl restart sshd" {
+		t.Errorf("Expected a lone prompt line as code, got %+v", blocks[3])
+	}
+	// Indented prose is not code
+	if blocks[4].Type != types.BlockTypeParagraph {
+		t.Errorf("Expected indented prose as a paragraph, got %+v", blocks[4])
+	}
+}
+
+func TestParseFootnotes(t *testing.T) {
+	parser, err := NewSimpleParser(types.ParserConfig{Provider: "simple"})
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	
+	content := `1.1 Passwords
+
+Passwords must be rotated after a suspected
+compromise.
+
+1 See NIST SP 800-63B for rotation
+guidance.
+2 Applies to service accounts too.
+                                   Page 4
+` + "\f" + `
+1.2 Logging
+
+Events must be recorded [3] in a central store.
+
+[3] Central stores are described in Appendix A.
+`
+	
+	pages := parser.parseTextContent(content)
+	if len(pages) != 2 {
+		t.Fatalf("Expected 2 pages, got %d", len(pages))
+	}
+	
+	blocks := pages[0].Blocks
+	if len(blocks) != 4 {
+		t.Fatalf("Expected heading, paragraph and 2 footnotes, got %+v", blocks)
+	}
+	if blocks[1].Type != types.BlockTypeParagraph || blocks[1].Text != "Passwords must be rotated after a suspected compromise." {
+		t.Errorf("Expected footnote kept out of the paragraph, got %+v", blocks[1])
+	}
+	if blocks[2].Type != types.BlockTypeFootnote || blocks[2].Text != "1 See NIST SP 800-63B for rotation guidance." {
+		t.Errorf("Expected wrapped footnote, got %+v", blocks[2])
+	}
+	if blocks[3].Type != types.BlockTypeFootnote || blocks[3].Text != "2 Applies to service accounts too." {
+		t.Errorf("Expected second footnote, got %+v", blocks[3])
+	}
+	
+	// Bracketed footnotes are found anywhere; inline references are not
+	blocks = pages[1].Blocks
+	if blocks[1].Type != types.BlockTypeParagraph {
+		t.Errorf("Expected paragraph with inline reference, got %+v", blocks[1])
+	}
+	if last := blocks[len(blocks)-1]; last.Type != types.BlockTypeFootnote || !strings.HasPrefix(last.Text, "[3]") {
+		t.Errorf("Expected bracketed footnote, got %+v", last)
+	}
+}
+
+func TestCollectTableFallback(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+	}{
+		{"single row", []string{"Name      Value"}},
+		{"inconsistent column count", []string{"Name      Value", "Alpha     1      extra"}},
+		{"misaligned columns", []string{"Name      Value", "Alpha             1"}},
+		{"numbered heading", []string{"1.1 Establish standards    Inspect", "1.2 Review rules           Verify"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if table, n := (&SimpleParser{}).collectTable(tt.lines); table != nil || n != 0 {
+				t.Errorf("collectTable() = %v, %d; want no table", table, n)
+			}
+		})
+	}
+}
+
+func FuzzParseTextContent(f *testing.F) {
+	seeds := []string{
+		"",
+		"\f",
+		"1. Access Control\n\nUsers must be authenticated.\n",
+		"Requirement 1: Install and maintain a firewall\n1.1 Establish standards\n",
+		"Chapter 1 .......................................... 15\n",
+		"- \n* \n1. \na. \n•\n",
+		"Date            Version     Description\nMay 2018        3.2.1       Fix\n                            continued\n",
+		"Date  Version\n\f\n01/2018  1.0\n",
+		strings.Repeat(".", 10000) + " 12",
+		strings.Repeat(". ", 5000),
+		strings.Repeat(" ", 5000) + "x",
+		strings.Repeat("A ", 5000),
+		"Page 1 of 2\n© 2018 All rights reserved\n42\n",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	
+	parser, err := NewSimpleParser(types.ParserConfig{Provider: "simple"})
+	if err != nil {
+		f.Fatalf("Failed to create parser: %v", err)
+	}
+	
+	f.Fuzz(func(t *testing.T, content string) {
+		pages := parser.parseTextContent(content)
+		for _, page := range pages {
+			if len(page.Blocks) == 0 {
+				t.Errorf("Page %d has no blocks", page.PageNumber)
+			}
+			for _, block := range page.Blocks {
+				if block.Type == types.BlockTypeTable && block.TableData == nil {
+					t.Errorf("Table block without table data on page %d", page.PageNumber)
+				}
+			}
+		}
+		
+		cleaned := cleanText(content)
+		if cleaned != strings.TrimSpace(cleaned) {
+			t.Errorf("cleanText left surrounding whitespace: %q", cleaned)
+		}
+	})
+}
+
+// worstCaseLines are crafted lines aimed at the parser regexes: dotted
+// leaders that never end in a page number, long numbered prefixes and
+// whitespace runs. Runtime should grow linearly with the line length.
+func worstCaseLines(n int) map[string]string {
+	return map[string]string{
+		"dotted-leaders": strings.Repeat("... 1 ", n) + "x",
+		"numbered":       strings.Repeat("1.", n) + " x",
+		"whitespace":     strings.Repeat(" \t", n) + "x",
+	}
+}
+
+func BenchmarkParseWorstCaseLine(b *testing.B) {
+	parser, err := NewSimpleParser(types.ParserConfig{Provider: "simple"})
+	if err != nil {
+		b.Fatalf("Failed to create parser: %v", err)
+	}
+	
+	for _, n := range []int{1000, 10000, 100000} {
+		for name, line := range worstCaseLines(n) {
+			b.Run(fmt.Sprintf("%s/%d", name, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					parser.parseTextContent(line)
+				}
+			})
+		}
+	}
+}
+
+func TestOrderColumnText(t *testing.T) {
+	bbox := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head>
+<title></title>
+<meta name="Producer" content="test"/>
+</head>
+<body>
+<doc>
+  <page width="612.000000" height="792.000000">
+    <flow>
+      <block xMin="72" yMin="50" xMax="540" yMax="70">
+        <line xMin="72" yMin="50" xMax="540" yMax="70"><word xMin="72" yMin="50" xMax="100" yMax="70">AC</word><word xMin="110" yMin="50" xMax="200" yMax="70">-</word><word xMin="210" yMin="50" xMax="300" yMax="70">ACCESS</word><word xMin="310" yMin="50" xMax="400" yMax="70">CONTROL</word></line>
+      </block>
+      <block xMin="72" yMin="100" xMax="290" yMax="120">
+        <line xMin="72" yMin="100" xMax="290" yMax="120"><word xMin="72" yMin="100" xMax="120" yMax="120">AC-1</word><word xMin="130" yMin="100" xMax="200" yMax="120">Policy</word></line>
+      </block>
+      <block xMin="320" yMin="100" xMax="540" yMax="120">
+        <line xMin="320" yMin="100" xMax="540" yMax="120"><word xMin="320" yMin="100" xMax="400" yMax="120">AC-3</word><word xMin="410" yMin="100" xMax="500" yMax="120">Enforcement</word></line>
+      </block>
+      <block xMin="72" yMin="130" xMax="290" yMax="150">
+        <line xMin="72" yMin="130" xMax="290" yMax="150"><word xMin="72" yMin="130" xMax="120" yMax="150">AC-2</word><word xMin="130" yMin="130" xMax="200" yMax="150">Accounts</word><word xMin="210" yMin="130" xMax="240" yMax="150">&amp;</word><word xMin="250" yMin="130" xMax="290" yMax="150">Roles</word></line>
+      </block>
+      <block xMin="320" yMin="130" xMax="540" yMax="150">
+        <line xMin="320" yMin="130" xMax="540" yMax="150"><word xMin="320" yMin="130" xMax="400" yMax="150">AC-4</word><word xMin="410" yMin="130" xMax="500" yMax="150">Flow</word></line>
+      </block>
+    </flow>
+  </page>
+  <page width="612.000000" height="792.000000">
+    <flow>
+      <block xMin="72" yMin="100" xMax="290" yMax="120">
+        <line xMin="72" yMin="100" xMax="290" yMax="120"><word xMin="72" yMin="100" xMax="120" yMax="120">Left</word></line>
+      </block>
+      <block xMin="320" yMin="100" xMax="540" yMax="120">
+        <line xMin="320" yMin="100" xMax="540" yMax="120"><word xMin="320" yMin="100" xMax="400" yMax="120">Right</word></line>
+      </block>
+    </flow>
+  </page>
+</doc>
+</body>
+</html>`
+
+	tests := []struct {
+		mode string
+		want string
+	}{
+		// Page 2 has one block per side, too few for auto to call it two columns
+		{ColumnsAuto, "AC - ACCESS CONTROL\n\nAC-1 Policy\n\nAC-2 Accounts & Roles\n\nAC-3 Enforcement\n\nAC-4 Flow\n\n\fLeft\n\nRight\n\n"},
+		{ColumnsTwo, "AC - ACCESS CONTROL\n\nAC-1 Policy\n\nAC-2 Accounts & Roles\n\nAC-3 Enforcement\n\nAC-4 Flow\n\n\fLeft\n\nRight\n\n"},
+		{ColumnsOne, "AC - ACCESS CONTROL\n\nAC-1 Policy\n\nAC-3 Enforcement\n\nAC-2 Accounts & Roles\n\nAC-4 Flow\n\n\fLeft\n\nRight\n\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			got, err := orderColumnText(bbox, tt.mode)
+			if err != nil {
+				t.Fatalf("orderColumnText() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("orderColumnText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewSimpleParser(types.ParserConfig{Provider: "simple", Options: map[string]string{"columns": "3"}}); err == nil {
+		t.Error("Expected an error for unsupported columns")
+	}
+}
+
+func TestOrderLayoutColumns(t *testing.T) {
+	text := strings.Join([]string{
+		"                      ACCESS CONTROL FAMILY",
+		"",
+		"AC-1 Policy and Procedures           AC-3 Access Enforcement",
+		"The organization develops a          The system enforces approved",
+		"policy.                              authorizations.",
+		"",
+		"AC-2 Account Management              AC-4 Information Flow",
+		"The organization manages             The system controls flow.",
+	}, "\n") + "\fSingle column page\nwith  two  spaces"
+
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{ColumnsAuto, strings.Join([]string{
+			"                      ACCESS CONTROL FAMILY",
+			"",
+			"AC-1 Policy and Procedures",
+			"The organization develops a",
+			"policy.",
+			"",
+			"AC-2 Account Management",
+			"The organization manages",
+			"",
+			"AC-3 Access Enforcement",
+			"The system enforces approved",
+			"authorizations.",
+			"",
+			"AC-4 Information Flow",
+			"The system controls flow.",
+			"",
+			"",
+		}, "\n") + "\fSingle column page\nwith  two  spaces"},
+		{ColumnsOne, text},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			if got := orderLayoutColumns(text, tt.mode); got != tt.want {
+				t.Errorf("orderLayoutColumns() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderLayoutColumns_OneSidedRows(t *testing.T) {
+	// A row with text on one side only doesn't break the other column's
+	// paragraph
+	text := strings.Join([]string{
+		"AC-1 Policy and Procedures      AC-3 Access Enforcement",
+		"The organization develops       The system enforces",
+		"and documents a policy.",
+		"It reviews it yearly.           approved authorizations.",
+	}, "\n")
+	want := strings.Join([]string{
+		"AC-1 Policy and Procedures",
+		"The organization develops",
+		"and documents a policy.",
+		"It reviews it yearly.",
+		"",
+		"AC-3 Access Enforcement",
+		"The system enforces",
+		"approved authorizations.",
+		"",
+		"",
+	}, "\n")
+	if got := orderLayoutColumns(text, ColumnsTwo); got != want {
+		t.Errorf("orderLayoutColumns() = %q, want %q", got, want)
+	}
+}
+
+func TestScanMetadata(t *testing.T) {
+	info := `Title:           Microsoft Word - PCI_DSS_v3-2-1.docx
+Author:          PCI Security Standards Council
+Creator:         Microsoft Word
+CreationDate:    2018-05-17T10:11:12-04:00
+Pages:           139
+`
+	fields := parsePdfinfo(info)
+	if fields["Author"] != "PCI Security Standards Council" || fields["Pages"] != "139" {
+		t.Errorf("Unexpected pdfinfo fields: %v", fields)
+	}
+	if !placeholderTitleRegex.MatchString(fields["Title"]) {
+		t.Errorf("Expected %q to be treated as a placeholder title", fields["Title"])
+	}
+	
+	meta := types.DocumentMetadata{Author: fields["Author"]}
+	firstPage := `
+        Payment Card Industry (PCI)
+        Data Security Standard
+
+        Requirements and Security Assessment Procedures
+        Version 3.2.1
+        May 2018
+        By: Someone Else
+`
+	scanFirstPage(firstPage, &meta)
+	if meta.Title != "Payment Card Industry (PCI)" {
+		t.Errorf("Title = %q", meta.Title)
+	}
+	if meta.Version != "3.2.1" {
+		t.Errorf("Version = %q, want 3.2.1", meta.Version)
+	}
+	if meta.PublicationDate != "May 2018" {
+		t.Errorf("PublicationDate = %q, want May 2018", meta.PublicationDate)
+	}
+	// Fields from pdfinfo are kept
+	if meta.Author != "PCI Security Standards Council" {
+		t.Errorf("Author = %q, want the pdfinfo author", meta.Author)
+	}
+	
+	meta = types.DocumentMetadata{}
+	scanFirstPage("Access Policy v2.1\nAuthor: Security Team\n", &meta)
+	if meta.Title != "Access Policy v2.1" || meta.Author != "Security Team" {
+		t.Errorf("Unexpected metadata %+v", meta)
+	}
+}
+
+func TestMarkdownParser(t *testing.T) {
+	content := `---
+title: Baseline
+---
+# Security Baseline
+
+Controls for open source projects.
+
+## Access Control
+
+Projects **must** enforce [MFA](https://example.org/mfa) for maintainers.
+
+- Require MFA
+  for all maintainers
+  - Including bots
+1. Review access
+2) Revoke stale access
+
+` + "```yaml" + `
+# not a heading
+mfa: required
+` + "```" + `
+
+## Build and Release
+
+| ID | Requirement |
+|----|:------------|
+| BR-01 | Signed releases |
+
+Release Process
+---------------
+
+Releases are tagged.
+`
+	path := filepath.Join(t.TempDir(), "baseline.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	
+	p, err := NewParser(types.ParserConfig{Provider: "markdown"})
+	if err != nil {
+		t.Fatalf("NewParser() error = %v", err)
+	}
+	doc, err := p.Parse(path)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	
+	// The title stays with the first section; each level 2 section, ATX or
+	// setext, gets a page
+	if len(doc.Pages) != 3 {
+		t.Fatalf("Expected a page per top-level section, got %d pages", len(doc.Pages))
+	}
+	
+	first := doc.Pages[0].Blocks
+	if first[0].Type != types.BlockTypeHeading || first[0].Level != 1 || first[0].Text != "Security Baseline" {
+		t.Errorf("Expected title heading, got %+v", first[0])
+	}
+	if first[2].Type != types.BlockTypeHeading || first[2].Level != 2 || first[2].Text != "Access Control" {
+		t.Errorf("Expected section heading, got %+v", first[2])
+	}
+	para := first[3]
+	if para.Text != "Projects must enforce MFA for maintainers." || len(para.Links) != 1 || para.Links[0].URL != "https://example.org/mfa" {
+		t.Errorf("Expected inline markup stripped and link kept, got %+v", para)
+	}
+	
+	wantItems := []struct {
+		text, marker, listType string
+		level                  int
+	}{
+		{"Require MFA for all maintainers", "-", "unordered", 1},
+		{"Including bots", "-", "unordered", 2},
+		{"Review access", "1.", "ordered", 1},
+		{"Revoke stale access", "2)", "ordered", 1},
+	}
+	for i, want := range wantItems {
+		block := first[4+i]
+		if block.Type != types.BlockTypeList || block.ListItem == nil {
+			t.Errorf("item %d: expected list block, got %+v", i, block)
+			continue
+		}
+		if block.Text != want.text || block.ListItem.Marker != want.marker || block.ListItem.Type != want.listType || block.ListItem.Level != want.level {
+			t.Errorf("item %d: got %q %+v, want %+v", i, block.Text, *block.ListItem, want)
+		}
+	}
+	code := first[len(first)-1]
+	if code.Type != types.BlockTypeCode || code.Text != "# not a heading\nmfa: required" {
+		t.Errorf("Expected fenced code block, got %+v", code)
+	}
+	
+	second := doc.Pages[1].Blocks
+	if second[0].Text != "Build and Release" {
+		t.Errorf("Expected second section on page 2, got %+v", second[0])
+	}
+	if second[1].Type != types.BlockTypeTable || len(second[1].TableData.Rows) != 2 || second[1].TableData.Rows[1][1] != "Signed releases" {
+		t.Errorf("Expected pipe table, got %+v", second[1])
+	}
+	third := doc.Pages[2].Blocks
+	if third[0].Type != types.BlockTypeHeading || third[0].Level != 2 || third[0].Text != "Release Process" {
+		t.Errorf("Expected setext heading to start a section, got %+v", third[0])
+	}
+}
+
+func TestLoadParsedDocument(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		return path
+	}
+	
+	path := write("external.json", `{
+		"metadata": {"parser": "upstream-ocr"},
+		"pages": [{"page_number": 1, "blocks": [
+			{"type": "heading", "level": 1, "text": "1. Access Control"},
+			{"type": "list", "text": "Use MFA", "list_item": {"level": 1, "marker": "a.", "type": "ordered"}}
+		]}]
+	}`)
+	doc, err := LoadParsedDocument(path)
+	if err != nil {
+		t.Fatalf("LoadParsedDocument failed: %v", err)
+	}
+	if doc.Metadata.Parser != "upstream-ocr" || doc.Metadata.SourceFile != path || doc.Metadata.ParsedAt.IsZero() {
+		t.Errorf("Unexpected metadata: %+v", doc.Metadata)
+	}
+	if len(doc.Pages) != 1 || len(doc.Pages[0].Blocks) != 2 || doc.Pages[0].Blocks[1].ListItem.Marker != "a." {
+		t.Errorf("Unexpected pages: %+v", doc.Pages)
+	}
+	
+	for name, content := range map[string]string{
+		"typo.json":     `{"pages": [{"page_number": 1, "blocks": [{"type": "heading", "txt": "Typo"}]}]}`,
+		"shape.json":    `{"pages": [{"page_number": 1, "blocks": [{"type": "figure", "text": "Diagram"}]}]}`,
+		"empty.json":    `{"metadata": {}, "pages": []}`,
+		"trailing.json": `{"pages": [{"page_number": 1, "blocks": []}]} {}`,
+		"array.json":    `[]`,
+	} {
+		if _, err := LoadParsedDocument(write(name, content)); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+	if _, err := LoadParsedDocument(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Expected a missing file to be rejected")
+	}
+}
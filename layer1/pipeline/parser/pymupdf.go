@@ -0,0 +1,277 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// pymupdfMissingModuleExit is the exit status pymupdf_convert.py uses when
+// the pymupdf module cannot be imported
+const pymupdfMissingModuleExit = 3
+
+// Font size thresholds for telling headings from body text
+const (
+	pymupdfHeadingRatio    = 1.15 // Minimum size relative to body text
+	pymupdfMaxHeadingLen   = 200  // Longer blocks are paragraphs whatever their size
+	pymupdfMaxHeadingLevel = 3
+)
+
+// unorderedMarkers are the leading characters of bulleted list items
+var unorderedMarkers = []string{"•", "▪", "◦", "●", "○", "■", "-", "*", "–"}
+
+// missingModuleError reports a Python module the helper script could not
+// import. It unwraps to exec.ErrNotFound so callers treat it like a missing
+// binary.
+type missingModuleError struct {
+	module string
+}
+
+func (e missingModuleError) Error() string {
+	return fmt.Sprintf("python module %s not found (install with: pip install %s)", e.module, e.module)
+}
+
+func (e missingModuleError) Unwrap() error {
+	return exec.ErrNotFound
+}
+
+// PyMuPDFParser uses the PyMuPDF Python library for PDF parsing, keeping
+// per-block positions and fonts for layout-aware segmentation
+type PyMuPDFParser struct {
+	ParserBase
+	scriptPath string
+}
+
+// NewPyMuPDFParser creates a new PyMuPDF parser
+func NewPyMuPDFParser(config types.ParserConfig) (*PyMuPDFParser, error) {
+	parser := &PyMuPDFParser{}
+	if err := parser.Configure(config); err != nil {
+		return nil, err
+	}
+
+	// Find the Python script path relative to this Go file
+	_, filename, _, ok := runtime.Caller(0)
+	if !ok {
+		return nil, fmt.Errorf("failed to get current file path")
+	}
+	parser.scriptPath = filepath.Join(filepath.Dir(filename), "pymupdf_convert.py")
+
+	return parser, nil
+}
+
+// Name returns the parser name
+func (p *PyMuPDFParser) Name() string {
+	return "pymupdf"
+}
+
+// PyMuPDFResponse represents the response from the Python script
+type PyMuPDFResponse struct {
+	Status string         `json:"status"`
+	Pages  []PyMuPDFPage  `json:"pages"`
+	Errors []DoclingError `json:"errors"`
+}
+
+// PyMuPDFPage holds the text blocks of one page
+type PyMuPDFPage struct {
+	PageNo int            `json:"page_no"`
+	Blocks []PyMuPDFBlock `json:"blocks"`
+}
+
+// PyMuPDFBlock is a text block with the font of its dominant span
+type PyMuPDFBlock struct {
+	Text     string     `json:"text"`
+	BBox     types.BBox `json:"bbox"`
+	FontSize float64    `json:"font_size"`
+	FontName string     `json:"font_name"`
+	Bold     bool       `json:"bold"`
+}
+
+// Parse extracts content from a PDF file using the PyMuPDF Python library
+func (p *PyMuPDFParser) Parse(filePath string) (*types.ParsedDocument, error) {
+	// Check if python3 is available
+	if _, err := exec.LookPath("python3"); err != nil {
+		return nil, fmt.Errorf("python3 not found (required by the pymupdf parser): %w", err)
+	}
+
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	if err := p.checkInputSize(absPath); err != nil {
+		return nil, err
+	}
+
+	// Run the Python script, capping how much of its output is buffered
+	cmd := exec.Command("python3", p.scriptPath, absPath)
+	if password := p.pdfPassword(); password != "" {
+		// Passed through the environment to keep it out of the process list
+		cmd.Env = append(os.Environ(), pdfPasswordEnv+"="+password)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run pymupdf: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run pymupdf: %w", err)
+	}
+	output, readErr := p.readLimited(stdout, "pymupdf output")
+	if readErr != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil, fmt.Errorf("failed to read pymupdf output: %w", readErr)
+	}
+	waitErr := cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(waitErr, &exitErr) && exitErr.ExitCode() == pymupdfMissingModuleExit {
+		return nil, missingModuleError{module: "pymupdf"}
+	}
+	if waitErr != nil && exitErr == nil {
+		return nil, fmt.Errorf("failed to run pymupdf: %w", waitErr)
+	}
+
+	// Parse JSON output; the script reports failures there too
+	var resp PyMuPDFResponse
+	if err := json.Unmarshal(output, &resp); err != nil {
+		if waitErr != nil {
+			return nil, fmt.Errorf("pymupdf conversion failed: %s", stderr.String())
+		}
+		return nil, fmt.Errorf("failed to parse pymupdf output: %w", err)
+	}
+
+	if resp.Status != "success" {
+		errMsgs := ""
+		for _, e := range resp.Errors {
+			errMsgs += e.ErrorMessage + "; "
+		}
+		if pwErr := passwordError(filePath, errMsgs); pwErr != nil {
+			return nil, pwErr
+		}
+		return nil, fmt.Errorf("pymupdf conversion failed: %s", errMsgs)
+	}
+
+	return p.convertDocument(filePath, &resp), nil
+}
+
+// convertDocument converts the script's pages to a ParsedDocument
+func (p *PyMuPDFParser) convertDocument(filePath string, resp *PyMuPDFResponse) *types.ParsedDocument {
+	doc := &types.ParsedDocument{
+		Metadata: types.ParsedMetadata{
+			SourceFile: filePath,
+			Parser:     "pymupdf-v1.0",
+			ParsedAt:   time.Now(),
+		},
+		Pages: []types.Page{},
+	}
+
+	bodySize := bodyFontSize(resp.Pages)
+	levels := headingLevels(resp.Pages, bodySize)
+
+	for _, page := range resp.Pages {
+		blocks := make([]types.Block, 0, len(page.Blocks))
+		for _, b := range page.Blocks {
+			blocks = append(blocks, convertPyMuPDFBlock(b, levels))
+		}
+		doc.Pages = append(doc.Pages, types.Page{
+			PageNumber: page.PageNo,
+			Blocks:     blocks,
+		})
+	}
+
+	p.normalizeListMarkers(doc.Pages)
+	return doc
+}
+
+// convertPyMuPDFBlock converts a block, using its font size to detect headings
+func convertPyMuPDFBlock(b PyMuPDFBlock, levels map[float64]int) types.Block {
+	bbox := b.BBox
+	block := types.Block{
+		Type:     types.BlockTypeParagraph,
+		Text:     b.Text,
+		BBox:     &bbox,
+		FontSize: b.FontSize,
+		FontName: b.FontName,
+	}
+	if b.Bold {
+		block.FontWeight = "bold"
+	}
+
+	if level, ok := levels[roundFontSize(b.FontSize)]; ok && len(b.Text) <= pymupdfMaxHeadingLen {
+		block.Type = types.BlockTypeHeading
+		block.Level = level
+		return block
+	}
+
+	for _, marker := range unorderedMarkers {
+		if rest, ok := strings.CutPrefix(b.Text, marker+" "); ok {
+			block.Type = types.BlockTypeList
+			block.Text = strings.TrimSpace(rest)
+			block.ListItem = &types.ListItem{Level: 1, Marker: marker, Type: "unordered"}
+			break
+		}
+	}
+	return block
+}
+
+// bodyFontSize returns the font size covering the most text
+func bodyFontSize(pages []PyMuPDFPage) float64 {
+	chars := make(map[float64]int)
+	for _, page := range pages {
+		for _, b := range page.Blocks {
+			chars[roundFontSize(b.FontSize)] += len(b.Text)
+		}
+	}
+
+	body, most := 0.0, 0
+	for size, n := range chars {
+		if n > most || (n == most && size < body) {
+			body, most = size, n
+		}
+	}
+	return body
+}
+
+// headingLevels numbers the font sizes noticeably larger than body text,
+// largest first; sizes past the deepest level share it
+func headingLevels(pages []PyMuPDFPage, bodySize float64) map[float64]int {
+	levels := make(map[float64]int)
+	if bodySize <= 0 {
+		return levels
+	}
+
+	seen := make(map[float64]bool)
+	var sizes []float64
+	for _, page := range pages {
+		for _, b := range page.Blocks {
+			size := roundFontSize(b.FontSize)
+			if size >= bodySize*pymupdfHeadingRatio && !seen[size] {
+				seen[size] = true
+				sizes = append(sizes, size)
+			}
+		}
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(sizes)))
+
+	for i, size := range sizes {
+		levels[size] = min(i+1, pymupdfMaxHeadingLevel)
+	}
+	return levels
+}
+
+// roundFontSize rounds to the nearest half point so sizes that differ only
+// by rendering noise compare equal
+func roundFontSize(size float64) float64 {
+	return math.Round(size*2) / 2
+}
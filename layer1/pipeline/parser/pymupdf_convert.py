@@ -0,0 +1,102 @@
+#!/usr/bin/env python3
+"""
+PyMuPDF PDF extraction script.
+Called by the Go pipeline to extract text blocks with their font and
+position information. Outputs JSON to stdout.
+"""
+
+import json
+import os
+import sys
+from pathlib import Path
+
+# Set by the Go parser from ParserConfig.Options["pdf_password"]
+PASSWORD_ENV = "GEMARA_PDF_PASSWORD"
+
+# Exit status telling the Go parser that the pymupdf module is missing
+EXIT_MISSING_MODULE = 3
+
+# PyMuPDF span flag for bold text
+FLAG_BOLD = 16
+
+
+def dominant_span(spans: list) -> dict:
+    """Return the span covering the most characters."""
+    return max(spans, key=lambda span: len(span.get("text", "")))
+
+
+def convert_block(block: dict) -> dict:
+    """Convert a PyMuPDF text block into the shape the Go parser expects."""
+    lines = []
+    spans = []
+    for line in block.get("lines", []):
+        text = "".join(span.get("text", "") for span in line.get("spans", [])).strip()
+        if text:
+            lines.append(text)
+        spans.extend(span for span in line.get("spans", []) if span.get("text", "").strip())
+
+    if not lines:
+        return None
+
+    span = dominant_span(spans)
+    x0, y0, x1, y1 = block.get("bbox", (0, 0, 0, 0))
+    return {
+        "text": " ".join(lines),
+        "bbox": {"x1": x0, "y1": y0, "x2": x1, "y2": y1},
+        "font_size": round(span.get("size", 0), 2),
+        "font_name": span.get("font", ""),
+        "bold": bool(span.get("flags", 0) & FLAG_BOLD),
+    }
+
+
+def convert_pdf(input_path: str, password: str) -> dict:
+    """Extract the text blocks of each page with PyMuPDF."""
+    import fitz
+
+    output = {"status": "success", "pages": [], "errors": []}
+
+    with fitz.open(input_path) as doc:
+        if doc.needs_pass and not doc.authenticate(password):
+            raise RuntimeError("Incorrect password")
+
+        # Pages come from the document's page index, not form feeds in text
+        for index, page in enumerate(doc):
+            blocks = []
+            for block in page.get_text("dict").get("blocks", []):
+                if block.get("type", 0) != 0:
+                    continue  # Images
+                converted = convert_block(block)
+                if converted:
+                    blocks.append(converted)
+            output["pages"].append({"page_no": index + 1, "blocks": blocks})
+
+    return output
+
+
+def main():
+    if len(sys.argv) != 2:
+        print(json.dumps({"status": "error", "errors": [{"error_message": "Usage: pymupdf_convert.py <pdf_path>"}]}))
+        sys.exit(1)
+
+    input_path = sys.argv[1]
+
+    if not Path(input_path).exists():
+        print(json.dumps({"status": "error", "errors": [{"error_message": f"File not found: {input_path}"}]}))
+        sys.exit(1)
+
+    try:
+        import fitz  # noqa: F401
+    except ImportError as e:
+        print(json.dumps({"status": "error", "errors": [{"error_message": f"pymupdf module not found: {e}"}]}))
+        sys.exit(EXIT_MISSING_MODULE)
+
+    try:
+        result = convert_pdf(input_path, os.environ.get(PASSWORD_ENV, ""))
+        print(json.dumps(result))
+    except Exception as e:
+        print(json.dumps({"status": "error", "errors": [{"error_message": str(e)}]}))
+        sys.exit(1)
+
+
+if __name__ == "__main__":
+    main()
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,7 +14,9 @@ import (
 	"github.com/ossf/gemara/layer1/pipeline/types"
 )
 
-// Pre-compiled regexes for performance
+// Pre-compiled regexes for performance. These run over untrusted document
+// text; Go's RE2-based regexp matches in time linear in the input, so none
+// can backtrack catastrophically. Keep them compiled once at package level.
 var (
 	// Matches numbered headings like "1.", "1.1", "1.1.1", "1.1.1.1" followed by uppercase text
 	// Also matches ALL CAPS headings
@@ -33,11 +36,106 @@ var (
 
 	// Matches ordered list markers
 	orderedListRegex = regexp.MustCompile(`^[0-9]+\.`)
+
+	// Matches footnotes marked with a bracketed number ("[3] See ...")
+	bracketFootnoteRegex = regexp.MustCompile(`^\s*\[\d{1,3}\]\s+\S`)
+
+	// Matches a footnote number or superscript digits opening a line ("1 See ...", "² Ibid.")
+	numberedFootnoteRegex = regexp.MustCompile(`^\s*(\d{1,3}|[¹²³⁴⁵⁶⁷⁸⁹⁰]+)\s+\S`)
+
+	// Matches the header row of a revision/change-history table,
+	// e.g. "Date            Version                Description"
+	revisionHeaderRegex = regexp.MustCompile(`(?i)^date\s{2,}.*version`)
+
+	// Matches dates used in revision tables ("May 2018", "April 29, 2016", "2018-05-01", "05/2018")
+	revisionDateRegex = regexp.MustCompile(`(?i)^((jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.?\s+(\d{1,2},?\s+)?\d{4}|\d{4}-\d{1,2}(-\d{1,2})?|\d{1,2}[/.-](\d{1,2}[/.-])?\d{2,4})$`)
+
+	// Matches the gap between columns in layout-mode text
+	columnGapRegex = regexp.MustCompile(`\s{2,}`)
+
+	// Matches trailing dotted leaders with page numbers ("Chapter 1 ..... 15")
+	tocTrailingDotsRegex = regexp.MustCompile(`\s*\.{3,}[\s\d]*$`)
+
+	// Matches inline dotted leaders separating sections
+	tocInlineDotsRegex = regexp.MustCompile(`\s+\.{3,}\s+`)
+
+	// Matches runs of whitespace collapsed by normalizeWhitespace
+	multiSpaceRegex = regexp.MustCompile(`\s{3,}`)
+
+	// Matches page headers, footers and copyright notices
+	pageHeaderFooterPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)page\s+\d+`),              // "Page 2", "page 123"
+		regexp.MustCompile(`(?i)©\s*\d{4}`),               // Copyright notice
+		regexp.MustCompile(`(?i)all\s+rights\s+reserved`), // Rights notice
+		regexp.MustCompile(`(?i)^\s*\d+\s*$`),             // Just a page number
+	}
+
+	// Matches header rows of tables that are skipped
+	tableHeaderPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)^requirement\s{4,}testing`), // Testing procedures table
+		regexp.MustCompile(`(?i)^pci\s+dss\s+requirement`), // Requirements table
+		regexp.MustCompile(`(?i)^guidance\s{4,}`),          // Guidance tables
+	}
+)
+
+// pdftotext extraction modes, selected via ParserConfig.Options["pdftotext_mode"]
+const (
+	PdftotextModeLayout = "layout" // Preserve physical layout (good for tables)
+	PdftotextModeRaw    = "raw"    // Content stream order (better for multi-column text)
+	PdftotextModeAuto   = "auto"   // Run both and keep the more structured result
 )
 
 // SimpleParser uses pdftotext (poppler-utils) for basic PDF parsing
 type SimpleParser struct {
 	ParserBase
+	headingPatterns []*regexp.Regexp // Extra patterns from Options["heading_patterns"]
+}
+
+// Configure sets the parser configuration, compiling any custom heading
+// patterns so a bad regex fails here rather than mid-parse
+func (p *SimpleParser) Configure(config types.ParserConfig) error {
+	if err := p.ParserBase.Configure(config); err != nil {
+		return err
+	}
+	patterns, err := compileHeadingPatterns(config.Options["heading_patterns"])
+	if err != nil {
+		return err
+	}
+	p.headingPatterns = patterns
+	return nil
+}
+
+// compileHeadingPatterns compiles a comma-separated list of heading regexes.
+// Empty entries are ignored; a pattern that needs a literal comma can write
+// it as \x2C.
+func compileHeadingPatterns(list string) ([]*regexp.Regexp, error) {
+	var patterns []*regexp.Regexp
+	for _, expr := range strings.Split(list, ",") {
+		expr = strings.TrimSpace(expr)
+		if expr == "" {
+			continue
+		}
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid heading_patterns entry %q: %w", expr, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// isHeading reports whether a trimmed line matches the default heading
+// pattern or any custom one
+func (p *SimpleParser) isHeading(line string) bool {
+	if headingRegex.MatchString(line) {
+		return true
+	}
+	for _, pattern := range p.headingPatterns {
+		if pattern.MatchString(line) {
+			return true
+		}
+	}
+	return false
 }
 
 // NewSimpleParser creates a new simple parser
@@ -46,9 +144,27 @@ func NewSimpleParser(config types.ParserConfig) (*SimpleParser, error) {
 	if err := parser.Configure(config); err != nil {
 		return nil, err
 	}
+	switch parser.pdftotextMode() {
+	case PdftotextModeLayout, PdftotextModeRaw, PdftotextModeAuto:
+	default:
+		return nil, fmt.Errorf("unsupported pdftotext_mode: %s (use layout, raw, or auto)", parser.pdftotextMode())
+	}
+	switch parser.columnsMode() {
+	case "", ColumnsAuto, ColumnsOne, ColumnsTwo:
+	default:
+		return nil, fmt.Errorf("unsupported columns: %s (use auto, 1, or 2)", parser.columnsMode())
+	}
 	return parser, nil
 }
 
+// pdftotextMode returns the configured pdftotext mode (default: layout)
+func (p *SimpleParser) pdftotextMode() string {
+	if mode := p.config.Options["pdftotext_mode"]; mode != "" {
+		return mode
+	}
+	return PdftotextModeLayout
+}
+
 // Name returns the parser name
 func (p *SimpleParser) Name() string {
 	return "simple"
@@ -60,43 +176,122 @@ func (p *SimpleParser) Parse(filePath string) (*types.ParsedDocument, error) {
 	if _, err := exec.LookPath("pdftotext"); err != nil {
 		return nil, fmt.Errorf("pdftotext not found (install poppler-utils): %w", err)
 	}
+	if err := p.checkInputSize(filePath); err != nil {
+		return nil, err
+	}
+
+	var pages []types.Page
+	switch mode := p.pdftotextMode(); {
+	case p.columnsMode() != "":
+		text, err := p.runColumnExtraction(filePath)
+		if err != nil {
+			return nil, err
+		}
+		pages = p.parseTextContent(text)
+	case mode == PdftotextModeAuto:
+		layoutText, err := p.runPdftotext(filePath, PdftotextModeLayout)
+		if err != nil {
+			return nil, err
+		}
+		rawText, err := p.runPdftotext(filePath, PdftotextModeRaw)
+		if err != nil {
+			return nil, err
+		}
+		pages, _ = p.pickStructuredPages(layoutText, rawText)
+	default:
+		text, err := p.runPdftotext(filePath, mode)
+		if err != nil {
+			return nil, err
+		}
+		pages = p.parseTextContent(text)
+	}
 
+	doc := &types.ParsedDocument{
+		Metadata: types.ParsedMetadata{
+			SourceFile: filePath,
+			Parser:     "simple-v1.0",
+			ParsedAt:   time.Now(),
+		},
+		Pages: pages,
+	}
+	p.normalizeListMarkers(doc.Pages)
+
+	return doc, nil
+}
+
+// passwordArgs returns the pdftotext flags for the configured PDF password,
+// tried as both the user and the owner password
+func (p *SimpleParser) passwordArgs() []string {
+	password := p.pdfPassword()
+	if password == "" {
+		return nil
+	}
+	return []string{"-upw", password, "-opw", password}
+}
+
+// runPdftotext extracts text from a PDF using the given pdftotext mode
+func (p *SimpleParser) runPdftotext(filePath, mode string) (string, error) {
 	// Create temp file for text output
 	tempDir := p.config.TempDir
 	if tempDir == "" {
 		tempDir = os.TempDir()
 	}
 	
-	textFile := filepath.Join(tempDir, fmt.Sprintf("parsed-%d.txt", time.Now().Unix()))
+	textFile := filepath.Join(tempDir, fmt.Sprintf("parsed-%d-%s.txt", time.Now().Unix(), mode))
 	defer func() {
 		if !p.config.KeepTempFiles {
 			_ = os.Remove(textFile) // Ignore cleanup errors
 		}
 	}()
 
-	// Run pdftotext with layout preservation
-	cmd := exec.Command("pdftotext", "-layout", filePath, textFile)
+	args := append([]string{"-" + mode}, p.passwordArgs()...)
+	cmd := exec.Command("pdftotext", append(args, filePath, textFile)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("pdftotext failed: %w", err)
+		if pwErr := passwordError(filePath, stderr.String()); pwErr != nil {
+			return "", pwErr
+		}
+		return "", fmt.Errorf("pdftotext (%s) failed: %w", mode, err)
 	}
 
 	// Read extracted text
-	content, err := os.ReadFile(textFile)
+	content, err := p.readTextFile(textFile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read text file: %w", err)
+		return "", err
 	}
 
-	// Parse the text into structured blocks
-	doc := &types.ParsedDocument{
-		Metadata: types.ParsedMetadata{
-			SourceFile: filePath,
-			Parser:     "simple-v1.0",
-			ParsedAt:   time.Now(),
-		},
-		Pages: p.parseTextContent(string(content)),
+	return string(content), nil
+}
+
+// pickStructuredPages parses both layout and raw extractions and returns the
+// pages with the more recognizable structure, along with the chosen mode.
+// Ties go to layout mode, which preserves tables.
+func (p *SimpleParser) pickStructuredPages(layoutText, rawText string) ([]types.Page, string) {
+	layoutPages := p.parseTextContent(layoutText)
+	rawPages := p.parseTextContent(rawText)
+
+	if structureScore(rawPages) > structureScore(layoutPages) {
+		return rawPages, PdftotextModeRaw
 	}
+	return layoutPages, PdftotextModeLayout
+}
 
-	return doc, nil
+// structureScore rates how much recognizable structure parsed pages contain.
+// Headings weigh most since they drive segmentation; list items add a little.
+func structureScore(pages []types.Page) int {
+	score := 0
+	for _, page := range pages {
+		for _, block := range page.Blocks {
+			switch block.Type {
+			case types.BlockTypeHeading:
+				score += 3
+			case types.BlockTypeList:
+				score++
+			}
+		}
+	}
+	return score
 }
 
 // parseTextContent converts plain text into structured blocks
@@ -112,7 +307,54 @@ func (p *SimpleParser) parseTextContent(content string) []types.Page {
 	var currentBlock *types.Block
 	var currentText strings.Builder
 	
-	for _, line := range lines {
+	// Revision tables are collected row by row until a non-row line appears
+	var revisionTable *types.TableData
+	var revisionTail strings.Builder // Wrapped text for the last row's final cell
+	flushRevisionTail := func() {
+		if revisionTail.Len() > 0 {
+			last := revisionTable.Rows[len(revisionTable.Rows)-1]
+			last[len(last)-1] += revisionTail.String()
+			revisionTail.Reset()
+		}
+	}
+	flushRevisionTable := func() {
+		if revisionTable != nil {
+			flushRevisionTail()
+		}
+		if revisionTable != nil && len(revisionTable.Rows) > 1 {
+			currentPage.Blocks = append(currentPage.Blocks, types.Block{
+				Type:      types.BlockTypeTable,
+				Text:      types.RevisionTableTitle,
+				TableData: revisionTable,
+			})
+		}
+		revisionTable = nil
+	}
+	
+	footnotes := footnoteStarts(lines)
+	margins := pageMargins(lines)
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if revisionTable != nil {
+			if emptyRegex.MatchString(line) {
+				continue
+			}
+			if !strings.Contains(line, "\f") {
+				if row := splitColumns(line); len(row) >= 2 && revisionDateRegex.MatchString(row[0]) {
+					flushRevisionTail()
+					revisionTable.Rows = append(revisionTable.Rows, row)
+					continue
+				}
+				// Wrapped descriptions continue on indented lines
+				if len(revisionTable.Rows) > 1 && strings.HasPrefix(line, " ") && !isPageHeaderFooter(line) {
+					revisionTail.WriteString(" ")
+					revisionTail.WriteString(normalizeWhitespace(line))
+					continue
+				}
+			}
+			flushRevisionTable()
+		}
+		
 		// Detect page breaks (form feed character)
 		if strings.Contains(line, "\f") {
 			// Flush current block
@@ -154,7 +396,63 @@ func (p *SimpleParser) parseTextContent(content string) []types.Page {
 			continue
 		}
 		
-		// Skip page headers, footers, copyright notices, table headers
+		// Start collecting a revision history table
+		if revisionHeaderRegex.MatchString(strings.TrimSpace(line)) {
+			if currentBlock != nil && currentText.Len() > 0 {
+				currentBlock.Text = strings.TrimSpace(currentText.String())
+				currentPage.Blocks = append(currentPage.Blocks, *currentBlock)
+			}
+			currentBlock = nil
+			currentText.Reset()
+			revisionTable = &types.TableData{Rows: [][]string{splitColumns(line)}}
+			continue
+		}
+		
+		// Footnotes start their own block so they don't run into the
+		// paragraph above; wrapped lines are appended below as usual
+		if footnotes[i] {
+			if currentBlock != nil && currentText.Len() > 0 {
+				currentBlock.Text = strings.TrimSpace(currentText.String())
+				currentPage.Blocks = append(currentPage.Blocks, *currentBlock)
+			}
+			currentBlock = &types.Block{Type: types.BlockTypeFootnote}
+			currentText.Reset()
+			currentText.WriteString(strings.TrimSpace(cleanText(line)))
+			continue
+		}
+		
+		// Keep indented command and configuration examples verbatim
+		if code, n := collectCode(lines[i:], margins[i]); code != nil {
+			if currentBlock != nil && currentText.Len() > 0 {
+				currentBlock.Text = strings.TrimSpace(currentText.String())
+				currentPage.Blocks = append(currentPage.Blocks, *currentBlock)
+			}
+			currentBlock = nil
+			currentText.Reset()
+			currentPage.Blocks = append(currentPage.Blocks, *code)
+			i += n - 1
+			continue
+		}
+		
+		// Collect aligned columns into a table block
+		if table, n := p.collectTable(lines[i:]); table != nil {
+			if currentBlock != nil && currentText.Len() > 0 {
+				currentBlock.Text = strings.TrimSpace(currentText.String())
+				currentPage.Blocks = append(currentPage.Blocks, *currentBlock)
+			}
+			currentBlock = nil
+			currentText.Reset()
+			currentPage.Blocks = append(currentPage.Blocks, types.Block{
+				Type:      types.BlockTypeTable,
+				Text:      tableBlockText,
+				TableData: table,
+			})
+			i += n - 1
+			continue
+		}
+		
+		// Skip page headers, footers, copyright notices, and the header
+		// rows of tables whose body couldn't be collected
 		if isPageHeaderFooter(line) || isTableHeader(line) {
 			continue
 		}
@@ -166,7 +464,7 @@ func (p *SimpleParser) parseTextContent(content string) []types.Page {
 		}
 		
 		// Detect headings
-		if headingRegex.MatchString(strings.TrimSpace(line)) {
+		if p.isHeading(strings.TrimSpace(line)) {
 			// Flush previous block
 			if currentBlock != nil && currentText.Len() > 0 {
 				currentBlock.Text = strings.TrimSpace(currentText.String())
@@ -234,6 +532,7 @@ func (p *SimpleParser) parseTextContent(content string) []types.Page {
 	}
 	
 	// Flush final block
+	flushRevisionTable()
 	if currentBlock != nil && currentText.Len() > 0 {
 		currentBlock.Text = strings.TrimSpace(currentText.String())
 		currentPage.Blocks = append(currentPage.Blocks, *currentBlock)
@@ -247,6 +546,50 @@ func (p *SimpleParser) parseTextContent(content string) []types.Page {
 	return pages
 }
 
+// footnoteRegionLines is how many non-empty lines at the bottom of a page
+// are searched for numbered footnotes
+const footnoteRegionLines = 8
+
+// footnoteStarts returns the indexes of lines that open a footnote: lines
+// starting with a bracketed number anywhere, and lines starting with a plain
+// or superscript number in a page's bottom region. The bottom region begins
+// at the highest such numbered line there that follows a blank line, so
+// numbered lines in running text are left alone.
+func footnoteStarts(lines []string) map[int]bool {
+	starts := make(map[int]bool)
+	pageStart := 0
+	for i := 0; i <= len(lines); i++ {
+		if i < len(lines) && !strings.Contains(lines[i], "\f") {
+			if bracketFootnoteRegex.MatchString(lines[i]) {
+				starts[i] = true
+			}
+			continue
+		}
+		
+		// Find the top of the page's footnote region
+		page := lines[pageStart:i]
+		first, seen := -1, 0
+		for j := len(page) - 1; j >= 0 && seen < footnoteRegionLines; j-- {
+			if emptyRegex.MatchString(page[j]) || isPageHeaderFooter(page[j]) {
+				continue
+			}
+			seen++
+			if numberedFootnoteRegex.MatchString(page[j]) && j > 0 && emptyRegex.MatchString(page[j-1]) {
+				first = j
+			}
+		}
+		if first >= 0 {
+			for j := first; j < len(page); j++ {
+				if numberedFootnoteRegex.MatchString(page[j]) && !isPageHeaderFooter(page[j]) {
+					starts[pageStart+j] = true
+				}
+			}
+		}
+		pageStart = i + 1
+	}
+	return starts
+}
+
 // detectHeadingLevel determines the heading level based on formatting
 func (p *SimpleParser) detectHeadingLevel(line string) int {
 	// Check for numbered headings (1., 1.1, 1.1.1, etc.)
@@ -280,9 +623,12 @@ func (p *SimpleParser) detectIndentLevel(line string) int {
 
 // ParseTextFile parses a plain text file (useful for testing)
 func (p *SimpleParser) ParseTextFile(filePath string) (*types.ParsedDocument, error) {
-	content, err := os.ReadFile(filePath)
+	if err := p.checkInputSize(filePath); err != nil {
+		return nil, err
+	}
+	content, err := p.readTextFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read text file: %w", err)
+		return nil, err
 	}
 
 	doc := &types.ParsedDocument{
@@ -291,8 +637,9 @@ func (p *SimpleParser) ParseTextFile(filePath string) (*types.ParsedDocument, er
 			Parser:     "simple-v1.0",
 			ParsedAt:   time.Now(),
 		},
-		Pages: p.parseTextContent(string(content)),
+		Pages: p.parseTextContent(orderLayoutColumns(string(content), p.columnsMode())),
 	}
+	p.normalizeListMarkers(doc.Pages)
 
 	return doc, nil
 }
@@ -338,16 +685,16 @@ func ExtractPDFMetadata(filePath string) (map[string]string, error) {
 // cleanTOCDots removes dotted leader patterns commonly found in tables of contents
 // These patterns look like: "Chapter 1 .......... 15" or "1.1 Overview ... 23"
 func cleanTOCDots(line string) string {
-	// Pattern: multiple dots (3+) optionally followed by spaces and page numbers
-	dotPattern := regexp.MustCompile(`\s*\.{3,}[\s\d]*$`)
+	// Most lines have no leaders; skip the regexes entirely
+	if !strings.Contains(line, "...") {
+		return strings.TrimSpace(line)
+	}
 	
 	// Remove trailing dot patterns with page numbers
-	cleaned := dotPattern.ReplaceAllString(line, "")
+	cleaned := tocTrailingDotsRegex.ReplaceAllString(line, "")
 	
 	// Also clean inline dots that separate sections
-	// Pattern: space + 3+ dots + space
-	inlineDotPattern := regexp.MustCompile(`\s+\.{3,}\s+`)
-	cleaned = inlineDotPattern.ReplaceAllString(cleaned, " - ")
+	cleaned = tocInlineDotsRegex.ReplaceAllString(cleaned, " - ")
 	
 	return strings.TrimSpace(cleaned)
 }
@@ -356,16 +703,8 @@ func cleanTOCDots(line string) string {
 func isPageHeaderFooter(line string) bool {
 	trimmed := strings.TrimSpace(line)
 	
-	// Common page footer patterns
-	patterns := []string{
-		`(?i)page\s+\d+`,                               // "Page 2", "page 123"
-		`(?i)©\s*\d{4}`,                                // Copyright notice
-		`(?i)all\s+rights\s+reserved`,                  // Rights notice
-		`(?i)^\s*\d+\s*$`,                              // Just a page number
-	}
-	
-	for _, pattern := range patterns {
-		if matched, _ := regexp.MatchString(pattern, trimmed); matched {
+	for _, pattern := range pageHeaderFooterPatterns {
+		if pattern.MatchString(trimmed) {
 			return true
 		}
 	}
@@ -377,17 +716,8 @@ func isPageHeaderFooter(line string) bool {
 func isTableHeader(line string) bool {
 	trimmed := strings.TrimSpace(line)
 	
-	// Common table header patterns with lots of spacing
-	// e.g., "Date            Version                Description"
-	tableHeaderPatterns := []string{
-		`(?i)^date\s{4,}version`,                       // Document change table
-		`(?i)^requirement\s{4,}testing`,               // Testing procedures table
-		`(?i)^pci\s+dss\s+requirement`,                // Requirements table
-		`(?i)^guidance\s{4,}`,                         // Guidance tables
-	}
-	
 	for _, pattern := range tableHeaderPatterns {
-		if matched, _ := regexp.MatchString(pattern, trimmed); matched {
+		if pattern.MatchString(trimmed) {
 			return true
 		}
 	}
@@ -395,11 +725,86 @@ func isTableHeader(line string) bool {
 	return false
 }
 
+// tableBlockText is the Text of table blocks collected from aligned columns
+const tableBlockText = "[Table]"
+
+// maxColumnDrift is how far, in characters, a column may start from where it
+// started in the table's first row
+const maxColumnDrift = 2
+
+// collectTable reads a table of aligned columns from the start of lines:
+// consecutive non-empty lines split by runs of 2+ spaces into the same
+// number (2 or more) of cells starting at about the same positions. It
+// returns nil unless at least two rows agree, along with the number of
+// lines consumed. Headings end a table so they still drive segmentation.
+func (p *SimpleParser) collectTable(lines []string) (*types.TableData, int) {
+	var rows [][]string
+	var starts []int
+	for _, line := range lines {
+		if emptyRegex.MatchString(line) || strings.Contains(line, "\f") || tocDotPattern.MatchString(line) ||
+			isPageHeaderFooter(line) || p.isHeading(strings.TrimSpace(line)) {
+			break
+		}
+		cells, cellStarts := columnCells(line)
+		if len(cells) < 2 {
+			break
+		}
+		if starts == nil {
+			starts = cellStarts
+		} else if !columnsAligned(starts, cellStarts) {
+			break
+		}
+		rows = append(rows, cells)
+	}
+	if len(rows) < 2 {
+		return nil, 0
+	}
+	return &types.TableData{Rows: rows}, len(rows)
+}
+
+// columnCells splits a layout-mode line into cells, returning each cell's
+// starting position in the line
+func columnCells(line string) ([]string, []int) {
+	line = strings.TrimRight(line, " \t")
+	text := strings.TrimLeft(line, " \t")
+	if text == "" {
+		return nil, nil
+	}
+	indent := len(line) - len(text)
+
+	starts := []int{indent}
+	for _, gap := range columnGapRegex.FindAllStringIndex(text, -1) {
+		starts = append(starts, indent+gap[1])
+	}
+	return columnGapRegex.Split(text, -1), starts
+}
+
+// columnsAligned reports whether a row's cells start where the first row's did
+func columnsAligned(first, row []int) bool {
+	if len(first) != len(row) {
+		return false
+	}
+	for i := range first {
+		if drift := row[i] - first[i]; drift > maxColumnDrift || drift < -maxColumnDrift {
+			return false
+		}
+	}
+	return true
+}
+
+// splitColumns splits a layout-mode line into its column cells
+func splitColumns(line string) []string {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" {
+		return nil
+	}
+	return columnGapRegex.Split(trimmed, -1)
+}
+
 // normalizeWhitespace collapses multiple spaces into single spaces
 func normalizeWhitespace(text string) string {
 	// Replace multiple spaces with single space
-	multiSpacePattern := regexp.MustCompile(`\s{3,}`)
-	cleaned := multiSpacePattern.ReplaceAllString(text, " ")
+	cleaned := multiSpaceRegex.ReplaceAllString(text, " ")
 	
 	// Clean up spacing around punctuation
 	cleaned = strings.TrimSpace(cleaned)
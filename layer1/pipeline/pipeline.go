@@ -0,0 +1,561 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ossf/gemara/layer1"
+	"github.com/ossf/gemara/layer1/pipeline/converter"
+	"github.com/ossf/gemara/layer1/pipeline/llm"
+	"github.com/ossf/gemara/layer1/pipeline/parser"
+	"github.com/ossf/gemara/layer1/pipeline/segmenter"
+	"github.com/ossf/gemara/layer1/pipeline/storage"
+	"github.com/ossf/gemara/layer1/pipeline/types"
+	"github.com/ossf/gemara/layer1/pipeline/validator"
+)
+
+// Errors returned by Run so callers can classify failures with errors.Is
+var (
+	ErrInvalidConfig    = errors.New("invalid pipeline configuration")
+	ErrValidationFailed = errors.New("schema validation failed")
+	ErrStorage          = errors.New("storage error")
+)
+
+// Config drives a pipeline run without relying on CLI flags or storage
+type Config struct {
+	DocumentID string // Defaults to the input file name without extension
+
+	// Input is read from InputPath, or from InputData when InputPath is empty.
+	// InputName names in-memory input (e.g. "standard.pdf") and defaults to "input.pdf".
+	InputPath string
+	InputData []byte
+	InputName string
+
+	Parser    types.ParserConfig
+	Segmenter types.SegmenterConfig
+	Enhance   *types.LLMConfig // Optional; nil skips LLM enhancement
+
+	// MinChangeConfidence drops enhancement changes the LLM is less sure
+	// of (0-1); 0 keeps them all
+	MinChangeConfidence float64
+
+	Strict         bool // Strict schema validation
+	SkipValidation bool // Convert without the validation gate
+	Coverage       bool // Include a coverage report in the result
+
+	// ValidatorOptions configure the validation gate beyond Strict
+	ValidatorOptions []validator.Option
+
+	// NormalizeIDs rewrites all IDs to a canonical scheme; nil preserves them
+	NormalizeIDs *converter.IDScheme
+
+	// SynthesizeParts gives guidelines without parts a single part built
+	// from their objective and recommendations
+	SynthesizeParts bool
+
+	// Storage persists intermediates, the final document and validation
+	// reports. When nil the pipeline runs entirely in memory.
+	Storage      *storage.Storage
+	OutputFormat string // Final document format when Storage is set (default: yaml)
+	SaveReport   bool   // Save validation reports when Storage is set
+
+	// SaveInvalid stores a document that fails validation under invalid/
+	// when Storage is set, so it can be inspected; the run still fails
+	SaveInvalid bool
+
+	// SaveLLMArtifacts stores the raw LLM prompt and response next to the
+	// post-enhance version when Storage is set
+	SaveLLMArtifacts bool
+
+	// AllowRemovals accepts an enhancement that removes categories or
+	// guidelines; by default such an enhancement fails the run
+	AllowRemovals bool
+
+	// Resume reuses the latest stored parsed and segmented versions when
+	// Storage is set and the input is unchanged (by checksum), re-running
+	// only the later stages. Stored versions must come from the same parser
+	// and segmenter; their other options are not compared.
+	Resume bool
+
+	// Logf receives progress messages; nil discards them
+	Logf func(format string, args ...interface{})
+}
+
+func (c *Config) logf(format string, args ...interface{}) {
+	if c.Logf != nil {
+		c.Logf(format, args...)
+	}
+}
+
+// Run executes parse -> segment -> (enhance) -> convert and returns the
+// combined result. The result is returned even on failure, populated up to
+// the failing stage.
+func Run(ctx context.Context, cfg Config) (*PipelineResult, error) {
+	result := &PipelineResult{
+		DocumentID: cfg.DocumentID,
+		StartedAt:  time.Now(),
+	}
+	fail := func(err error) (*PipelineResult, error) {
+		result.Error = err.Error()
+		result.CompletedAt = time.Now()
+		return result, err
+	}
+
+	if cfg.OutputFormat == "" {
+		cfg.OutputFormat = "yaml"
+	}
+	if cfg.DocumentID == "" {
+		cfg.DocumentID = defaultDocumentID(cfg)
+		result.DocumentID = cfg.DocumentID
+	}
+
+	var parsed *types.ParsedDocument
+	var segmented *types.SegmentedDocument
+	var err error
+	if cfg.Resume && cfg.Storage != nil {
+		parsed, segmented = resumeIntermediates(cfg)
+	}
+
+	// Parse
+	if parsed != nil {
+		cfg.logf("Resuming %s from parsed v%d (input unchanged)\n", cfg.DocumentID, parsed.Metadata.Version)
+	} else {
+		parsed, err = ParseInput(cfg)
+		if err != nil {
+			return fail(err)
+		}
+		if cfg.Storage != nil {
+			if err := cfg.Storage.SaveParsed(parsed); err != nil {
+				return fail(fmt.Errorf("%w: failed to save parsed document: %w", ErrStorage, err))
+			}
+		}
+	}
+	result.DocumentID = parsed.Metadata.DocumentID
+	cfg.DocumentID = parsed.Metadata.DocumentID
+	result.ParsedDocument = parsed
+	result.Parsed = NewParsedStats(parsed)
+	cfg.logf("Parsed %s: %d pages, %d blocks\n", cfg.DocumentID, result.Parsed.Pages, result.Parsed.Blocks)
+
+	// Segment
+	if segmented != nil {
+		cfg.logf("Resuming %s from segmented v%d\n", cfg.DocumentID, segmented.Metadata.Version)
+	} else {
+		segmented, err = Segment(parsed, cfg.Segmenter)
+		if err != nil {
+			return fail(err)
+		}
+		if cfg.Storage != nil {
+			if err := cfg.Storage.SaveSegmented(segmented); err != nil {
+				return fail(fmt.Errorf("%w: failed to save segmented document: %w", ErrStorage, err))
+			}
+		}
+	}
+	cfg.logf("Segmented %s: %d categories\n", cfg.DocumentID, len(segmented.Categories))
+	for _, warning := range segmented.Warnings {
+		cfg.logf("Warning: %s\n", warning)
+	}
+
+	// Enhance (optional)
+	if cfg.Enhance != nil {
+		preEnhanceVersion := segmented.Metadata.Version
+		enhanced, enhancement, err := Enhance(ctx, segmented, *cfg.Enhance)
+		if err != nil {
+			return fail(err)
+		}
+		enhanced, dropped, err := FilterEnhancement(enhancement, cfg.MinChangeConfidence)
+		if err != nil {
+			return fail(err)
+		}
+		if dropped > 0 {
+			cfg.logf("Dropped %d changes below confidence %.2f\n", dropped, cfg.MinChangeConfidence)
+		}
+		if err := llm.CheckRetention(segmented, enhanced); err != nil {
+			if !cfg.AllowRemovals {
+				return fail(err)
+			}
+			cfg.logf("Warning: %v\n", err)
+		}
+		if cfg.Storage != nil {
+			label := fmt.Sprintf("post-enhance-%s (pre-enhance: v%d)", cfg.Enhance.Provider, preEnhanceVersion)
+			if err := cfg.Storage.SaveSegmentedWithLabel(enhanced, label); err != nil {
+				return fail(fmt.Errorf("%w: failed to save enhanced document: %w", ErrStorage, err))
+			}
+			if cfg.SaveLLMArtifacts {
+				artifact := NewEnhancementArtifact(cfg.DocumentID, enhanced.Metadata.Version, preEnhanceVersion, enhancement)
+				if err := cfg.Storage.SaveEnhancementArtifact(artifact); err != nil {
+					return fail(fmt.Errorf("%w: failed to save LLM artifact: %w", ErrStorage, err))
+				}
+			}
+		}
+		result.Enhancement = NewEnhancementStats(enhancement)
+		segmented = enhanced
+		cfg.logf("Enhanced %s with %s: %d changes\n", cfg.DocumentID, enhancement.Provider, len(enhancement.Changes))
+	}
+	result.SegmentedDocument = segmented
+	result.Segmented = NewSegmentedStats(segmented)
+
+	if cfg.Coverage {
+		result.Coverage = validator.NewCoverageAnalyzer(cfg.Strict).AnalyzeFromSegmented(parsed, segmented)
+	}
+
+	// Convert and validate
+	var convOpts []converter.Option
+	if cfg.NormalizeIDs != nil {
+		convOpts = append(convOpts, converter.WithNormalizedIDs(*cfg.NormalizeIDs))
+	}
+	if cfg.SynthesizeParts {
+		convOpts = append(convOpts, converter.WithSynthesizeParts(true))
+	}
+	conv := converter.NewConverter(convOpts...)
+	layer1Doc, validation, err := ConvertWith(conv, segmented, cfg.Strict, !cfg.SkipValidation, cfg.ValidatorOptions...)
+	result.IDIssues = conv.IDIssues()
+	for _, issue := range result.IDIssues {
+		cfg.logf("Warning: %s\n", issue)
+	}
+	result.Layer1 = layer1Doc
+	result.Validation = validation
+	if err != nil && layer1Doc == nil {
+		return fail(err)
+	}
+
+	if cfg.Storage != nil {
+		var report *storage.ValidationReport
+		if cfg.SaveReport {
+			report = NewValidationReport(cfg.DocumentID, "convert", segmented.Metadata.Version, cfg.Strict, validation)
+			report.Toolchain = NewToolchain(parsed, segmented, conv)
+		}
+		if err != nil {
+			// Keep the report of a failed validation for reference
+			if report != nil {
+				if saveErr := cfg.Storage.SaveValidationReport(report); saveErr != nil {
+					cfg.logf("Warning: failed to save validation report: %v\n", saveErr)
+				}
+			}
+			if cfg.SaveInvalid {
+				if path, saveErr := cfg.Storage.SaveInvalid(cfg.DocumentID, layer1Doc, cfg.OutputFormat); saveErr != nil {
+					cfg.logf("Warning: failed to save invalid document: %v\n", saveErr)
+				} else {
+					cfg.logf("Invalid document saved to %s\n", path)
+				}
+			}
+			return fail(err)
+		}
+		if saveErr := cfg.Storage.SaveFinalWithValidation(cfg.DocumentID, layer1Doc, cfg.OutputFormat, report); saveErr != nil {
+			return fail(fmt.Errorf("%w: failed to save final document: %w", ErrStorage, saveErr))
+		}
+		if saveErr := cfg.Storage.SaveProvenance(cfg.DocumentID, conv.Provenance()); saveErr != nil {
+			return fail(fmt.Errorf("%w: failed to save provenance: %w", ErrStorage, saveErr))
+		}
+	} else if err != nil {
+		return fail(err)
+	}
+	cfg.logf("Converted %s: %d categories\n", cfg.DocumentID, len(layer1Doc.Categories))
+
+	result.CompletedAt = time.Now()
+	return result, nil
+}
+
+// ParseInput parses the configured input file or in-memory data. The
+// returned document's ID is set from cfg.DocumentID or the input name.
+func ParseInput(cfg Config) (*types.ParsedDocument, error) {
+	p, err := parser.NewParser(cfg.Parser)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	inputPath := cfg.InputPath
+	inputName := cfg.InputName
+	switch {
+	case inputPath != "":
+		if inputName == "" {
+			inputName = filepath.Base(inputPath)
+		}
+	case len(cfg.InputData) > 0:
+		if inputName == "" {
+			inputName = "input.pdf"
+		}
+		// Parsers work on files, so stage in-memory input in a temp file
+		tempDir := cfg.Parser.TempDir
+		if tempDir != "" {
+			if err := os.MkdirAll(tempDir, 0755); err != nil {
+				return nil, fmt.Errorf("failed to create temp directory: %w", err)
+			}
+		}
+		f, err := os.CreateTemp(tempDir, "input-*"+filepath.Ext(inputName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to stage input data: %w", err)
+		}
+		defer func() { _ = os.Remove(f.Name()) }()
+		if _, err := f.Write(cfg.InputData); err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("failed to stage input data: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return nil, fmt.Errorf("failed to stage input data: %w", err)
+		}
+		inputPath = f.Name()
+	default:
+		return nil, fmt.Errorf("%w: an input path or input data is required", ErrInvalidConfig)
+	}
+
+	doc, err := p.Parse(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("parsing failed: %w", err)
+	}
+	if doc.Metadata.SourceChecksum, err = fileChecksum(inputPath); err != nil {
+		return nil, fmt.Errorf("failed to checksum input: %w", err)
+	}
+
+	doc.Metadata.DocumentID = cfg.DocumentID
+	if doc.Metadata.DocumentID == "" {
+		doc.Metadata.DocumentID = defaultDocumentID(cfg)
+	}
+	if cfg.InputPath == "" {
+		doc.Metadata.SourceFile = inputName
+	}
+
+	return doc, nil
+}
+
+// resumeIntermediates returns the latest stored parsed document if it was
+// produced from the same input bytes by the configured parser, and the
+// latest plain (non-enhanced) segmented version if it was segmented from
+// that parsed version by the configured segmenter. Either is nil when it
+// can't be reused.
+func resumeIntermediates(cfg Config) (*types.ParsedDocument, *types.SegmentedDocument) {
+	checksum, err := inputChecksum(cfg)
+	if err != nil {
+		return nil, nil
+	}
+	p, err := parser.NewParser(cfg.Parser)
+	if err != nil {
+		return nil, nil
+	}
+	parsed, err := cfg.Storage.LoadParsed(cfg.DocumentID, 0)
+	// Stored parser names carry a version suffix, e.g. "simple-v1.0"
+	if err != nil || parsed.Metadata.SourceChecksum != checksum || !strings.HasPrefix(parsed.Metadata.Parser, p.Name()) {
+		return nil, nil
+	}
+
+	seg, err := segmenter.NewSegmenter(cfg.Segmenter)
+	if err != nil {
+		return parsed, nil
+	}
+	versions, err := cfg.Storage.ListVersions(cfg.DocumentID, "segmented")
+	if err != nil {
+		return parsed, nil
+	}
+	for _, v := range versions {
+		// Labeled versions are enhancement output, not plain segmentation
+		if v.Description != "" {
+			continue
+		}
+		segmented, err := cfg.Storage.LoadSegmented(cfg.DocumentID, v.Version)
+		if err != nil || segmented.Metadata.SourceVersion != parsed.Metadata.Version || segmented.Metadata.Segmenter != seg.Name() {
+			return parsed, nil
+		}
+		return parsed, segmented
+	}
+	return parsed, nil
+}
+
+// inputChecksum returns the SHA-256 of the configured input
+func inputChecksum(cfg Config) (string, error) {
+	if cfg.InputPath != "" {
+		return fileChecksum(cfg.InputPath)
+	}
+	if len(cfg.InputData) == 0 {
+		return "", fmt.Errorf("%w: an input path or input data is required", ErrInvalidConfig)
+	}
+	sum := sha256.Sum256(cfg.InputData)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// fileChecksum returns the hex-encoded SHA-256 of a file's contents
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// defaultDocumentID derives a document ID from the input name
+func defaultDocumentID(cfg Config) string {
+	name := cfg.InputName
+	if name == "" && cfg.InputPath != "" {
+		name = filepath.Base(cfg.InputPath)
+	}
+	if name == "" && len(cfg.InputData) > 0 {
+		name = "input.pdf"
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// Segment segments a parsed document using the configured segmenter
+func Segment(parsed *types.ParsedDocument, cfg types.SegmenterConfig) (*types.SegmentedDocument, error) {
+	seg, err := segmenter.NewSegmenter(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	segmented, err := seg.Segment(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("segmentation failed: %w", err)
+	}
+
+	return segmented, nil
+}
+
+// Enhance runs LLM enhancement on a segmented document
+func Enhance(ctx context.Context, segmented *types.SegmentedDocument, cfg types.LLMConfig) (*types.SegmentedDocument, *types.EnhancementResult, error) {
+	enhancer, err := llm.NewEnhancer(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	result, err := enhancer.EnhanceSegmentation(ctx, segmented)
+	if err != nil {
+		return nil, nil, fmt.Errorf("enhancement failed: %w", err)
+	}
+
+	enhanced, ok := result.EnhancedData.(*types.SegmentedDocument)
+	if !ok {
+		return nil, nil, fmt.Errorf("enhanced data is not a SegmentedDocument")
+	}
+
+	return enhanced, result, nil
+}
+
+// FilterEnhancement drops the changes in an enhancement result below
+// minConfidence (0-1) and returns the enhanced document rebuilt from the
+// kept changes, with the number dropped. It fails when a kept change
+// depended on a dropped one; see llm.FilterChanges.
+func FilterEnhancement(result *types.EnhancementResult, minConfidence float64) (*types.SegmentedDocument, int, error) {
+	if minConfidence < 0 || minConfidence > 1 {
+		return nil, 0, fmt.Errorf("%w: minimum change confidence must be between 0 and 1", ErrInvalidConfig)
+	}
+	dropped, err := llm.FilterChanges(result, minConfidence)
+	if err != nil {
+		return nil, dropped, err
+	}
+	enhanced, ok := result.EnhancedData.(*types.SegmentedDocument)
+	if !ok {
+		return nil, dropped, fmt.Errorf("enhanced data is not a SegmentedDocument")
+	}
+	return enhanced, dropped, nil
+}
+
+// NewEnhancementArtifact builds a storable audit record of the LLM exchange
+// that produced an enhanced version
+func NewEnhancementArtifact(documentID string, version, preEnhanceVersion int, result *types.EnhancementResult) *storage.EnhancementArtifact {
+	return &storage.EnhancementArtifact{
+		DocumentID:        documentID,
+		Version:           version,
+		PreEnhanceVersion: preEnhanceVersion,
+		Provider:          result.Provider,
+		Model:             result.Model,
+		Prompt:            result.Prompt,
+		RawResponse:       result.RawResponse,
+		Usage:             result.Usage,
+		CostEstimate:      result.CostEstimate,
+		Timestamp:         result.Timestamp,
+	}
+}
+
+// Convert converts a segmented document to Layer-1 and optionally validates
+// it. On validation failure the document and result are returned alongside
+// an error wrapping ErrValidationFailed.
+func Convert(segmented *types.SegmentedDocument, strict, validate bool) (*layer1.GuidanceDocument, *validator.ValidationResult, error) {
+	return ConvertWith(converter.NewConverter(), segmented, strict, validate)
+}
+
+// ConvertWith is Convert using a caller-configured converter. Validator
+// options are applied after the strict mode, so they can extend it (e.g.
+// with a published schema).
+func ConvertWith(conv converter.Converter, segmented *types.SegmentedDocument, strict, validate bool, opts ...validator.Option) (*layer1.GuidanceDocument, *validator.ValidationResult, error) {
+	layer1Doc, err := conv.Convert(segmented)
+	if err != nil {
+		return nil, nil, fmt.Errorf("conversion failed: %w", err)
+	}
+
+	if !validate {
+		return layer1Doc, nil, nil
+	}
+
+	opts = append([]validator.Option{validator.WithStrictMode(strict)}, opts...)
+	result := validator.NewValidator(opts...).Validate(layer1Doc)
+	if !result.Valid {
+		return layer1Doc, result, fmt.Errorf("%w with %d errors", ErrValidationFailed, len(result.Errors))
+	}
+
+	return layer1Doc, result, nil
+}
+
+// NewToolchain names the components behind a converted document. Any
+// argument may be nil when that stage's output isn't at hand.
+func NewToolchain(parsed *types.ParsedDocument, segmented *types.SegmentedDocument, conv converter.Converter) *types.Toolchain {
+	toolchain := &types.Toolchain{}
+	if parsed != nil {
+		toolchain.Parser = parsed.Metadata.Parser
+	}
+	if segmented != nil {
+		toolchain.Segmenter = segmented.Metadata.Segmenter
+	}
+	if conv != nil {
+		toolchain.Converter = conv.Name()
+	}
+	return toolchain
+}
+
+// NewValidationReport builds a storable report from a validation result.
+// A nil result produces a report marked as unvalidated.
+func NewValidationReport(documentID, stage string, sourceVersion int, strict bool, result *validator.ValidationResult) *storage.ValidationReport {
+	report := &storage.ValidationReport{
+		DocumentID:    documentID,
+		Timestamp:     time.Now(),
+		StrictMode:    strict,
+		SourceVersion: sourceVersion,
+		Stage:         stage,
+	}
+
+	if result == nil {
+		report.Unvalidated = true
+		return report
+	}
+
+	report.ToolVersion = validator.Version()
+	report.Valid = result.Valid
+	report.ErrorCount = len(result.Errors)
+	report.WarningCount = len(result.Warnings)
+	for _, e := range result.Errors {
+		report.Errors = append(report.Errors, storage.ValidationError{
+			Path:    e.Path,
+			Message: e.Message,
+			Value:   e.Value,
+		})
+	}
+	for _, w := range result.Warnings {
+		report.Warnings = append(report.Warnings, storage.ValidationError{
+			Path:    w.Path,
+			Message: w.Message,
+			Value:   w.Value,
+		})
+	}
+
+	return report
+}
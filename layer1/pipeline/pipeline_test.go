@@ -1,6 +1,8 @@
-package main
+package pipeline
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -11,6 +13,7 @@ import (
 	"github.com/ossf/gemara/layer1/pipeline/parser"
 	"github.com/ossf/gemara/layer1/pipeline/segmenter"
 	"github.com/ossf/gemara/layer1/pipeline/storage"
+	"github.com/ossf/gemara/layer1/pipeline/validator"
 )
 
 func TestFullPipeline(t *testing.T) {
@@ -258,6 +261,77 @@ func TestStorageVersioning(t *testing.T) {
 	}
 }
 
+func TestResumeIntermediates(t *testing.T) {
+	tempDir := t.TempDir()
+	store, err := storage.NewStorage(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	
+	inputPath := filepath.Join(tempDir, "input.pdf")
+	if err := os.WriteFile(inputPath, []byte("original"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+	checksum, err := fileChecksum(inputPath)
+	if err != nil {
+		t.Fatalf("Failed to checksum input: %v", err)
+	}
+	
+	parsed := &types.ParsedDocument{
+		Metadata: types.ParsedMetadata{
+			Parser:         "simple-v1.0",
+			DocumentID:     "resume-test",
+			SourceChecksum: checksum,
+		},
+		Pages: []types.Page{{PageNumber: 1, Blocks: []types.Block{
+			{Type: types.BlockTypeHeading, Text: "Requirement 1: Firewalls"},
+		}}},
+	}
+	if err := store.SaveParsed(parsed); err != nil {
+		t.Fatalf("Failed to save parsed: %v", err)
+	}
+	segmented, err := Segment(parsed, types.SegmenterConfig{DocumentType: "generic"})
+	if err != nil {
+		t.Fatalf("Segment failed: %v", err)
+	}
+	if err := store.SaveSegmented(segmented); err != nil {
+		t.Fatalf("Failed to save segmented: %v", err)
+	}
+	// Enhanced versions are never resumed from
+	if err := store.SaveSegmentedWithLabel(segmented, "post-enhance-mock (pre-enhance: v1)"); err != nil {
+		t.Fatalf("Failed to save enhanced: %v", err)
+	}
+	
+	cfg := Config{
+		DocumentID: "resume-test",
+		InputPath:  inputPath,
+		Parser:     types.ParserConfig{Provider: "simple"},
+		Segmenter:  types.SegmenterConfig{DocumentType: "generic"},
+		Storage:    store,
+	}
+	gotParsed, gotSegmented := resumeIntermediates(cfg)
+	if gotParsed == nil || gotParsed.Metadata.Version != 1 {
+		t.Fatalf("Expected to resume from parsed v1, got %+v", gotParsed)
+	}
+	if gotSegmented == nil || gotSegmented.Metadata.Version != 1 {
+		t.Fatalf("Expected to resume from segmented v1, got %+v", gotSegmented)
+	}
+	
+	// A different segmenter reuses only the parsed document
+	cfg.Segmenter.DocumentType = "pci-dss"
+	if gotParsed, gotSegmented = resumeIntermediates(cfg); gotParsed == nil || gotSegmented != nil {
+		t.Errorf("Expected parsed only, got %v / %v", gotParsed != nil, gotSegmented != nil)
+	}
+	
+	// A changed input reuses nothing
+	if err := os.WriteFile(inputPath, []byte("changed"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+	if gotParsed, gotSegmented = resumeIntermediates(cfg); gotParsed != nil || gotSegmented != nil {
+		t.Errorf("Expected nothing to resume after input changed")
+	}
+}
+
 func countTestBlocks(doc *types.ParsedDocument) int {
 	count := 0
 	for _, page := range doc.Pages {
@@ -279,3 +353,136 @@ func countTestLayer1Guidelines(doc interface{}) int {
 	return 0
 }
 
+
+func TestPipelineResultStats(t *testing.T) {
+	parsed := &types.ParsedDocument{
+		Metadata: types.ParsedMetadata{
+			Parser:  "simple-v1.0",
+			Version: 2,
+		},
+		Pages: []types.Page{
+			{PageNumber: 1, Blocks: []types.Block{{Type: types.BlockTypeHeading}, {Type: types.BlockTypeParagraph}}},
+			{PageNumber: 2, Blocks: []types.Block{{Type: types.BlockTypeParagraph}}},
+		},
+	}
+	
+	parsedStats := NewParsedStats(parsed)
+	if parsedStats.Pages != 2 || parsedStats.Blocks != 3 || parsedStats.Version != 2 {
+		t.Errorf("Unexpected parsed stats: %+v", parsedStats)
+	}
+	
+	segmented := &types.SegmentedDocument{
+		Metadata: types.SegmentedMetadata{Segmenter: "generic-v1.0", Version: 1},
+		Categories: []types.SegmentCategory{
+			{ID: "1", Guidelines: []types.SegmentGuideline{
+				{ID: "1.1", Parts: []types.SegmentPart{{ID: "1.1.1"}, {ID: "1.1.2"}}},
+				{ID: "1.2"},
+			}},
+		},
+	}
+	
+	segmentedStats := NewSegmentedStats(segmented)
+	if segmentedStats.Categories != 1 || segmentedStats.Guidelines != 2 || segmentedStats.Parts != 2 {
+		t.Errorf("Unexpected segmented stats: %+v", segmentedStats)
+	}
+	
+	result := &PipelineResult{Parsed: parsedStats, Segmented: segmentedStats}
+	if result.Succeeded() {
+		t.Error("Expected result without a Layer-1 document to be unsuccessful")
+	}
+}
+
+func TestRunRequiresInput(t *testing.T) {
+	result, err := Run(context.Background(), Config{DocumentID: "empty"})
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("Expected ErrInvalidConfig, got %v", err)
+	}
+	if result == nil || result.Error == "" || result.Succeeded() {
+		t.Errorf("Expected a failed result, got %+v", result)
+	}
+	if result.DocumentID != "empty" {
+		t.Errorf("Expected document ID 'empty', got %q", result.DocumentID)
+	}
+}
+
+func TestFilterEnhancement(t *testing.T) {
+	segmented := &types.SegmentedDocument{
+		DocumentMetadata: types.DocumentMetadata{Description: "Original"},
+		Categories:       []types.SegmentCategory{{ID: "1", Title: "Access"}},
+	}
+	_, enhancement, err := Enhance(context.Background(), segmented, types.LLMConfig{Provider: "mock"})
+	if err != nil {
+		t.Fatalf("Enhance failed: %v", err)
+	}
+	
+	if _, _, err := FilterEnhancement(enhancement, 1.5); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig for a threshold above 1, got %v", err)
+	}
+	
+	// The mock's only change is below the threshold
+	enhanced, dropped, err := FilterEnhancement(enhancement, 0.99)
+	if err != nil {
+		t.Fatalf("FilterEnhancement failed: %v", err)
+	}
+	if dropped != 1 || enhanced.DocumentMetadata.Description != "Original" || len(enhancement.Changes) != 0 {
+		t.Errorf("Expected the mock change dropped, got %d dropped, description %q", dropped, enhanced.DocumentMetadata.Description)
+	}
+}
+
+func TestInMemoryStages(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "sample.txt")
+	content := `Requirement 1: Install and maintain a firewall configuration
+
+1.1 Establish firewall and router configuration standards
+
+Objective: Build firewall and router configuration standards.
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	
+	simpleParser, err := parser.NewSimpleParser(types.ParserConfig{Provider: "simple", TempDir: tempDir})
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	parsed, err := simpleParser.ParseTextFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	parsed.Metadata.DocumentID = "in-memory"
+	
+	segmented, err := Segment(parsed, types.SegmenterConfig{DocumentType: "pci-dss"})
+	if err != nil {
+		t.Fatalf("Segment failed: %v", err)
+	}
+	
+	// No storage is involved; validation is skipped so the result is nil
+	doc, result, err := Convert(segmented, true, false)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if doc == nil || result != nil {
+		t.Fatalf("Expected a document and no validation result, got %v / %v", doc, result)
+	}
+	
+	report := NewValidationReport("in-memory", "convert", segmented.Metadata.Version, true, result)
+	if !report.Unvalidated || report.Valid {
+		t.Errorf("Expected an unvalidated report, got %+v", report)
+	}
+	
+	warned := &validator.ValidationResult{Valid: true}
+	warned.AddWarning("categories[0].guidelines[0].objective", "optional field is empty", nil)
+	validated := NewValidationReport("in-memory", "convert", segmented.Metadata.Version, true, warned)
+	if validated.ToolVersion != validator.Version() {
+		t.Errorf("Expected tool version %s, got %q", validator.Version(), validated.ToolVersion)
+	}
+	if !validated.Valid || validated.ErrorCount != 0 || validated.WarningCount != 1 || len(validated.Warnings) != 1 {
+		t.Errorf("Expected a valid report with one warning, got %+v", validated)
+	}
+	
+	toolchain := NewToolchain(parsed, segmented, converter.NewConverter())
+	if toolchain.Parser != "simple-v1.0" || toolchain.Segmenter != "pci-dss-v1.0" || toolchain.Converter != "default-v1.0" {
+		t.Errorf("Unexpected toolchain: %+v", toolchain)
+	}
+}
@@ -0,0 +1,101 @@
+package pipeline
+
+import (
+	"time"
+
+	"github.com/ossf/gemara/layer1"
+	"github.com/ossf/gemara/layer1/pipeline/converter"
+	"github.com/ossf/gemara/layer1/pipeline/types"
+	"github.com/ossf/gemara/layer1/pipeline/validator"
+)
+
+// PipelineResult collects the output of every stage of a pipeline run
+type PipelineResult struct {
+	DocumentID  string                      `json:"document_id" yaml:"document_id"`
+	StartedAt   time.Time                   `json:"started_at" yaml:"started_at"`
+	CompletedAt time.Time                   `json:"completed_at,omitempty" yaml:"completed_at,omitempty"`
+	Parsed      *ParsedStats                `json:"parsed,omitempty" yaml:"parsed,omitempty"`
+	Segmented   *SegmentedStats             `json:"segmented,omitempty" yaml:"segmented,omitempty"`
+	Enhancement *EnhancementStats           `json:"enhancement,omitempty" yaml:"enhancement,omitempty"`
+	Layer1      *layer1.GuidanceDocument    `json:"layer1,omitempty" yaml:"layer1,omitempty"`
+	Validation  *validator.ValidationResult `json:"validation,omitempty" yaml:"validation,omitempty"`
+	Coverage    *validator.CoverageReport   `json:"coverage,omitempty" yaml:"coverage,omitempty"`
+	IDIssues    []converter.IDIssue         `json:"id_issues,omitempty" yaml:"id_issues,omitempty"` // Set when IDs were normalized
+	Error       string                      `json:"error,omitempty" yaml:"error,omitempty"`         // Set when a stage failed
+
+	// Intermediate documents for in-memory callers; not serialized
+	ParsedDocument    *types.ParsedDocument    `json:"-" yaml:"-"`
+	SegmentedDocument *types.SegmentedDocument `json:"-" yaml:"-"`
+}
+
+// ParsedStats summarizes the parse stage
+type ParsedStats struct {
+	Version int    `json:"version" yaml:"version"`
+	Parser  string `json:"parser" yaml:"parser"`
+	Pages   int    `json:"pages" yaml:"pages"`
+	Blocks  int    `json:"blocks" yaml:"blocks"`
+}
+
+// SegmentedStats summarizes the segment stage
+type SegmentedStats struct {
+	Version    int    `json:"version" yaml:"version"`
+	Segmenter  string `json:"segmenter" yaml:"segmenter"`
+	Categories int    `json:"categories" yaml:"categories"`
+	Guidelines int    `json:"guidelines" yaml:"guidelines"`
+	Parts      int    `json:"parts" yaml:"parts"`
+}
+
+// EnhancementStats summarizes the optional enhance stage
+type EnhancementStats struct {
+	Provider   string  `json:"provider" yaml:"provider"`
+	Model      string  `json:"model" yaml:"model"`
+	Confidence float64 `json:"confidence" yaml:"confidence"`
+	Changes    int     `json:"changes" yaml:"changes"`
+}
+
+// NewParsedStats computes stats for a parsed document
+func NewParsedStats(doc *types.ParsedDocument) *ParsedStats {
+	stats := &ParsedStats{
+		Version: doc.Metadata.Version,
+		Parser:  doc.Metadata.Parser,
+		Pages:   len(doc.Pages),
+	}
+	for _, page := range doc.Pages {
+		stats.Blocks += len(page.Blocks)
+	}
+	return stats
+}
+
+// NewSegmentedStats computes stats for a segmented document
+func NewSegmentedStats(doc *types.SegmentedDocument) *SegmentedStats {
+	stats := &SegmentedStats{
+		Version:    doc.Metadata.Version,
+		Segmenter:  doc.Metadata.Segmenter,
+		Categories: len(doc.Categories),
+	}
+	for _, cat := range doc.Categories {
+		stats.Guidelines += len(cat.Guidelines)
+		for _, guide := range cat.Guidelines {
+			stats.Parts += len(guide.Parts)
+		}
+	}
+	return stats
+}
+
+// Succeeded reports whether every stage completed and validation (if run) passed
+func (r *PipelineResult) Succeeded() bool {
+	if r.Error != "" || r.Layer1 == nil {
+		return false
+	}
+	return r.Validation == nil || r.Validation.Valid
+}
+
+// NewEnhancementStats summarizes an enhancement result
+func NewEnhancementStats(result *types.EnhancementResult) *EnhancementStats {
+	return &EnhancementStats{
+		Provider:   result.Provider,
+		Model:      result.Model,
+		Confidence: result.Confidence,
+		Changes:    len(result.Changes),
+	}
+}
@@ -0,0 +1,43 @@
+package segmenter
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// calloutPattern matches a line opening a callout ("Note: ...",
+// "WARNING: ...", "Important:")
+var calloutPattern = regexp.MustCompile(`(?i)^(note|warning|important)\s*:\s*(.*)$`)
+
+// collectAnnotations moves a callout out of a content block's text into an
+// annotation on the guideline's last part, or on the guideline if it has no
+// parts yet. The callout runs from its label to the end of the block. It
+// returns the text before the callout, which is all of it when there is
+// none.
+func collectAnnotations(guideline *types.SegmentGuideline, text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		matches := calloutPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+		body := strings.Join(strings.Fields(strings.Join(append([]string{matches[2]}, lines[i+1:]...), " ")), " ")
+		if body == "" {
+			return text
+		}
+
+		annotation := types.Annotation{
+			Kind: types.AnnotationKind(strings.ToLower(matches[1])),
+			Text: body,
+		}
+		if n := len(guideline.Parts); n > 0 {
+			guideline.Parts[n-1].Annotations = append(guideline.Parts[n-1].Annotations, annotation)
+		} else {
+			guideline.Annotations = append(guideline.Annotations, annotation)
+		}
+		return strings.TrimSpace(strings.Join(lines[:i], "\n"))
+	}
+	return text
+}
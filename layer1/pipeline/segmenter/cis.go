@@ -0,0 +1,317 @@
+package segmenter
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+var (
+	// cisRecommendationPattern matches a recommendation heading such as
+	// "1.1.1.1 (L1) Ensure mounting of cramfs filesystems is disabled
+	// (Automated)": number, profile level and title without the assessment
+	// status
+	cisRecommendationPattern = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)+)\s+\((L[0-9])\)\s+(.+?)(?:\s+\((?:Automated|Manual|Scored|Not Scored)\))?$`)
+
+	// cisSectionPattern matches a numbered section heading without a
+	// profile level, such as "1 Initial Setup" or "1.1 Filesystem
+	// Configuration"
+	cisSectionPattern = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)*)\s+([A-Z].*)$`)
+
+	// cisLabelPattern matches the label opening a section of a
+	// recommendation, with any text following it in the same block
+	cisLabelPattern = regexp.MustCompile(`(?is)^(profile applicability|description|rationale|impact|audit|remediation|default value|references|cis controls|additional information)\s*:\s*(.*)$`)
+
+	// cisTOCEntryPattern matches a table of contents entry's dot leader and
+	// page number
+	cisTOCEntryPattern = regexp.MustCompile(`\.{4,}\s*[0-9]+$`)
+)
+
+// maxCISHeadingLength bounds the blocks read as headings, so body text
+// opening with a number isn't taken for one
+const maxCISHeadingLength = 200
+
+// cisPartSections lists the recommendation sections kept as parts, in
+// output order. Rationale becomes the objective and Remediation the
+// recommendations; Profile Applicability, References and CIS Controls are
+// left out.
+var cisPartSections = []struct {
+	label string // Lowercase section label
+	id    string // Part ID suffix
+	title string
+}{
+	{"description", "description", "Description"},
+	{"impact", "impact", "Impact"},
+	{"audit", "audit", "Audit"},
+	{"default value", "default-value", "Default Value"},
+	{"additional information", "additional-information", "Additional Information"},
+}
+
+// CISBenchmarkSegmenter applies CIS Benchmark specific rules. Top-level
+// sections become categories and recommendations guidelines, carrying
+// their profile level; the numbered sections in between only group
+// recommendations and are skipped.
+type CISBenchmarkSegmenter struct {
+	GenericSegmenter
+}
+
+// NewCISBenchmarkSegmenter creates a new CIS Benchmark segmenter
+func NewCISBenchmarkSegmenter(config types.SegmenterConfig) (*CISBenchmarkSegmenter, error) {
+	s := &CISBenchmarkSegmenter{}
+	// The structure patterns serve front matter detection and the generic
+	// fallback; recommendations are read by extractRecommendations
+	s.rules = &SegmentationRules{
+		CategoryPattern:  regexp.MustCompile(`^([0-9]+)\s+([A-Z].*)`),
+		GuidelinePattern: regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)+)\s+(\(L[0-9]\)\s+.*)`),
+		PartPattern:      noMatch,
+
+		TitlePatterns: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)^(CIS\s+.+?\s+Benchmark)`),
+		},
+		VersionPatterns: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)\bv([0-9]+\.[0-9]+(?:\.[0-9]+)?)\b`),
+			regexp.MustCompile(`(?i)version\s+([0-9]+\.[0-9]+(?:\.[0-9]+)?)`),
+		},
+		AuthorPatterns: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)(Center\s+for\s+Internet\s+Security)`),
+		},
+
+		ObjectiveKeywords: []string{
+			"rationale", "description",
+		},
+		RecommendationKeywords: []string{
+			"remediation", "ensure", "set", "run", "configure", "edit",
+		},
+		RequirementKeywords: []string{
+			"ensure", "must", "should",
+		},
+		RiskKeywords:    defaultRiskKeywords,
+		OutcomeKeywords: defaultOutcomeKeywords,
+
+		CategoryHeadingLevel:  1,
+		GuidelineHeadingLevel: 3,
+		PartHeadingLevel:      4,
+	}
+
+	if err := s.Configure(config); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Name returns the segmenter name
+func (s *CISBenchmarkSegmenter) Name() string {
+	return "cis-benchmark-v1.0"
+}
+
+// Segment reads the benchmark's recommendations, falling back to generic
+// segmentation when none is found
+func (s *CISBenchmarkSegmenter) Segment(doc *types.ParsedDocument) (*types.SegmentedDocument, error) {
+	metadata := s.extractMetadata(doc)
+	if metadata.Author == "Unknown" {
+		metadata.Author = "Center for Internet Security"
+	}
+	metadata.DocumentType = "Best Practice"
+
+	categories := s.extractRecommendations(doc)
+	var warnings []string
+	if len(categories) == 0 {
+		categories, warnings = s.fallbackCategories(doc, metadata.Title)
+	}
+	classifyNormativity(categories)
+
+	return &types.SegmentedDocument{
+		Metadata: types.SegmentedMetadata{
+			SourceVersion: doc.Metadata.Version,
+			Segmenter:     s.Name(),
+			SegmentedAt:   time.Now(),
+			DocumentID:    doc.Metadata.DocumentID,
+		},
+		DocumentMetadata: metadata,
+		FrontMatter:      s.extractFrontMatter(doc),
+		RevisionHistory:  extractRevisionHistory(doc),
+		Categories:       categories,
+		Warnings:         warnings,
+	}, nil
+}
+
+// cisRecommendation collects a recommendation's blocks by section until it
+// ends
+type cisRecommendation struct {
+	guideline types.SegmentGuideline
+	section   string                       // Lowercase label of the section being read
+	sections  map[string][]string          // Text blocks by section label
+	sources   map[string][]types.SourceRef // Source blocks by section label
+}
+
+// extractRecommendations reads categories and recommendations. A
+// recommendation runs until the next numbered heading; text before its
+// first label belongs to its description.
+func (s *CISBenchmarkSegmenter) extractRecommendations(doc *types.ParsedDocument) []types.SegmentCategory {
+	var categories []types.SegmentCategory
+	var category *types.SegmentCategory
+	var current *cisRecommendation
+	seenCategoryIDs := make(map[string]int)
+	seenGuidelineIDs := make(map[string]int)
+
+	finish := func() {
+		if current == nil {
+			return
+		}
+		guideline := s.finishRecommendation(current)
+		// Recommendations before any top-level section get one named after
+		// their leading number
+		if category == nil {
+			id := strings.SplitN(guideline.ID, ".", 2)[0]
+			category = &types.SegmentCategory{
+				ID:          makeUniqueID(id, seenCategoryIDs),
+				Title:       "Section " + id,
+				Description: "Section " + id,
+			}
+		}
+		category.Guidelines = append(category.Guidelines, guideline)
+		current = nil
+	}
+
+	for _, page := range doc.Pages {
+		for j, block := range page.Blocks {
+			if block.Type == types.BlockTypeFootnote {
+				continue
+			}
+			source := types.SourceRef{Page: page.PageNumber, Block: j}
+
+			if block.Type == types.BlockTypeHeading || block.Type == types.BlockTypeParagraph {
+				heading := strings.Join(strings.Fields(block.Text), " ")
+				if len(heading) <= maxCISHeadingLength {
+					if cisTOCEntryPattern.MatchString(heading) {
+						continue
+					}
+					if matches := cisRecommendationPattern.FindStringSubmatch(heading); matches != nil {
+						finish()
+						current = &cisRecommendation{
+							guideline: types.SegmentGuideline{
+								ID:      makeUniqueID(matches[1], seenGuidelineIDs),
+								Title:   matches[3],
+								Profile: matches[2],
+								Sources: []types.SourceRef{source},
+							},
+							section:  "description",
+							sections: make(map[string][]string),
+							sources:  make(map[string][]types.SourceRef),
+						}
+						continue
+					}
+					if matches := cisSectionPattern.FindStringSubmatch(heading); matches != nil {
+						finish()
+						if !strings.Contains(matches[1], ".") {
+							if category != nil {
+								categories = append(categories, *category)
+							}
+							description := matches[2]
+							if len(description) > 200 {
+								description = description[:197] + "..."
+							}
+							category = &types.SegmentCategory{
+								ID:          makeUniqueID(matches[1], seenCategoryIDs),
+								Title:       matches[2],
+								Description: description,
+								Sources:     []types.SourceRef{source},
+							}
+						}
+						continue
+					}
+				}
+			}
+
+			if current == nil {
+				if category != nil && block.Type == types.BlockTypeParagraph {
+					category.Sources = append(category.Sources, source)
+				}
+				continue
+			}
+			current.add(block, source)
+		}
+	}
+
+	finish()
+	if category != nil {
+		categories = append(categories, *category)
+	}
+	return categories
+}
+
+// add files a block under the recommendation's current section, switching
+// sections at a label. Tables and code are kept as in generic segmentation.
+func (r *cisRecommendation) add(block types.Block, source types.SourceRef) {
+	switch block.Type {
+	case types.BlockTypeTable:
+		if isRevisionTable(block) || block.TableData == nil || len(block.TableData.Rows) == 0 {
+			return
+		}
+		table := *block.TableData
+		table.Source = &source
+		r.guideline.Tables = append(r.guideline.Tables, table)
+	case types.BlockTypeCode:
+		if strings.TrimSpace(block.Text) == "" {
+			return
+		}
+		r.guideline.Code = append(r.guideline.Code, block.Text)
+	case types.BlockTypeHeading, types.BlockTypeParagraph, types.BlockTypeList:
+		text := strings.TrimSpace(block.Text)
+		if matches := cisLabelPattern.FindStringSubmatch(text); matches != nil {
+			r.section = strings.ToLower(strings.Join(strings.Fields(matches[1]), " "))
+			text = strings.TrimSpace(matches[2])
+		}
+		if text = collectAnnotations(&r.guideline, text); text != "" {
+			r.sections[r.section] = append(r.sections[r.section], text)
+			r.sources[r.section] = append(r.sources[r.section], source)
+		}
+		r.guideline.Links = append(r.guideline.Links, block.Links...)
+	default:
+		return
+	}
+	r.guideline.Sources = append(r.guideline.Sources, source)
+}
+
+// finishRecommendation builds a recommendation's guideline from its
+// sections: Rationale is the objective (or else the description's first
+// sentence), each Remediation line a recommendation, and the remaining
+// kept sections parts
+func (s *CISBenchmarkSegmenter) finishRecommendation(r *cisRecommendation) types.SegmentGuideline {
+	guideline := r.guideline
+
+	guideline.Objective = strings.Join(r.sections["rationale"], "\n")
+	if guideline.Objective == "" && len(r.sections["description"]) > 0 {
+		guideline.Objective = strings.TrimSpace(strings.SplitN(r.sections["description"][0], ".", 2)[0])
+	}
+
+	var candidates []recommendationCandidate
+	for _, text := range r.sections["remediation"] {
+		for _, line := range strings.Split(text, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				candidates = append(candidates, recommendationCandidate{
+					line:      line,
+					relevance: recommendationRelevance(line, s.rules.RecommendationKeywords),
+				})
+			}
+		}
+	}
+	guideline.Recommendations = selectRecommendations(candidates, s.maxRecommendations())
+
+	for _, section := range cisPartSections {
+		if len(r.sections[section.label]) == 0 {
+			continue
+		}
+		guideline.Parts = append(guideline.Parts, types.SegmentPart{
+			ID:      guideline.ID + "." + section.id,
+			Title:   section.title,
+			Text:    strings.Join(r.sections[section.label], "\n"),
+			Sources: r.sources[section.label],
+		})
+	}
+
+	return guideline
+}
@@ -0,0 +1,63 @@
+package segmenter
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// defaultFlatTitle titles the implicit category of a flat document without
+// a title
+const defaultFlatTitle = "General"
+
+// noMatch is a pattern that matches no text
+var noMatch = regexp.MustCompile(`[^\s\S]`)
+
+// nonSlugChars matches runs of characters replaced in a category ID
+// derived from a title
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// flat reports whether SegmenterConfig.Options["flat"] asks for a flat
+// document: numbered requirements without categories
+func (s *SegmenterBase) flat() bool {
+	return s.config.Options["flat"] == "true"
+}
+
+// flatCategories segments a flat document, shifting the structure down a
+// level: top-level numbered items ("1. ...", or level-1 headings) become
+// guidelines, second-level items become parts, and all guidelines go into
+// a single implicit category. The category is titled by
+// Options["flat_title"], the document title, or "General", and its ID is
+// derived from the title so it can't collide with numbered guideline IDs.
+// Returns nil if no guideline was found.
+func (s *GenericSegmenter) flatCategories(doc *types.ParsedDocument, docTitle string) []types.SegmentCategory {
+	title := s.config.Options["flat_title"]
+	if title == "" {
+		title = docTitle
+	}
+	if title == "" {
+		title = defaultFlatTitle
+	}
+	id := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if id == "" {
+		id = strings.ToLower(defaultFlatTitle)
+	}
+
+	rules := *s.rules
+	rules.CategoryPattern, rules.CategoryHeadingLevel = noMatch, 0
+	rules.GuidelinePattern, rules.GuidelineHeadingLevel = s.rules.CategoryPattern, s.rules.CategoryHeadingLevel
+	rules.PartPattern, rules.PartHeadingLevel = s.rules.GuidelinePattern, s.rules.GuidelineHeadingLevel
+	shifted := *s
+	shifted.rules = &rules
+
+	categories := shifted.extractCategories(doc, &types.SegmentCategory{
+		ID:          id,
+		Title:       title,
+		Description: title,
+	})
+	if len(categories) == 0 || len(categories[0].Guidelines) == 0 {
+		return nil
+	}
+	return categories
+}
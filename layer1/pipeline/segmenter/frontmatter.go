@@ -0,0 +1,95 @@
+package segmenter
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+const (
+	// metadataPages bounds how many leading pages are searched for metadata
+	metadataPages = 5
+
+	// minDescriptionWords is the fewest words an introductory paragraph
+	// needs to serve as the document description
+	minDescriptionWords = 8
+
+	// maxDescriptionLength bounds the document description
+	maxDescriptionLength = 500
+)
+
+// boilerplatePattern matches front matter paragraphs that aren't
+// introductory text: metadata lines such as "Version: 2.0", copyright and
+// licensing notices, and table of contents entries
+var boilerplatePattern = regexp.MustCompile(`(?i)(^(title|author|version|published|publication date|date)\s*:|copyright|©|all rights reserved|licensed under|\.{4,}\s*[0-9]+$)`)
+
+// jurisdictionPatterns detects the geographical or legal areas a document
+// applies in, in output order. The acronyms EU, UK and USA are matched
+// case-sensitively.
+var jurisdictionPatterns = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"European Union", regexp.MustCompile(`\b(?:(?i:European Union)|EU)\b`)},
+	{"United States", regexp.MustCompile(`(?:\b(?i:United States)\b|\bU\.S\.|\bUSA\b)`)},
+	{"United Kingdom", regexp.MustCompile(`\b(?:(?i:United Kingdom)|UK)\b`)},
+	{"Canada", regexp.MustCompile(`(?i)\bCanad(?:a|ian)\b`)},
+	{"Australia", regexp.MustCompile(`(?i)\bAustralian?\b`)},
+	{"New Zealand", regexp.MustCompile(`(?i)\bNew Zealand\b`)},
+	{"Germany", regexp.MustCompile(`(?i)\b(?:Germany|German Federal)\b`)},
+	{"France", regexp.MustCompile(`(?i)\bFrance\b`)},
+	{"Japan", regexp.MustCompile(`(?i)\bJapan(?:ese)?\b`)},
+	{"Singapore", regexp.MustCompile(`(?i)\bSingapore\b`)},
+	{"India", regexp.MustCompile(`(?i)\bIndia\b`)},
+	{"Brazil", regexp.MustCompile(`(?i)\bBrazil(?:ian)?\b`)},
+	{"California", regexp.MustCompile(`(?i)\bCalifornia\b`)},
+}
+
+// frontMatterParagraphs returns the paragraphs before the first category,
+// skipping title headings, from at most maxPages leading pages (0 = all)
+func (s *GenericSegmenter) frontMatterParagraphs(doc *types.ParsedDocument, maxPages int) []string {
+	var paragraphs []string
+	for i, page := range doc.Pages {
+		if maxPages > 0 && i >= maxPages {
+			break
+		}
+		for _, block := range page.Blocks {
+			if kind, _, _ := s.matchStructure(block); kind == structureCategory {
+				return paragraphs
+			}
+			if block.Type == types.BlockTypeParagraph {
+				paragraphs = append(paragraphs, block.Text)
+			}
+		}
+	}
+	return paragraphs
+}
+
+// introDescription returns the first substantive introductory paragraph:
+// one long enough to be prose that isn't metadata or boilerplate
+func introDescription(paragraphs []string) string {
+	for _, paragraph := range paragraphs {
+		text := strings.Join(strings.Fields(paragraph), " ")
+		if len(strings.Fields(text)) < minDescriptionWords || boilerplatePattern.MatchString(text) {
+			continue
+		}
+		if len(text) > maxDescriptionLength {
+			text = text[:maxDescriptionLength-3] + "..."
+		}
+		return text
+	}
+	return ""
+}
+
+// detectJurisdictions returns the jurisdictions named in the paragraphs
+func detectJurisdictions(paragraphs []string) []string {
+	text := strings.Join(paragraphs, "\n")
+	var jurisdictions []string
+	for _, j := range jurisdictionPatterns {
+		if j.pattern.MatchString(text) {
+			jurisdictions = append(jurisdictions, j.name)
+		}
+	}
+	return jurisdictions
+}
@@ -0,0 +1,128 @@
+package segmenter
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// mappingPhrase matches the phrases that introduce references to other
+// frameworks, capturing the reference text after them
+var mappingPhrase = regexp.MustCompile(`(?i)\b(?:maps?\s+to|mapped\s+to|see\s+also|cross[- ]?references?(?:\s+to)?)\s*:?\s+(.+)`)
+
+// controlIDPattern matches control identifiers such as "A.9.2", "AC-2(1)",
+// "PR.AC-1", "CC6.1" or "8.3.1". Framework numbers like "27001" or
+// "800-53" don't match since they have neither letters nor dots.
+var controlIDPattern = regexp.MustCompile(`^[A-Za-z]{0,4}(?:[.\-][A-Za-z]{1,4})*[.\-]?\d+(?:[.\-]?[A-Za-z0-9]+)*(?:\(\d+\))?$`)
+
+// versionToken matches versions ("v8", "4.0") that look like control IDs
+var versionToken = regexp.MustCompile(`^(?:[vV]\d|\d+\.0$)`)
+
+// Words joining references that belong to neither framework names nor IDs
+var mappingConnectors = []string{"and", "or", "&", "the"}
+
+// Words naming parts of this document, so "See also section 3.1" isn't
+// taken for a framework
+var internalReferenceWords = []string{
+	"section", "sections", "guideline", "guidelines", "requirement", "requirements",
+	"control", "controls", "chapter", "appendix", "part", "parts",
+}
+
+// isControlID reports whether a token identifies a control
+func isControlID(token string) bool {
+	if versionToken.MatchString(token) || !controlIDPattern.MatchString(token) {
+		return false
+	}
+	return strings.ContainsAny(token, ".ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz")
+}
+
+// parseMappings finds framework references in a line of guideline text.
+// Words before a run of control IDs name their framework, so one line can
+// reference several frameworks ("ISO 27001 A.9.2, A.9.4 and NIST CSF
+// PR.AC-1"). A framework named without IDs is kept with no entries.
+// Framework names start with a capital or digit, so the references end at
+// the first lowercase word that doesn't continue a name ("... A.9.2 for
+// access reviews").
+func parseMappings(line string) []types.SegmentMapping {
+	matches := mappingPhrase.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
+	text := strings.TrimSpace(line)
+
+	var mappings []types.SegmentMapping
+	var words []string
+	current := -1 // Index of the mapping control IDs are added to
+	flush := func() {
+		current = -1
+		if len(words) > 0 && !isInternalReference(words) {
+			mappings = append(mappings, types.SegmentMapping{Framework: strings.Join(words, " "), Text: text})
+			current = len(mappings) - 1
+		}
+		words = nil
+	}
+	tokens := strings.FieldsFunc(matches[1], func(r rune) bool {
+		return r == ' ' || r == '\t' || r == ',' || r == ';'
+	})
+	for _, token := range tokens {
+		// Trim enclosing punctuation, keeping the parenthesis of "AC-2(1)"
+		token = strings.TrimRight(strings.TrimLeft(token, "("), ".:)")
+		if strings.Count(token, "(") > strings.Count(token, ")") {
+			token += ")"
+		}
+		if token == "" || slices.Contains(mappingConnectors, strings.ToLower(token)) {
+			continue
+		}
+		if isControlID(token) {
+			if len(words) > 0 {
+				flush()
+			}
+			if current >= 0 {
+				mappings[current].Entries = append(mappings[current].Entries, token)
+			}
+			continue
+		}
+		if len(words) == 0 && (strings.Contains(token, "://") || !startsName(token) && !isInternalReference([]string{token})) {
+			break
+		}
+		words = append(words, token)
+	}
+	flush()
+	return mappings
+}
+
+// startsName reports whether a token can begin a framework name
+func startsName(token string) bool {
+	r := []rune(token)[0]
+	return unicode.IsUpper(r) || unicode.IsDigit(r)
+}
+
+// isInternalReference reports whether a reference names part of this
+// document rather than another framework
+func isInternalReference(words []string) bool {
+	return slices.Contains(internalReferenceWords, strings.ToLower(words[0]))
+}
+
+// collectMappings records the framework references in a content block,
+// merging repeated references to the same framework
+func collectMappings(guideline *types.SegmentGuideline, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		for _, mapping := range parseMappings(line) {
+			i := slices.IndexFunc(guideline.Mappings, func(m types.SegmentMapping) bool {
+				return strings.EqualFold(m.Framework, mapping.Framework)
+			})
+			if i < 0 {
+				guideline.Mappings = append(guideline.Mappings, mapping)
+				continue
+			}
+			for _, entry := range mapping.Entries {
+				if !slices.Contains(guideline.Mappings[i].Entries, entry) {
+					guideline.Mappings[i].Entries = append(guideline.Mappings[i].Entries, entry)
+				}
+			}
+		}
+	}
+}
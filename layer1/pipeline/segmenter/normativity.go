@@ -0,0 +1,46 @@
+package segmenter
+
+import (
+	"regexp"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// Normative keywords per RFC 2119, strongest first. Matching is
+// case-insensitive since many standards don't capitalize them.
+var normativityPatterns = []struct {
+	normativity types.Normativity
+	pattern     *regexp.Regexp
+}{
+	{types.NormativityMust, regexp.MustCompile(`(?i)\b(must|shall|required)\b`)},
+	{types.NormativityShould, regexp.MustCompile(`(?i)\b(should|recommended)\b`)},
+	{types.NormativityMay, regexp.MustCompile(`(?i)\b(may|optional)\b`)},
+}
+
+// ClassifyNormativity returns the strongest RFC 2119 keyword level found in
+// the text, or "" if it contains none
+func ClassifyNormativity(texts ...string) types.Normativity {
+	for _, level := range normativityPatterns {
+		for _, text := range texts {
+			if level.pattern.MatchString(text) {
+				return level.normativity
+			}
+		}
+	}
+	return ""
+}
+
+// classifyNormativity records the normative strength of every guideline's
+// recommendations and every part's text
+func classifyNormativity(categories []types.SegmentCategory) {
+	for i := range categories {
+		for j := range categories[i].Guidelines {
+			guideline := &categories[i].Guidelines[j]
+			guideline.Normativity = ClassifyNormativity(guideline.Recommendations...)
+			for k := range guideline.Parts {
+				part := &guideline.Parts[k]
+				part.Normativity = ClassifyNormativity(append([]string{part.Text}, part.Recommendations...)...)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package segmenter
+
+import (
+	"strings"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// promoteObjectives lifts an objective the segmenter left in part text back
+// to its guideline. When numbering is off, "1.1.1 Objective: ..." starts a
+// part and the guideline ends up without an objective. For guidelines with
+// no objective whose first part opens with "<keyword>:", the first sentence
+// after the keyword becomes the objective. The remaining text stays in the
+// part, and a part left empty is dropped, its sources moving to the
+// guideline.
+func promoteObjectives(categories []types.SegmentCategory, keywords []string) {
+	for i := range categories {
+		for j := range categories[i].Guidelines {
+			guideline := &categories[i].Guidelines[j]
+			if guideline.Objective != "" || len(guideline.Parts) == 0 {
+				continue
+			}
+			part := &guideline.Parts[0]
+			objective, rest, ok := splitObjective(part.Text, keywords)
+			if !ok {
+				continue
+			}
+			guideline.Objective = objective
+			part.Text = rest
+			if part.Text == "" && len(part.Recommendations) == 0 && len(part.Links) == 0 {
+				guideline.Sources = append(guideline.Sources, part.Sources...)
+				guideline.Parts = guideline.Parts[1:]
+			}
+		}
+	}
+}
+
+// splitObjective splits text opening with "<keyword>:" into the first
+// sentence after the keyword and the remaining text
+func splitObjective(text string, keywords []string) (objective, rest string, ok bool) {
+	trimmed := strings.TrimSpace(text)
+	for _, keyword := range keywords {
+		label := keyword + ":"
+		if len(trimmed) < len(label) || !strings.EqualFold(trimmed[:len(label)], label) {
+			continue
+		}
+		body := strings.TrimSpace(trimmed[len(label):])
+		end := len(body)
+		if i := strings.IndexByte(body, '\n'); i >= 0 {
+			end = i
+		}
+		if i := strings.Index(body[:end], ". "); i >= 0 {
+			end = i + 1
+		}
+		objective = strings.TrimSpace(body[:end])
+		if objective == "" {
+			return "", "", false
+		}
+		return objective, strings.TrimSpace(body[end:]), true
+	}
+	return "", "", false
+}
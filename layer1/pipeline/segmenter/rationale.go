@@ -0,0 +1,109 @@
+package segmenter
+
+import (
+	"strings"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// Labels that introduce a guideline's risks and outcomes, either inline
+// ("Risk: data is exposed") or heading a list ("Risks:")
+var (
+	defaultRiskKeywords    = []string{"risk", "risks", "threat", "threats"}
+	defaultOutcomeKeywords = []string{"outcome", "outcomes", "benefit", "benefits"}
+)
+
+// maxRationaleTitle caps a risk or outcome title taken from its text
+const maxRationaleTitle = 60
+
+// rationaleKind is the kind of rationale a label introduces
+type rationaleKind int
+
+const (
+	rationaleNone rationaleKind = iota
+	rationaleRisk
+	rationaleOutcome
+)
+
+// matchRationaleLabel reports whether a line is a risk or outcome label,
+// alone or followed by a colon, returning the text after the colon
+func (s *GenericSegmenter) matchRationaleLabel(line string) (rationaleKind, string) {
+	label, rest, _ := strings.Cut(line, ":")
+	label = strings.TrimSpace(label)
+	for _, keyword := range s.rules.RiskKeywords {
+		if strings.EqualFold(label, keyword) {
+			return rationaleRisk, strings.TrimSpace(rest)
+		}
+	}
+	for _, keyword := range s.rules.OutcomeKeywords {
+		if strings.EqualFold(label, keyword) {
+			return rationaleOutcome, strings.TrimSpace(rest)
+		}
+	}
+	return rationaleNone, ""
+}
+
+// collectRationale records the risks and outcomes in a content block. A
+// label with text after it is one entry; a bare label ("Risks:", or a
+// "Risks" heading) makes the list items that follow entries until other
+// content ends the list. It returns the list kind in effect after the
+// block.
+func (s *GenericSegmenter) collectRationale(guideline *types.SegmentGuideline, block types.Block, list rationaleKind) rationaleKind {
+	for _, line := range strings.Split(block.Text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		kind, rest := s.matchRationaleLabel(line)
+		switch {
+		case kind != rationaleNone && rest == "":
+			list = kind
+			continue
+		case kind != rationaleNone:
+			addRationale(guideline, kind, rest)
+		case block.Type == types.BlockTypeList && list != rationaleNone:
+			addRationale(guideline, list, line)
+			continue
+		}
+		list = rationaleNone
+	}
+	return list
+}
+
+// addRationale appends a risk or outcome built from its text to a guideline
+func addRationale(guideline *types.SegmentGuideline, kind rationaleKind, text string) {
+	item := rationaleItem(text)
+	if kind == rationaleRisk {
+		guideline.Risks = append(guideline.Risks, item)
+	} else {
+		guideline.Outcomes = append(guideline.Outcomes, item)
+	}
+}
+
+// rationaleItem splits text such as "Data exposure: records can be read"
+// into a title and description. Text without a short leading title is kept
+// whole as the description, titled by its first sentence, so both fields
+// Layer-1 requires are always set.
+func rationaleItem(text string) types.RationaleItem {
+	for _, sep := range []string{": ", " - ", " – ", " — "} {
+		if title, description, ok := strings.Cut(text, sep); ok {
+			title, description = strings.TrimSpace(title), strings.TrimSpace(description)
+			if title != "" && description != "" && len(title) <= maxRationaleTitle {
+				return types.RationaleItem{Title: title, Description: description}
+			}
+		}
+	}
+
+	title := text
+	if end := strings.IndexAny(title, ".;"); end > 0 {
+		title = title[:end]
+	}
+	if len(title) > maxRationaleTitle {
+		cut := strings.LastIndex(title[:maxRationaleTitle], " ")
+		if cut <= 0 {
+			cut = maxRationaleTitle
+		}
+		title = strings.TrimSpace(title[:cut]) + "..."
+	}
+	return types.RationaleItem{Title: title, Description: text}
+}
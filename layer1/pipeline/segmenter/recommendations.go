@@ -0,0 +1,78 @@
+package segmenter
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// imperativeStart matches lines that open with a normative keyword or an
+// imperative verb, after an optional bullet or list marker ("- Ensure ...",
+// "a) Must ..."). Such lines state what to do rather than merely
+// mentioning a recommendation keyword.
+var imperativeStart = regexp.MustCompile(`(?i)^(?:[-*•]\s*|\(?[a-z0-9]{1,3}[.)]\s+)?(?:must|shall|should|do not|never|always|ensure|implement|use|enable|disable|require|establish|maintain|define|document|review|verify|restrict|limit|configure|protect|monitor|apply|avoid|perform|enforce|encrypt|rotate)\b`)
+
+// maxRecommendations returns the cap on recommendations extracted per
+// guideline, set via SegmenterConfig.Options["max_recommendations"]
+// (0 = no cap)
+func (s *SegmenterBase) maxRecommendations() int {
+	n, err := strconv.Atoi(s.config.Options["max_recommendations"])
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// Relevance of a line matching a recommendation keyword, highest first
+const (
+	recommendationLeads     = 2 // Opens with an imperative, normative or recommendation keyword
+	recommendationNormative = 1 // States a requirement ("Users must ...")
+	recommendationMentions  = 0 // Merely mentions a keyword ("see the implementation notes")
+)
+
+// recommendationCandidate is a line matching a recommendation keyword
+type recommendationCandidate struct {
+	line      string
+	relevance int
+}
+
+// recommendationRelevance ranks a line matching one of the recommendation
+// keywords
+func recommendationRelevance(line string, keywords []string) int {
+	if imperativeStart.MatchString(line) {
+		return recommendationLeads
+	}
+	lower := strings.ToLower(line)
+	for _, keyword := range keywords {
+		if strings.HasPrefix(lower, strings.ToLower(keyword)) {
+			return recommendationLeads
+		}
+	}
+	if ClassifyNormativity(line) != "" {
+		return recommendationNormative
+	}
+	return recommendationMentions
+}
+
+// selectRecommendations keeps at most limit candidates (0 = all), the most
+// relevant first, and returns them in document order
+func selectRecommendations(candidates []recommendationCandidate, limit int) []string {
+	keep := make([]bool, len(candidates))
+	kept := 0
+	for relevance := recommendationLeads; relevance >= recommendationMentions; relevance-- {
+		for i, candidate := range candidates {
+			if candidate.relevance == relevance && (limit == 0 || kept < limit) {
+				keep[i] = true
+				kept++
+			}
+		}
+	}
+
+	var lines []string
+	for i, candidate := range candidates {
+		if keep[i] {
+			lines = append(lines, candidate.line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,83 @@
+package segmenter
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+// Factory creates a segmenter from its configuration
+type Factory func(config types.SegmenterConfig) (Segmenter, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+func init() {
+	mustRegister("generic", func(config types.SegmenterConfig) (Segmenter, error) {
+		return NewGenericSegmenter(config)
+	})
+	mustRegister("pci-dss", func(config types.SegmenterConfig) (Segmenter, error) {
+		return NewPCIDSSSegmenter(config)
+	})
+	mustRegister("nist-800-53", func(config types.SegmenterConfig) (Segmenter, error) {
+		return NewNIST80053Segmenter(config)
+	})
+	mustRegister("cis-benchmark", func(config types.SegmenterConfig) (Segmenter, error) {
+		return NewCISBenchmarkSegmenter(config)
+	})
+}
+
+// Register makes a segmenter available to NewSegmenter under a document
+// type. Downstream projects register their segmenters from an init
+// function, so SegmenterConfig.DocumentType (and the CLI's --segmenter)
+// selects them without the pipeline being edited. Registering a type twice
+// is an error.
+func Register(docType string, factory Factory) error {
+	if docType == "" || factory == nil {
+		return fmt.Errorf("segmenter registration needs a document type and a factory")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[docType]; ok {
+		return fmt.Errorf("segmenter already registered for document type %q", docType)
+	}
+	registry[docType] = factory
+	return nil
+}
+
+func mustRegister(docType string, factory Factory) {
+	if err := Register(docType, factory); err != nil {
+		panic(err)
+	}
+}
+
+// unregister removes a document type's segmenter, so tests can undo Register
+func unregister(docType string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(registry, docType)
+}
+
+// lookup returns the factory registered for a document type
+func lookup(docType string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	factory, ok := registry[docType]
+	return factory, ok
+}
+
+// DocumentTypes returns the registered document types, sorted
+func DocumentTypes() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	docTypes := make([]string, 0, len(registry))
+	for docType := range registry {
+		docTypes = append(docTypes, docType)
+	}
+	sort.Strings(docTypes)
+	return docTypes
+}
@@ -0,0 +1,182 @@
+package segmenter
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// rulesFile is the YAML form of SegmentationRules. Patterns are Go regular
+// expressions with the same capture groups as the built-in rules.
+type rulesFile struct {
+	CategoryPattern  string `yaml:"category_pattern"`
+	GuidelinePattern string `yaml:"guideline_pattern"`
+	PartPattern      string `yaml:"part_pattern"`
+	SubPartPattern   string `yaml:"sub_part_pattern"`
+	SubPartIDFormat  string `yaml:"sub_part_id_format"`
+
+	TitlePatterns       []string `yaml:"title_patterns"`
+	VersionPatterns     []string `yaml:"version_patterns"`
+	AuthorPatterns      []string `yaml:"author_patterns"`
+	PublicationPatterns []string `yaml:"publication_patterns"`
+
+	ObjectiveKeywords      []string `yaml:"objective_keywords"`
+	RecommendationKeywords []string `yaml:"recommendation_keywords"`
+	RequirementKeywords    []string `yaml:"requirement_keywords"`
+	RiskKeywords           []string `yaml:"risk_keywords"`
+	OutcomeKeywords        []string `yaml:"outcome_keywords"`
+
+	CategoryHeadingLevel  int `yaml:"category_heading_level"`
+	GuidelineHeadingLevel int `yaml:"guideline_heading_level"`
+	PartHeadingLevel      int `yaml:"part_heading_level"`
+}
+
+// LoadRulesFromFile reads segmentation rules from a YAML file. Fields the
+// file leaves out are nil or zero; a segmenter configured with the file
+// keeps its built-in rules for those. Unknown keys, invalid regular
+// expressions and patterns missing capture groups are errors naming the
+// offending field.
+func LoadRulesFromFile(path string) (*SegmentationRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	var file rulesFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse rules file %s: %w", path, err)
+	}
+
+	rules := &SegmentationRules{
+		SubPartIDFormat:        file.SubPartIDFormat,
+		ObjectiveKeywords:      file.ObjectiveKeywords,
+		RecommendationKeywords: file.RecommendationKeywords,
+		RequirementKeywords:    file.RequirementKeywords,
+		RiskKeywords:           file.RiskKeywords,
+		OutcomeKeywords:        file.OutcomeKeywords,
+		CategoryHeadingLevel:   file.CategoryHeadingLevel,
+		GuidelineHeadingLevel:  file.GuidelineHeadingLevel,
+		PartHeadingLevel:       file.PartHeadingLevel,
+	}
+
+	// The segmenters index the submatches, so each pattern needs the
+	// capture groups its built-in counterpart has: number and text for
+	// structure patterns, parent, letter and text for sub-parts
+	patterns := []struct {
+		field   string
+		pattern string
+		groups  int
+		target  **regexp.Regexp
+	}{
+		{"category_pattern", file.CategoryPattern, 2, &rules.CategoryPattern},
+		{"guideline_pattern", file.GuidelinePattern, 2, &rules.GuidelinePattern},
+		{"part_pattern", file.PartPattern, 2, &rules.PartPattern},
+		{"sub_part_pattern", file.SubPartPattern, 3, &rules.SubPartPattern},
+	}
+	for _, p := range patterns {
+		if p.pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile(p.pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rules file %s: invalid %s: %w", path, p.field, err)
+		}
+		if re.NumSubexp() != p.groups {
+			return nil, fmt.Errorf("rules file %s: invalid %s: needs %d capture groups, has %d", path, p.field, p.groups, re.NumSubexp())
+		}
+		*p.target = re
+	}
+
+	// Metadata patterns take their value from the first capture group;
+	// publication patterns may match the date as a whole
+	patternLists := []struct {
+		field     string
+		patterns  []string
+		minGroups int
+		target    *[]*regexp.Regexp
+	}{
+		{"title_patterns", file.TitlePatterns, 1, &rules.TitlePatterns},
+		{"version_patterns", file.VersionPatterns, 1, &rules.VersionPatterns},
+		{"author_patterns", file.AuthorPatterns, 1, &rules.AuthorPatterns},
+		{"publication_patterns", file.PublicationPatterns, 0, &rules.PublicationPatterns},
+	}
+	for _, p := range patternLists {
+		for i, pattern := range p.patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rules file %s: invalid %s[%d]: %w", path, p.field, i, err)
+			}
+			if re.NumSubexp() < p.minGroups {
+				return nil, fmt.Errorf("rules file %s: invalid %s[%d]: needs a capture group for the value", path, p.field, i)
+			}
+			*p.target = append(*p.target, re)
+		}
+	}
+
+	for field, level := range map[string]int{
+		"category_heading_level":  file.CategoryHeadingLevel,
+		"guideline_heading_level": file.GuidelineHeadingLevel,
+		"part_heading_level":      file.PartHeadingLevel,
+	} {
+		if level < 0 {
+			return nil, fmt.Errorf("rules file %s: invalid %s: %d (use a positive heading level)", path, field, level)
+		}
+	}
+
+	return rules, nil
+}
+
+// override replaces the rules with those set in other, keeping the rest
+func (r *SegmentationRules) override(other *SegmentationRules) {
+	for _, p := range []struct{ target, value **regexp.Regexp }{
+		{&r.CategoryPattern, &other.CategoryPattern},
+		{&r.GuidelinePattern, &other.GuidelinePattern},
+		{&r.PartPattern, &other.PartPattern},
+		{&r.SubPartPattern, &other.SubPartPattern},
+	} {
+		if *p.value != nil {
+			*p.target = *p.value
+		}
+	}
+	if other.SubPartIDFormat != "" {
+		r.SubPartIDFormat = other.SubPartIDFormat
+	}
+
+	for _, p := range []struct{ target, value *[]*regexp.Regexp }{
+		{&r.TitlePatterns, &other.TitlePatterns},
+		{&r.VersionPatterns, &other.VersionPatterns},
+		{&r.AuthorPatterns, &other.AuthorPatterns},
+		{&r.PublicationPatterns, &other.PublicationPatterns},
+	} {
+		if len(*p.value) > 0 {
+			*p.target = *p.value
+		}
+	}
+
+	for _, k := range []struct{ target, value *[]string }{
+		{&r.ObjectiveKeywords, &other.ObjectiveKeywords},
+		{&r.RecommendationKeywords, &other.RecommendationKeywords},
+		{&r.RequirementKeywords, &other.RequirementKeywords},
+		{&r.RiskKeywords, &other.RiskKeywords},
+		{&r.OutcomeKeywords, &other.OutcomeKeywords},
+	} {
+		if len(*k.value) > 0 {
+			*k.target = *k.value
+		}
+	}
+
+	for _, l := range []struct{ target, value *int }{
+		{&r.CategoryHeadingLevel, &other.CategoryHeadingLevel},
+		{&r.GuidelineHeadingLevel, &other.GuidelineHeadingLevel},
+		{&r.PartHeadingLevel, &other.PartHeadingLevel},
+	} {
+		if *l.value != 0 {
+			*l.target = *l.value
+		}
+	}
+}
@@ -2,8 +2,11 @@ package segmenter
 
 import (
 	"fmt"
+	"maps"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ossf/gemara/layer1/pipeline/types"
@@ -21,22 +24,24 @@ type Segmenter interface {
 	Configure(config types.SegmenterConfig) error
 }
 
-// NewSegmenter creates a segmenter based on document type
+// NewSegmenter creates the segmenter registered for the document type,
+// defaulting to the generic segmenter for unknown types
 func NewSegmenter(config types.SegmenterConfig) (Segmenter, error) {
-	switch config.DocumentType {
-	case "pci-dss":
-		return NewPCIDSSSegmenter(config)
-	case "nist-800-53":
-		return NewNIST80053Segmenter(config)
-	case "generic":
-		return NewGenericSegmenter(config)
-	default:
-		// Default to generic segmenter
+	factory, ok := lookup(config.DocumentType)
+	if !ok {
 		config.DocumentType = "generic"
-		return NewGenericSegmenter(config)
+		factory, _ = lookup(config.DocumentType)
 	}
+	return factory(config)
 }
 
+// Structure matching modes, selected via SegmenterConfig.Options["structure_by"]
+const (
+	StructureByRegex = "regex" // Match numbered text with the category/guideline/part patterns
+	StructureByLevel = "level" // Match heading blocks by CategoryHeadingLevel/GuidelineHeadingLevel/PartHeadingLevel
+	StructureByBoth  = "both"  // Try the patterns first, then fall back to heading levels
+)
+
 // SegmenterBase provides common functionality
 type SegmenterBase struct {
 	config types.SegmenterConfig
@@ -46,9 +51,46 @@ type SegmenterBase struct {
 // Configure sets the segmenter configuration
 func (s *SegmenterBase) Configure(config types.SegmenterConfig) error {
 	s.config = config
+	switch s.structureBy() {
+	case StructureByRegex, StructureByLevel, StructureByBoth:
+	default:
+		return fmt.Errorf("unsupported structure_by: %s (use regex, level, or both)", s.structureBy())
+	}
+	if value := config.Options["max_recommendations"]; value != "" {
+		if n, err := strconv.Atoi(value); err != nil || n < 0 {
+			return fmt.Errorf("invalid max_recommendations: %s (use a non-negative integer)", value)
+		}
+	}
+	switch config.Options["flat"] {
+	case "", "true", "false":
+	default:
+		return fmt.Errorf("invalid flat: %s (use true or false)", config.Options["flat"])
+	}
+	
+	// Rules from a file override the segmenter's built-in ones, so
+	// constructors set those first
+	if config.RulesFile != "" {
+		rules, err := LoadRulesFromFile(config.RulesFile)
+		if err != nil {
+			return err
+		}
+		if s.rules == nil {
+			s.rules = rules
+		} else {
+			s.rules.override(rules)
+		}
+	}
 	return nil
 }
 
+// structureBy returns the configured structure matching mode (default: regex)
+func (s *SegmenterBase) structureBy() string {
+	if mode := s.config.Options["structure_by"]; mode != "" {
+		return mode
+	}
+	return StructureByRegex
+}
+
 // GetConfig returns the segmenter configuration
 func (s *SegmenterBase) GetConfig() types.SegmenterConfig {
 	return s.config
@@ -61,6 +103,13 @@ type SegmentationRules struct {
 	GuidelinePattern *regexp.Regexp
 	PartPattern      *regexp.Regexp
 	
+	// Lettered sub-parts ("(a) ...", "1.1 (b) ..."): group 1 is an optional
+	// explicit parent number, group 2 the letter, group 3 the text. Sub-parts
+	// join the current guideline's parts with IDs built from SubPartIDFormat
+	// (parent ID, letter), defaulting to "%s(%s)".
+	SubPartPattern  *regexp.Regexp
+	SubPartIDFormat string
+	
 	// Metadata patterns
 	TitlePatterns       []*regexp.Regexp
 	VersionPatterns     []*regexp.Regexp
@@ -71,6 +120,8 @@ type SegmentationRules struct {
 	ObjectiveKeywords     []string
 	RecommendationKeywords []string
 	RequirementKeywords   []string
+	RiskKeywords          []string // Labels introducing risks ("Risks:")
+	OutcomeKeywords       []string // Labels introducing outcomes ("Outcomes:")
 	
 	// Structure hints
 	CategoryHeadingLevel  int
@@ -86,15 +137,12 @@ type GenericSegmenter struct {
 // NewGenericSegmenter creates a new generic segmenter
 func NewGenericSegmenter(config types.SegmenterConfig) (*GenericSegmenter, error) {
 	s := &GenericSegmenter{}
-	if err := s.Configure(config); err != nil {
-		return nil, err
-	}
-	
 	// Initialize generic rules
 	s.rules = &SegmentationRules{
 		CategoryPattern:  regexp.MustCompile(`^([0-9]+)\.\s+([A-Z].*)`),
 		GuidelinePattern: regexp.MustCompile(`^([0-9]+\.[0-9]+)\s+([A-Z].*)`),
 		PartPattern:      regexp.MustCompile(`^([0-9]+\.[0-9]+\.[0-9]+)\s+(.*)`),
+		SubPartPattern:   regexp.MustCompile(`^(?:([0-9]+(?:\.[0-9]+)+)\s*)?\(([a-z])\)\s+(.*)`),
 		
 		TitlePatterns: []*regexp.Regexp{
 			regexp.MustCompile(`(?i)^title:\s*(.+)`),
@@ -122,12 +170,18 @@ func NewGenericSegmenter(config types.SegmenterConfig) (*GenericSegmenter, error
 		RequirementKeywords: []string{
 			"requirement", "shall", "must", "required",
 		},
+		RiskKeywords:    defaultRiskKeywords,
+		OutcomeKeywords: defaultOutcomeKeywords,
 		
 		CategoryHeadingLevel:  1,
 		GuidelineHeadingLevel: 2,
 		PartHeadingLevel:      3,
 	}
 	
+	if err := s.Configure(config); err != nil {
+		return nil, err
+	}
+	
 	return s, nil
 }
 
@@ -144,8 +198,23 @@ func (s *GenericSegmenter) Segment(doc *types.ParsedDocument) (*types.SegmentedD
 	// Extract front matter (everything before first category)
 	frontMatter := s.extractFrontMatter(doc)
 	
-	// Extract categories and guidelines
-	categories := s.extractCategories(doc)
+	// Extract the change log from any revision history table
+	revisions := extractRevisionHistory(doc)
+	
+	// Extract categories and guidelines, degrading gracefully when the
+	// document doesn't follow the expected structure
+	var categories []types.SegmentCategory
+	if s.flat() {
+		categories = s.flatCategories(doc, metadata.Title)
+	} else {
+		categories = s.extractCategories(doc, nil)
+	}
+	var warnings []string
+	if len(categories) == 0 {
+		categories, warnings = s.fallbackCategories(doc, metadata.Title)
+	}
+	promoteObjectives(categories, s.rules.ObjectiveKeywords)
+	classifyNormativity(categories)
 	
 	segmented := &types.SegmentedDocument{
 		Metadata: types.SegmentedMetadata{
@@ -156,12 +225,22 @@ func (s *GenericSegmenter) Segment(doc *types.ParsedDocument) (*types.SegmentedD
 		},
 		DocumentMetadata: metadata,
 		FrontMatter:      frontMatter,
+		RevisionHistory:  revisions,
 		Categories:       categories,
+		Warnings:         warnings,
+	}
+	
+	// Fall back to the latest revision when no version was stated
+	if segmented.DocumentMetadata.Version == "" && len(revisions) > 0 {
+		segmented.DocumentMetadata.Version = revisions[len(revisions)-1].Version
 	}
 	
 	return segmented, nil
 }
 
+// maxMetadataScanLength bounds how much of each block the metadata patterns see
+const maxMetadataScanLength = 512
+
 // extractMetadata extracts document metadata from parsed content
 func (s *GenericSegmenter) extractMetadata(doc *types.ParsedDocument) types.DocumentMetadata {
 	meta := types.DocumentMetadata{
@@ -169,19 +248,19 @@ func (s *GenericSegmenter) extractMetadata(doc *types.ParsedDocument) types.Docu
 	}
 	
 	// Look through first few pages for metadata
-	for i := 0; i < len(doc.Pages) && i < 5; i++ {
+	for i := 0; i < len(doc.Pages) && i < metadataPages; i++ {
 		page := doc.Pages[i]
 		for _, block := range page.Blocks {
+			// Metadata sits at the start of a block; bounding the text keeps
+			// the unanchored patterns cheap on very long blocks
 			text := block.Text
+			if len(text) > maxMetadataScanLength {
+				text = text[:maxMetadataScanLength]
+			}
 			
 			// Try to extract title
 			if meta.Title == "" {
-			for _, pattern := range s.rules.TitlePatterns {
-				if matches := pattern.FindStringSubmatch(text); len(matches) > 1 {
-					meta.Title = strings.TrimSpace(matches[1])
-					break
-				}
-			}
+				meta.Title = matchValue(s.rules.TitlePatterns, text, 1)
 				
 				// If no pattern match, use first heading
 				if meta.Title == "" && block.Type == types.BlockTypeHeading && block.Level == 1 {
@@ -191,36 +270,26 @@ func (s *GenericSegmenter) extractMetadata(doc *types.ParsedDocument) types.Docu
 			
 			// Try to extract version
 			if meta.Version == "" {
-			for _, pattern := range s.rules.VersionPatterns {
-				if matches := pattern.FindStringSubmatch(text); len(matches) > 1 {
-					meta.Version = matches[1]
-					break
-				}
-			}
+				meta.Version = matchValue(s.rules.VersionPatterns, text, 1)
 			}
 			
 			// Try to extract author
 			if meta.Author == "" {
-			for _, pattern := range s.rules.AuthorPatterns {
-				if matches := pattern.FindStringSubmatch(text); len(matches) > 1 {
-					meta.Author = strings.TrimSpace(matches[1])
-					break
-				}
-			}
+				meta.Author = matchValue(s.rules.AuthorPatterns, text, 1)
 			}
 			
 			// Try to extract publication date
 			if meta.PublicationDate == "" {
-			for _, pattern := range s.rules.PublicationPatterns {
-				if matches := pattern.FindStringSubmatch(text); len(matches) >= 1 {
-					meta.PublicationDate = strings.TrimSpace(matches[len(matches)-1])
-					break
-				}
-			}
+				meta.PublicationDate = matchValue(s.rules.PublicationPatterns, text, -1)
 			}
 		}
 	}
 	
+	// The introduction describes the document and where it applies
+	intro := s.frontMatterParagraphs(doc, metadataPages)
+	meta.Description = introDescription(intro)
+	meta.Jurisdictions = detectJurisdictions(intro)
+	
 	// Set defaults if not found
 	if meta.Title == "" {
 		meta.Title = "Untitled Document"
@@ -238,58 +307,136 @@ func (s *GenericSegmenter) extractMetadata(doc *types.ParsedDocument) types.Docu
 	return meta
 }
 
+// matchValue returns the trimmed submatch group of the first pattern that
+// matches text with a non-empty value there, counting groups from the end
+// when group is negative. A pattern whose group is empty doesn't stop later
+// patterns from being tried.
+func matchValue(patterns []*regexp.Regexp, text string, group int) string {
+	for _, pattern := range patterns {
+		matches := pattern.FindStringSubmatch(text)
+		i := group
+		if i < 0 {
+			i += len(matches)
+		}
+		if i < 0 || i >= len(matches) {
+			continue
+		}
+		if value := strings.TrimSpace(matches[i]); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
 // extractFrontMatter extracts introductory text
 func (s *GenericSegmenter) extractFrontMatter(doc *types.ParsedDocument) string {
-	var frontMatter strings.Builder
-	foundFirstCategory := false
+	return strings.TrimSpace(strings.Join(s.frontMatterParagraphs(doc, 0), "\n\n"))
+}
+
+// extractRevisionHistory reads revisions from change-history tables. The
+// first row of each table is its header and names the columns.
+func extractRevisionHistory(doc *types.ParsedDocument) []types.Revision {
+	var revisions []types.Revision
 	
 	for _, page := range doc.Pages {
 		for _, block := range page.Blocks {
-			// Stop at first category
-			if s.rules.CategoryPattern.MatchString(block.Text) {
-				foundFirstCategory = true
-				break
+			if !isRevisionTable(block) {
+				continue
 			}
 			
-			// Skip headings that look like title/metadata
-			if block.Type == types.BlockTypeHeading && block.Level <= 1 {
-				continue
+			dateCol, versionCol, descCol := -1, -1, -1
+			for i, cell := range block.TableData.Rows[0] {
+				header := strings.ToLower(cell)
+				switch {
+				case dateCol < 0 && strings.Contains(header, "date"):
+					dateCol = i
+				case versionCol < 0 && strings.Contains(header, "version"):
+					versionCol = i
+				case descCol < 0 && (strings.Contains(header, "description") || strings.Contains(header, "change")):
+					descCol = i
+				}
 			}
 			
-			// Collect paragraph content
-			if block.Type == types.BlockTypeParagraph {
-				if frontMatter.Len() > 0 {
-					frontMatter.WriteString("\n\n")
+			for _, row := range block.TableData.Rows[1:] {
+				revision := types.Revision{
+					Date:    cellAt(row, dateCol),
+					Version: cellAt(row, versionCol),
+				}
+				// Description absorbs any trailing cells the layout split apart
+				if descCol >= 0 && descCol < len(row) {
+					revision.Description = strings.Join(row[descCol:], " ")
+				}
+				if revision.Version != "" || revision.Date != "" {
+					revisions = append(revisions, revision)
 				}
-				frontMatter.WriteString(block.Text)
 			}
 		}
-		
-		if foundFirstCategory {
-			break
-		}
 	}
 	
-	return strings.TrimSpace(frontMatter.String())
+	return revisions
+}
+
+// isRevisionTable reports whether a block is a change-history table
+func isRevisionTable(block types.Block) bool {
+	return block.Type == types.BlockTypeTable && block.Text == types.RevisionTableTitle &&
+		block.TableData != nil && len(block.TableData.Rows) > 1
 }
 
-// extractCategories extracts categories and their guidelines
-func (s *GenericSegmenter) extractCategories(doc *types.ParsedDocument) []types.SegmentCategory {
+// cellAt returns the trimmed cell at index i, or "" when out of range
+func cellAt(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// extractCategories extracts categories and their guidelines. Guidelines
+// found before the first category go into implicit, if given.
+func (s *GenericSegmenter) extractCategories(doc *types.ParsedDocument, implicit *types.SegmentCategory) []types.SegmentCategory {
 	var categories []types.SegmentCategory
-	var currentCategory *types.SegmentCategory
+	currentCategory := implicit
 	var currentGuideline *types.SegmentGuideline
 	var currentText strings.Builder
+	// ID of the numbered part lettered sub-parts belong to, if any
+	var currentPartID string
+	// Kind of the risks or outcomes list being read, if any
+	var rationaleList rationaleKind
 	
 	// Track seen IDs to ensure uniqueness
 	seenCategoryIDs := make(map[string]int)
 	seenGuidelineIDs := make(map[string]int)
 	
 	for _, page := range doc.Pages {
-		for _, block := range page.Blocks {
+		for j, block := range page.Blocks {
+			// Footnotes belong to the page, not the guideline above them,
+			// and their numbers would otherwise read as categories
+			if block.Type == types.BlockTypeFootnote {
+				continue
+			}
 			text := block.Text
+			source := types.SourceRef{Page: page.PageNumber, Block: j}
+			
+			// Check for lettered sub-part (e.g., "(a) Sub-part Text")
+			if parentID, letter, subText, ok := s.matchSubPart(block); ok && currentGuideline != nil {
+				if parentID == "" {
+					parentID = currentPartID
+				}
+				if parentID == "" {
+					parentID = currentGuideline.ID
+				}
+				currentGuideline.Parts = append(currentGuideline.Parts, types.SegmentPart{
+					ID:      s.subPartID(parentID, letter),
+					Text:    strings.TrimSpace(subText),
+					Links:   block.Links,
+					Sources: []types.SourceRef{source},
+				})
+				continue
+			}
+			
+			kind, structureID, structureText := s.matchStructure(block)
 			
 			// Check for category (e.g., "1. Category Name")
-			if matches := s.rules.CategoryPattern.FindStringSubmatch(text); matches != nil {
+			if kind == structureCategory {
 				// Save previous guideline
 				if currentGuideline != nil && currentText.Len() > 0 {
 					s.finalizeGuideline(currentGuideline, currentText.String())
@@ -304,12 +451,15 @@ func (s *GenericSegmenter) extractCategories(doc *types.ParsedDocument) []types.
 					categories = append(categories, *currentCategory)
 				}
 				
-				// Generate unique category ID
-				baseID := matches[1]
+				// Generate unique category ID, numbering unnumbered headings
+				baseID := structureID
+				if baseID == "" {
+					baseID = fmt.Sprintf("%d", len(categories)+1)
+				}
 				uniqueID := makeUniqueID(baseID, seenCategoryIDs)
 				
 				// Start new category with title as default description
-				title := strings.TrimSpace(matches[2])
+				title := strings.TrimSpace(structureText)
 				description := title
 				if len(description) > 200 {
 					description = description[:197] + "..."
@@ -319,13 +469,15 @@ func (s *GenericSegmenter) extractCategories(doc *types.ParsedDocument) []types.
 					ID:          uniqueID,
 					Title:       title,
 					Description: description,
+					Sources:     []types.SourceRef{source},
 				}
 				currentGuideline = nil
+				rationaleList = rationaleNone
 				continue
 			}
 			
 			// Check for guideline (e.g., "1.1 Guideline Name")
-			if matches := s.rules.GuidelinePattern.FindStringSubmatch(text); matches != nil {
+			if kind == structureGuideline {
 				// Save previous guideline
 				if currentGuideline != nil && currentText.Len() > 0 {
 					s.finalizeGuideline(currentGuideline, currentText.String())
@@ -336,41 +488,102 @@ func (s *GenericSegmenter) extractCategories(doc *types.ParsedDocument) []types.
 					currentCategory.Guidelines = append(currentCategory.Guidelines, *currentGuideline)
 				}
 				
-				// Generate unique guideline ID
-				baseID := matches[1]
+				// Generate unique guideline ID, numbering unnumbered headings
+				// within their category
+				baseID := structureID
+				if baseID == "" {
+					prefix, count := "", 1
+					if currentCategory != nil {
+						prefix = currentCategory.ID + "."
+						count = len(currentCategory.Guidelines) + 1
+					}
+					baseID = fmt.Sprintf("%s%d", prefix, count)
+				}
 				uniqueID := makeUniqueID(baseID, seenGuidelineIDs)
 				
 				// Start new guideline
 				currentGuideline = &types.SegmentGuideline{
-					ID:    uniqueID,
-					Title: strings.TrimSpace(matches[2]),
+					ID:      uniqueID,
+					Title:   strings.TrimSpace(structureText),
+					Sources: []types.SourceRef{source},
 				}
+				currentPartID = ""
+				rationaleList = rationaleNone
 				continue
 			}
 			
 			// Check for part (e.g., "1.1.1 Part Text")
-			if matches := s.rules.PartPattern.FindStringSubmatch(text); matches != nil {
+			if kind == structurePart {
 				if currentGuideline != nil {
 					// Parts use the guideline's ID context for uniqueness
-					partID := matches[1]
-					if currentGuideline.ID != "" {
-						partID = currentGuideline.ID + "." + strings.TrimPrefix(matches[1], currentGuideline.ID+".")
+					partID := structureID
+					if partID == "" {
+						partID = fmt.Sprintf("%d", len(currentGuideline.Parts)+1)
+					}
+					// NIST enhancements ("AC-2(1)") already carry the guideline ID
+					if currentGuideline.ID != "" && !strings.HasPrefix(partID, currentGuideline.ID+"(") {
+						partID = currentGuideline.ID + "." + strings.TrimPrefix(partID, currentGuideline.ID+".")
 					}
 					part := types.SegmentPart{
-						ID:   partID,
-						Text: strings.TrimSpace(matches[2]),
+						ID:      partID,
+						Text:    strings.TrimSpace(structureText),
+						Links:   block.Links,
+						Sources: []types.SourceRef{source},
 					}
 					currentGuideline.Parts = append(currentGuideline.Parts, part)
+					currentPartID = partID
+				}
+				continue
+			}
+			
+			// Attach tables to the guideline they appear under so the
+			// converter can carry them into the final document
+			if block.Type == types.BlockTypeTable {
+				if isRevisionTable(block) {
+					continue
+				}
+				if currentGuideline != nil && block.TableData != nil && len(block.TableData.Rows) > 0 {
+					table := *block.TableData
+					table.Source = &source
+					currentGuideline.Tables = append(currentGuideline.Tables, table)
+					currentGuideline.Sources = append(currentGuideline.Sources, source)
 				}
 				continue
 			}
 			
-			// Accumulate content text
+			// Code examples are kept verbatim rather than folded into the
+			// guideline's text
+			if block.Type == types.BlockTypeCode {
+				if currentGuideline != nil && strings.TrimSpace(text) != "" {
+					currentGuideline.Code = append(currentGuideline.Code, text)
+					currentGuideline.Sources = append(currentGuideline.Sources, source)
+				}
+				continue
+			}
+			
+			// Risks and outcomes are read from headings and content text
+			if currentGuideline != nil && (block.Type == types.BlockTypeHeading || block.Type == types.BlockTypeParagraph || block.Type == types.BlockTypeList) {
+				rationaleList = s.collectRationale(currentGuideline, block, rationaleList)
+			}
+			
+			// Accumulate content text, setting callouts apart as annotations
 			if block.Type == types.BlockTypeParagraph || block.Type == types.BlockTypeList {
-				if currentText.Len() > 0 {
-					currentText.WriteString("\n")
+				if currentGuideline != nil {
+					text = collectAnnotations(currentGuideline, text)
+				}
+				if text != "" {
+					if currentText.Len() > 0 {
+						currentText.WriteString("\n")
+					}
+					currentText.WriteString(text)
+				}
+				if currentGuideline != nil {
+					collectMappings(currentGuideline, block.Text)
+					currentGuideline.Links = append(currentGuideline.Links, block.Links...)
+					currentGuideline.Sources = append(currentGuideline.Sources, source)
+				} else if currentCategory != nil {
+					currentCategory.Sources = append(currentCategory.Sources, source)
 				}
-				currentText.WriteString(text)
 			}
 		}
 	}
@@ -392,6 +605,145 @@ func (s *GenericSegmenter) extractCategories(doc *types.ParsedDocument) []types.
 	return categories
 }
 
+// structureKind is a block's role in the category/guideline/part hierarchy
+type structureKind int
+
+const (
+	structureNone structureKind = iota
+	structureCategory
+	structureGuideline
+	structurePart
+)
+
+// matchStructure classifies a block according to the structure_by mode. It
+// returns the block's number (empty for unnumbered headings) and its text
+// without the number.
+func (s *SegmenterBase) matchStructure(block types.Block) (structureKind, string, string) {
+	mode := s.structureBy()
+	if mode != StructureByLevel {
+		if kind, number, text := matchNumbered(block.Text, s.rules); kind != structureNone {
+			return kind, number, text
+		}
+		if mode == StructureByRegex {
+			return structureNone, "", ""
+		}
+	}
+	
+	if block.Type != types.BlockTypeHeading || block.Level == 0 {
+		return structureNone, "", ""
+	}
+	for _, p := range structurePatterns(s.rules) {
+		if block.Level != p.level {
+			continue
+		}
+		// Keep the heading's own number when it has one
+		if matches := p.pattern.FindStringSubmatch(block.Text); matches != nil {
+			return p.kind, matches[1], matches[2]
+		}
+		return p.kind, "", strings.TrimSpace(block.Text)
+	}
+	return structureNone, "", ""
+}
+
+// matchNumbered matches text against the category, guideline, and part
+// patterns in turn, returning the kind, number, and remaining text
+func matchNumbered(text string, rules *SegmentationRules) (structureKind, string, string) {
+	for _, p := range structurePatterns(rules) {
+		if matches := p.pattern.FindStringSubmatch(text); matches != nil {
+			return p.kind, matches[1], matches[2]
+		}
+	}
+	return structureNone, "", ""
+}
+
+// structurePattern pairs a structure kind with its pattern and heading level
+type structurePattern struct {
+	kind    structureKind
+	pattern *regexp.Regexp
+	level   int
+}
+
+// structurePatterns lists the rules' structure patterns from outermost in
+func structurePatterns(rules *SegmentationRules) []structurePattern {
+	return []structurePattern{
+		{structureCategory, rules.CategoryPattern, rules.CategoryHeadingLevel},
+		{structureGuideline, rules.GuidelinePattern, rules.GuidelineHeadingLevel},
+		{structurePart, rules.PartPattern, rules.PartHeadingLevel},
+	}
+}
+
+// matchSubPart matches a lettered sub-part, either by the rules'
+// SubPartPattern or as a list item with a lowercase letter marker. It
+// returns the explicit parent number (usually empty), the letter, and the
+// sub-part's text.
+func (s *SegmenterBase) matchSubPart(block types.Block) (string, string, string, bool) {
+	if s.rules.SubPartPattern == nil {
+		return "", "", "", false
+	}
+	if matches := s.rules.SubPartPattern.FindStringSubmatch(block.Text); matches != nil {
+		return matches[1], matches[2], matches[3], true
+	}
+	if item := block.ListItem; block.Type == types.BlockTypeList && item != nil && item.MarkerKind == types.MarkerKindLowerAlpha {
+		return "", strings.Trim(item.Marker, "().: "), block.Text, true
+	}
+	return "", "", "", false
+}
+
+// subPartID builds a sub-part's composite ID from its parent's ID and letter
+func (s *SegmenterBase) subPartID(parentID, letter string) string {
+	format := s.rules.SubPartIDFormat
+	if format == "" {
+		format = "%s(%s)"
+	}
+	return fmt.Sprintf(format, parentID, letter)
+}
+
+// fallbackCategories recovers a usable structure when no category matched.
+// Top-level headings become categories if the structure_by mode ignored
+// heading levels; otherwise all content is wrapped in one synthetic category.
+func (s *GenericSegmenter) fallbackCategories(doc *types.ParsedDocument, title string) ([]types.SegmentCategory, []string) {
+	if s.structureBy() == StructureByRegex {
+		// Keep the caller's other options, such as max_recommendations
+		byLevel := *s
+		byLevel.config.Options = make(map[string]string, len(s.config.Options)+1)
+		maps.Copy(byLevel.config.Options, s.config.Options)
+		byLevel.config.Options["structure_by"] = StructureByBoth
+		if categories := byLevel.extractCategories(doc, nil); len(categories) > 0 {
+			return categories, []string{fmt.Sprintf("no categories matched the numbering pattern; used %d top-level headings as categories", len(categories))}
+		}
+	}
+	
+	var content strings.Builder
+	for _, page := range doc.Pages {
+		for _, block := range page.Blocks {
+			if block.Type != types.BlockTypeParagraph && block.Type != types.BlockTypeList {
+				continue
+			}
+			if content.Len() > 0 {
+				content.WriteString("\n")
+			}
+			content.WriteString(block.Text)
+		}
+	}
+	if content.Len() == 0 {
+		return nil, []string{"no categories or content found; the document may be scanned images or use an unsupported layout"}
+	}
+	
+	if title == "" {
+		title = "General"
+	}
+	guideline := types.SegmentGuideline{ID: "1.1", Title: title}
+	s.finalizeGuideline(&guideline, content.String())
+	
+	category := types.SegmentCategory{
+		ID:          "1",
+		Title:       title,
+		Description: title,
+		Guidelines:  []types.SegmentGuideline{guideline},
+	}
+	return []types.SegmentCategory{category}, []string{"no categories or headings found; wrapped all content in a single synthetic category"}
+}
+
 // makeUniqueID ensures an ID is unique by appending a suffix if needed
 func makeUniqueID(baseID string, seenIDs map[string]int) string {
 	seenIDs[baseID]++
@@ -404,12 +756,36 @@ func makeUniqueID(baseID string, seenIDs map[string]int) string {
 	return fmt.Sprintf("%s-%d", baseID, count)
 }
 
+var (
+	objectivePatternsMu sync.Mutex
+	objectivePatterns   = make(map[string]*regexp.Regexp)
+)
+
+// objectivePattern returns the compiled "<keyword>: text" pattern, caching it
+// since finalizeGuideline runs once per guideline. Keywords are quoted so
+// configured rules can't inject regex syntax.
+func objectivePattern(keyword string) *regexp.Regexp {
+	objectivePatternsMu.Lock()
+	defer objectivePatternsMu.Unlock()
+	
+	pattern, ok := objectivePatterns[keyword]
+	if !ok {
+		pattern = regexp.MustCompile(fmt.Sprintf(`(?i)%s:\s*([^\n]+)`, regexp.QuoteMeta(keyword)))
+		objectivePatterns[keyword] = pattern
+	}
+	return pattern
+}
+
 // finalizeGuideline processes accumulated text for a guideline
 func (s *GenericSegmenter) finalizeGuideline(guideline *types.SegmentGuideline, text string) {
 	// Extract objective if present
+	lowerText := strings.ToLower(text)
 	for _, keyword := range s.rules.ObjectiveKeywords {
-		pattern := regexp.MustCompile(fmt.Sprintf(`(?i)%s:\s*([^\n]+)`, keyword))
-		if matches := pattern.FindStringSubmatch(text); matches != nil {
+		// Cheap substring check before running the pattern over the whole text
+		if !strings.Contains(lowerText, strings.ToLower(keyword)+":") {
+			continue
+		}
+		if matches := objectivePattern(keyword).FindStringSubmatch(text); matches != nil {
 			guideline.Objective = strings.TrimSpace(matches[1])
 			break
 		}
@@ -423,18 +799,23 @@ func (s *GenericSegmenter) finalizeGuideline(guideline *types.SegmentGuideline,
 		}
 	}
 	
-	// Extract recommendations
+	// Extract recommendations, capped at the most relevant lines
+	var candidates []recommendationCandidate
 	lines := strings.Split(text, "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		for _, keyword := range s.rules.RecommendationKeywords {
 			if strings.Contains(strings.ToLower(line), strings.ToLower(keyword)) {
 				if len(line) > 0 {
-					guideline.Recommendations = append(guideline.Recommendations, line)
+					candidates = append(candidates, recommendationCandidate{
+						line:      line,
+						relevance: recommendationRelevance(line, s.rules.RecommendationKeywords),
+					})
 				}
 				break
 			}
 		}
 	}
+	guideline.Recommendations = append(guideline.Recommendations, selectRecommendations(candidates, s.maxRecommendations())...)
 }
 
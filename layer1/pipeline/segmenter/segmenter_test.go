@@ -1,6 +1,12 @@
 package segmenter
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -93,6 +99,47 @@ func TestGenericSegmenter(t *testing.T) {
 	}
 }
 
+func TestIntroMetadata(t *testing.T) {
+	seg, err := NewGenericSegmenter(types.SegmenterConfig{DocumentType: "generic"})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	intro := "This standard sets baseline security controls for payment processors operating in the European Union and the United Kingdom."
+	doc := &types.ParsedDocument{
+		Pages: []types.Page{{PageNumber: 1, Blocks: []types.Block{
+			{Type: types.BlockTypeHeading, Level: 1, Text: "Payment Security Standard"},
+			{Type: types.BlockTypeParagraph, Text: "Version 2.0"},
+			{Type: types.BlockTypeParagraph, Text: "Copyright 2024 Example Council. All rights reserved. Reproduction is prohibited."},
+			{Type: types.BlockTypeParagraph, Text: intro},
+			{Type: types.BlockTypeHeading, Level: 1, Text: "1. Access Control"},
+			{Type: types.BlockTypeParagraph, Text: "Systems hosted in Canada must use regional keys."},
+		}}},
+	}
+	segmented, err := seg.Segment(doc)
+	if err != nil {
+		t.Fatalf("Failed to segment document: %v", err)
+	}
+	
+	meta := segmented.DocumentMetadata
+	if meta.Description != intro {
+		t.Errorf("Expected the intro paragraph as description, got %q", meta.Description)
+	}
+	// Only the introduction is scanned, not the body
+	if want := []string{"European Union", "United Kingdom"}; !slices.Equal(meta.Jurisdictions, want) {
+		t.Errorf("Expected jurisdictions %v, got %v", want, meta.Jurisdictions)
+	}
+	
+	// Without an introduction the placeholder remains
+	doc.Pages[0].Blocks = slices.Delete(doc.Pages[0].Blocks, 2, 4)
+	segmented, err = seg.Segment(doc)
+	if err != nil {
+		t.Fatalf("Failed to segment document: %v", err)
+	}
+	if segmented.DocumentMetadata.Description != "Automatically extracted from PDF" || segmented.DocumentMetadata.Jurisdictions != nil {
+		t.Errorf("Expected placeholder description and no jurisdictions, got %+v", segmented.DocumentMetadata)
+	}
+}
+
 func TestPCIDSSSegmenter(t *testing.T) {
 	// Create test parsed document with PCI-DSS structure
 	doc := &types.ParsedDocument{
@@ -180,6 +227,163 @@ func TestPCIDSSSegmenter(t *testing.T) {
 	}
 }
 
+func TestCISBenchmarkSegmenter(t *testing.T) {
+	doc := &types.ParsedDocument{
+		Metadata: types.ParsedMetadata{DocumentID: "cis-ubuntu", Version: 1},
+		Pages: []types.Page{
+			{
+				PageNumber: 1,
+				Blocks: []types.Block{
+					{Type: types.BlockTypeHeading, Level: 1, Text: "CIS Ubuntu Linux 22.04 LTS Benchmark"},
+					{Type: types.BlockTypeParagraph, Text: "v1.0.0 - 08-30-2022"},
+					// Table of contents entries are not recommendations
+					{Type: types.BlockTypeParagraph, Text: "1.1.1.1 (L1) Ensure mounting of cramfs filesystems is disabled (Automated) ........ 17"},
+				},
+			},
+			{
+				PageNumber: 2,
+				Blocks: []types.Block{
+					{Type: types.BlockTypeHeading, Level: 1, Text: "1 Initial Setup"},
+					{Type: types.BlockTypeHeading, Level: 2, Text: "1.1 Filesystem Configuration"},
+					{Type: types.BlockTypeHeading, Level: 3, Text: "1.1.1.1 (L1) Ensure mounting of cramfs\nfilesystems is disabled (Automated)"},
+					{Type: types.BlockTypeParagraph, Text: "Profile Applicability:\n• Level 1 - Server"},
+					{Type: types.BlockTypeParagraph, Text: "Description:\nThe cramfs filesystem type is a compressed read-only Linux filesystem."},
+					{Type: types.BlockTypeParagraph, Text: "Rationale:"},
+					{Type: types.BlockTypeParagraph, Text: "Removing support for unneeded filesystem types reduces the local attack surface."},
+					{Type: types.BlockTypeParagraph, Text: "Audit:\nRun the following script to verify cramfs is not loadable:"},
+					{Type: types.BlockTypeCode, Text: "modprobe -n -v cramfs"},
+					{Type: types.BlockTypeParagraph, Text: "Remediation:\nEdit or create a file in /etc/modprobe.d/ ending in .conf\nUnload cramfs from the kernel"},
+					{Type: types.BlockTypeParagraph, Text: "Note: Reboot to apply the change."},
+					{Type: types.BlockTypeHeading, Level: 3, Text: "1.1.1.2 (L2) Ensure mounting of squashfs filesystems is disabled (Manual)"},
+					{Type: types.BlockTypeParagraph, Text: "Description: The squashfs filesystem type is a compressed read-only Linux filesystem. It is used by snaps."},
+					{Type: types.BlockTypeHeading, Level: 1, Text: "2 Services"},
+					{Type: types.BlockTypeHeading, Level: 3, Text: "2.1.1 (L1) Ensure time synchronization is in use (Automated)"},
+				},
+			},
+		},
+	}
+	
+	seg, err := NewSegmenter(types.SegmenterConfig{DocumentType: "cis-benchmark"})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	result, err := seg.Segment(doc)
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+	
+	if result.Metadata.Segmenter != "cis-benchmark-v1.0" {
+		t.Errorf("Unexpected segmenter name: %s", result.Metadata.Segmenter)
+	}
+	if result.DocumentMetadata.Title != "CIS Ubuntu Linux 22.04 LTS Benchmark" || result.DocumentMetadata.Version != "1.0.0" {
+		t.Errorf("Unexpected metadata: %+v", result.DocumentMetadata)
+	}
+	if len(result.Categories) != 2 || result.Categories[0].Title != "Initial Setup" || result.Categories[1].ID != "2" {
+		t.Fatalf("Expected categories 1 Initial Setup and 2 Services, got %+v", result.Categories)
+	}
+	if len(result.Categories[0].Guidelines) != 2 || len(result.Categories[1].Guidelines) != 1 {
+		t.Fatalf("Expected 2 and 1 recommendations, got %+v", result.Categories)
+	}
+	
+	first := result.Categories[0].Guidelines[0]
+	if first.ID != "1.1.1.1" || first.Title != "Ensure mounting of cramfs filesystems is disabled" || first.Profile != "L1" {
+		t.Errorf("Unexpected recommendation heading: %s %q %s", first.ID, first.Title, first.Profile)
+	}
+	if first.Objective != "Removing support for unneeded filesystem types reduces the local attack surface." {
+		t.Errorf("Expected the rationale as objective, got %q", first.Objective)
+	}
+	if strings.Join(first.Recommendations, "|") != "Edit or create a file in /etc/modprobe.d/ ending in .conf|Unload cramfs from the kernel" {
+		t.Errorf("Expected remediation lines as recommendations, got %q", first.Recommendations)
+	}
+	var parts []string
+	for _, part := range first.Parts {
+		parts = append(parts, part.ID+" "+part.Title+": "+part.Text)
+	}
+	wantParts := []string{
+		"1.1.1.1.description Description: The cramfs filesystem type is a compressed read-only Linux filesystem.",
+		"1.1.1.1.audit Audit: Run the following script to verify cramfs is not loadable:",
+	}
+	if strings.Join(parts, "\n") != strings.Join(wantParts, "\n") {
+		t.Errorf("Expected parts %q, got %q", wantParts, parts)
+	}
+	if len(first.Code) != 1 || len(first.Annotations) != 1 || first.Annotations[0].Kind != types.AnnotationNote {
+		t.Errorf("Expected the audit command as code and the note as annotation, got %q %+v", first.Code, first.Annotations)
+	}
+	
+	second := result.Categories[0].Guidelines[1]
+	if second.Profile != "L2" || second.Objective != "The squashfs filesystem type is a compressed read-only Linux filesystem" {
+		t.Errorf("Expected an L2 recommendation objective from its description, got %s %q", second.Profile, second.Objective)
+	}
+}
+
+func TestRevisionHistory(t *testing.T) {
+	doc := &types.ParsedDocument{
+		Metadata: types.ParsedMetadata{DocumentID: "test-doc", Version: 1},
+		Pages: []types.Page{
+			{
+				PageNumber: 1,
+				Blocks: []types.Block{
+					{
+						Type: types.BlockTypeTable,
+						Text: types.RevisionTableTitle,
+						TableData: &types.TableData{Rows: [][]string{
+							{"Date", "Version", "Description"},
+							{"October 2008", "1.2", "Introduced v1.2"},
+							{"May 2018", "3.2.1", "Minor corrections", "(continued)"},
+						}},
+					},
+					{
+						Type:  types.BlockTypeHeading,
+						Level: 1,
+						Text:  "1. Access Control",
+					},
+					{
+						Type:  types.BlockTypeHeading,
+						Level: 2,
+						Text:  "1.1 User Authentication",
+					},
+					{
+						Type: types.BlockTypeParagraph,
+						Text: "Users must be authenticated before access.",
+					},
+				},
+			},
+		},
+	}
+	
+	seg, err := NewGenericSegmenter(types.SegmenterConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	
+	segmented, err := seg.Segment(doc)
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+	
+	if len(segmented.RevisionHistory) != 2 {
+		t.Fatalf("Expected 2 revisions, got %d: %+v", len(segmented.RevisionHistory), segmented.RevisionHistory)
+	}
+	latest := segmented.RevisionHistory[1]
+	if latest.Version != "3.2.1" || latest.Date != "May 2018" || latest.Description != "Minor corrections (continued)" {
+		t.Errorf("Unexpected revision: %+v", latest)
+	}
+	
+	// The latest revision fills in a missing document version
+	if segmented.DocumentMetadata.Version != "3.2.1" {
+		t.Errorf("Expected version 3.2.1 from revision history, got %q", segmented.DocumentMetadata.Version)
+	}
+	
+	// Revision tables are not attached to guidelines
+	for _, cat := range segmented.Categories {
+		for _, guide := range cat.Guidelines {
+			if len(guide.Tables) > 0 {
+				t.Errorf("Revision table leaked into guideline %s", guide.ID)
+			}
+		}
+	}
+}
+
 func TestSegmenterFactory(t *testing.T) {
 	tests := []struct {
 		docType string
@@ -188,6 +392,7 @@ func TestSegmenterFactory(t *testing.T) {
 		{"generic", false},
 		{"pci-dss", false},
 		{"nist-800-53", false},
+		{"cis-benchmark", false},
 		{"unknown", false}, // Should default to generic
 	}
 	
@@ -205,4 +410,763 @@ func TestSegmenterFactory(t *testing.T) {
 	}
 }
 
+// stubSegmenter is a downstream segmenter registered by TestRegister
+type stubSegmenter struct {
+	GenericSegmenter
+}
+
+func (s *stubSegmenter) Name() string {
+	return "stub-v1.0"
+}
+
+func TestRegister(t *testing.T) {
+	factory := func(config types.SegmenterConfig) (Segmenter, error) {
+		s := &stubSegmenter{}
+		return s, s.Configure(config)
+	}
+	if err := Register("test-stub", factory); err != nil {
+		t.Fatalf("Failed to register segmenter: %v", err)
+	}
+	t.Cleanup(func() { unregister("test-stub") })
+	
+	seg, err := NewSegmenter(types.SegmenterConfig{DocumentType: "test-stub"})
+	if err != nil {
+		t.Fatalf("Failed to create registered segmenter: %v", err)
+	}
+	if seg.Name() != "stub-v1.0" {
+		t.Errorf("Expected the registered segmenter, got %s", seg.Name())
+	}
+	if !slices.Contains(DocumentTypes(), "test-stub") {
+		t.Errorf("Expected test-stub among %v", DocumentTypes())
+	}
+	
+	// Built-ins are registered the same way, so neither can be replaced
+	for _, docType := range []string{"test-stub", "generic", "pci-dss", "nist-800-53", "cis-benchmark"} {
+		if err := Register(docType, factory); err == nil {
+			t.Errorf("Expected an error registering %s twice", docType)
+		}
+	}
+	if err := Register("", factory); err == nil {
+		t.Error("Expected an error registering an empty document type")
+	}
+}
+
+func TestStructureBy(t *testing.T) {
+	// Docling-style output: reliable heading levels, numbering only on some
+	doc := &types.ParsedDocument{
+		Metadata: types.ParsedMetadata{DocumentID: "levels"},
+		Pages: []types.Page{{
+			PageNumber: 1,
+			Blocks: []types.Block{
+				{Type: types.BlockTypeParagraph, Text: "Introductory text."},
+				{Type: types.BlockTypeHeading, Level: 1, Text: "Access Control"},
+				{Type: types.BlockTypeHeading, Level: 2, Text: "Passwords"},
+				{Type: types.BlockTypeParagraph, Text: "Passwords must be strong."},
+				{Type: types.BlockTypeHeading, Level: 3, Text: "Use at least 12 characters"},
+				{Type: types.BlockTypeHeading, Level: 2, Text: "Sessions"},
+				{Type: types.BlockTypeHeading, Level: 1, Text: "2. Logging"},
+				{Type: types.BlockTypeHeading, Level: 2, Text: "2.4 Retention"},
+			},
+		}},
+	}
+	
+	tests := []struct {
+		mode           string
+		wantCategories []string
+		wantGuidelines []string
+	}{
+		{StructureByRegex, []string{"2"}, []string{"2.4"}},
+		{StructureByLevel, []string{"1", "2"}, []string{"1.1", "1.2", "2.4"}},
+		{StructureByBoth, []string{"1", "2"}, []string{"1.1", "1.2", "2.4"}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			seg, err := NewGenericSegmenter(types.SegmenterConfig{
+				Options: map[string]string{"structure_by": tt.mode},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create segmenter: %v", err)
+			}
+			segmented, err := seg.Segment(doc)
+			if err != nil {
+				t.Fatalf("Segmentation failed: %v", err)
+			}
+			
+			var categories, guidelines []string
+			for _, cat := range segmented.Categories {
+				categories = append(categories, cat.ID)
+				for _, guide := range cat.Guidelines {
+					guidelines = append(guidelines, guide.ID)
+				}
+			}
+			if strings.Join(categories, ",") != strings.Join(tt.wantCategories, ",") {
+				t.Errorf("Categories = %v, want %v", categories, tt.wantCategories)
+			}
+			if strings.Join(guidelines, ",") != strings.Join(tt.wantGuidelines, ",") {
+				t.Errorf("Guidelines = %v, want %v", guidelines, tt.wantGuidelines)
+			}
+			if segmented.FrontMatter != "Introductory text." && tt.mode != StructureByRegex {
+				t.Errorf("Expected front matter to stop at the first heading, got %q", segmented.FrontMatter)
+			}
+		})
+	}
+	
+	seg, _ := NewGenericSegmenter(types.SegmenterConfig{Options: map[string]string{"structure_by": StructureByLevel}})
+	segmented, _ := seg.Segment(doc)
+	first := segmented.Categories[0]
+	if first.Title != "Access Control" || first.Guidelines[0].Title != "Passwords" {
+		t.Errorf("Expected heading text as titles, got %q / %q", first.Title, first.Guidelines[0].Title)
+	}
+	if parts := first.Guidelines[0].Parts; len(parts) != 1 || parts[0].ID != "1.1.1" || parts[0].Text != "Use at least 12 characters" {
+		t.Errorf("Expected level-3 heading as part 1.1.1, got %+v", parts)
+	}
+	
+	if _, err := NewGenericSegmenter(types.SegmenterConfig{Options: map[string]string{"structure_by": "font"}}); err == nil {
+		t.Error("Expected error for unsupported structure_by")
+	}
+}
+
+func TestFallbackCategories(t *testing.T) {
+	seg, err := NewGenericSegmenter(types.SegmenterConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	
+	// Unnumbered headings: fall back to heading levels
+	headings := &types.ParsedDocument{
+		Metadata: types.ParsedMetadata{DocumentID: "headings"},
+		Pages: []types.Page{{
+			PageNumber: 1,
+			Blocks: []types.Block{
+				{Type: types.BlockTypeHeading, Level: 1, Text: "Access Control"},
+				{Type: types.BlockTypeHeading, Level: 2, Text: "Passwords"},
+				{Type: types.BlockTypeHeading, Level: 1, Text: "Logging"},
+			},
+		}},
+	}
+	segmented, err := seg.Segment(headings)
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+	if len(segmented.Categories) != 2 || segmented.Categories[0].Title != "Access Control" {
+		t.Errorf("Expected headings as categories, got %+v", segmented.Categories)
+	}
+	if len(segmented.Warnings) != 1 {
+		t.Errorf("Expected one warning, got %v", segmented.Warnings)
+	}
+	
+	// The heading fallback keeps the caller's other options
+	limited, err := NewGenericSegmenter(types.SegmenterConfig{Options: map[string]string{"max_recommendations": "1"}})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	headings.Pages[0].Blocks = append(headings.Pages[0].Blocks[:2:2],
+		types.Block{Type: types.BlockTypeParagraph, Text: "Users should pick long passwords.\nAdmins must rotate shared passwords."},
+		headings.Pages[0].Blocks[2])
+	segmented, err = limited.Segment(headings)
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+	if guides := segmented.Categories[0].Guidelines; len(guides) != 1 || len(guides[0].Recommendations) != 1 {
+		t.Errorf("Expected max_recommendations to apply after the fallback, got %+v", guides)
+	}
+	
+	// No headings at all: wrap content in a synthetic category
+	plain := &types.ParsedDocument{
+		Metadata: types.ParsedMetadata{DocumentID: "plain"},
+		Pages: []types.Page{{
+			PageNumber: 1,
+			Blocks: []types.Block{
+				{Type: types.BlockTypeParagraph, Text: "Staff must lock their screens."},
+				{Type: types.BlockTypeList, Text: "- Use a password manager"},
+			},
+		}},
+	}
+	segmented, err = seg.Segment(plain)
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+	if len(segmented.Categories) != 1 || len(segmented.Categories[0].Guidelines) != 1 {
+		t.Fatalf("Expected a single synthetic category and guideline, got %+v", segmented.Categories)
+	}
+	if guide := segmented.Categories[0].Guidelines[0]; guide.ID != "1.1" || !strings.Contains(guide.Objective, "lock their screens") {
+		t.Errorf("Expected content in synthetic guideline, got %+v", guide)
+	}
+	if len(segmented.Warnings) != 1 {
+		t.Errorf("Expected one warning, got %v", segmented.Warnings)
+	}
+	
+	// Numbered documents don't warn
+	segmented, _ = seg.Segment(&types.ParsedDocument{Pages: []types.Page{{Blocks: []types.Block{
+		{Type: types.BlockTypeHeading, Text: "1. Access Control"},
+	}}}})
+	if len(segmented.Warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", segmented.Warnings)
+	}
+}
+
+func TestClassifyNormativity(t *testing.T) {
+	tests := []struct {
+		text string
+		want types.Normativity
+	}{
+		{"Passwords MUST be at least 12 characters.", types.NormativityMust},
+		{"The entity shall not store card data.", types.NormativityMust},
+		{"Encryption is REQUIRED for transit.", types.NormativityMust},
+		{"Sessions should time out; tokens may be reused.", types.NormativityShould},
+		{"Rotation is NOT RECOMMENDED more than daily.", types.NormativityShould},
+		{"Logging to a SIEM is OPTIONAL.", types.NormativityMay},
+		{"Review the mustang maybe.", ""},
+	}
+	for _, tt := range tests {
+		if got := ClassifyNormativity(tt.text); got != tt.want {
+			t.Errorf("ClassifyNormativity(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+	
+	seg, err := NewGenericSegmenter(types.SegmenterConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	segmented, err := seg.Segment(&types.ParsedDocument{Pages: []types.Page{{Blocks: []types.Block{
+		{Type: types.BlockTypeHeading, Text: "1. Access Control"},
+		{Type: types.BlockTypeHeading, Text: "1.1 Passwords"},
+		{Type: types.BlockTypeParagraph, Text: "Objective: Protect accounts.\nAdministrators should enable MFA."},
+		{Type: types.BlockTypeParagraph, Text: "1.1.1 Passwords must be unique."},
+		{Type: types.BlockTypeParagraph, Text: "1.1.2 Users may use a password manager."},
+	}}}})
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+	guideline := segmented.Categories[0].Guidelines[0]
+	if guideline.Normativity != types.NormativityShould {
+		t.Errorf("Expected guideline normativity 'should', got %q (recommendations %v)", guideline.Normativity, guideline.Recommendations)
+	}
+	if len(guideline.Parts) != 2 || guideline.Parts[0].Normativity != types.NormativityMust || guideline.Parts[1].Normativity != types.NormativityMay {
+		t.Errorf("Expected parts classified as must/may, got %+v", guideline.Parts)
+	}
+}
+
+func TestExtractMetadata_PatternFallthrough(t *testing.T) {
+	seg, err := NewGenericSegmenter(types.SegmenterConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	// The first version pattern matches "Version" with an empty group, so
+	// the second has to supply the value
+	seg.rules.VersionPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)version\s*([0-9.]*)`),
+		regexp.MustCompile(`(?i)rev\.\s*([0-9.]+)`),
+	}
+	meta := seg.extractMetadata(&types.ParsedDocument{Pages: []types.Page{{PageNumber: 1, Blocks: []types.Block{
+		{Type: types.BlockTypeParagraph, Text: "Title:  "},
+		{Type: types.BlockTypeParagraph, Text: "Version Rev. 2.1"},
+		{Type: types.BlockTypeParagraph, Text: "Title: Secure Coding"},
+	}}}})
+	if meta.Version != "2.1" {
+		t.Errorf("Expected version 2.1 from the second pattern, got %q", meta.Version)
+	}
+	if meta.Title != "Secure Coding" {
+		t.Errorf("Expected a blank title match to be skipped, got %q", meta.Title)
+	}
+}
+
+func TestLetteredSubParts(t *testing.T) {
+	tests := []struct {
+		name    string
+		newSeg  func() (Segmenter, error)
+		blocks  []types.Block
+		wantIDs []string
+	}{
+		{
+			name:   "generic",
+			newSeg: func() (Segmenter, error) { return NewGenericSegmenter(types.SegmenterConfig{}) },
+			blocks: []types.Block{
+				{Type: types.BlockTypeHeading, Level: 1, Text: "1. Access Control"},
+				{Type: types.BlockTypeHeading, Level: 2, Text: "1.1 Passwords"},
+				{Type: types.BlockTypeParagraph, Text: "(a) Passwords are never shared."},
+				{Type: types.BlockTypeParagraph, Text: "1.1.1 Passwords must be unique."},
+				{Type: types.BlockTypeParagraph, Text: "(a) At least 12 characters."},
+				{Type: types.BlockTypeList, Text: "Not reused across systems.", ListItem: &types.ListItem{Marker: "b.", MarkerKind: types.MarkerKindLowerAlpha}},
+				{Type: types.BlockTypeParagraph, Text: "1.1.2 Passwords must be rotated."},
+				{Type: types.BlockTypeParagraph, Text: "1.1 (c) Passwords are stored hashed."},
+			},
+			wantIDs: []string{"1.1(a)", "1.1.1", "1.1.1(a)", "1.1.1(b)", "1.1.2", "1.1(c)"},
+		},
+		{
+			name:   "nist",
+			newSeg: func() (Segmenter, error) { return NewNIST80053Segmenter(types.SegmenterConfig{}) },
+			blocks: []types.Block{
+				{Type: types.BlockTypeHeading, Level: 1, Text: "AC - ACCESS CONTROL"},
+				{Type: types.BlockTypeHeading, Level: 2, Text: "AC-2 Account Management"},
+				{Type: types.BlockTypeParagraph, Text: "a. Define the types of accounts allowed."},
+				{Type: types.BlockTypeParagraph, Text: "b. Assign account managers."},
+				{Type: types.BlockTypeParagraph, Text: "AC-2(1) Automated System Account Management"},
+				{Type: types.BlockTypeParagraph, Text: "(a) Support account management with automated mechanisms."},
+			},
+			wantIDs: []string{"AC-2a", "AC-2b", "AC-2(1)", "AC-2(1)a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seg, err := tt.newSeg()
+			if err != nil {
+				t.Fatalf("Failed to create segmenter: %v", err)
+			}
+			doc := &types.ParsedDocument{
+				Metadata: types.ParsedMetadata{DocumentID: "sub-parts"},
+				Pages:    []types.Page{{PageNumber: 1, Blocks: tt.blocks}},
+			}
+			segmented, err := seg.Segment(doc)
+			if err != nil {
+				t.Fatalf("Failed to segment document: %v", err)
+			}
+			if len(segmented.Categories) != 1 || len(segmented.Categories[0].Guidelines) != 1 {
+				t.Fatalf("Expected 1 category with 1 guideline, got %+v", segmented.Categories)
+			}
+			
+			var ids []string
+			for _, part := range segmented.Categories[0].Guidelines[0].Parts {
+				ids = append(ids, part.ID)
+			}
+			if strings.Join(ids, " ") != strings.Join(tt.wantIDs, " ") {
+				t.Errorf("Expected part IDs %v, got %v", tt.wantIDs, ids)
+			}
+			
+			// Each part records the block it came from
+			for i, part := range segmented.Categories[0].Guidelines[0].Parts {
+				if len(part.Sources) != 1 || part.Sources[0].Page != 1 {
+					t.Errorf("Part %d: expected one source block on page 1, got %+v", i, part.Sources)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSegmentWorstCaseLine(b *testing.B) {
+	seg, err := NewGenericSegmenter(types.SegmenterConfig{})
+	if err != nil {
+		b.Fatalf("Failed to create segmenter: %v", err)
+	}
+	
+	// Lines aimed at the title, version and objective patterns, which run
+	// unanchored over block text
+	for _, n := range []int{1000, 10000, 100000} {
+		text := strings.Repeat("standard v objective ", n) + "x"
+		doc := &types.ParsedDocument{
+			Metadata: types.ParsedMetadata{DocumentID: "bench"},
+			Pages: []types.Page{{
+				PageNumber: 1,
+				Blocks: []types.Block{
+					{Type: types.BlockTypeParagraph, Text: text},
+					{Type: types.BlockTypeHeading, Level: 1, Text: "1. Category"},
+					{Type: types.BlockTypeHeading, Level: 2, Text: "1.1 Guideline"},
+					{Type: types.BlockTypeParagraph, Text: text},
+				},
+			}},
+		}
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := seg.Segment(doc); err != nil {
+					b.Fatalf("Segmentation failed: %v", err)
+				}
+			}
+		})
+	}
+}
 
+func TestSegmenterSkipsFootnotes(t *testing.T) {
+	seg, err := NewGenericSegmenter(types.SegmenterConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	
+	doc := &types.ParsedDocument{Pages: []types.Page{{
+		PageNumber: 1,
+		Blocks: []types.Block{
+			{Type: types.BlockTypeHeading, Level: 1, Text: "1. Access Control"},
+			{Type: types.BlockTypeHeading, Level: 2, Text: "1.1 Passwords"},
+			{Type: types.BlockTypeParagraph, Text: "Passwords must be rotated after compromise."},
+			{Type: types.BlockTypeFootnote, Text: "2 Organizations should see NIST SP 800-63B."},
+		},
+	}}}
+	segmented, err := seg.Segment(doc)
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+	if len(segmented.Categories) != 1 || len(segmented.Categories[0].Guidelines) != 1 {
+		t.Fatalf("Expected the footnote not to start a category, got %+v", segmented.Categories)
+	}
+	guide := segmented.Categories[0].Guidelines[0]
+	for _, rec := range guide.Recommendations {
+		if strings.Contains(rec, "NIST") {
+			t.Errorf("Expected footnote to be left out of guideline text, got %q", rec)
+		}
+	}
+	if guide.Objective != "Passwords must be rotated after compromise" {
+		t.Errorf("Unexpected objective %q", guide.Objective)
+	}
+}
+
+func TestSegmenterRationale(t *testing.T) {
+	seg, err := NewGenericSegmenter(types.SegmenterConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	
+	doc := &types.ParsedDocument{Pages: []types.Page{{
+		PageNumber: 1,
+		Blocks: []types.Block{
+			{Type: types.BlockTypeHeading, Level: 1, Text: "1. Access Control"},
+			{Type: types.BlockTypeHeading, Level: 2, Text: "1.1 Passwords"},
+			{Type: types.BlockTypeHeading, Level: 3, Text: "Threats"},
+			{Type: types.BlockTypeList, Text: "Attackers who phish one password can reuse it across every service the user has signed up for"},
+			{Type: types.BlockTypeHeading, Level: 2, Text: "1.2 Sessions"},
+			{Type: types.BlockTypeList, Text: "Sessions must expire."},
+			{Type: types.BlockTypeParagraph, Text: "Benefit: Idle sessions can't be hijacked"},
+		},
+	}}}
+	segmented, err := seg.Segment(doc)
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+	
+	passwords, sessions := segmented.Categories[0].Guidelines[0], segmented.Categories[0].Guidelines[1]
+	if len(passwords.Risks) != 1 || passwords.Risks[0].Title != "Attackers who phish one password can reuse it across every..." {
+		t.Errorf("Expected a risk titled from its truncated text, got %+v", passwords.Risks)
+	}
+	// A new guideline ends the list, so its list items aren't risks
+	if len(sessions.Risks) != 0 {
+		t.Errorf("Expected no risks for 1.2, got %+v", sessions.Risks)
+	}
+	if len(sessions.Outcomes) != 1 || sessions.Outcomes[0] != (types.RationaleItem{Title: "Idle sessions can't be hijacked", Description: "Idle sessions can't be hijacked"}) {
+		t.Errorf("Unexpected outcomes: %+v", sessions.Outcomes)
+	}
+}
+
+func TestSegmenterAnnotations(t *testing.T) {
+	seg, err := NewGenericSegmenter(types.SegmenterConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	
+	doc := &types.ParsedDocument{Pages: []types.Page{{
+		PageNumber: 1,
+		Blocks: []types.Block{
+			{Type: types.BlockTypeHeading, Level: 1, Text: "1. Access Control"},
+			{Type: types.BlockTypeHeading, Level: 2, Text: "1.1 Passwords"},
+			{Type: types.BlockTypeParagraph, Text: "Passwords must be at least 12 characters.\nNote: Passphrases\nare easier to remember."},
+			{Type: types.BlockTypeParagraph, Text: "1.1.1 Rotate passwords after a breach."},
+			{Type: types.BlockTypeParagraph, Text: "WARNING: Forced periodic rotation weakens passwords."},
+		},
+	}}}
+	segmented, err := seg.Segment(doc)
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+	
+	guideline := segmented.Categories[0].Guidelines[0]
+	want := types.Annotation{Kind: types.AnnotationNote, Text: "Passphrases are easier to remember."}
+	if len(guideline.Annotations) != 1 || guideline.Annotations[0] != want {
+		t.Errorf("Expected the note on the guideline, got %+v", guideline.Annotations)
+	}
+	// A callout after a part belongs to the part
+	if len(guideline.Parts) != 1 || len(guideline.Parts[0].Annotations) != 1 || guideline.Parts[0].Annotations[0].Kind != types.AnnotationWarning {
+		t.Fatalf("Expected the warning on part 1.1.1, got %+v", guideline.Parts)
+	}
+	// Callouts are no longer merged into the guideline's text
+	for _, rec := range guideline.Recommendations {
+		if strings.Contains(rec, "Passphrases") || strings.Contains(rec, "rotation") {
+			t.Errorf("Callout text leaked into recommendations: %q", rec)
+		}
+	}
+	if guideline.Objective != "Passwords must be at least 12 characters" {
+		t.Errorf("Unexpected objective: %q", guideline.Objective)
+	}
+}
+
+func TestFlatSegmentation(t *testing.T) {
+	doc := &types.ParsedDocument{Pages: []types.Page{{Blocks: []types.Block{
+		{Type: types.BlockTypeParagraph, Text: "These requirements apply to all services."},
+		{Type: types.BlockTypeParagraph, Text: "1. Passwords must be at least 12 characters."},
+		{Type: types.BlockTypeParagraph, Text: "1.1 Passphrases are encouraged."},
+		{Type: types.BlockTypeParagraph, Text: "2. Sessions must expire after 15 minutes."},
+		{Type: types.BlockTypeParagraph, Text: "3. Logs should be kept for a year."},
+	}}}}
+	
+	seg, err := NewGenericSegmenter(types.SegmenterConfig{Options: map[string]string{"flat": "true", "flat_title": "Baseline Requirements"}})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	segmented, err := seg.Segment(doc)
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+	
+	if len(segmented.Categories) != 1 {
+		t.Fatalf("Expected a single implicit category, got %+v", segmented.Categories)
+	}
+	category := segmented.Categories[0]
+	if category.ID != "baseline-requirements" || category.Title != "Baseline Requirements" {
+		t.Errorf("Unexpected implicit category %q %q", category.ID, category.Title)
+	}
+	var ids []string
+	for _, guideline := range category.Guidelines {
+		ids = append(ids, guideline.ID)
+	}
+	if strings.Join(ids, ",") != "1,2,3" {
+		t.Errorf("Expected top-level items as guidelines 1,2,3, got %v", ids)
+	}
+	if first := category.Guidelines[0]; first.Title != "Passwords must be at least 12 characters." || len(first.Parts) != 1 || first.Parts[0].ID != "1.1" {
+		t.Errorf("Expected second-level items as parts, got %+v", first)
+	}
+	if segmented.FrontMatter != "These requirements apply to all services." {
+		t.Errorf("Expected text before the first item as front matter, got %q", segmented.FrontMatter)
+	}
+	
+	// Without flat mode each top-level item becomes its own category
+	seg, _ = NewGenericSegmenter(types.SegmenterConfig{})
+	segmented, _ = seg.Segment(doc)
+	if len(segmented.Categories) != 3 || len(segmented.Categories[0].Guidelines) != 1 {
+		t.Errorf("Expected numbered items as categories without flat mode, got %+v", segmented.Categories)
+	}
+}
+
+func TestPromoteObjectives(t *testing.T) {
+	seg, err := NewGenericSegmenter(types.SegmenterConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	
+	// Objectives numbered as parts, as when the numbering is off by a level
+	doc := &types.ParsedDocument{Pages: []types.Page{{Blocks: []types.Block{
+		{Type: types.BlockTypeHeading, Text: "1. Access Control"},
+		{Type: types.BlockTypeHeading, Text: "1.1 Passwords"},
+		{Type: types.BlockTypeParagraph, Text: "1.1.1 Objective: Protect accounts from takeover. Use MFA everywhere."},
+		{Type: types.BlockTypeParagraph, Text: "1.1.2 Rotate keys."},
+		{Type: types.BlockTypeHeading, Text: "1.2 Sessions"},
+		{Type: types.BlockTypeParagraph, Text: "1.2.1 Purpose: Limit exposure of idle sessions"},
+		{Type: types.BlockTypeParagraph, Text: "1.2.2 Expire sessions after 15 minutes."},
+	}}}}
+	segmented, err := seg.Segment(doc)
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+	
+	passwords, sessions := segmented.Categories[0].Guidelines[0], segmented.Categories[0].Guidelines[1]
+	if passwords.Objective != "Protect accounts from takeover." {
+		t.Errorf("Expected the objective sentence to be promoted, got %q", passwords.Objective)
+	}
+	if len(passwords.Parts) != 2 || passwords.Parts[0].Text != "Use MFA everywhere." {
+		t.Errorf("Expected the rest of the part text to stay, got %+v", passwords.Parts)
+	}
+	
+	// A part holding only the objective is dropped, its source kept
+	if sessions.Objective != "Limit exposure of idle sessions" {
+		t.Errorf("Expected the purpose to be promoted, got %q", sessions.Objective)
+	}
+	if len(sessions.Parts) != 1 || sessions.Parts[0].ID != "1.2.2" {
+		t.Errorf("Expected the emptied part to be dropped, got %+v", sessions.Parts)
+	}
+	if !slices.Contains(sessions.Sources, types.SourceRef{Page: 0, Block: 5}) {
+		t.Errorf("Expected the dropped part's source on the guideline, got %+v", sessions.Sources)
+	}
+}
+
+func TestMaxRecommendations(t *testing.T) {
+	text := strings.Join([]string{
+		"Objective: Protect accounts.",
+		"This section provides implementation guidance for administrators.",
+		"Administrators should review access quarterly.",
+		"See the implementation notes in Appendix B.",
+		"Ensure MFA is enabled; this guidance covers all accounts.",
+		"Passwords must not be reused.",
+		"Guidance: rotate recovery codes after use.",
+	}, "\n")
+	doc := &types.ParsedDocument{Pages: []types.Page{{Blocks: []types.Block{
+		{Type: types.BlockTypeHeading, Text: "1. Access Control"},
+		{Type: types.BlockTypeHeading, Text: "1.1 Passwords"},
+		{Type: types.BlockTypeParagraph, Text: text},
+	}}}}
+	
+	seg, err := NewGenericSegmenter(types.SegmenterConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	segmented, err := seg.Segment(doc)
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+	if got := segmented.Categories[0].Guidelines[0].Recommendations; len(got) != 6 {
+		t.Errorf("Expected all 6 keyword lines without a cap, got %d: %v", len(got), got)
+	}
+	
+	seg, err = NewGenericSegmenter(types.SegmenterConfig{Options: map[string]string{"max_recommendations": "3"}})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	segmented, err = seg.Segment(doc)
+	if err != nil {
+		t.Fatalf("Segmentation failed: %v", err)
+	}
+	// Lines opening with an imperative or keyword come first, then stated
+	// requirements; passing mentions are dropped. Document order is kept.
+	want := []string{
+		"Administrators should review access quarterly.",
+		"Ensure MFA is enabled; this guidance covers all accounts.",
+		"Guidance: rotate recovery codes after use.",
+	}
+	got := segmented.Categories[0].Guidelines[0].Recommendations
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Recommendations = %v, want %v", got, want)
+	}
+	
+	if _, err := NewGenericSegmenter(types.SegmenterConfig{Options: map[string]string{"max_recommendations": "-1"}}); err == nil {
+		t.Error("Expected error for a negative max_recommendations")
+	}
+}
+
+func TestParseMappings(t *testing.T) {
+	tests := []struct {
+		line string
+		want map[string][]string // Framework to entries
+	}{
+		{"Maps to ISO 27001 A.9.2, A.9.4 and NIST CSF PR.AC-1.", map[string][]string{"ISO 27001": {"A.9.2", "A.9.4"}, "NIST CSF": {"PR.AC-1"}}},
+		{"See also NIST SP 800-53 AC-2(1), AC-3 for access reviews.", map[string][]string{"NIST SP 800-53": {"AC-2(1)", "AC-3"}}},
+		{"Cross-reference: CIS Controls v8 5.3; PCI DSS 4.0 8.3.1", map[string][]string{"CIS Controls v8": {"5.3"}, "PCI DSS 4.0": {"8.3.1"}}},
+		{"This control is mapped to (ISO/IEC 27001:2013 A.12.4.1).", map[string][]string{"ISO/IEC 27001:2013": {"A.12.4.1"}}},
+		{"See also the OWASP ASVS.", map[string][]string{"OWASP ASVS": nil}},
+		{"See also section 3.1.", map[string][]string{}},
+		{"See also: https://example.org/guide", map[string][]string{}},
+		{"Passwords must be rotated.", map[string][]string{}},
+	}
+	for _, tt := range tests {
+		got := map[string][]string{}
+		for _, mapping := range parseMappings(tt.line) {
+			got[mapping.Framework] = mapping.Entries
+			if mapping.Text != tt.line {
+				t.Errorf("%q: expected the line as the mapping text, got %q", tt.line, mapping.Text)
+			}
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.line, got, tt.want)
+			continue
+		}
+		for framework, entries := range tt.want {
+			if !slices.Equal(got[framework], entries) {
+				t.Errorf("%q: %s entries = %v, want %v", tt.line, framework, got[framework], entries)
+			}
+		}
+	}
+}
+
+func TestSegmenterRecordsSources(t *testing.T) {
+	seg, err := NewGenericSegmenter(types.SegmenterConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	
+	doc := &types.ParsedDocument{
+		Metadata: types.ParsedMetadata{DocumentID: "traced"},
+		Pages: []types.Page{
+			{PageNumber: 1, Blocks: []types.Block{
+				{Type: types.BlockTypeHeading, Level: 1, Text: "1. Access Control"},
+				{Type: types.BlockTypeParagraph, Text: "Control access to systems."},
+				{Type: types.BlockTypeHeading, Level: 2, Text: "1.1 Passwords"},
+			}},
+			{PageNumber: 2, Blocks: []types.Block{
+				{Type: types.BlockTypeParagraph, Text: "Passwords must be strong."},
+				{Type: types.BlockTypeParagraph, Text: "1.1.1 Passwords must be unique."},
+				{Type: types.BlockTypeCode, Text: "$ passwd --expire alice"},
+			}},
+		},
+	}
+	segmented, err := seg.Segment(doc)
+	if err != nil {
+		t.Fatalf("Failed to segment document: %v", err)
+	}
+	
+	category := segmented.Categories[0]
+	if want := []types.SourceRef{{Page: 1, Block: 0}, {Page: 1, Block: 1}}; !slices.Equal(category.Sources, want) {
+		t.Errorf("Expected category sources %v, got %v", want, category.Sources)
+	}
+	guideline := category.Guidelines[0]
+	if want := []types.SourceRef{{Page: 1, Block: 2}, {Page: 2, Block: 0}, {Page: 2, Block: 2}}; !slices.Equal(guideline.Sources, want) {
+		t.Errorf("Expected guideline sources %v, got %v", want, guideline.Sources)
+	}
+	if len(guideline.Code) != 1 || guideline.Code[0] != "$ passwd --expire alice" {
+		t.Errorf("Expected the code example kept on the guideline, got %q", guideline.Code)
+	}
+	if want := []types.SourceRef{{Page: 2, Block: 1}}; len(guideline.Parts) != 1 || !slices.Equal(guideline.Parts[0].Sources, want) {
+		t.Errorf("Expected part sources %v, got %+v", want, guideline.Parts)
+	}
+}
+
+func TestLoadRulesFromFile(t *testing.T) {
+	dir := t.TempDir()
+	rulesPath := filepath.Join(dir, "rules.yaml")
+	rules := `category_pattern: '^Chapter ([0-9]+):\s+(.+)'
+guideline_pattern: '^Rule ([0-9]+\.[0-9]+)\s+-\s+(.+)'
+title_patterns:
+  - '^(Acme Hardening Guide)'
+recommendation_keywords: [configure]
+`
+	if err := os.WriteFile(rulesPath, []byte(rules), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	
+	seg, err := NewSegmenter(types.SegmenterConfig{DocumentType: "generic", RulesFile: rulesPath})
+	if err != nil {
+		t.Fatalf("Failed to create segmenter: %v", err)
+	}
+	doc := &types.ParsedDocument{
+		Pages: []types.Page{{PageNumber: 1, Blocks: []types.Block{
+			{Type: types.BlockTypeHeading, Level: 1, Text: "Acme Hardening Guide"},
+			{Type: types.BlockTypeHeading, Level: 1, Text: "Chapter 1: Network"},
+			{Type: types.BlockTypeHeading, Level: 2, Text: "Rule 1.1 - Firewalls"},
+			{Type: types.BlockTypeParagraph, Text: "Configure a host firewall."},
+			{Type: types.BlockTypeHeading, Level: 2, Text: "1.2 Not a rule"},
+		}}},
+	}
+	segmented, err := seg.Segment(doc)
+	if err != nil {
+		t.Fatalf("Failed to segment document: %v", err)
+	}
+	
+	if segmented.DocumentMetadata.Title != "Acme Hardening Guide" {
+		t.Errorf("Expected title from the rules file pattern, got %q", segmented.DocumentMetadata.Title)
+	}
+	if len(segmented.Categories) != 1 || segmented.Categories[0].ID != "1" || segmented.Categories[0].Title != "Network" {
+		t.Fatalf("Expected category 1 Network, got %+v", segmented.Categories)
+	}
+	guidelines := segmented.Categories[0].Guidelines
+	if len(guidelines) != 1 || guidelines[0].ID != "1.1" || guidelines[0].Title != "Firewalls" {
+		t.Fatalf("Expected only guideline 1.1 Firewalls, got %+v", guidelines)
+	}
+	if !slices.Contains(guidelines[0].Recommendations, "Configure a host firewall.") {
+		t.Errorf("Expected the rules file keyword to select the recommendation, got %q", guidelines[0].Recommendations)
+	}
+	
+	// Invalid regexes, missing capture groups and unknown keys name the
+	// offending field
+	for _, tt := range []struct {
+		rules string
+		want  string
+	}{
+		{"guideline_pattern: '^(Rule'\n", "guideline_pattern"},
+		{"author_patterns: ['^by (.+)', '[a-']\n", "author_patterns[1]"},
+		{"categry_pattern: '^x'\n", "categry_pattern"},
+		{"category_pattern: '^Chapter [0-9]+'\n", "category_pattern: needs 2 capture groups"},
+		{"sub_part_pattern: '^\\(([a-z])\\)\\s+(.*)'\n", "sub_part_pattern: needs 3 capture groups"},
+		{"version_patterns: ['^Version [0-9.]+']\n", "version_patterns[0]"},
+	} {
+		if err := os.WriteFile(rulesPath, []byte(tt.rules), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := NewSegmenter(types.SegmenterConfig{DocumentType: "generic", RulesFile: rulesPath})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expected an error naming %s, got %v", tt.want, err)
+		}
+	}
+}
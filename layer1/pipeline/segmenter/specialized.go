@@ -15,10 +15,6 @@ type PCIDSSSegmenter struct {
 // NewPCIDSSSegmenter creates a new PCI-DSS segmenter
 func NewPCIDSSSegmenter(config types.SegmenterConfig) (*PCIDSSSegmenter, error) {
 	s := &PCIDSSSegmenter{}
-	if err := s.Configure(config); err != nil {
-		return nil, err
-	}
-	
 	// Initialize PCI-DSS specific rules
 	s.rules = &SegmentationRules{
 		// PCI-DSS uses patterns like:
@@ -50,12 +46,18 @@ func NewPCIDSSSegmenter(config types.SegmenterConfig) (*PCIDSSSegmenter, error)
 		RequirementKeywords: []string{
 			"requirement", "must", "shall",
 		},
+		RiskKeywords:    defaultRiskKeywords,
+		OutcomeKeywords: defaultOutcomeKeywords,
 		
 		CategoryHeadingLevel:  1,
 		GuidelineHeadingLevel: 2,
 		PartHeadingLevel:      3,
 	}
 	
+	if err := s.Configure(config); err != nil {
+		return nil, err
+	}
+	
 	return s, nil
 }
 
@@ -169,19 +171,18 @@ type NIST80053Segmenter struct {
 // NewNIST80053Segmenter creates a new NIST 800-53 segmenter
 func NewNIST80053Segmenter(config types.SegmenterConfig) (*NIST80053Segmenter, error) {
 	s := &NIST80053Segmenter{}
-	if err := s.Configure(config); err != nil {
-		return nil, err
-	}
-	
 	// Initialize NIST 800-53 specific rules
 	s.rules = &SegmentationRules{
 		// NIST 800-53 uses patterns like:
 		// "AC - ACCESS CONTROL"
 		// "AC-1 Policy and Procedures"
 		// "AC-2(1) Automated System Account Management"
+		// "a. Define and document the types of accounts allowed..."
 		CategoryPattern:  regexp.MustCompile(`^([A-Z]{2,3})\s*[-–]\s*([A-Z\s]+)`),
 		GuidelinePattern: regexp.MustCompile(`^([A-Z]{2,3}-[0-9]+)\s+([A-Z].*)`),
-		PartPattern:      regexp.MustCompile(`^([A-Z]{2,3}-[0-9]+)\(([0-9]+)\)\s+(.*)`),
+		PartPattern:      regexp.MustCompile(`^([A-Z]{2,3}-[0-9]+\([0-9]+\))\s+(.*)`),
+		SubPartPattern:   regexp.MustCompile(`^(?:([A-Z]{2,3}-[0-9]+(?:\([0-9]+\))?)\s*)?\(?([a-z])[.)]\s+(.*)`),
+		SubPartIDFormat:  "%s%s", // "AC-2a", "AC-2(4)a"
 		
 		TitlePatterns: []*regexp.Regexp{
 			regexp.MustCompile(`(?i)NIST.*800-53`),
@@ -205,12 +206,18 @@ func NewNIST80053Segmenter(config types.SegmenterConfig) (*NIST80053Segmenter, e
 		RequirementKeywords: []string{
 			"control", "requirement",
 		},
+		RiskKeywords:    defaultRiskKeywords,
+		OutcomeKeywords: defaultOutcomeKeywords,
 		
 		CategoryHeadingLevel:  1,
 		GuidelineHeadingLevel: 2,
 		PartHeadingLevel:      3,
 	}
 	
+	if err := s.Configure(config); err != nil {
+		return nil, err
+	}
+	
 	return s, nil
 }
 
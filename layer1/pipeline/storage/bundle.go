@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// bundleManifestName names the manifest at the root of a bundle
+const bundleManifestName = "manifest.json"
+
+// coverageReportSuffix matches the "-{YYYYMMDD-HHMMSS}.json" suffix of a
+// coverage report file name after its document ID
+var coverageReportSuffix = regexp.MustCompile(`^-\d{8}-\d{6}\.json$`)
+
+// BundleFile is a stored file that belongs in a document's bundle
+type BundleFile struct {
+	Name string // Slash-separated path within the bundle, e.g. "validation-reports/convert-20250101-120000.json"
+	Path string // Path on disk
+}
+
+// BundleManifest lists the contents of a bundle, so reviewers can check
+// that nothing was altered or left out
+type BundleManifest struct {
+	DocumentID string               `json:"document_id" yaml:"document_id"`
+	CreatedAt  time.Time            `json:"created_at" yaml:"created_at"`
+	Files      []BundleManifestFile `json:"files" yaml:"files"`
+}
+
+// BundleManifestFile records a bundled file's size and SHA-256 checksum
+type BundleManifestFile struct {
+	Name     string `json:"name" yaml:"name"`
+	Size     int64  `json:"size" yaml:"size"`
+	Checksum string `json:"checksum" yaml:"checksum"`
+}
+
+// BundleFiles enumerates a document's QA history: the final document and
+// its provenance, every validation report and every coverage report.
+// Returns an error if the document has no final version.
+func (s *Storage) BundleFiles(documentID string) ([]BundleFile, error) {
+	finalPath, err := s.FinalPath(documentID)
+	if err != nil {
+		return nil, err
+	}
+	files := []BundleFile{{Name: "final/" + filepath.Base(finalPath), Path: finalPath}}
+
+	provPath := filepath.Join(s.baseDir, "final", documentID+provenanceSuffix)
+	if _, err := os.Stat(provPath); err == nil {
+		files = append(files, BundleFile{Name: "final/" + documentID + provenanceSuffix, Path: provPath})
+	}
+
+	reports, err := listFiles(filepath.Join(s.baseDir, "validation-reports", documentID), func(name string) bool {
+		return strings.HasSuffix(name, ".json")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read validation reports directory: %w", err)
+	}
+	for _, name := range reports {
+		files = append(files, BundleFile{
+			Name: "validation-reports/" + name,
+			Path: filepath.Join(s.baseDir, "validation-reports", documentID, name),
+		})
+	}
+
+	// Coverage reports of all documents share a directory, named
+	// {document-id}-{timestamp}.json
+	coverage, err := listFiles(filepath.Join(s.baseDir, "coverage-reports"), func(name string) bool {
+		return strings.HasPrefix(name, documentID) && coverageReportSuffix.MatchString(name[len(documentID):])
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read coverage reports directory: %w", err)
+	}
+	for _, name := range coverage {
+		files = append(files, BundleFile{
+			Name: "coverage-reports/" + name,
+			Path: filepath.Join(s.baseDir, "coverage-reports", name),
+		})
+	}
+
+	return files, nil
+}
+
+// listFiles returns the sorted names of the regular files in dir accepted by
+// keep, or none if dir doesn't exist
+func listFiles(dir string, keep func(name string) bool) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && keep(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// WriteBundle writes a document's BundleFiles to w as a gzip-compressed
+// tarball. Entries sit under a {document-id}/ directory, led by a
+// manifest.json listing every file with its checksum.
+func (s *Storage) WriteBundle(documentID string, w io.Writer) (*BundleManifest, error) {
+	files, err := s.BundleFiles(documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read everything up front: the manifest comes first but needs the
+	// checksums
+	manifest := &BundleManifest{DocumentID: documentID, CreatedAt: time.Now()}
+	contents := make([][]byte, len(files))
+	modTimes := make([]time.Time, len(files))
+	for i, file := range files {
+		info, err := os.Stat(file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+		}
+		data, err := os.ReadFile(file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+		}
+		contents[i], modTimes[i] = data, info.ModTime()
+		manifest.Files = append(manifest.Files, BundleManifestFile{
+			Name:     file.Name,
+			Size:     int64(len(data)),
+			Checksum: checksum(data),
+		})
+	}
+
+	manifestData, err := MarshalCanonicalJSON(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bundle manifest: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	writeEntry := func(name string, data []byte, modTime time.Time) error {
+		header := &tar.Header{
+			Name:    path.Join(documentID, name),
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: modTime,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write bundle entry %s: %w", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("failed to write bundle entry %s: %w", name, err)
+		}
+		return nil
+	}
+
+	if err := writeEntry(bundleManifestName, manifestData, manifest.CreatedAt); err != nil {
+		return nil, err
+	}
+	for i, file := range files {
+		if err := writeEntry(file.Name, contents[i], modTimes[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	return manifest, nil
+}
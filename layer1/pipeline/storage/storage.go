@@ -1,8 +1,13 @@
 package storage
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -15,6 +20,13 @@ import (
 	"github.com/ossf/gemara/layer1/pipeline/types"
 )
 
+// ErrNoChecksum is returned by VerifyChecksum for versions stored before
+// checksums were recorded; their integrity is unverified rather than bad
+var ErrNoChecksum = errors.New("no checksum recorded")
+
+// provenanceSuffix names the provenance sidecar of a final document
+const provenanceSuffix = ".provenance.json"
+
 // Storage manages versioned intermediate and final outputs
 type Storage struct {
 	baseDir string
@@ -51,7 +63,7 @@ func (s *Storage) SaveParsed(doc *types.ParsedDocument) error {
 
 	// Save parsed document
 	filePath := filepath.Join(dir, "parsed.json")
-	data, err := json.MarshalIndent(doc, "", "  ")
+	data, err := MarshalCanonicalJSON(doc)
 	if err != nil {
 		return fmt.Errorf("failed to marshal parsed document: %w", err)
 	}
@@ -67,6 +79,7 @@ func (s *Storage) SaveParsed(doc *types.ParsedDocument) error {
 		Type:       "parsed",
 		StoredAt:   time.Now(),
 		Size:       int64(len(data)),
+		Checksum:   checksum(data),
 	}
 	return s.saveMetadataWithType(dir, meta, "parsed")
 }
@@ -103,7 +116,7 @@ func (s *Storage) SaveSegmented(doc *types.SegmentedDocument) error {
 
 	// Save segmented document
 	filePath := filepath.Join(dir, "segmented.json")
-	data, err := json.MarshalIndent(doc, "", "  ")
+	data, err := MarshalCanonicalJSON(doc)
 	if err != nil {
 		return fmt.Errorf("failed to marshal segmented document: %w", err)
 	}
@@ -119,6 +132,7 @@ func (s *Storage) SaveSegmented(doc *types.SegmentedDocument) error {
 		Type:       "segmented",
 		StoredAt:   time.Now(),
 		Size:       int64(len(data)),
+		Checksum:   checksum(data),
 	}
 	return s.saveMetadataWithType(dir, meta, "segmented")
 }
@@ -145,9 +159,22 @@ func (s *Storage) LoadSegmented(documentID string, version int) (*types.Segmente
 
 // SaveFinal saves the final Layer-1 document
 func (s *Storage) SaveFinal(documentID string, data interface{}, format string) error {
-	dir := filepath.Join(s.baseDir, "final")
+	_, err := s.writeDocument("final", documentID, data, format)
+	return err
+}
+
+// SaveInvalid saves a Layer-1 document that failed validation to
+// invalid/{document-id}.{format}, apart from final documents, so it can be
+// inspected and fixed. Returns the path written.
+func (s *Storage) SaveInvalid(documentID string, data interface{}, format string) (string, error) {
+	return s.writeDocument("invalid", documentID, data, format)
+}
+
+// writeDocument encodes a document as YAML or JSON to {subdir}/{document-id}.{format}
+func (s *Storage) writeDocument(subdir, documentID string, data interface{}, format string) (string, error) {
+	dir := filepath.Join(s.baseDir, subdir)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create final directory: %w", err)
+		return "", fmt.Errorf("failed to create %s directory: %w", subdir, err)
 	}
 
 	var fileName string
@@ -160,53 +187,202 @@ func (s *Storage) SaveFinal(documentID string, data interface{}, format string)
 		fileData, err = yaml.Marshal(data)
 	case "json":
 		fileName = fmt.Sprintf("%s.json", documentID)
-		fileData, err = json.MarshalIndent(data, "", "  ")
+		fileData, err = MarshalCanonicalJSON(data)
 	default:
-		return fmt.Errorf("unsupported format: %s", format)
+		return "", fmt.Errorf("unsupported format: %s", format)
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to marshal final document: %w", err)
+		return "", fmt.Errorf("failed to marshal %s document: %w", subdir, err)
 	}
 
 	filePath := filepath.Join(dir, fileName)
 	if err := os.WriteFile(filePath, fileData, 0644); err != nil {
-		return fmt.Errorf("failed to write final document: %w", err)
+		return "", fmt.Errorf("failed to write %s document: %w", subdir, err)
 	}
 
-	return nil
+	return filePath, nil
+}
+
+// SaveFinalVersion stores an already encoded final document ("yaml" or
+// "json") as the next final version, with its checksum and a label such as
+// "imported from my-standard.yaml", and makes it the current final
+// document. The bytes are kept as given, so an externally edited file keeps
+// its formatting and comments. Final versions share the version directories
+// of parsed and segmented versions but are numbered on their own.
+func (s *Storage) SaveFinalVersion(documentID string, data []byte, format, label string) (*StorageMetadata, error) {
+	switch format {
+	case "yaml", "yml":
+		format = "yaml"
+	case "json":
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+
+	version := s.getNextVersion(documentID, "final")
+	dir := filepath.Join(s.baseDir, "intermediate", documentID, fmt.Sprintf("v%d", version))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create version directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "final."+format), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write final version: %w", err)
+	}
+
+	meta := StorageMetadata{
+		DocumentID:  documentID,
+		Version:     version,
+		Type:        "final",
+		StoredAt:    time.Now(),
+		Size:        int64(len(data)),
+		Checksum:    checksum(data),
+		Description: label,
+	}
+	if err := s.saveMetadataWithType(dir, meta, "final"); err != nil {
+		return nil, err
+	}
+
+	// Replace the current final document, removing one in another format
+	// that FinalPath would otherwise prefer
+	finalDir := filepath.Join(s.baseDir, "final")
+	if err := os.MkdirAll(finalDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create final directory: %w", err)
+	}
+	for _, ext := range []string{".yaml", ".yml", ".json"} {
+		if err := os.Remove(filepath.Join(finalDir, documentID+ext)); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to replace final document: %w", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(finalDir, documentID+"."+format), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write final document: %w", err)
+	}
+
+	return &meta, nil
 }
 
 // LoadFinal loads a final Layer-1 document by document ID
 func (s *Storage) LoadFinal(documentID string) (*layer1.GuidanceDocument, error) {
+	return s.loadFinal(documentID, false)
+}
+
+// LoadFinalStrict is LoadFinal rejecting keys that aren't Layer-1 fields,
+// see DecodeLayer1
+func (s *Storage) LoadFinalStrict(documentID string) (*layer1.GuidanceDocument, error) {
+	return s.loadFinal(documentID, true)
+}
+
+func (s *Storage) loadFinal(documentID string, strict bool) (*layer1.GuidanceDocument, error) {
+	filePath, err := s.FinalPath(documentID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read final document: %w", err)
+	}
+
+	return DecodeLayer1(data, strings.TrimPrefix(filepath.Ext(filePath), "."), strict)
+}
+
+// FinalPath returns the path of a final Layer-1 document, preferring YAML
+// over JSON when both exist
+func (s *Storage) FinalPath(documentID string) (string, error) {
 	dir := filepath.Join(s.baseDir, "final")
 
-	// Try YAML first, then JSON
 	for _, ext := range []string{".yaml", ".yml", ".json"} {
 		filePath := filepath.Join(dir, documentID+ext)
-		data, err := os.ReadFile(filePath)
-		if err != nil {
+		if _, err := os.Stat(filePath); err != nil {
 			if os.IsNotExist(err) {
 				continue
 			}
-			return nil, fmt.Errorf("failed to read final document: %w", err)
+			return "", fmt.Errorf("failed to read final document: %w", err)
 		}
+		return filePath, nil
+	}
 
-		var doc layer1.GuidanceDocument
-		if strings.HasSuffix(ext, "json") {
-			if err := json.Unmarshal(data, &doc); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
-			}
-		} else {
-			if err := yaml.Unmarshal(data, &doc); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal YAML: %w", err)
-			}
+	return "", fmt.Errorf("final document not found: %s", documentID)
+}
+
+// DecodeLayer1 decodes a Layer-1 document in the given format ("yaml",
+// "yml" or "json"). Unknown keys are normally ignored; with strict they are
+// errors, which catches typos (e.g. "titel") in hand-edited files that would
+// otherwise surface as confusing empty-field validation failures.
+func DecodeLayer1(data []byte, format string, strict bool) (*layer1.GuidanceDocument, error) {
+	var doc layer1.GuidanceDocument
+	switch format {
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		if strict {
+			dec.DisallowUnknownFields()
 		}
+		if err := dec.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+	case "yaml", "yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(strict)
+		// An empty document decodes to EOF rather than a zero value
+		if err := dec.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to unmarshal YAML: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+	return &doc, nil
+}
 
-		return &doc, nil
+// ListFinal lists the IDs of all stored final documents, sorted
+func (s *Storage) ListFinal() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.baseDir, "final"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read final directory: %w", err)
 	}
 
-	return nil, fmt.Errorf("final document not found: %s", documentID)
+	seen := make(map[string]bool)
+	ids := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(entry.Name())
+		if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+			continue
+		}
+		if strings.HasSuffix(entry.Name(), provenanceSuffix) {
+			continue
+		}
+		id := strings.TrimSuffix(entry.Name(), ext)
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// StreamFinal writes every final document to w as NDJSON: one compact JSON
+// document per line, in ListFinal order, for bulk loading into indexers
+func (s *Storage) StreamFinal(w io.Writer) error {
+	ids, err := s.ListFinal()
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	for _, id := range ids {
+		doc, err := s.LoadFinal(id)
+		if err != nil {
+			return fmt.Errorf("failed to load final document %s: %w", id, err)
+		}
+		// Encode terminates each document with a newline
+		if err := enc.Encode(doc); err != nil {
+			return fmt.Errorf("failed to write final document %s: %w", id, err)
+		}
+	}
+	return nil
 }
 
 // ListVersions lists all versions for a document and type
@@ -264,6 +440,149 @@ func (s *Storage) ListVersions(documentID, docType string) ([]StorageMetadata, e
 	return metas, nil
 }
 
+// VerifyChecksum recomputes the SHA-256 of a stored parsed, segmented or
+// final version (0 = latest) and reports whether it matches the checksum
+// recorded when it was saved. Versions without a recorded checksum return
+// ErrNoChecksum.
+func (s *Storage) VerifyChecksum(documentID, docType string, version int) (bool, error) {
+	if docType != "parsed" && docType != "segmented" && docType != "final" {
+		return false, fmt.Errorf("unsupported document type: %s", docType)
+	}
+	if version == 0 {
+		version = s.getLatestVersion(documentID, docType)
+	}
+
+	dir := filepath.Join(s.baseDir, "intermediate", documentID, fmt.Sprintf("v%d", version))
+	metaData, err := os.ReadFile(filepath.Join(dir, fmt.Sprintf("metadata-%s.json", docType)))
+	if err != nil {
+		// Fallback to generic metadata.json, as ListVersions does
+		if metaData, err = os.ReadFile(filepath.Join(dir, "metadata.json")); err != nil {
+			return false, fmt.Errorf("failed to read metadata: %w", err)
+		}
+	}
+	var meta StorageMetadata
+	if err := json.Unmarshal(metaData, &meta); err != nil {
+		return false, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+	if meta.Checksum == "" {
+		return false, ErrNoChecksum
+	}
+
+	docPath := filepath.Join(dir, docType+".json")
+	if docType == "final" {
+		docPath = finalVersionPath(dir)
+	}
+	data, err := os.ReadFile(docPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s document: %w", docType, err)
+	}
+	return checksum(data) == meta.Checksum, nil
+}
+
+// DeleteVersion removes a version directory, including every document type
+// and artifact stored in it
+func (s *Storage) DeleteVersion(documentID string, version int) error {
+	if version < 1 {
+		return fmt.Errorf("invalid version: %d", version)
+	}
+	dir := filepath.Join(s.baseDir, "intermediate", documentID, fmt.Sprintf("v%d", version))
+	if _, err := os.Stat(dir); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("version %d of %s not found", version, documentID)
+		}
+		return fmt.Errorf("failed to read version directory: %w", err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to delete version directory: %w", err)
+	}
+	return nil
+}
+
+// versionFiles lists the files each document type stores in a version
+// directory; the LLM artifact belongs to the segmented version it produced
+var versionFiles = map[string][]string{
+	"parsed":    {"parsed.json", "metadata-parsed.json"},
+	"segmented": {"segmented.json", "metadata-segmented.json", "llm-artifact.json"},
+}
+
+// PruneVersions keeps the keep newest parsed and segmented versions of a
+// document and removes the older ones. Parsed and segmented versions share
+// version directories, so an expired type's files are removed on their own
+// and a directory is only deleted once no document is left in it. Final
+// versions are never pruned.
+func (s *Storage) PruneVersions(documentID string, keep int) error {
+	if keep < 0 {
+		return fmt.Errorf("invalid keep count: %d", keep)
+	}
+
+	expired := make(map[int]bool)
+	for _, docType := range []string{"parsed", "segmented"} {
+		versions, err := s.ListVersions(documentID, docType)
+		if err != nil {
+			return err
+		}
+		if len(versions) <= keep {
+			continue
+		}
+		for _, v := range versions[keep:] {
+			dir := filepath.Join(s.baseDir, "intermediate", documentID, fmt.Sprintf("v%d", v.Version))
+			for _, name := range versionFiles[docType] {
+				if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("failed to remove %s v%d: %w", docType, v.Version, err)
+				}
+			}
+			expired[v.Version] = true
+		}
+	}
+
+	for version := range expired {
+		dir := filepath.Join(s.baseDir, "intermediate", documentID, fmt.Sprintf("v%d", version))
+		if hasVersionDocument(dir) {
+			continue
+		}
+		if err := s.DeleteVersion(documentID, version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finalVersionFiles are the files a final version may be stored in
+var finalVersionFiles = []string{"final.yaml", "final.json"}
+
+// finalVersionPath returns the path of the final version in a version
+// directory, whichever format it was saved in
+func finalVersionPath(dir string) string {
+	for _, name := range finalVersionFiles {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return filepath.Join(dir, name)
+		}
+	}
+	return filepath.Join(dir, finalVersionFiles[0])
+}
+
+// hasVersionDocument reports whether a version directory still holds a
+// parsed, segmented or final document
+func hasVersionDocument(dir string) bool {
+	for _, files := range versionFiles {
+		if _, err := os.Stat(filepath.Join(dir, files[0])); err == nil {
+			return true
+		}
+	}
+	for _, name := range finalVersionFiles {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checksum returns the hex-encoded SHA-256 of data
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
 // getNextVersion determines the next version number
 func (s *Storage) getNextVersion(documentID, docType string) int {
 	latest := s.getLatestVersion(documentID, docType)
@@ -282,7 +601,7 @@ func (s *Storage) getLatestVersion(documentID, docType string) int {
 // saveMetadataWithType saves metadata for a version with type-specific filename
 func (s *Storage) saveMetadataWithType(dir string, meta StorageMetadata, docType string) error {
 	metaPath := filepath.Join(dir, fmt.Sprintf("metadata-%s.json", docType))
-	data, err := json.MarshalIndent(meta, "", "  ")
+	data, err := MarshalCanonicalJSON(meta)
 	if err != nil {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
@@ -299,7 +618,7 @@ func (s *Storage) saveMetadataWithType(dir string, meta StorageMetadata, docType
 //nolint:unused // Reserved for metadata persistence feature
 func (s *Storage) saveMetadata(dir string, meta StorageMetadata) error {
 	metaPath := filepath.Join(dir, "metadata.json")
-	data, err := json.MarshalIndent(meta, "", "  ")
+	data, err := MarshalCanonicalJSON(meta)
 	if err != nil {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
@@ -324,8 +643,13 @@ type ValidationReport struct {
 	Valid         bool                `json:"valid" yaml:"valid"`
 	ErrorCount    int                 `json:"error_count" yaml:"error_count"`
 	Errors        []ValidationError   `json:"errors,omitempty" yaml:"errors,omitempty"`
+	WarningCount  int                 `json:"warning_count" yaml:"warning_count"`
+	Warnings      []ValidationError   `json:"warnings,omitempty" yaml:"warnings,omitempty"`
 	SourceVersion int                 `json:"source_version,omitempty" yaml:"source_version,omitempty"`
-	Stage         string              `json:"stage" yaml:"stage"` // "convert", "enhance", "validate"
+	Stage         string              `json:"stage" yaml:"stage"` // "convert", "enhance", "validate", "revalidate", "import"
+	Unvalidated   bool                `json:"unvalidated,omitempty" yaml:"unvalidated,omitempty"` // Validation was skipped; Valid carries no meaning
+	ToolVersion   string              `json:"tool_version,omitempty" yaml:"tool_version,omitempty"` // Validator version that produced the verdict
+	Toolchain     *types.Toolchain    `json:"toolchain,omitempty" yaml:"toolchain,omitempty"`       // Components that produced the validated document, when known
 }
 
 // ValidationError mirrors the validator package error type for storage
@@ -335,27 +659,49 @@ type ValidationError struct {
 	Value   any    `json:"value,omitempty" yaml:"value,omitempty"`
 }
 
-// SaveValidationReport saves a validation report
+// SaveValidationReport saves a validation report as
+// {stage}-{YYYYMMDD-HHMMSS}.json. Reports of the same stage within the same
+// second get a numeric suffix ("-2", "-3", ...) instead of overwriting each
+// other, while saving an identical report again (e.g. on retry) is a no-op.
 func (s *Storage) SaveValidationReport(report *ValidationReport) error {
 	dir := filepath.Join(s.baseDir, "validation-reports", report.DocumentID)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create validation reports directory: %w", err)
 	}
 
-	// Create timestamped filename
-	filename := fmt.Sprintf("%s-%s.json", report.Stage, report.Timestamp.Format("20060102-150405"))
-	filePath := filepath.Join(dir, filename)
-
-	data, err := json.MarshalIndent(report, "", "  ")
+	data, err := MarshalCanonicalJSON(report)
 	if err != nil {
 		return fmt.Errorf("failed to marshal validation report: %w", err)
 	}
 
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write validation report: %w", err)
-	}
+	base := fmt.Sprintf("%s-%s", report.Stage, report.Timestamp.Format("20060102-150405"))
+	for n := 1; ; n++ {
+		filename := base + ".json"
+		if n > 1 {
+			filename = fmt.Sprintf("%s-%d.json", base, n)
+		}
+		filePath := filepath.Join(dir, filename)
 
-	return nil
+		// Exclusive create, so concurrent writers can't claim the same name
+		f, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			if existing, readErr := os.ReadFile(filePath); readErr == nil && bytes.Equal(existing, data) {
+				return nil
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write validation report: %w", err)
+		}
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write validation report: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write validation report: %w", err)
+		}
+		return nil
+	}
 }
 
 // LoadValidationReports loads all validation reports for a document
@@ -409,7 +755,7 @@ func (s *Storage) SaveSegmentedWithLabel(doc *types.SegmentedDocument, label str
 
 	// Save segmented document
 	filePath := filepath.Join(dir, "segmented.json")
-	data, err := json.MarshalIndent(doc, "", "  ")
+	data, err := MarshalCanonicalJSON(doc)
 	if err != nil {
 		return fmt.Errorf("failed to marshal segmented document: %w", err)
 	}
@@ -425,11 +771,47 @@ func (s *Storage) SaveSegmentedWithLabel(doc *types.SegmentedDocument, label str
 		Type:        "segmented",
 		StoredAt:    time.Now(),
 		Size:        int64(len(data)),
+		Checksum:    checksum(data),
 		Description: label,
 	}
 	return s.saveMetadataWithType(dir, meta, "segmented")
 }
 
+// SaveProvenance saves a final document's provenance map as a sidecar
+// ({document-id}.provenance.json) next to it
+func (s *Storage) SaveProvenance(documentID string, prov *types.Provenance) error {
+	dir := filepath.Join(s.baseDir, "final")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create final directory: %w", err)
+	}
+
+	data, err := MarshalCanonicalJSON(prov)
+	if err != nil {
+		return fmt.Errorf("failed to marshal provenance: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, documentID+provenanceSuffix), data, 0644); err != nil {
+		return fmt.Errorf("failed to write provenance: %w", err)
+	}
+
+	return nil
+}
+
+// LoadProvenance loads the provenance map saved with a final document
+func (s *Storage) LoadProvenance(documentID string) (*types.Provenance, error) {
+	data, err := os.ReadFile(filepath.Join(s.baseDir, "final", documentID+provenanceSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provenance: %w", err)
+	}
+
+	var prov types.Provenance
+	if err := json.Unmarshal(data, &prov); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal provenance: %w", err)
+	}
+
+	return &prov, nil
+}
+
 // SaveFinalWithValidation saves the final document along with its validation report
 func (s *Storage) SaveFinalWithValidation(documentID string, data interface{}, format string, report *ValidationReport) error {
 	// Save the document
@@ -447,3 +829,54 @@ func (s *Storage) SaveFinalWithValidation(documentID string, data interface{}, f
 	return nil
 }
 
+
+// EnhancementArtifact is the raw LLM exchange behind an enhanced version,
+// kept so automated changes to a document can be audited
+type EnhancementArtifact struct {
+	DocumentID        string            `json:"document_id" yaml:"document_id"`
+	Version           int               `json:"version" yaml:"version"`                         // Post-enhance segmented version
+	PreEnhanceVersion int               `json:"pre_enhance_version" yaml:"pre_enhance_version"` // Segmented version sent to the LLM
+	Provider          string            `json:"provider" yaml:"provider"`
+	Model             string            `json:"model" yaml:"model"`
+	Prompt            string            `json:"prompt" yaml:"prompt"`
+	RawResponse       string            `json:"raw_response" yaml:"raw_response"`
+	Usage             *types.TokenUsage `json:"usage,omitempty" yaml:"usage,omitempty"`
+	CostEstimate      float64           `json:"cost_estimate,omitempty" yaml:"cost_estimate,omitempty"` // Estimated USD cost, if known
+	Timestamp         time.Time         `json:"timestamp" yaml:"timestamp"`
+}
+
+// SaveEnhancementArtifact saves an LLM artifact alongside the post-enhance
+// segmented version it produced
+func (s *Storage) SaveEnhancementArtifact(artifact *EnhancementArtifact) error {
+	dir := filepath.Join(s.baseDir, "intermediate", artifact.DocumentID, fmt.Sprintf("v%d", artifact.Version))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create version directory: %w", err)
+	}
+
+	data, err := MarshalCanonicalJSON(artifact)
+	if err != nil {
+		return fmt.Errorf("failed to marshal enhancement artifact: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "llm-artifact.json"), data, 0600); err != nil {
+		return fmt.Errorf("failed to write enhancement artifact: %w", err)
+	}
+
+	return nil
+}
+
+// LoadEnhancementArtifact loads the LLM artifact stored for a segmented version
+func (s *Storage) LoadEnhancementArtifact(documentID string, version int) (*EnhancementArtifact, error) {
+	filePath := filepath.Join(s.baseDir, "intermediate", documentID, fmt.Sprintf("v%d", version), "llm-artifact.json")
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read enhancement artifact: %w", err)
+	}
+
+	var artifact EnhancementArtifact
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal enhancement artifact: %w", err)
+	}
+
+	return &artifact, nil
+}
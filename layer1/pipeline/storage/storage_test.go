@@ -1,11 +1,19 @@
 package storage
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/ossf/gemara/layer1"
 	"github.com/ossf/gemara/layer1/pipeline/types"
 )
 
@@ -212,6 +220,150 @@ func TestSaveFinal(t *testing.T) {
 	}
 }
 
+func TestSaveInvalid(t *testing.T) {
+	tempDir := t.TempDir()
+	store, err := NewStorage(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	
+	path, err := store.SaveInvalid("test-doc", map[string]interface{}{"id": "TEST"}, "json")
+	if err != nil {
+		t.Fatalf("SaveInvalid failed: %v", err)
+	}
+	if path != filepath.Join(tempDir, "invalid", "test-doc.json") {
+		t.Errorf("Unexpected invalid document path: %s", path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Invalid document not created: %v", err)
+	}
+	
+	// An invalid document is not a final document
+	if _, err := store.FinalPath("test-doc"); err == nil {
+		t.Error("Expected no final document after SaveInvalid")
+	}
+}
+
+func TestListFinal(t *testing.T) {
+	store, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	
+	ids, err := store.ListFinal()
+	if err != nil || len(ids) != 0 {
+		t.Fatalf("Expected no final documents, got %v (%v)", ids, err)
+	}
+	
+	data := map[string]interface{}{"id": "TEST"}
+	for _, saved := range []struct{ id, format string }{{"b-doc", "yaml"}, {"a-doc", "json"}, {"b-doc", "json"}} {
+		if err := store.SaveFinal(saved.id, data, saved.format); err != nil {
+			t.Fatalf("Failed to save %s: %v", saved.id, err)
+		}
+	}
+	
+	// Provenance sidecars are not final documents
+	if err := store.SaveProvenance("a-doc", &types.Provenance{DocumentID: "a-doc"}); err != nil {
+		t.Fatalf("Failed to save provenance: %v", err)
+	}
+	
+	ids, err = store.ListFinal()
+	if err != nil {
+		t.Fatalf("ListFinal failed: %v", err)
+	}
+	if strings.Join(ids, ",") != "a-doc,b-doc" {
+		t.Errorf("Expected [a-doc b-doc], got %v", ids)
+	}
+}
+
+func TestSaveAndLoadProvenance(t *testing.T) {
+	store, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	
+	prov := &types.Provenance{
+		DocumentID:    "traced-doc",
+		ParsedVersion: 2,
+		Elements: []types.ElementProvenance{
+			{ID: "1.1", Kind: "guideline", Path: "categories[0].guidelines[0]", Sources: []types.SourceRef{{Page: 3, Block: 4}}},
+		},
+	}
+	if err := store.SaveProvenance("traced-doc", prov); err != nil {
+		t.Fatalf("Failed to save provenance: %v", err)
+	}
+	
+	loaded, err := store.LoadProvenance("traced-doc")
+	if err != nil {
+		t.Fatalf("Failed to load provenance: %v", err)
+	}
+	found := loaded.Find("1.1")
+	if loaded.ParsedVersion != 2 || len(found) != 1 || len(found[0].Sources) != 1 || found[0].Sources[0] != (types.SourceRef{Page: 3, Block: 4}) {
+		t.Errorf("Provenance not preserved: %+v", loaded)
+	}
+	
+	if _, err := store.LoadProvenance("missing-doc"); err == nil {
+		t.Error("Expected error loading provenance of an unconverted document")
+	}
+}
+
+func TestStreamFinal(t *testing.T) {
+	store, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	
+	for _, saved := range []struct{ id, format string }{{"b-doc", "yaml"}, {"a-doc", "json"}} {
+		doc := &layer1.GuidanceDocument{Metadata: layer1.Metadata{Id: saved.id, Title: "Doc " + saved.id}}
+		if err := store.SaveFinal(saved.id, doc, saved.format); err != nil {
+			t.Fatalf("Failed to save %s: %v", saved.id, err)
+		}
+	}
+	
+	var buf bytes.Buffer
+	if err := store.StreamFinal(&buf); err != nil {
+		t.Fatalf("StreamFinal failed: %v", err)
+	}
+	
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d:\n%s", len(lines), buf.String())
+	}
+	for i, want := range []string{"a-doc", "b-doc"} {
+		var doc layer1.GuidanceDocument
+		if err := json.Unmarshal([]byte(lines[i]), &doc); err != nil {
+			t.Fatalf("Line %d is not JSON: %v", i+1, err)
+		}
+		if doc.Metadata.Id != want {
+			t.Errorf("Line %d: expected %s, got %s", i+1, want, doc.Metadata.Id)
+		}
+	}
+}
+
+func TestDecodeLayer1StrictDecode(t *testing.T) {
+	inputs := map[string]string{
+		"yaml": "metadata:\n  id: doc\n  titel: Typo\n",
+		"json": `{"metadata": {"id": "doc", "titel": "Typo"}}`,
+	}
+	for format, data := range inputs {
+		doc, err := DecodeLayer1([]byte(data), format, false)
+		if err != nil {
+			t.Fatalf("%s: lenient decode failed: %v", format, err)
+		}
+		if doc.Metadata.Id != "doc" || doc.Metadata.Title != "" {
+			t.Errorf("%s: unexpected metadata %+v", format, doc.Metadata)
+		}
+		
+		if _, err := DecodeLayer1([]byte(data), format, true); err == nil || !strings.Contains(err.Error(), "titel") {
+			t.Errorf("%s: expected strict decode to report 'titel', got %v", format, err)
+		}
+	}
+	
+	if _, err := DecodeLayer1(nil, "yaml", true); err != nil {
+		t.Errorf("Expected an empty YAML document to decode, got %v", err)
+	}
+}
+
 func TestListVersionsEmptyDir(t *testing.T) {
 	tempDir := t.TempDir()
 	store, err := NewStorage(tempDir)
@@ -351,3 +503,364 @@ func TestParsedAndSegmentedSameVersion(t *testing.T) {
 }
 
 
+
+func TestSaveAndLoadEnhancementArtifact(t *testing.T) {
+	store, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	
+	artifact := &EnhancementArtifact{
+		DocumentID:        "audit-doc",
+		Version:           2,
+		PreEnhanceVersion: 1,
+		Provider:          "openai-gpt-4-v1.0",
+		Model:             "gpt-4",
+		Prompt:            "Review this document segmentation",
+		RawResponse:       `{"confidence": 0.9}`,
+		Usage:             &types.TokenUsage{PromptTokens: 120, CompletionTokens: 8, TotalTokens: 128},
+		Timestamp:         time.Now(),
+	}
+	if err := store.SaveEnhancementArtifact(artifact); err != nil {
+		t.Fatalf("Failed to save artifact: %v", err)
+	}
+	
+	loaded, err := store.LoadEnhancementArtifact("audit-doc", 2)
+	if err != nil {
+		t.Fatalf("Failed to load artifact: %v", err)
+	}
+	
+	if loaded.Prompt != artifact.Prompt || loaded.RawResponse != artifact.RawResponse {
+		t.Errorf("Exchange not preserved: got %+v", loaded)
+	}
+	if loaded.Usage == nil || loaded.Usage.TotalTokens != 128 {
+		t.Errorf("Expected token usage to be preserved, got %+v", loaded.Usage)
+	}
+	if loaded.PreEnhanceVersion != 1 {
+		t.Errorf("Expected pre-enhance version 1, got %d", loaded.PreEnhanceVersion)
+	}
+	
+	if _, err := store.LoadEnhancementArtifact("audit-doc", 1); err == nil {
+		t.Error("Expected error loading artifact for a version without one")
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	tempDir := t.TempDir()
+	store, err := NewStorage(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	
+	doc := &types.ParsedDocument{
+		Metadata: types.ParsedMetadata{DocumentID: "checked-doc"},
+		Pages:    []types.Page{{PageNumber: 1}},
+	}
+	if err := store.SaveParsed(doc); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+	
+	versions, err := store.ListVersions("checked-doc", "parsed")
+	if err != nil || len(versions) != 1 {
+		t.Fatalf("Expected 1 version, got %d (err: %v)", len(versions), err)
+	}
+	if len(versions[0].Checksum) != 64 {
+		t.Errorf("Expected a SHA-256 checksum, got %q", versions[0].Checksum)
+	}
+	
+	ok, err := store.VerifyChecksum("checked-doc", "parsed", 0)
+	if err != nil || !ok {
+		t.Errorf("Expected intact version to verify, got %v (err: %v)", ok, err)
+	}
+	
+	dir := filepath.Join(tempDir, "intermediate", "checked-doc", "v1")
+	if err := os.WriteFile(filepath.Join(dir, "parsed.json"), []byte(`{"pages": []}`), 0644); err != nil {
+		t.Fatalf("Failed to corrupt document: %v", err)
+	}
+	ok, err = store.VerifyChecksum("checked-doc", "parsed", 1)
+	if err != nil || ok {
+		t.Errorf("Expected modified version to fail verification, got %v (err: %v)", ok, err)
+	}
+	
+	// Metadata written before checksums were recorded
+	legacy := `{"document_id": "checked-doc", "version": 1, "type": "parsed", "size": 13}`
+	if err := os.WriteFile(filepath.Join(dir, "metadata-parsed.json"), []byte(legacy), 0644); err != nil {
+		t.Fatalf("Failed to write metadata: %v", err)
+	}
+	if _, err := store.VerifyChecksum("checked-doc", "parsed", 1); !errors.Is(err, ErrNoChecksum) {
+		t.Errorf("Expected ErrNoChecksum, got %v", err)
+	}
+}
+
+func TestSaveFinalVersion(t *testing.T) {
+	tempDir := t.TempDir()
+	store, err := NewStorage(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	
+	edited := []byte("# Edited by hand\nmetadata:\n  id: imported-doc\n  title: Imported\n")
+	meta, err := store.SaveFinalVersion("imported-doc", edited, "yml", "imported from edited.yml")
+	if err != nil {
+		t.Fatalf("Failed to save final version: %v", err)
+	}
+	if meta.Version != 1 || meta.Type != "final" || meta.Description != "imported from edited.yml" || len(meta.Checksum) != 64 {
+		t.Errorf("Unexpected metadata: %+v", meta)
+	}
+	
+	// The file is kept byte for byte, comments included
+	data, err := os.ReadFile(filepath.Join(tempDir, "final", "imported-doc.yaml"))
+	if err != nil || string(data) != string(edited) {
+		t.Errorf("Expected current final document to be the imported file, got %q (err: %v)", data, err)
+	}
+	doc, err := store.LoadFinal("imported-doc")
+	if err != nil || doc.Metadata.Title != "Imported" {
+		t.Errorf("Expected to load imported document, got %+v (err: %v)", doc, err)
+	}
+	if ok, err := store.VerifyChecksum("imported-doc", "final", 1); err != nil || !ok {
+		t.Errorf("Expected final version to verify, got %v (err: %v)", ok, err)
+	}
+	
+	// A JSON import replaces the YAML one and gets the next version
+	meta, err = store.SaveFinalVersion("imported-doc", []byte(`{"metadata": {"id": "imported-doc", "title": "Imported again"}}`), "json", "imported from edited.json")
+	if err != nil || meta.Version != 2 {
+		t.Fatalf("Expected version 2, got %+v (err: %v)", meta, err)
+	}
+	if path, err := store.FinalPath("imported-doc"); err != nil || filepath.Ext(path) != ".json" {
+		t.Errorf("Expected the JSON import to be current, got %s (err: %v)", path, err)
+	}
+	versions, err := store.ListVersions("imported-doc", "final")
+	if err != nil || len(versions) != 2 {
+		t.Fatalf("Expected 2 final versions, got %d (err: %v)", len(versions), err)
+	}
+	
+	dir := filepath.Join(tempDir, "intermediate", "imported-doc", "v2")
+	if err := os.WriteFile(filepath.Join(dir, "final.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatalf("Failed to corrupt document: %v", err)
+	}
+	if ok, err := store.VerifyChecksum("imported-doc", "final", 0); err != nil || ok {
+		t.Errorf("Expected modified version to fail verification, got %v (err: %v)", ok, err)
+	}
+	
+	if _, err := store.SaveFinalVersion("imported-doc", edited, "xml", ""); err == nil {
+		t.Error("Expected an error for an unsupported format")
+	}
+}
+
+func TestSaveValidationReportSameSecond(t *testing.T) {
+	tempDir := t.TempDir()
+	store, err := NewStorage(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	
+	timestamp := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		report := &ValidationReport{DocumentID: "report-doc", Stage: "revalidate", Timestamp: timestamp.Add(time.Duration(i) * time.Millisecond), ErrorCount: i}
+		if err := store.SaveValidationReport(report); err != nil {
+			t.Fatalf("Failed to save report %d: %v", i, err)
+		}
+	}
+	// Saving an identical report again, as a retry would, adds nothing
+	retry := &ValidationReport{DocumentID: "report-doc", Stage: "revalidate", Timestamp: timestamp, ErrorCount: 0}
+	if err := store.SaveValidationReport(retry); err != nil {
+		t.Fatalf("Failed to save retried report: %v", err)
+	}
+	
+	entries, err := os.ReadDir(filepath.Join(tempDir, "validation-reports", "report-doc"))
+	if err != nil {
+		t.Fatalf("Failed to read reports: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	want := []string{"revalidate-20250301-120000-2.json", "revalidate-20250301-120000-3.json", "revalidate-20250301-120000.json"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("Report files = %v, want %v", names, want)
+	}
+	
+	reports, err := store.LoadValidationReports("report-doc")
+	if err != nil || len(reports) != 3 || reports[0].ErrorCount != 2 {
+		t.Errorf("Expected 3 reports newest first, got %+v (err: %v)", reports, err)
+	}
+}
+
+func TestWriteBundle(t *testing.T) {
+	store, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	if _, err := store.WriteBundle("bundle-doc", &bytes.Buffer{}); err == nil {
+		t.Error("Expected an error for a document without a final version")
+	}
+
+	doc := &layer1.GuidanceDocument{Metadata: layer1.Metadata{Id: "bundle-doc", Title: "Bundle"}}
+	if err := store.SaveFinalWithValidation("bundle-doc", doc, "yaml", &ValidationReport{DocumentID: "bundle-doc", Stage: "convert", Timestamp: time.Now(), Valid: true}); err != nil {
+		t.Fatalf("Failed to save final document: %v", err)
+	}
+	coverageDir := filepath.Join(store.GetBaseDir(), "coverage-reports")
+	if err := os.MkdirAll(coverageDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// The second report belongs to a document whose ID extends this one
+	for _, name := range []string{"bundle-doc-20250101-120000.json", "bundle-doc-2-20250101-120000.json"} {
+		if err := os.WriteFile(filepath.Join(coverageDir, name), []byte(`{}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	manifest, err := store.WriteBundle("bundle-doc", &buf)
+	if err != nil {
+		t.Fatalf("WriteBundle failed: %v", err)
+	}
+	if len(manifest.Files) != 3 {
+		t.Fatalf("Expected final document, validation and coverage report, got %+v", manifest.Files)
+	}
+
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("Bundle is not gzip-compressed: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	contents := map[string][]byte{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read bundle: %v", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("Failed to read bundle entry: %v", err)
+		}
+		names = append(names, header.Name)
+		contents[header.Name] = data
+	}
+
+	if len(names) != 4 || names[0] != "bundle-doc/manifest.json" {
+		t.Fatalf("Expected the manifest followed by 3 files, got %v", names)
+	}
+	if names[1] != "bundle-doc/final/bundle-doc.yaml" || names[3] != "bundle-doc/coverage-reports/bundle-doc-20250101-120000.json" {
+		t.Errorf("Unexpected bundle entries: %v", names)
+	}
+	if !strings.HasPrefix(names[2], "bundle-doc/validation-reports/convert-") {
+		t.Errorf("Expected the validation report, got %s", names[2])
+	}
+
+	var bundled BundleManifest
+	if err := json.Unmarshal(contents["bundle-doc/manifest.json"], &bundled); err != nil {
+		t.Fatalf("Failed to decode manifest: %v", err)
+	}
+	for _, file := range bundled.Files {
+		if data := contents["bundle-doc/"+file.Name]; checksum(data) != file.Checksum {
+			t.Errorf("Checksum mismatch for %s", file.Name)
+		}
+	}
+}
+
+func TestDeleteAndPruneVersions(t *testing.T) {
+	tempDir := t.TempDir()
+	store, err := NewStorage(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	
+	// Parsed v1-v2 and segmented v1-v3 share the v1 and v2 directories
+	for i := 0; i < 2; i++ {
+		if err := store.SaveParsed(&types.ParsedDocument{Metadata: types.ParsedMetadata{DocumentID: "pruned-doc"}}); err != nil {
+			t.Fatalf("Failed to save parsed: %v", err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if err := store.SaveSegmented(&types.SegmentedDocument{Metadata: types.SegmentedMetadata{DocumentID: "pruned-doc"}}); err != nil {
+			t.Fatalf("Failed to save segmented: %v", err)
+		}
+	}
+	
+	if err := store.PruneVersions("pruned-doc", 1); err != nil {
+		t.Fatalf("Failed to prune: %v", err)
+	}
+	
+	docDir := filepath.Join(tempDir, "intermediate", "pruned-doc")
+	if _, err := os.Stat(filepath.Join(docDir, "v1")); !os.IsNotExist(err) {
+		t.Errorf("Expected v1 to be deleted, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(docDir, "v2", "parsed.json")); err != nil {
+		t.Errorf("Expected latest parsed version to be kept: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(docDir, "v2", "segmented.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected segmented v2 to be removed, got %v", err)
+	}
+	
+	parsed, _ := store.ListVersions("pruned-doc", "parsed")
+	segmented, _ := store.ListVersions("pruned-doc", "segmented")
+	if len(parsed) != 1 || parsed[0].Version != 2 || len(segmented) != 1 || segmented[0].Version != 3 {
+		t.Errorf("Expected parsed v2 and segmented v3 to remain, got %+v and %+v", parsed, segmented)
+	}
+	
+	if err := store.DeleteVersion("pruned-doc", 3); err != nil {
+		t.Fatalf("Failed to delete version: %v", err)
+	}
+	if _, err := store.LoadSegmented("pruned-doc", 0); err == nil {
+		t.Error("Expected no segmented version after deleting v3")
+	}
+	if err := store.DeleteVersion("pruned-doc", 3); err == nil {
+		t.Error("Expected error deleting a missing version")
+	}
+}
+
+func TestMarshalCanonicalJSON(t *testing.T) {
+	stats := &types.CoverageStats{
+		TotalSourceBlocks:  10,
+		MappedBlocks:       7,
+		UnmappedBlocks:     3,
+		CoveragePercentage: 70.0 / 3,
+		UnmappedByType: map[string]int{
+			"table":    1,
+			"figure":   1,
+			"appendix": 1,
+			"footnote": 0,
+		},
+	}
+	
+	first, err := MarshalCanonicalJSON(stats)
+	if err != nil {
+		t.Fatalf("MarshalCanonicalJSON failed: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		again, err := MarshalCanonicalJSON(stats)
+		if err != nil {
+			t.Fatalf("MarshalCanonicalJSON failed: %v", err)
+		}
+		if !bytes.Equal(first, again) {
+			t.Fatalf("Marshal output differs between runs:\n%s\n---\n%s", first, again)
+		}
+	}
+	
+	// Struct fields and map keys alike come out sorted
+	out := string(first)
+	order := []string{`"coverage_percentage"`, `"mapped_blocks"`, `"total_source_blocks"`, `"unmapped_blocks"`, `"unmapped_by_type"`, `"appendix"`, `"figure"`, `"footnote"`, `"table"`}
+	last := -1
+	for _, key := range order {
+		idx := strings.Index(out, key)
+		if idx <= last {
+			t.Fatalf("Expected %s after previous keys in:\n%s", key, out)
+		}
+		last = idx
+	}
+	
+	// Floats keep full precision, however reports round them for display
+	var decoded types.CoverageStats
+	if err := json.Unmarshal(first, &decoded); err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if decoded.CoveragePercentage != stats.CoveragePercentage {
+		t.Errorf("Expected coverage %v to round-trip exactly, got %v", stats.CoveragePercentage, decoded.CoveragePercentage)
+	}
+}
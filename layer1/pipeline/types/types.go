@@ -1,6 +1,13 @@
 package types
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
 
 // ParsedDocument represents the raw output from PDF parsing
 type ParsedDocument struct {
@@ -15,6 +22,8 @@ type ParsedMetadata struct {
 	ParsedAt   time.Time `json:"parsed_at" yaml:"parsed_at"`
 	Version    int       `json:"version" yaml:"version"`
 	DocumentID string    `json:"document_id" yaml:"document_id"`
+	// SHA-256 of the input bytes, used to detect an unchanged source when resuming
+	SourceChecksum string `json:"source_checksum,omitempty" yaml:"source_checksum,omitempty"`
 }
 
 // Page represents a single page from the PDF
@@ -34,6 +43,13 @@ type Block struct {
 	FontName   string     `json:"font_name,omitempty" yaml:"font_name,omitempty"`
 	ListItem   *ListItem  `json:"list_item,omitempty" yaml:"list_item,omitempty"`
 	TableData  *TableData `json:"table_data,omitempty" yaml:"table_data,omitempty"`
+	Links      []Link     `json:"links,omitempty" yaml:"links,omitempty"`
+}
+
+// Link is a hyperlink annotation on a block's text
+type Link struct {
+	Text string `json:"text,omitempty" yaml:"text,omitempty"` // Anchor text
+	URL  string `json:"url" yaml:"url"`                       // Target; internal cross-references are kept as-is
 }
 
 // BlockType represents the type of content block
@@ -49,6 +65,10 @@ const (
 	BlockTypeCaption   BlockType = "caption"
 )
 
+// RevisionTableTitle is the Text of table blocks holding a document's
+// change history. The first row of such a table is its header.
+const RevisionTableTitle = "Document Changes"
+
 // BBox represents a bounding box
 type BBox struct {
 	X1 float64 `json:"x1" yaml:"x1"`
@@ -59,14 +79,145 @@ type BBox struct {
 
 // ListItem contains list-specific information
 type ListItem struct {
-	Level  int    `json:"level" yaml:"level"`
-	Marker string `json:"marker" yaml:"marker"` // e.g., "1.", "a.", "•"
-	Type   string `json:"type" yaml:"type"`     // "ordered" or "unordered"
+	Level      int        `json:"level" yaml:"level"`
+	Marker     string     `json:"marker" yaml:"marker"` // e.g., "1.", "a.", "•"
+	Type       string     `json:"type" yaml:"type"`     // "ordered" or "unordered"
+	MarkerKind MarkerKind `json:"marker_kind,omitempty" yaml:"marker_kind,omitempty"`
+}
+
+// MarkerKind is the canonical numbering style of a list marker, independent
+// of its punctuation and of the parser that produced it
+type MarkerKind string
+
+const (
+	MarkerKindDecimal    MarkerKind = "decimal"     // 1. 2) (3) 1.2
+	MarkerKindLowerAlpha MarkerKind = "lower-alpha" // a. b) (c)
+	MarkerKindUpperAlpha MarkerKind = "upper-alpha" // A. B) (C)
+	MarkerKindRoman      MarkerKind = "roman"       // i. ii) (IV)
+	MarkerKindBullet     MarkerKind = "bullet"      // • * - ▪
+)
+
+// romanMarkerPattern matches roman numerals in either case, but not mixed
+var romanMarkerPattern = regexp.MustCompile(`^(?:m{0,3}(?:cm|cd|d?c{0,3})(?:xc|xl|l?x{0,3})(?:ix|iv|v?i{0,3})|M{0,3}(?:CM|CD|D?C{0,3})(?:XC|XL|L?X{0,3})(?:IX|IV|V?I{0,3}))$`)
+
+// ClassifyMarker returns the kind of a list marker, ignoring surrounding
+// punctuation such as "(", ")", "." and "]". Single letters are read as
+// alphabetic except "i" and "I", which are read as roman; callers with the
+// neighbouring markers at hand can resolve that ambiguity better. It returns
+// "" for an empty or unrecognized marker.
+func ClassifyMarker(marker string) MarkerKind {
+	marker = strings.TrimSpace(marker)
+	if marker == "" {
+		return ""
+	}
+	core := strings.Trim(marker, "()[].:")
+	switch {
+	case core == "":
+		return ""
+	case strings.IndexFunc(core, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0:
+		return MarkerKindBullet
+	case strings.Trim(core, "0123456789.") == "":
+		return MarkerKindDecimal
+	case len(core) == 1 && core != "i" && core != "I" && unicode.IsLetter(rune(core[0])):
+		if unicode.IsUpper(rune(core[0])) {
+			return MarkerKindUpperAlpha
+		}
+		return MarkerKindLowerAlpha
+	case romanMarkerPattern.MatchString(core):
+		return MarkerKindRoman
+	}
+	return ""
 }
 
 // TableData contains table-specific information
 type TableData struct {
-	Rows [][]string `json:"rows" yaml:"rows"`
+	Rows   [][]string `json:"rows" yaml:"rows"`
+	Source *SourceRef `json:"source,omitempty" yaml:"source,omitempty"` // Block the table came from, set when segmented into a guideline
+}
+
+// ToText renders the parsed blocks back to plain text, for diffing against
+// the source's extracted text. Blocks are separated by a blank line except
+// consecutive list items, table cells are tab-separated, and pages are
+// separated by a form feed as pdftotext does.
+func (d *ParsedDocument) ToText() string {
+	var sb strings.Builder
+	for i, page := range d.Pages {
+		if i > 0 {
+			sb.WriteString("\f")
+		}
+		var prev *Block
+		for j := range page.Blocks {
+			block := &page.Blocks[j]
+			if prev != nil {
+				if prev.Type == BlockTypeList && block.Type == BlockTypeList {
+					sb.WriteString("\n")
+				} else {
+					sb.WriteString("\n\n")
+				}
+			}
+			sb.WriteString(block.plainText())
+			prev = block
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Validate checks that a parsed document produced outside the pipeline has
+// the shape the segmenter relies on: at least one page, positive page
+// numbers, known block types, heading levels 0-6, and table blocks with
+// rows. All problems are reported, each prefixed with its location.
+func (d *ParsedDocument) Validate() error {
+	if len(d.Pages) == 0 {
+		return errors.New("document has no pages")
+	}
+
+	var errs []error
+	for i, page := range d.Pages {
+		if page.PageNumber < 1 {
+			errs = append(errs, fmt.Errorf("pages[%d]: page_number must be at least 1, got %d", i, page.PageNumber))
+		}
+		for j, block := range page.Blocks {
+			where := fmt.Sprintf("pages[%d].blocks[%d]", i, j)
+			switch block.Type {
+			case BlockTypeHeading, BlockTypeParagraph, BlockTypeList, BlockTypeTable, BlockTypeCode, BlockTypeFootnote, BlockTypeCaption:
+			case "":
+				errs = append(errs, fmt.Errorf("%s: type is required", where))
+			default:
+				errs = append(errs, fmt.Errorf("%s: unknown block type %q", where, block.Type))
+			}
+			if block.Level < 0 || block.Level > 6 {
+				errs = append(errs, fmt.Errorf("%s: level must be between 0 and 6, got %d", where, block.Level))
+			}
+			if block.Type == BlockTypeTable && (block.TableData == nil || len(block.TableData.Rows) == 0) {
+				errs = append(errs, fmt.Errorf("%s: table block has no table_data rows", where))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// plainText renders a single block, restoring list markers and indentation
+func (b *Block) plainText() string {
+	switch {
+	case b.Type == BlockTypeList && b.ListItem != nil:
+		indent := ""
+		if b.ListItem.Level > 1 {
+			indent = strings.Repeat("  ", b.ListItem.Level-1)
+		}
+		if b.ListItem.Marker == "" {
+			return indent + b.Text
+		}
+		return indent + b.ListItem.Marker + " " + b.Text
+	case b.Type == BlockTypeTable && b.TableData != nil:
+		rows := make([]string, len(b.TableData.Rows))
+		for i, row := range b.TableData.Rows {
+			rows[i] = strings.Join(row, "\t")
+		}
+		return strings.Join(rows, "\n")
+	default:
+		return b.Text
+	}
 }
 
 // SegmentedDocument represents the document after rule-based segmentation
@@ -74,10 +225,20 @@ type SegmentedDocument struct {
 	Metadata         SegmentedMetadata `json:"metadata" yaml:"metadata"`
 	DocumentMetadata DocumentMetadata  `json:"document_metadata" yaml:"document_metadata"`
 	FrontMatter      string            `json:"front_matter,omitempty" yaml:"front_matter,omitempty"`
+	RevisionHistory  []Revision        `json:"revision_history,omitempty" yaml:"revision_history,omitempty"`
 	Categories       []SegmentCategory `json:"categories" yaml:"categories"`
 	// Coverage tracking - what couldn't be captured by the schema
 	UnmappedContent  []UnmappedContent `json:"unmapped_content,omitempty" yaml:"unmapped_content,omitempty"`
 	CoverageStats    *CoverageStats    `json:"coverage_stats,omitempty" yaml:"coverage_stats,omitempty"`
+	// Warnings about degraded segmentation (e.g. fallback structure was used)
+	Warnings         []string          `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// Revision is an entry from a document's change-history table
+type Revision struct {
+	Version     string `json:"version" yaml:"version"`
+	Date        string `json:"date" yaml:"date"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
 }
 
 // UnmappedContent represents content from source that couldn't fit in the schema
@@ -122,6 +283,14 @@ type SegmentedMetadata struct {
 	DocumentID    string    `json:"document_id" yaml:"document_id"`
 }
 
+// Toolchain names the pipeline components that produced a document, so
+// stored reports are self-describing
+type Toolchain struct {
+	Parser    string `json:"parser,omitempty" yaml:"parser,omitempty"`
+	Segmenter string `json:"segmenter,omitempty" yaml:"segmenter,omitempty"`
+	Converter string `json:"converter,omitempty" yaml:"converter,omitempty"`
+}
+
 // DocumentMetadata contains extracted document metadata
 type DocumentMetadata struct {
 	ID              string   `json:"id" yaml:"id"`
@@ -137,10 +306,12 @@ type DocumentMetadata struct {
 
 // SegmentCategory represents a category with its guidelines
 type SegmentCategory struct {
-	ID          string             `json:"id" yaml:"id"`
-	Title       string             `json:"title" yaml:"title"`
-	Description string             `json:"description" yaml:"description"`
-	Guidelines  []SegmentGuideline `json:"guidelines,omitempty" yaml:"guidelines,omitempty"`
+	ID           string             `json:"id" yaml:"id"`
+	Title        string             `json:"title" yaml:"title"`
+	Description  string             `json:"description" yaml:"description"`
+	DocumentType string             `json:"document_type,omitempty" yaml:"document_type,omitempty"` // Overrides the document's type for this category
+	Guidelines   []SegmentGuideline `json:"guidelines,omitempty" yaml:"guidelines,omitempty"`
+	Sources      []SourceRef        `json:"sources,omitempty" yaml:"sources,omitempty"` // Parsed blocks the category came from
 }
 
 // SegmentGuideline represents a guideline with its parts
@@ -149,7 +320,31 @@ type SegmentGuideline struct {
 	Title           string        `json:"title" yaml:"title"`
 	Objective       string        `json:"objective,omitempty" yaml:"objective,omitempty"`
 	Recommendations []string      `json:"recommendations,omitempty" yaml:"recommendations,omitempty"`
+	Normativity     Normativity   `json:"normativity,omitempty" yaml:"normativity,omitempty"` // Strongest RFC 2119 keyword in the recommendations
+	Profile         string        `json:"profile,omitempty" yaml:"profile,omitempty"`         // Benchmark profile level, e.g. "L1" or "L2" (CIS Benchmarks)
 	Parts           []SegmentPart `json:"parts,omitempty" yaml:"parts,omitempty"`
+	Tables          []TableData   `json:"tables,omitempty" yaml:"tables,omitempty"` // Tables found within the guideline's content
+	Code            []string      `json:"code,omitempty" yaml:"code,omitempty"`     // Code and command examples found within the guideline's content
+	Links           []Link        `json:"links,omitempty" yaml:"links,omitempty"`   // Hyperlinks found within the guideline's content
+	Risks           []RationaleItem `json:"risks,omitempty" yaml:"risks,omitempty"`       // Risks of not following the guideline, from "Risks:" text
+	Outcomes        []RationaleItem `json:"outcomes,omitempty" yaml:"outcomes,omitempty"` // Outcomes of following it, from "Outcomes:" text
+	Mappings        []SegmentMapping `json:"mappings,omitempty" yaml:"mappings,omitempty"` // References to other frameworks ("Maps to ISO 27001 A.9.2")
+	Annotations     []Annotation  `json:"annotations,omitempty" yaml:"annotations,omitempty"` // Callouts in the guideline's text ("Note: ...")
+	Sources         []SourceRef   `json:"sources,omitempty" yaml:"sources,omitempty"` // Parsed blocks the guideline came from
+}
+
+// SegmentMapping is a guideline's reference to controls in another
+// framework
+type SegmentMapping struct {
+	Framework string   `json:"framework" yaml:"framework"`                 // Referenced framework, e.g. "ISO 27001:2013"
+	Entries   []string `json:"entries,omitempty" yaml:"entries,omitempty"` // Referenced control IDs, e.g. "A.9.2"
+	Text      string   `json:"text,omitempty" yaml:"text,omitempty"`       // Sentence the reference was found in
+}
+
+// RationaleItem is a risk or outcome stated in a guideline's text
+type RationaleItem struct {
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description" yaml:"description"`
 }
 
 // SegmentPart represents a part of a guideline
@@ -157,9 +352,71 @@ type SegmentPart struct {
 	ID              string   `json:"id" yaml:"id"`
 	Title           string   `json:"title,omitempty" yaml:"title,omitempty"`
 	Text            string   `json:"text" yaml:"text"`
-	Recommendations []string `json:"recommendations,omitempty" yaml:"recommendations,omitempty"`
+	Recommendations []string    `json:"recommendations,omitempty" yaml:"recommendations,omitempty"`
+	Normativity     Normativity `json:"normativity,omitempty" yaml:"normativity,omitempty"` // Strongest RFC 2119 keyword in the text
+	Links           []Link      `json:"links,omitempty" yaml:"links,omitempty"`
+	Annotations     []Annotation `json:"annotations,omitempty" yaml:"annotations,omitempty"` // Callouts following the part ("Warning: ...")
+	Sources         []SourceRef `json:"sources,omitempty" yaml:"sources,omitempty"` // Parsed blocks the part came from
+}
+
+// AnnotationKind is the kind of callout an annotation came from
+type AnnotationKind string
+
+const (
+	AnnotationNote      AnnotationKind = "note"      // "Note:"
+	AnnotationWarning   AnnotationKind = "warning"   // "Warning:"
+	AnnotationImportant AnnotationKind = "important" // "Important:"
+)
+
+// Annotation is a callout the source sets apart from the surrounding text
+type Annotation struct {
+	Kind AnnotationKind `json:"kind" yaml:"kind"`
+	Text string         `json:"text" yaml:"text"`
+}
+
+// SourceRef locates a block in the parsed document
+type SourceRef struct {
+	Page  int `json:"page" yaml:"page"`   // Page number
+	Block int `json:"block" yaml:"block"` // Index of the block within the page, from 0
+}
+
+// Provenance maps each element of a final Layer-1 document to the parsed
+// blocks that produced it
+type Provenance struct {
+	DocumentID    string              `json:"document_id" yaml:"document_id"`
+	ParsedVersion int                 `json:"parsed_version" yaml:"parsed_version"` // Parsed version the block references point into
+	Elements      []ElementProvenance `json:"elements" yaml:"elements"`
 }
 
+// Find returns the elements with the given ID; a category and a part may
+// share an ID, so there can be more than one
+func (p *Provenance) Find(id string) []ElementProvenance {
+	var found []ElementProvenance
+	for _, element := range p.Elements {
+		if element.ID == id {
+			found = append(found, element)
+		}
+	}
+	return found
+}
+
+// ElementProvenance lists the source blocks of one Layer-1 element
+type ElementProvenance struct {
+	ID      string      `json:"id" yaml:"id"`
+	Kind    string      `json:"kind" yaml:"kind"` // "category", "guideline", "part"
+	Path    string      `json:"path" yaml:"path"` // Layer-1 path, e.g. "categories[0].guidelines[1]"
+	Sources []SourceRef `json:"sources,omitempty" yaml:"sources,omitempty"`
+}
+
+// Normativity is the normative strength of a requirement per RFC 2119
+type Normativity string
+
+const (
+	NormativityMust   Normativity = "must"   // MUST, MUST NOT, SHALL, SHALL NOT, REQUIRED
+	NormativityShould Normativity = "should" // SHOULD, SHOULD NOT, RECOMMENDED, NOT RECOMMENDED
+	NormativityMay    Normativity = "may"    // MAY, OPTIONAL
+)
+
 // ParserConfig contains configuration for the PDF parser
 type ParserConfig struct {
 	Provider      string            `json:"provider" yaml:"provider"` // "docling", "pymupdf", etc.
@@ -167,12 +424,13 @@ type ParserConfig struct {
 	Options       map[string]string `json:"options,omitempty" yaml:"options,omitempty"`
 	TempDir       string            `json:"temp_dir" yaml:"temp_dir"`
 	KeepTempFiles bool              `json:"keep_temp_files" yaml:"keep_temp_files"`
+	MaxBytes      int64             `json:"max_bytes,omitempty" yaml:"max_bytes,omitempty"` // Input/extracted text size limit (0 = 256MB default, negative = unlimited)
 }
 
 // SegmenterConfig contains configuration for the segmenter
 type SegmenterConfig struct {
 	RulesFile    string            `json:"rules_file" yaml:"rules_file"`
-	DocumentType string            `json:"document_type" yaml:"document_type"` // "pci-dss", "nist-800-53", etc.
+	DocumentType string            `json:"document_type" yaml:"document_type"` // "pci-dss", "nist-800-53", "cis-benchmark", etc.
 	Options      map[string]string `json:"options,omitempty" yaml:"options,omitempty"`
 }
 
@@ -196,6 +454,22 @@ type EnhancementResult struct {
 	Provider     string            `json:"provider" yaml:"provider"`
 	Model        string            `json:"model" yaml:"model"`
 	Timestamp    time.Time         `json:"timestamp" yaml:"timestamp"`
+	
+	// Raw exchange with the LLM, kept for auditing
+	Prompt      string      `json:"prompt,omitempty" yaml:"prompt,omitempty"`
+	RawResponse string      `json:"raw_response,omitempty" yaml:"raw_response,omitempty"`
+	Usage       *TokenUsage `json:"usage,omitempty" yaml:"usage,omitempty"`
+	
+	// Estimated USD cost from Usage and the model's price; zero when the
+	// provider didn't report usage or the model has no known price
+	CostEstimate float64 `json:"cost_estimate,omitempty" yaml:"cost_estimate,omitempty"`
+}
+
+// TokenUsage reports the tokens consumed by an LLM call
+type TokenUsage struct {
+	PromptTokens     int `json:"prompt_tokens" yaml:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens" yaml:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens" yaml:"total_tokens"`
 }
 
 // EnhancementChange describes a change made by LLM enhancement
@@ -0,0 +1,108 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsedDocumentToText(t *testing.T) {
+	doc := &ParsedDocument{
+		Pages: []Page{
+			{
+				PageNumber: 1,
+				Blocks: []Block{
+					{Type: BlockTypeHeading, Level: 1, Text: "1. Access Control"},
+					{Type: BlockTypeParagraph, Text: "Users must authenticate."},
+					{Type: BlockTypeList, Text: "Use MFA", ListItem: &ListItem{Level: 1, Marker: "•"}},
+					{Type: BlockTypeList, Text: "For admins", ListItem: &ListItem{Level: 2, Marker: "a."}},
+				},
+			},
+			{
+				PageNumber: 2,
+				Blocks: []Block{
+					{Type: BlockTypeTable, TableData: &TableData{Rows: [][]string{{"Date", "Change"}, {"2024", "Initial"}}}},
+				},
+			},
+		},
+	}
+
+	want := "1. Access Control\n\nUsers must authenticate.\n\n• Use MFA\n  a. For admins\n" +
+		"\fDate\tChange\n2024\tInitial\n"
+	if got := doc.ToText(); got != want {
+		t.Errorf("ToText() = %q, want %q", got, want)
+	}
+}
+
+func TestParsedDocumentValidate(t *testing.T) {
+	valid := &ParsedDocument{
+		Pages: []Page{{
+			PageNumber: 1,
+			Blocks: []Block{
+				{Type: BlockTypeHeading, Level: 1, Text: "1. Access Control"},
+				{Type: BlockTypeTable, TableData: &TableData{Rows: [][]string{{"Setting", "Value"}}}},
+			},
+		}},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected a valid document, got %v", err)
+	}
+
+	if err := (&ParsedDocument{}).Validate(); err == nil {
+		t.Error("Expected a document without pages to be rejected")
+	}
+
+	invalid := &ParsedDocument{
+		Pages: []Page{{
+			PageNumber: 0,
+			Blocks: []Block{
+				{Type: "figure", Text: "Diagram"},
+				{Text: "Untyped"},
+				{Type: BlockTypeHeading, Level: 9, Text: "Too deep"},
+				{Type: BlockTypeTable},
+			},
+		}},
+	}
+	err := invalid.Validate()
+	if err == nil {
+		t.Fatal("Expected an invalid document to be rejected")
+	}
+	for _, want := range []string{
+		"pages[0]: page_number",
+		`pages[0].blocks[0]: unknown block type "figure"`,
+		"pages[0].blocks[1]: type is required",
+		"pages[0].blocks[2]: level",
+		"pages[0].blocks[3]: table block has no table_data rows",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestClassifyMarker(t *testing.T) {
+	tests := []struct {
+		marker string
+		want   MarkerKind
+	}{
+		{"1.", MarkerKindDecimal},
+		{"(12)", MarkerKindDecimal},
+		{"1.2", MarkerKindDecimal},
+		{"a.", MarkerKindLowerAlpha},
+		{"b)", MarkerKindLowerAlpha},
+		{"(C)", MarkerKindUpperAlpha},
+		{"i.", MarkerKindRoman},
+		{"(iv)", MarkerKindRoman},
+		{"XII.", MarkerKindRoman},
+		{"•", MarkerKindBullet},
+		{"*", MarkerKindBullet},
+		{"-", MarkerKindBullet},
+		{"", ""},
+		{"Ab.", ""},
+		{"iV.", ""},
+	}
+	for _, tt := range tests {
+		if got := ClassifyMarker(tt.marker); got != tt.want {
+			t.Errorf("ClassifyMarker(%q) = %q, want %q", tt.marker, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,9 @@ package validator
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/ossf/gemara/layer1"
@@ -12,6 +15,8 @@ import (
 type CoverageReport struct {
 	DocumentID        string              `json:"document_id" yaml:"document_id"`
 	Timestamp         time.Time           `json:"timestamp" yaml:"timestamp"`
+	ToolVersion       string              `json:"tool_version" yaml:"tool_version"` // Analyzer version that produced the report
+	Toolchain         *types.Toolchain    `json:"toolchain,omitempty" yaml:"toolchain,omitempty"`
 	
 	// Source document stats
 	SourceStats       SourceStats         `json:"source_stats" yaml:"source_stats"`
@@ -30,6 +35,25 @@ type CoverageReport struct {
 	
 	// Recommendations for schema improvements
 	Recommendations   []SchemaRecommendation `json:"recommendations,omitempty" yaml:"recommendations,omitempty"`
+	
+	// Schema validation outcome, when a Layer-1 document was available
+	Validation        *ValidationSummary  `json:"validation,omitempty" yaml:"validation,omitempty"`
+}
+
+// ValidationSummary counts the errors and warnings of a validation result
+type ValidationSummary struct {
+	Valid        bool `json:"valid" yaml:"valid"`
+	ErrorCount   int  `json:"error_count" yaml:"error_count"`
+	WarningCount int  `json:"warning_count" yaml:"warning_count"`
+}
+
+// SummarizeValidation condenses a validation result for a coverage report
+func SummarizeValidation(result *ValidationResult) *ValidationSummary {
+	return &ValidationSummary{
+		Valid:        result.Valid,
+		ErrorCount:   len(result.Errors),
+		WarningCount: len(result.Warnings),
+	}
 }
 
 // SourceStats tracks statistics from the source document
@@ -86,6 +110,15 @@ type CoverageAnalyzer struct {
 	strictMode bool
 }
 
+// analyzerVersion identifies the coverage analysis rules; bump it whenever
+// metrics or gap detection change
+const analyzerVersion = "1.2.0"
+
+// AnalyzerVersion returns the coverage analyzer version recorded in reports
+func AnalyzerVersion() string {
+	return analyzerVersion
+}
+
 // NewCoverageAnalyzer creates a new coverage analyzer
 func NewCoverageAnalyzer(strict bool) *CoverageAnalyzer {
 	return &CoverageAnalyzer{strictMode: strict}
@@ -94,8 +127,13 @@ func NewCoverageAnalyzer(strict bool) *CoverageAnalyzer {
 // AnalyzeFromSegmented analyzes coverage from a segmented document
 func (a *CoverageAnalyzer) AnalyzeFromSegmented(parsed *types.ParsedDocument, segmented *types.SegmentedDocument) *CoverageReport {
 	report := &CoverageReport{
-		DocumentID: segmented.Metadata.DocumentID,
-		Timestamp:  time.Now(),
+		DocumentID:  segmented.Metadata.DocumentID,
+		Timestamp:   time.Now(),
+		ToolVersion: analyzerVersion,
+		Toolchain: &types.Toolchain{
+			Parser:    parsed.Metadata.Parser,
+			Segmenter: segmented.Metadata.Segmenter,
+		},
 	}
 	
 	// Calculate source stats
@@ -122,8 +160,9 @@ func (a *CoverageAnalyzer) AnalyzeFromSegmented(parsed *types.ParsedDocument, se
 // AnalyzeLayer1 analyzes coverage of a Layer-1 document against expected schema
 func (a *CoverageAnalyzer) AnalyzeLayer1(doc *layer1.GuidanceDocument) *CoverageReport {
 	report := &CoverageReport{
-		DocumentID: doc.Metadata.Id,
-		Timestamp:  time.Now(),
+		DocumentID:  doc.Metadata.Id,
+		Timestamp:   time.Now(),
+		ToolVersion: analyzerVersion,
 	}
 	
 	// Analyze what's captured in Layer-1
@@ -236,6 +275,9 @@ func (a *CoverageAnalyzer) calculateCoverageMetrics(parsed *types.ParsedDocument
 		metrics.BlockCoverage = float64(mappedBlocks) / float64(totalBlocks) * 100
 	}
 	
+	// Character coverage
+	metrics.CharacterCoverage = characterCoverage(parsed, segmented)
+	
 	// Required fields (based on Layer-1 schema)
 	metrics.RequiredFieldsTotal = 4 // id, title, description, author
 	if segmented.DocumentMetadata.ID != "" {
@@ -275,9 +317,10 @@ func (a *CoverageAnalyzer) calculateCoverageMetrics(parsed *types.ParsedDocument
 	if metrics.OptionalFieldsTotal > 0 {
 		optionalScore = float64(metrics.OptionalFieldsCovered) / float64(metrics.OptionalFieldsTotal) * 20
 	}
-	blockScore := metrics.BlockCoverage * 0.2
+	// Content capture counts block and character coverage equally
+	contentScore := (metrics.BlockCoverage + metrics.CharacterCoverage) / 2 * 0.2
 	
-	metrics.OverallScore = requiredScore + optionalScore + blockScore
+	metrics.OverallScore = requiredScore + optionalScore + contentScore
 	
 	// Quality indicators
 	if metrics.OverallScore >= 90 {
@@ -301,6 +344,64 @@ func (a *CoverageAnalyzer) calculateCoverageMetrics(parsed *types.ParsedDocument
 	return metrics
 }
 
+// characterCoverage returns the percentage of the source text that made it
+// into category, guideline and part text, annotations and recommendations. Whitespace is
+// collapsed on both sides, since segmentation joins lines and trims
+// indentation, and recommendations quoted from their guideline's text are
+// counted once, so the result stays within 100%.
+func characterCoverage(parsed *types.ParsedDocument, segmented *types.SegmentedDocument) float64 {
+	source := 0
+	for _, page := range parsed.Pages {
+		for _, block := range page.Blocks {
+			source += normalizedLength(block.Text)
+		}
+	}
+	if source == 0 {
+		return 0
+	}
+	
+	captured := 0
+	for _, cat := range segmented.Categories {
+		captured += normalizedLength(cat.Title)
+		if cat.Description != cat.Title {
+			captured += normalizedLength(cat.Description)
+		}
+		for _, guide := range cat.Guidelines {
+			texts := []string{guide.Title, guide.Objective}
+			for _, annotation := range guide.Annotations {
+				texts = append(texts, annotation.Text)
+			}
+			for _, part := range guide.Parts {
+				texts = append(texts, part.Title, part.Text)
+				for _, annotation := range part.Annotations {
+					texts = append(texts, annotation.Text)
+				}
+			}
+			body := strings.Join(strings.Fields(strings.Join(texts, " ")), " ")
+			captured += len(body)
+			
+			recommendations := append([]string{}, guide.Recommendations...)
+			for _, part := range guide.Parts {
+				recommendations = append(recommendations, part.Recommendations...)
+			}
+			for _, rec := range recommendations {
+				rec = strings.Join(strings.Fields(rec), " ")
+				if !strings.Contains(body, rec) {
+					captured += len(rec)
+				}
+			}
+		}
+	}
+	
+	return math.Min(float64(captured)/float64(source)*100, 100)
+}
+
+// normalizedLength returns the length of text with runs of whitespace
+// collapsed to single spaces and leading and trailing whitespace removed
+func normalizedLength(text string) int {
+	return len(strings.Join(strings.Fields(text), " "))
+}
+
 // identifySchemaGaps identifies gaps in the schema based on unmapped content
 func (a *CoverageAnalyzer) identifySchemaGaps(segmented *types.SegmentedDocument) []types.SchemaGap {
 	if segmented == nil {
@@ -599,3 +700,79 @@ func boolToInt(b bool) int {
 	return 0
 }
 
+
+// CoverageDiff compares the coverage of two versions of a document, e.g.
+// before and after tuning the segmenter
+type CoverageDiff struct {
+	DocumentID        string      `json:"document_id" yaml:"document_id"`
+	OverallScore      MetricDelta `json:"overall_score" yaml:"overall_score"`
+	BlockCoverage     MetricDelta `json:"block_coverage" yaml:"block_coverage"`
+	CharacterCoverage MetricDelta `json:"character_coverage" yaml:"character_coverage"`
+	Categories        CountDelta  `json:"categories" yaml:"categories"`
+	Guidelines        CountDelta  `json:"guidelines" yaml:"guidelines"`
+	Parts             CountDelta  `json:"parts" yaml:"parts"`
+	
+	// Content types unmapped in the old version only, and in the new one only
+	NewlyMapped       []string    `json:"newly_mapped,omitempty" yaml:"newly_mapped,omitempty"`
+	NewlyUnmapped     []string    `json:"newly_unmapped,omitempty" yaml:"newly_unmapped,omitempty"`
+}
+
+// MetricDelta is a metric's old and new value and the change between them
+type MetricDelta struct {
+	Old   float64 `json:"old" yaml:"old"`
+	New   float64 `json:"new" yaml:"new"`
+	Delta float64 `json:"delta" yaml:"delta"`
+}
+
+// CountDelta is a count's old and new value and the change between them
+type CountDelta struct {
+	Old   int `json:"old" yaml:"old"`
+	New   int `json:"new" yaml:"new"`
+	Delta int `json:"delta" yaml:"delta"`
+}
+
+// Diff compares two coverage reports of the same document
+func (a *CoverageAnalyzer) Diff(old, new *CoverageReport) *CoverageDiff {
+	metric := func(o, n float64) MetricDelta {
+		return MetricDelta{Old: o, New: n, Delta: n - o}
+	}
+	count := func(o, n int) CountDelta {
+		return CountDelta{Old: o, New: n, Delta: n - o}
+	}
+	
+	diff := &CoverageDiff{
+		DocumentID:        new.DocumentID,
+		OverallScore:      metric(old.CoverageMetrics.OverallScore, new.CoverageMetrics.OverallScore),
+		BlockCoverage:     metric(old.CoverageMetrics.BlockCoverage, new.CoverageMetrics.BlockCoverage),
+		CharacterCoverage: metric(old.CoverageMetrics.CharacterCoverage, new.CoverageMetrics.CharacterCoverage),
+		Categories:        count(old.CapturedContent.Categories, new.CapturedContent.Categories),
+		Guidelines:        count(old.CapturedContent.Guidelines, new.CapturedContent.Guidelines),
+		Parts:             count(old.CapturedContent.Parts, new.CapturedContent.Parts),
+	}
+	
+	oldTypes := unmappedTypes(old)
+	newTypes := unmappedTypes(new)
+	for contentType := range oldTypes {
+		if !newTypes[contentType] {
+			diff.NewlyMapped = append(diff.NewlyMapped, contentType)
+		}
+	}
+	for contentType := range newTypes {
+		if !oldTypes[contentType] {
+			diff.NewlyUnmapped = append(diff.NewlyUnmapped, contentType)
+		}
+	}
+	sort.Strings(diff.NewlyMapped)
+	sort.Strings(diff.NewlyUnmapped)
+	
+	return diff
+}
+
+// unmappedTypes returns the content types of a report's unmapped content
+func unmappedTypes(report *CoverageReport) map[string]bool {
+	found := make(map[string]bool)
+	for _, unmapped := range report.UnmappedContent {
+		found[unmapped.ContentType] = true
+	}
+	return found
+}
@@ -0,0 +1,226 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+)
+
+// maxExportedContent caps the unmapped content quoted in exported reports
+const maxExportedContent = 100
+
+// ToMarkdown renders the report as a Markdown document with the sections
+// the coverage command prints, as tables, for sharing outside the pipeline
+func (r *CoverageReport) ToMarkdown() (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Schema Coverage Report: %s\n\n", r.DocumentID)
+	fmt.Fprintf(&b, "Generated %s by coverage analyzer %s.\n", r.Timestamp.Format("2006-01-02 15:04:05 MST"), r.ToolVersion)
+
+	if r.SourceStats.TotalBlocks > 0 {
+		b.WriteString("\n## Source Document\n\n| Metric | Value |\n| --- | --- |\n")
+		fmt.Fprintf(&b, "| Pages | %d |\n", r.SourceStats.TotalPages)
+		fmt.Fprintf(&b, "| Blocks | %d |\n", r.SourceStats.TotalBlocks)
+		fmt.Fprintf(&b, "| Characters | %d |\n", r.SourceStats.TotalCharacters)
+		if len(r.SourceStats.BlocksByType) > 0 {
+			b.WriteString("\n| Block type | Count |\n| --- | --- |\n")
+			for _, typ := range sortedKeys(r.SourceStats.BlocksByType) {
+				fmt.Fprintf(&b, "| %s | %d |\n", markdownCell(typ), r.SourceStats.BlocksByType[typ])
+			}
+		}
+	}
+
+	b.WriteString("\n## Captured Content\n\n| Element | Count |\n| --- | --- |\n")
+	fmt.Fprintf(&b, "| Categories | %d |\n", r.CapturedContent.Categories)
+	fmt.Fprintf(&b, "| Guidelines | %d |\n", r.CapturedContent.Guidelines)
+	fmt.Fprintf(&b, "| Parts | %d |\n", r.CapturedContent.Parts)
+	fmt.Fprintf(&b, "| Recommendations | %d |\n", r.CapturedContent.Recommendations)
+	if len(r.CapturedContent.FieldsCaptured) > 0 {
+		b.WriteString("\nFields populated:\n\n")
+		for _, field := range r.CapturedContent.FieldsCaptured {
+			fmt.Fprintf(&b, "- ✓ %s\n", field)
+		}
+	}
+	if len(r.CapturedContent.FieldsEmpty) > 0 {
+		b.WriteString("\nFields empty/missing:\n\n")
+		for _, field := range r.CapturedContent.FieldsEmpty {
+			fmt.Fprintf(&b, "- ✗ %s\n", field)
+		}
+	}
+
+	if r.Validation != nil {
+		b.WriteString("\n## Schema Validation\n\n| Check | Result |\n| --- | --- |\n")
+		fmt.Fprintf(&b, "| Valid | %v |\n", r.Validation.Valid)
+		fmt.Fprintf(&b, "| Errors | %d |\n", r.Validation.ErrorCount)
+		fmt.Fprintf(&b, "| Warnings | %d |\n", r.Validation.WarningCount)
+	}
+
+	metrics := r.CoverageMetrics
+	b.WriteString("\n## Coverage Metrics\n\n| Metric | Value |\n| --- | --- |\n")
+	fmt.Fprintf(&b, "| Overall Score | %.1f/100 |\n", metrics.OverallScore)
+	fmt.Fprintf(&b, "| Block Coverage | %.1f%% |\n", metrics.BlockCoverage)
+	fmt.Fprintf(&b, "| Character Coverage | %.1f%% |\n", metrics.CharacterCoverage)
+	fmt.Fprintf(&b, "| Required Fields | %d/%d |\n", metrics.RequiredFieldsCovered, metrics.RequiredFieldsTotal)
+	fmt.Fprintf(&b, "| Optional Fields | %d/%d |\n", metrics.OptionalFieldsCovered, metrics.OptionalFieldsTotal)
+	if len(metrics.QualityIndicators) > 0 {
+		b.WriteString("\n| Quality indicator | Value |\n| --- | --- |\n")
+		for _, indicator := range sortedKeys(metrics.QualityIndicators) {
+			fmt.Fprintf(&b, "| %s | %s |\n", markdownCell(indicator), markdownCell(metrics.QualityIndicators[indicator]))
+		}
+	}
+
+	if len(r.UnmappedContent) > 0 {
+		fmt.Fprintf(&b, "\n## Unmapped Content\n\n%d unmapped items.\n\n", len(r.UnmappedContent))
+		b.WriteString("| Type | Location | Reason | Suggested field | Content |\n| --- | --- | --- | --- | --- |\n")
+		for _, unmapped := range r.UnmappedContent {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+				markdownCell(unmapped.ContentType), markdownCell(unmapped.SourceLocation), markdownCell(unmapped.Reason),
+				markdownCell(unmapped.SuggestedField), markdownCell(truncate(unmapped.Content, maxExportedContent)))
+		}
+	}
+
+	if len(r.SchemaGaps) > 0 {
+		b.WriteString("\n## Schema Gaps\n\n| Priority | Suggested field | Description | Occurrences | Examples |\n| --- | --- | --- | --- | --- |\n")
+		for _, gap := range r.SchemaGaps {
+			fmt.Fprintf(&b, "| %s | %s | %s | %d | %s |\n",
+				markdownCell(gap.Priority), markdownCell(gap.SuggestedField), markdownCell(gap.Description),
+				gap.OccurrenceCount, markdownCell(strings.Join(gap.Examples, "; ")))
+		}
+	}
+
+	if len(r.Recommendations) > 0 {
+		b.WriteString("\n## Recommendations\n\n| # | Priority | Recommendation | Target | Rationale |\n| --- | --- | --- | --- | --- |\n")
+		for i, rec := range r.Recommendations {
+			fmt.Fprintf(&b, "| %d | %s | %s | %s | %s |\n",
+				i+1, markdownCell(rec.Priority), markdownCell(rec.Description), markdownCell(rec.Target), markdownCell(rec.Rationale))
+		}
+	}
+
+	return b.String(), nil
+}
+
+// ToHTML renders the report as a standalone HTML page with the same
+// sections as ToMarkdown, coloring priorities and the validation outcome by
+// severity
+func (r *CoverageReport) ToHTML() (string, error) {
+	var buf bytes.Buffer
+	if err := coverageHTML.Execute(&buf, r); err != nil {
+		return "", fmt.Errorf("failed to render coverage report: %w", err)
+	}
+	return buf.String(), nil
+}
+
+var coverageHTML = template.Must(template.New("coverage").Funcs(template.FuncMap{
+	"sortedCounts": sortedKeys[int],
+	"sortedLabels": sortedKeys[string],
+	"truncate":     func(content string) string { return truncate(content, maxExportedContent) },
+	"join":         strings.Join,
+	"inc":          func(i int) int { return i + 1 },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Schema Coverage Report: {{.DocumentID}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f3f3f3; }
+.high, .invalid { color: #b00020; font-weight: bold; }
+.medium { color: #b26a00; font-weight: bold; }
+.low, .valid { color: #2e7d32; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>Schema Coverage Report: {{.DocumentID}}</h1>
+<p>Generated {{.Timestamp.Format "2006-01-02 15:04:05 MST"}} by coverage analyzer {{.ToolVersion}}.</p>
+{{with .SourceStats}}{{if gt .TotalBlocks 0}}
+<h2>Source Document</h2>
+<table>
+<tr><th>Metric</th><th>Value</th></tr>
+<tr><td>Pages</td><td>{{.TotalPages}}</td></tr>
+<tr><td>Blocks</td><td>{{.TotalBlocks}}</td></tr>
+<tr><td>Characters</td><td>{{.TotalCharacters}}</td></tr>
+</table>
+{{if .BlocksByType}}<table>
+<tr><th>Block type</th><th>Count</th></tr>
+{{range $typ := sortedCounts .BlocksByType}}<tr><td>{{$typ}}</td><td>{{index $.SourceStats.BlocksByType $typ}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}{{end}}
+<h2>Captured Content</h2>
+{{with .CapturedContent}}<table>
+<tr><th>Element</th><th>Count</th></tr>
+<tr><td>Categories</td><td>{{.Categories}}</td></tr>
+<tr><td>Guidelines</td><td>{{.Guidelines}}</td></tr>
+<tr><td>Parts</td><td>{{.Parts}}</td></tr>
+<tr><td>Recommendations</td><td>{{.Recommendations}}</td></tr>
+</table>
+{{if .FieldsCaptured}}<p>Fields populated:</p>
+<ul>
+{{range .FieldsCaptured}}<li class="valid">✓ {{.}}</li>
+{{end}}</ul>
+{{end}}{{if .FieldsEmpty}}<p>Fields empty/missing:</p>
+<ul>
+{{range .FieldsEmpty}}<li class="invalid">✗ {{.}}</li>
+{{end}}</ul>
+{{end}}{{end}}{{with .Validation}}
+<h2>Schema Validation</h2>
+<table>
+<tr><th>Check</th><th>Result</th></tr>
+<tr><td>Valid</td><td class="{{if .Valid}}valid{{else}}invalid{{end}}">{{.Valid}}</td></tr>
+<tr><td>Errors</td><td{{if .ErrorCount}} class="high"{{end}}>{{.ErrorCount}}</td></tr>
+<tr><td>Warnings</td><td{{if .WarningCount}} class="medium"{{end}}>{{.WarningCount}}</td></tr>
+</table>
+{{end}}
+<h2>Coverage Metrics</h2>
+{{with .CoverageMetrics}}<table>
+<tr><th>Metric</th><th>Value</th></tr>
+<tr><td>Overall Score</td><td>{{printf "%.1f" .OverallScore}}/100</td></tr>
+<tr><td>Block Coverage</td><td>{{printf "%.1f" .BlockCoverage}}%</td></tr>
+<tr><td>Character Coverage</td><td>{{printf "%.1f" .CharacterCoverage}}%</td></tr>
+<tr><td>Required Fields</td><td>{{.RequiredFieldsCovered}}/{{.RequiredFieldsTotal}}</td></tr>
+<tr><td>Optional Fields</td><td>{{.OptionalFieldsCovered}}/{{.OptionalFieldsTotal}}</td></tr>
+</table>
+{{if .QualityIndicators}}<table>
+<tr><th>Quality indicator</th><th>Value</th></tr>
+{{range $indicator := sortedLabels .QualityIndicators}}<tr><td>{{$indicator}}</td><td>{{index $.CoverageMetrics.QualityIndicators $indicator}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}{{if .UnmappedContent}}
+<h2>Unmapped Content</h2>
+<p>{{len .UnmappedContent}} unmapped items.</p>
+<table>
+<tr><th>Type</th><th>Location</th><th>Reason</th><th>Suggested field</th><th>Content</th></tr>
+{{range .UnmappedContent}}<tr><td>{{.ContentType}}</td><td>{{.SourceLocation}}</td><td>{{.Reason}}</td><td>{{.SuggestedField}}</td><td>{{truncate .Content}}</td></tr>
+{{end}}</table>
+{{end}}{{if .SchemaGaps}}
+<h2>Schema Gaps</h2>
+<table>
+<tr><th>Priority</th><th>Suggested field</th><th>Description</th><th>Occurrences</th><th>Examples</th></tr>
+{{range .SchemaGaps}}<tr><td class="{{.Priority}}">{{.Priority}}</td><td>{{.SuggestedField}}</td><td>{{.Description}}</td><td>{{.OccurrenceCount}}</td><td>{{join .Examples "; "}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Recommendations}}
+<h2>Recommendations</h2>
+<table>
+<tr><th>#</th><th>Priority</th><th>Recommendation</th><th>Target</th><th>Rationale</th></tr>
+{{range $i, $rec := .Recommendations}}<tr><td>{{inc $i}}</td><td class="{{$rec.Priority}}">{{$rec.Priority}}</td><td>{{$rec.Description}}</td><td>{{$rec.Target}}</td><td>{{$rec.Rationale}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`))
+
+// sortedKeys returns a map's keys in sorted order, for stable output
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// markdownCell escapes text for a Markdown table cell
+func markdownCell(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.ReplaceAll(text, "|", `\|`)
+}
@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ossf/gemara/layer1/pipeline/types"
+)
+
+func TestCharacterCoverage(t *testing.T) {
+	requirement := "Encrypt stored cardholder data using strong cryptography."
+	parsed := &types.ParsedDocument{
+		Pages: []types.Page{{
+			PageNumber: 1,
+			Blocks: []types.Block{
+				{Type: types.BlockTypeHeading, Text: "1 Protect Data"},
+				{Type: types.BlockTypeHeading, Text: "1.1   Encryption"},
+				{Type: types.BlockTypeParagraph, Text: "  " + requirement + "\n"},
+				{Type: types.BlockTypeParagraph, Text: strings.Repeat("Appendix text. ", 10)},
+			},
+		}},
+	}
+	segmented := &types.SegmentedDocument{
+		Categories: []types.SegmentCategory{{
+			ID:          "1",
+			Title:       "Protect Data",
+			Description: "Protect Data",
+			Guidelines: []types.SegmentGuideline{{
+				ID:    "1.1",
+				Title: "Encryption",
+				Parts: []types.SegmentPart{{
+					ID:              "1.1.a",
+					Text:            requirement,
+					Recommendations: []string{requirement}, // Quoted from the part, counted once
+				}},
+			}},
+		}},
+		UnmappedContent: []types.UnmappedContent{{ContentType: "appendix"}},
+	}
+
+	metrics := NewCoverageAnalyzer(false).calculateCoverageMetrics(parsed, segmented)
+
+	// About 80 of the 235 normalized source characters are captured; the
+	// appendix and the section numbers aren't
+	if metrics.CharacterCoverage < 30 || metrics.CharacterCoverage > 40 {
+		t.Errorf("Expected character coverage between 30%% and 40%%, got %.2f%%", metrics.CharacterCoverage)
+	}
+	if metrics.BlockCoverage != 75 {
+		t.Errorf("Expected block coverage 75%%, got %.2f%%", metrics.BlockCoverage)
+	}
+	// Content capture adds the mean of block and character coverage, weighted 0.2
+	if metrics.OverallScore != (metrics.BlockCoverage+metrics.CharacterCoverage)/2*0.2 {
+		t.Errorf("Expected only content capture in the score, got %.2f", metrics.OverallScore)
+	}
+
+	// Text repeated across the segmentation can't push coverage past 100%
+	segmented.Categories = append(segmented.Categories, segmented.Categories[0], segmented.Categories[0], segmented.Categories[0])
+	if coverage := characterCoverage(parsed, segmented); coverage != 100 {
+		t.Errorf("Expected coverage capped at 100%%, got %.2f%%", coverage)
+	}
+}
+
+func TestCoverageDiff(t *testing.T) {
+	old := &CoverageReport{
+		DocumentID:      "doc",
+		CapturedContent: CapturedContent{Categories: 2, Guidelines: 5, Parts: 8},
+		CoverageMetrics: CoverageMetrics{OverallScore: 60, BlockCoverage: 70},
+		UnmappedContent: []types.UnmappedContent{{ContentType: "table"}, {ContentType: "figure"}, {ContentType: "table"}},
+	}
+	new := &CoverageReport{
+		DocumentID:      "doc",
+		CapturedContent: CapturedContent{Categories: 2, Guidelines: 9, Parts: 6},
+		CoverageMetrics: CoverageMetrics{OverallScore: 72.5, BlockCoverage: 85},
+		UnmappedContent: []types.UnmappedContent{{ContentType: "figure"}, {ContentType: "appendix"}},
+	}
+
+	diff := NewCoverageAnalyzer(false).Diff(old, new)
+
+	if diff.OverallScore != (MetricDelta{Old: 60, New: 72.5, Delta: 12.5}) {
+		t.Errorf("Unexpected overall score delta: %+v", diff.OverallScore)
+	}
+	if diff.BlockCoverage.Delta != 15 {
+		t.Errorf("Expected block coverage +15, got %+v", diff.BlockCoverage)
+	}
+	if diff.Categories.Delta != 0 || diff.Guidelines.Delta != 4 || diff.Parts.Delta != -2 {
+		t.Errorf("Unexpected count deltas: %+v %+v %+v", diff.Categories, diff.Guidelines, diff.Parts)
+	}
+	if len(diff.NewlyMapped) != 1 || diff.NewlyMapped[0] != "table" {
+		t.Errorf("Expected table newly mapped, got %v", diff.NewlyMapped)
+	}
+	if len(diff.NewlyUnmapped) != 1 || diff.NewlyUnmapped[0] != "appendix" {
+		t.Errorf("Expected appendix newly unmapped, got %v", diff.NewlyUnmapped)
+	}
+}
+
+func TestCoverageReportExport(t *testing.T) {
+	report := &CoverageReport{
+		DocumentID:      "doc",
+		ToolVersion:     analyzerVersion,
+		SourceStats:     SourceStats{TotalPages: 2, TotalBlocks: 10, BlocksByType: map[string]int{"text": 8, "table": 2}},
+		CapturedContent: CapturedContent{Categories: 1, Guidelines: 3, FieldsEmpty: []string{"rationale"}},
+		Validation:      &ValidationSummary{Valid: false, ErrorCount: 2},
+		CoverageMetrics: CoverageMetrics{OverallScore: 72.345, BlockCoverage: 80},
+		SchemaGaps:      []types.SchemaGap{{SuggestedField: "tables", Description: "Tables | grids", OccurrenceCount: 2, Priority: "high"}},
+		Recommendations: []SchemaRecommendation{{Priority: "medium", Description: "Add <table> support", Target: "guideline"}},
+	}
+
+	md, err := report.ToMarkdown()
+	if err != nil {
+		t.Fatalf("ToMarkdown failed: %v", err)
+	}
+	for _, want := range []string{"# Schema Coverage Report: doc", "| Overall Score | 72.3/100 |", "| table | 2 |", `Tables \| grids`, "## Recommendations", "- ✗ rationale"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Expected Markdown to contain %q, got:\n%s", want, md)
+		}
+	}
+
+	html, err := report.ToHTML()
+	if err != nil {
+		t.Fatalf("ToHTML failed: %v", err)
+	}
+	for _, want := range []string{`<td class="high">high</td>`, `<td class="invalid">false</td>`, "Add &lt;table&gt; support", "72.3/100"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected HTML to contain %q, got:\n%s", want, html)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/ossf/gemara/layer1"
+)
+
+// CUEDefinition is the definition in the Layer-1 CUE schema that documents
+// are checked against
+const CUEDefinition = "#GuidanceDocument"
+
+var (
+	// Matches the summary CUE reports for a value outside an enum
+	cueDisjunctionRegex = regexp.MustCompile(`^\d+ errors in empty disjunction$`)
+
+	// Matches one alternative of an enum the value conflicted with
+	cueConflictRegex = regexp.MustCompile(`^conflicting values (.+) and (.+)$`)
+)
+
+// WithCUESchema additionally validates documents against the CUE schema at
+// schemaPath, such as schemas/layer-1.cue, so validation follows the schema
+// of record as it evolves. Documents are checked by the cue command-line
+// tool (cue vet), which must be installed.
+func WithCUESchema(schemaPath string) Option {
+	return func(v *Validator) {
+		v.cueSchema = schemaPath
+	}
+}
+
+// validateCUE checks the marshaled document against the CUE schema's
+// CUEDefinition and reports each CUE error at its document path
+func (v *Validator) validateCUE(doc *layer1.GuidanceDocument, result *ValidationResult) {
+	if _, err := os.Stat(v.cueSchema); err != nil {
+		result.AddError("", fmt.Sprintf("failed to load CUE schema %s: %v", v.cueSchema, err), nil)
+		return
+	}
+	if _, err := exec.LookPath("cue"); err != nil {
+		result.AddError("", fmt.Sprintf("cue not found (install cuelang.org/go/cmd/cue): %v", err), nil)
+		return
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		result.AddError("", fmt.Sprintf("failed to marshal document for CUE validation: %v", err), nil)
+		return
+	}
+	docFile, err := os.CreateTemp("", "layer1-*.json")
+	if err != nil {
+		result.AddError("", fmt.Sprintf("failed to write document for CUE validation: %v", err), nil)
+		return
+	}
+	defer os.Remove(docFile.Name())
+	_, err = docFile.Write(data)
+	if closeErr := docFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		result.AddError("", fmt.Sprintf("failed to write document for CUE validation: %v", err), nil)
+		return
+	}
+
+	cmd := exec.Command("cue", "vet", "-c", "-d", CUEDefinition, v.cueSchema, docFile.Name())
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return
+	}
+	var exitErr *exec.ExitError
+	cueErrors := parseCUEErrors(string(output))
+	if !errors.As(err, &exitErr) || len(cueErrors) == 0 {
+		result.AddError("", fmt.Sprintf("cue vet failed: %v: %s", err, strings.TrimSpace(string(output))), nil)
+		return
+	}
+	for _, e := range cueErrors {
+		result.AddError(e.Path, e.Message, e.Value)
+	}
+}
+
+// parseCUEErrors reads the errors cue vet prints, one "path: message" line
+// each followed by indented source positions. A value outside an enum is
+// reported once, as "must be one of" the enum's values, rather than once
+// per alternative.
+func parseCUEErrors(output string) []ValidationError {
+	var errs []ValidationError
+	seen := make(map[string]bool)
+	disjunctions := make(map[string]int) // path -> index into errs
+	conflicts := make(map[string][][2]string)
+
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" || line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		path, message, ok := splitCUEError(line)
+		if !ok {
+			continue
+		}
+		message = strings.TrimSuffix(message, ":")
+
+		if cueDisjunctionRegex.MatchString(message) {
+			if _, ok := disjunctions[path]; !ok {
+				disjunctions[path] = len(errs)
+				errs = append(errs, ValidationError{Path: path, Message: message})
+			}
+			continue
+		}
+		if matches := cueConflictRegex.FindStringSubmatch(message); matches != nil {
+			if _, ok := disjunctions[path]; ok {
+				conflicts[path] = append(conflicts[path], [2]string{matches[1], matches[2]})
+				continue
+			}
+		}
+		if key := path + "\x00" + message; !seen[key] {
+			seen[key] = true
+			errs = append(errs, ValidationError{Path: path, Message: message})
+		}
+	}
+
+	for path, i := range disjunctions {
+		if allowed, value, ok := enumConflict(conflicts[path]); ok {
+			errs[i].Message = "must be one of: " + strings.Join(allowed, ", ")
+			errs[i].Value = value
+		}
+	}
+	return errs
+}
+
+// splitCUEError splits a CUE error line at the colon ending its path, which
+// may contain quoted labels such as metadata."document-type", and converts
+// the path to the validator's style
+func splitCUEError(line string) (string, string, bool) {
+	var labels []string
+	var label strings.Builder
+	quoted := false
+	for i := 0; i < len(line); i++ {
+		switch ch := line[i]; {
+		case ch == '"':
+			quoted = !quoted
+		case quoted:
+			label.WriteByte(ch)
+		case ch == '.':
+			labels = append(labels, label.String())
+			label.Reset()
+		case ch == ':' && strings.HasPrefix(line[i:], ": "):
+			labels = append(labels, label.String())
+			// The definition the document was checked against is not part of its path
+			if strings.HasPrefix(labels[0], "#") {
+				labels = labels[1:]
+			}
+			return instancePath(labels), strings.TrimSpace(line[i+2:]), true
+		case ch == ' ':
+			return "", "", false
+		default:
+			label.WriteByte(ch)
+		}
+	}
+	return "", "", false
+}
+
+// enumConflict finds the document's value among an enum's conflicts, as the
+// one value every conflict shares, and returns the enum's other values
+func enumConflict(conflicts [][2]string) ([]string, string, bool) {
+	if len(conflicts) < 2 {
+		return nil, "", false
+	}
+	var value string
+	for _, candidate := range conflicts[0] {
+		shared := true
+		for _, pair := range conflicts[1:] {
+			if pair[0] != candidate && pair[1] != candidate {
+				shared = false
+				break
+			}
+		}
+		if shared {
+			value = candidate
+			break
+		}
+	}
+	if value == "" {
+		return nil, "", false
+	}
+
+	allowed := make([]string, 0, len(conflicts))
+	for _, pair := range conflicts {
+		other := pair[0]
+		if other == value {
+			other = pair[1]
+		}
+		allowed = append(allowed, strings.Trim(other, `"`))
+	}
+	return allowed, strings.Trim(value, `"`), true
+}
@@ -0,0 +1,78 @@
+package validator
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// cueVetOutput is cue vet's report for a document with an unknown document
+// type and no title
+const cueVetOutput = `#GuidanceDocument.metadata."document-type": 4 errors in empty disjunction:
+#GuidanceDocument.metadata."document-type": conflicting values "Best Practice" and "Guidance":
+    ./test-data/layer-1.cue:17:47
+    /tmp/layer1-1234.json:1:80
+#GuidanceDocument.metadata."document-type": conflicting values "Framework" and "Guidance":
+    ./test-data/layer-1.cue:17:65
+    /tmp/layer1-1234.json:1:80
+#GuidanceDocument.metadata."document-type": conflicting values "Regulation" and "Guidance":
+    ./test-data/layer-1.cue:17:30
+    /tmp/layer1-1234.json:1:80
+#GuidanceDocument.metadata."document-type": conflicting values "Standard" and "Guidance":
+    ./test-data/layer-1.cue:17:15
+    /tmp/layer1-1234.json:1:80
+#GuidanceDocument.metadata.title: incomplete value string:
+    ./test-data/layer-1.cue:12:20
+#GuidanceDocument.categories.0.id: conflicting values 1 and string (mismatched types int and string):
+    ./test-data/layer-1.cue:8:12
+`
+
+func TestParseCUEErrors(t *testing.T) {
+	errs := parseCUEErrors(cueVetOutput)
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 errors, got %v", errs)
+	}
+
+	enum := errs[0]
+	if enum.Path != "metadata.document-type" || enum.Message != "must be one of: Best Practice, Framework, Regulation, Standard" || enum.Value != "Guidance" {
+		t.Errorf("Expected the enum violation reported once, got %v", enum)
+	}
+	if errs[1].Path != "metadata.title" || errs[1].Message != "incomplete value string" {
+		t.Errorf("Unexpected error: %v", errs[1])
+	}
+	if errs[2].Path != "categories[0].id" {
+		t.Errorf("Expected a dotted path with an index, got %q", errs[2].Path)
+	}
+
+	if errs := parseCUEErrors("some instances are incomplete; use the -c flag to show errors or suppress this message\n"); len(errs) != 0 {
+		t.Errorf("Expected notes without a path to be skipped, got %v", errs)
+	}
+}
+
+func TestValidator_CUESchema(t *testing.T) {
+	schemaPath := filepath.Join("test-data", "layer-1.cue")
+
+	result := NewValidator(WithCUESchema(filepath.Join(t.TempDir(), "missing.cue"))).Validate(schemaTestDocument())
+	if result.Valid {
+		t.Error("Expected an unreadable CUE schema to fail validation")
+	}
+
+	if _, err := exec.LookPath("cue"); err != nil {
+		t.Skip("cue not installed")
+	}
+
+	if result := NewValidator(WithCUESchema(schemaPath)).Validate(schemaTestDocument()); !result.Valid {
+		t.Errorf("Expected a valid document to pass the CUE schema, got %v", result.Errors)
+	}
+
+	doc := schemaTestDocument()
+	doc.Metadata.DocumentType = "Guidance"
+	builtIn := NewValidator().Validate(doc)
+	result = NewValidator(WithCUESchema(schemaPath)).Validate(doc)
+
+	// The CUE errors follow the built-in checks' own
+	cueErrors := result.Errors[len(builtIn.Errors):]
+	if len(cueErrors) != 1 || cueErrors[0].Path != "metadata.document-type" || cueErrors[0].Value != "Guidance" {
+		t.Errorf("Expected the document type rejected by the CUE schema, got %v", cueErrors)
+	}
+}
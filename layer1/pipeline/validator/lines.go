@@ -0,0 +1,67 @@
+package validator
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// pathSegmentRegex matches one dot-separated segment of a validation path
+// ("guidelines[1]", "guideline-parts[0]", "document-type")
+var pathSegmentRegex = regexp.MustCompile(`^([\w-]+)((?:\[\d+\])*)$`)
+
+// PathLine returns the line in a YAML or JSON document of the value a
+// validation path such as "categories[0].guidelines[1].id" refers to. A
+// path ending at a missing field, such as an omitted required field, gets
+// the line of its closest existing parent. It returns 0 when the document
+// can't be parsed.
+func PathLine(data []byte, path string) int {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil || len(root.Content) == 0 {
+		return 0
+	}
+	node := root.Content[0]
+	line := node.Line
+	if path == "" {
+		return line
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		matches := pathSegmentRegex.FindStringSubmatch(segment)
+		if matches == nil {
+			return line
+		}
+
+		if node = mappingValue(node, matches[1]); node == nil {
+			return line
+		}
+		line = node.Line
+		for _, index := range strings.Split(strings.Trim(matches[2], "[]"), "][") {
+			if index == "" {
+				continue
+			}
+			i, _ := strconv.Atoi(index)
+			if node.Kind != yaml.SequenceNode || i >= len(node.Content) {
+				return line
+			}
+			node = node.Content[i]
+			line = node.Line
+		}
+	}
+	return line
+}
+
+// mappingValue returns the value of key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ossf/gemara/layer1"
+)
+
+// Lint severities, ordered from most to least severe
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+)
+
+// Severities lists lint severities from most to least severe
+var Severities = []string{SeverityHigh, SeverityMedium, SeverityLow}
+
+// LintRule checks a Layer-1 document for a soft-quality issue.
+// Findings reuse SchemaRecommendation: Type is the rule name and Priority the severity.
+type LintRule struct {
+	Name        string
+	Description string
+	Check       func(doc *layer1.GuidanceDocument, cfg LintConfig) []SchemaRecommendation
+}
+
+// LintConfig holds the thresholds used by lint rules
+type LintConfig struct {
+	MinPartTextLength      int // Parts with less text than this are flagged
+	MinRecommendationWords int // Recommendations with fewer words than this are flagged
+	MaxTextLength          int // Part text and recommendations longer than this are flagged (0 = no limit)
+}
+
+// DefaultLintConfig returns the default lint thresholds
+func DefaultLintConfig() LintConfig {
+	return LintConfig{
+		MinPartTextLength:      20,
+		MinRecommendationWords: 4,
+		MaxTextLength:          2000,
+	}
+}
+
+// DefaultLintRules are the rules run by a Linter unless overridden
+var DefaultLintRules = []LintRule{
+	{
+		Name:        "missing-objective",
+		Description: "Guidelines should state an objective",
+		Check:       lintMissingObjective,
+	},
+	{
+		Name:        "short-part-text",
+		Description: "Part text is too short to be meaningful",
+		Check:       lintShortPartText,
+	},
+	{
+		Name:        "single-guideline-category",
+		Description: "Categories with a single guideline may be mis-segmented",
+		Check:       lintSingleGuidelineCategory,
+	},
+	{
+		Name:        "fragment-recommendation",
+		Description: "Recommendations should be complete statements",
+		Check:       lintFragmentRecommendation,
+	},
+	{
+		Name:        "oversized-text",
+		Description: "Part text or recommendations so long they likely merge several requirements",
+		Check:       lintOversizedText,
+	},
+}
+
+// Linter reports soft-quality issues that don't violate the schema
+type Linter struct {
+	rules  []LintRule
+	config LintConfig
+}
+
+// LintOption is a functional option for configuring the linter
+type LintOption func(*Linter)
+
+// WithLintRules replaces the rule set
+func WithLintRules(rules ...LintRule) LintOption {
+	return func(l *Linter) {
+		l.rules = rules
+	}
+}
+
+// WithDisabledLintRules removes rules by name
+func WithDisabledLintRules(names ...string) LintOption {
+	return func(l *Linter) {
+		disabled := make(map[string]bool)
+		for _, name := range names {
+			disabled[strings.TrimSpace(name)] = true
+		}
+		var rules []LintRule
+		for _, rule := range l.rules {
+			if !disabled[rule.Name] {
+				rules = append(rules, rule)
+			}
+		}
+		l.rules = rules
+	}
+}
+
+// WithLintConfig sets the lint thresholds
+func WithLintConfig(cfg LintConfig) LintOption {
+	return func(l *Linter) {
+		l.config = cfg
+	}
+}
+
+// NewLinter creates a linter with the default rules and optional configuration
+func NewLinter(opts ...LintOption) *Linter {
+	l := &Linter{
+		rules:  DefaultLintRules,
+		config: DefaultLintConfig(),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Rules returns the rules the linter will run
+func (l *Linter) Rules() []LintRule {
+	return l.rules
+}
+
+// Lint runs all configured rules against the document
+func (l *Linter) Lint(doc *layer1.GuidanceDocument) []SchemaRecommendation {
+	if doc == nil {
+		return nil
+	}
+
+	var findings []SchemaRecommendation
+	for _, rule := range l.rules {
+		findings = append(findings, rule.Check(doc, l.config)...)
+	}
+	return findings
+}
+
+// GroupBySeverity buckets findings by their Priority
+func GroupBySeverity(findings []SchemaRecommendation) map[string][]SchemaRecommendation {
+	groups := make(map[string][]SchemaRecommendation)
+	for _, f := range findings {
+		groups[f.Priority] = append(groups[f.Priority], f)
+	}
+	return groups
+}
+
+func lintMissingObjective(doc *layer1.GuidanceDocument, cfg LintConfig) []SchemaRecommendation {
+	var findings []SchemaRecommendation
+	for i, cat := range doc.Categories {
+		for j, guide := range cat.Guidelines {
+			if strings.TrimSpace(guide.Objective) == "" {
+				findings = append(findings, SchemaRecommendation{
+					Type:        "missing-objective",
+					Target:      fmt.Sprintf("categories[%d].guidelines[%d].objective", i, j),
+					Description: fmt.Sprintf("Guideline '%s' has no objective", guide.Id),
+					Priority:    SeverityMedium,
+					Rationale:   "Objectives help clarify the purpose of each guideline",
+				})
+			}
+		}
+	}
+	return findings
+}
+
+func lintShortPartText(doc *layer1.GuidanceDocument, cfg LintConfig) []SchemaRecommendation {
+	var findings []SchemaRecommendation
+	for i, cat := range doc.Categories {
+		for j, guide := range cat.Guidelines {
+			for k, part := range guide.GuidelineParts {
+				text := strings.TrimSpace(part.Text)
+				if text != "" && len(text) < cfg.MinPartTextLength {
+					findings = append(findings, SchemaRecommendation{
+						Type:        "short-part-text",
+						Target:      fmt.Sprintf("categories[%d].guidelines[%d].guideline-parts[%d].text", i, j, k),
+						Description: fmt.Sprintf("Part '%s' has very short text (%d characters)", part.Id, len(text)),
+						Priority:    SeverityLow,
+						Rationale:   fmt.Sprintf("Part text under %d characters is often a truncated extraction", cfg.MinPartTextLength),
+						Examples:    []string{text},
+					})
+				}
+			}
+		}
+	}
+	return findings
+}
+
+func lintSingleGuidelineCategory(doc *layer1.GuidanceDocument, cfg LintConfig) []SchemaRecommendation {
+	var findings []SchemaRecommendation
+	for i, cat := range doc.Categories {
+		if len(cat.Guidelines) == 1 {
+			findings = append(findings, SchemaRecommendation{
+				Type:        "single-guideline-category",
+				Target:      fmt.Sprintf("categories[%d]", i),
+				Description: fmt.Sprintf("Category '%s' contains a single guideline", cat.Id),
+				Priority:    SeverityLow,
+				Rationale:   "Single-guideline categories often indicate a missed category or guideline boundary",
+			})
+		}
+	}
+	return findings
+}
+
+func lintFragmentRecommendation(doc *layer1.GuidanceDocument, cfg LintConfig) []SchemaRecommendation {
+	var findings []SchemaRecommendation
+	check := func(recs []string, path, owner string) {
+		for i, rec := range recs {
+			if isRecommendationFragment(rec, cfg.MinRecommendationWords) {
+				findings = append(findings, SchemaRecommendation{
+					Type:        "fragment-recommendation",
+					Target:      fmt.Sprintf("%s.recommendations[%d]", path, i),
+					Description: fmt.Sprintf("Recommendation on '%s' looks like a sentence fragment", owner),
+					Priority:    SeverityMedium,
+					Rationale:   "Keyword-based extraction can capture partial lines containing 'should' or 'must'",
+					Examples:    []string{truncate(rec, 100)},
+				})
+			}
+		}
+	}
+
+	for i, cat := range doc.Categories {
+		for j, guide := range cat.Guidelines {
+			guidePath := fmt.Sprintf("categories[%d].guidelines[%d]", i, j)
+			check(guide.Recommendations, guidePath, guide.Id)
+			for k, part := range guide.GuidelineParts {
+				check(part.Recommendations, fmt.Sprintf("%s.guideline-parts[%d]", guidePath, k), part.Id)
+			}
+		}
+	}
+	return findings
+}
+
+func lintOversizedText(doc *layer1.GuidanceDocument, cfg LintConfig) []SchemaRecommendation {
+	if cfg.MaxTextLength <= 0 {
+		return nil
+	}
+
+	var findings []SchemaRecommendation
+	check := func(text, path, description string) {
+		text = strings.TrimSpace(text)
+		if length := utf8.RuneCountInString(text); length > cfg.MaxTextLength {
+			findings = append(findings, SchemaRecommendation{
+				Type:        "oversized-text",
+				Target:      path,
+				Description: fmt.Sprintf("%s is %d characters long and may need splitting", description, length),
+				Priority:    SeverityMedium,
+				Rationale:   fmt.Sprintf("Text over %d characters usually means segmentation failed to split several requirements", cfg.MaxTextLength),
+				Examples:    []string{truncate(text, 100)},
+			})
+		}
+	}
+	checkRecommendations := func(recs []string, path, owner string) {
+		for i, rec := range recs {
+			check(rec, fmt.Sprintf("%s.recommendations[%d]", path, i), fmt.Sprintf("Recommendation on '%s'", owner))
+		}
+	}
+
+	for i, cat := range doc.Categories {
+		for j, guide := range cat.Guidelines {
+			guidePath := fmt.Sprintf("categories[%d].guidelines[%d]", i, j)
+			checkRecommendations(guide.Recommendations, guidePath, guide.Id)
+			for k, part := range guide.GuidelineParts {
+				partPath := fmt.Sprintf("%s.guideline-parts[%d]", guidePath, k)
+				check(part.Text, partPath+".text", fmt.Sprintf("Text of part '%s'", part.Id))
+				checkRecommendations(part.Recommendations, partPath, part.Id)
+			}
+		}
+	}
+	return findings
+}
+
+// isRecommendationFragment reports whether a recommendation is too short to
+// stand alone or starts mid-sentence with a modal verb (e.g. "should be reviewed")
+func isRecommendationFragment(rec string, minWords int) bool {
+	words := strings.Fields(rec)
+	if len(words) == 0 {
+		return true
+	}
+	if len(words) < minWords {
+		return true
+	}
+	first := strings.ToLower(words[0])
+	return first == "should" || first == "must" || first == "shall"
+}
@@ -0,0 +1,196 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ossf/gemara/layer1"
+)
+
+func lintTestDocument() *layer1.GuidanceDocument {
+	return &layer1.GuidanceDocument{
+		Metadata: layer1.Metadata{
+			Id:          "lint-doc",
+			Title:       "Lint Document",
+			Description: "A document for lint tests",
+			Author:      "Test Author",
+		},
+		Categories: []layer1.Category{
+			{
+				Id:          "cat-1",
+				Title:       "Category 1",
+				Description: "First category",
+				Guidelines: []layer1.Guideline{
+					{
+						Id:        "guide-1",
+						Title:     "Guideline 1",
+						Objective: "Keep systems patched",
+						Recommendations: []string{
+							"Apply security patches within 30 days of release",
+						},
+						GuidelineParts: []layer1.Part{
+							{Id: "part-1", Text: "Maintain an inventory of all installed software"},
+						},
+					},
+					{
+						Id:        "guide-2",
+						Title:     "Guideline 2",
+						Objective: "Monitor systems",
+					},
+				},
+			},
+		},
+	}
+}
+
+func countFindings(findings []SchemaRecommendation, ruleType string) int {
+	count := 0
+	for _, f := range findings {
+		if f.Type == ruleType {
+			count++
+		}
+	}
+	return count
+}
+
+func TestLinter_CleanDocument(t *testing.T) {
+	findings := NewLinter().Lint(lintTestDocument())
+	if len(findings) != 0 {
+		t.Errorf("Expected no findings, got %v", findings)
+	}
+}
+
+func TestLinter_Rules(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*layer1.GuidanceDocument)
+		ruleType string
+		severity string
+	}{
+		{
+			name: "missing objective",
+			modify: func(d *layer1.GuidanceDocument) {
+				d.Categories[0].Guidelines[0].Objective = ""
+			},
+			ruleType: "missing-objective",
+			severity: SeverityMedium,
+		},
+		{
+			name: "short part text",
+			modify: func(d *layer1.GuidanceDocument) {
+				d.Categories[0].Guidelines[0].GuidelineParts[0].Text = "See above"
+			},
+			ruleType: "short-part-text",
+			severity: SeverityLow,
+		},
+		{
+			name: "single guideline category",
+			modify: func(d *layer1.GuidanceDocument) {
+				d.Categories[0].Guidelines = d.Categories[0].Guidelines[:1]
+			},
+			ruleType: "single-guideline-category",
+			severity: SeverityLow,
+		},
+		{
+			name: "should fragment",
+			modify: func(d *layer1.GuidanceDocument) {
+				d.Categories[0].Guidelines[0].Recommendations = []string{"should be reviewed annually by management"}
+			},
+			ruleType: "fragment-recommendation",
+			severity: SeverityMedium,
+		},
+		{
+			name: "too few words in part recommendation",
+			modify: func(d *layer1.GuidanceDocument) {
+				d.Categories[0].Guidelines[0].GuidelineParts[0].Recommendations = []string{"must"}
+			},
+			ruleType: "fragment-recommendation",
+			severity: SeverityMedium,
+		},
+		{
+			name: "oversized part text",
+			modify: func(d *layer1.GuidanceDocument) {
+				d.Categories[0].Guidelines[0].GuidelineParts[0].Text = strings.Repeat("Systems must be patched. ", 100)
+			},
+			ruleType: "oversized-text",
+			severity: SeverityMedium,
+		},
+		{
+			name: "oversized recommendation",
+			modify: func(d *layer1.GuidanceDocument) {
+				d.Categories[0].Guidelines[0].Recommendations = []string{strings.Repeat("Apply security patches promptly. ", 70)}
+			},
+			ruleType: "oversized-text",
+			severity: SeverityMedium,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := lintTestDocument()
+			tt.modify(doc)
+
+			findings := NewLinter().Lint(doc)
+			if countFindings(findings, tt.ruleType) != 1 {
+				t.Fatalf("Expected 1 %s finding, got %v", tt.ruleType, findings)
+			}
+			groups := GroupBySeverity(findings)
+			if countFindings(groups[tt.severity], tt.ruleType) != 1 {
+				t.Errorf("Expected %s finding with severity %s", tt.ruleType, tt.severity)
+			}
+		})
+	}
+}
+
+func TestLinter_Configuration(t *testing.T) {
+	doc := lintTestDocument()
+	doc.Categories[0].Guidelines[0].Objective = ""
+	doc.Categories[0].Guidelines[0].GuidelineParts[0].Text = "Short text here"
+
+	t.Run("disabled rule", func(t *testing.T) {
+		findings := NewLinter(WithDisabledLintRules("missing-objective")).Lint(doc)
+		if countFindings(findings, "missing-objective") != 0 {
+			t.Errorf("Expected missing-objective to be disabled, got %v", findings)
+		}
+		if countFindings(findings, "short-part-text") != 1 {
+			t.Errorf("Expected short-part-text to still run, got %v", findings)
+		}
+	})
+
+	t.Run("custom threshold", func(t *testing.T) {
+		cfg := DefaultLintConfig()
+		cfg.MinPartTextLength = 5
+		findings := NewLinter(WithLintConfig(cfg)).Lint(doc)
+		if countFindings(findings, "short-part-text") != 0 {
+			t.Errorf("Expected no short-part-text findings with lower threshold, got %v", findings)
+		}
+	})
+
+	t.Run("text length limit", func(t *testing.T) {
+		cfg := DefaultLintConfig()
+		cfg.MaxTextLength = 20
+		findings := NewLinter(WithLintConfig(cfg)).Lint(doc)
+		if countFindings(findings, "oversized-text") != 1 {
+			t.Errorf("Expected the recommendation over 20 characters flagged, got %v", findings)
+		}
+		cfg.MaxTextLength = 0
+		if findings := NewLinter(WithLintConfig(cfg)).Lint(doc); countFindings(findings, "oversized-text") != 0 {
+			t.Errorf("Expected no oversized-text findings without a limit, got %v", findings)
+		}
+	})
+
+	t.Run("text length counts characters", func(t *testing.T) {
+		// 1,500 characters, but 4,500 bytes
+		wide := lintTestDocument()
+		wide.Categories[0].Guidelines[0].GuidelineParts[0].Text = strings.Repeat("認証を", 500)
+		if findings := NewLinter().Lint(wide); countFindings(findings, "oversized-text") != 0 {
+			t.Errorf("Expected multi-byte text under the limit not flagged, got %v", findings)
+		}
+	})
+
+	t.Run("nil document", func(t *testing.T) {
+		if findings := NewLinter().Lint(nil); len(findings) != 0 {
+			t.Errorf("Expected no findings for nil document, got %v", findings)
+		}
+	})
+}
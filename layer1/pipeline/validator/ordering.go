@@ -0,0 +1,93 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ossf/gemara/layer1"
+)
+
+// numericIDPattern splits an ID like "REQ-1.2" into a prefix and a dotted
+// numeric suffix
+var numericIDPattern = regexp.MustCompile(`^(.*?)(\d+(?:\.\d+)*)$`)
+
+// numericID is an ID reduced to a comparable form
+type numericID struct {
+	prefix  string
+	numbers []int
+}
+
+// parseNumericID returns the comparable form of an ID, or false when the
+// ID doesn't end in a dotted number
+func parseNumericID(id string) (numericID, bool) {
+	matches := numericIDPattern.FindStringSubmatch(strings.TrimSpace(id))
+	if matches == nil {
+		return numericID{}, false
+	}
+
+	parsed := numericID{prefix: strings.ToUpper(matches[1])}
+	for _, field := range strings.Split(matches[2], ".") {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return numericID{}, false
+		}
+		parsed.numbers = append(parsed.numbers, n)
+	}
+	return parsed, true
+}
+
+// less reports whether a sorts before b. IDs with different prefixes are
+// not comparable and callers must check that first.
+func (a numericID) less(b numericID) bool {
+	for i := 0; i < len(a.numbers) && i < len(b.numbers); i++ {
+		if a.numbers[i] != b.numbers[i] {
+			return a.numbers[i] < b.numbers[i]
+		}
+	}
+	return len(a.numbers) < len(b.numbers)
+}
+
+// validateOrdering warns when categories, or the guidelines within a
+// category, appear out of numeric ID order. Out-of-order extraction usually
+// points at a parsing problem even though the document is schema-valid.
+func (v *Validator) validateOrdering(categories []layer1.Category, result *ValidationResult) {
+	catIDs := make([]string, len(categories))
+	for i, cat := range categories {
+		catIDs[i] = cat.Id
+	}
+	checkOrdering(catIDs, "categories", "category", result)
+
+	for i, cat := range categories {
+		guideIDs := make([]string, len(cat.Guidelines))
+		for j, guide := range cat.Guidelines {
+			guideIDs[j] = guide.Id
+		}
+		checkOrdering(guideIDs, fmt.Sprintf("categories[%d].guidelines", i), "guideline", result)
+	}
+}
+
+// checkOrdering reports the first ID that sorts before the preceding
+// comparable ID. IDs are comparable when they share the same prefix.
+func checkOrdering(ids []string, path, kind string, result *ValidationResult) {
+	previous := make(map[string]numericID)
+	previousID := make(map[string]string)
+
+	for i, id := range ids {
+		parsed, ok := parseNumericID(id)
+		if !ok {
+			continue
+		}
+		if prev, seen := previous[parsed.prefix]; seen && parsed.less(prev) {
+			result.AddWarning(
+				fmt.Sprintf("%s[%d].id", path, i),
+				fmt.Sprintf("%s ID appears after '%s' but sorts before it; extraction may be out of order", kind, previousID[parsed.prefix]),
+				id,
+			)
+			return
+		}
+		previous[parsed.prefix] = parsed
+		previousID[parsed.prefix] = id
+	}
+}
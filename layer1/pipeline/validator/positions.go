@@ -0,0 +1,113 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// Position is a 1-based line and column in a source document
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// SourceIndex maps validation paths ("categories[0].guidelines[1].id") to
+// where they appear in the YAML or JSON document they were decoded from
+type SourceIndex map[string]Position
+
+// IndexSource builds the path index of a YAML or JSON document (JSON being
+// YAML), using goccy/go-yaml's node positions like the repository's
+// loaders. A mapping entry is indexed at its key and a list item at the
+// item.
+func IndexSource(data []byte) (SourceIndex, error) {
+	file, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+	index := SourceIndex{}
+	if len(file.Docs) > 0 && file.Docs[0].Body != nil {
+		node := file.Docs[0].Body
+		index[""] = nodePosition(node)
+		index.add(node, "")
+	}
+	return index, nil
+}
+
+// add indexes the children of node, which sits at path
+func (idx SourceIndex) add(node ast.Node, path string) {
+	switch n := node.(type) {
+	case *ast.AnchorNode:
+		idx.add(n.Value, path)
+	case *ast.TagNode:
+		idx.add(n.Value, path)
+	case *ast.MappingNode:
+		for _, value := range n.Values {
+			idx.add(value, path)
+		}
+	case *ast.MappingValueNode:
+		key := n.Key.GetToken()
+		childPath := key.Value
+		if path != "" {
+			childPath = path + "." + key.Value
+		}
+		idx[childPath] = Position{Line: key.Position.Line, Column: key.Position.Column}
+		idx.add(n.Value, childPath)
+	case *ast.SequenceNode:
+		for i, item := range n.Values {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			idx[itemPath] = nodePosition(item)
+			idx.add(item, itemPath)
+		}
+	}
+}
+
+// nodePosition returns where a node's content starts: a block mapping's
+// first key rather than the mapping itself
+func nodePosition(node ast.Node) Position {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		if !n.IsFlowStyle && len(n.Values) > 0 {
+			return nodePosition(n.Values[0])
+		}
+	case *ast.MappingValueNode:
+		node = n.Key
+	}
+	pos := node.GetToken().Position
+	return Position{Line: pos.Line, Column: pos.Column}
+}
+
+// Lookup returns the position of path. A path that isn't in the document,
+// such as an omitted required field, gets the position of its closest
+// existing parent.
+func (idx SourceIndex) Lookup(path string) (Position, bool) {
+	for {
+		if pos, ok := idx[path]; ok {
+			return pos, true
+		}
+		if path == "" {
+			return Position{}, false
+		}
+		if strings.HasSuffix(path, "]") {
+			path = path[:strings.LastIndex(path, "[")]
+		} else if i := strings.LastIndex(path, "."); i >= 0 {
+			path = path[:i]
+		} else {
+			path = ""
+		}
+	}
+}
+
+// Locate sets the Line and Column of each error and warning from the
+// document's source index
+func (r *ValidationResult) Locate(idx SourceIndex) {
+	for _, list := range [][]ValidationError{r.Errors, r.Warnings} {
+		for i := range list {
+			if pos, ok := idx.Lookup(list[i].Path); ok {
+				list[i].Line, list[i].Column = pos.Line, pos.Column
+			}
+		}
+	}
+}
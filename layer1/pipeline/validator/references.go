@@ -0,0 +1,43 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/ossf/gemara/layer1"
+)
+
+// validateReferenceIDs checks that every mapping's reference-id names a
+// reference declared in metadata.mapping-references. Dangling references
+// pass the per-field checks but produce OSCAL profile imports with nothing
+// to import, so they're errors in strict and lenient mode alike.
+//
+// Mapping entries aren't checked: their reference-ids name controls within
+// the referenced framework ("AC-2" in NIST-800-53), not declared references.
+func (v *Validator) validateReferenceIDs(doc *layer1.GuidanceDocument, result *ValidationResult) {
+	declared := make(map[string]bool, len(doc.Metadata.MappingReferences))
+	for _, ref := range doc.Metadata.MappingReferences {
+		if ref.Id != "" {
+			declared[ref.Id] = true
+		}
+	}
+
+	check := func(mappings []layer1.Mapping, path string) {
+		for i, mapping := range mappings {
+			if mapping.ReferenceId != "" && !declared[mapping.ReferenceId] {
+				result.AddError(fmt.Sprintf("%s[%d].reference-id", path, i),
+					"does not match any metadata.mapping-references id",
+					mapping.ReferenceId)
+			}
+		}
+	}
+
+	check(doc.ImportedGuidelines, "imported-guidelines")
+	check(doc.ImportedPrinciples, "imported-principles")
+	for i, cat := range doc.Categories {
+		for j, guide := range cat.Guidelines {
+			path := fmt.Sprintf("categories[%d].guidelines[%d]", i, j)
+			check(guide.GuidelineMappings, path+".guideline-mappings")
+			check(guide.PrincipleMappings, path+".principle-mappings")
+		}
+	}
+}
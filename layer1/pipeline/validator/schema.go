@@ -0,0 +1,210 @@
+package validator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	"github.com/ossf/gemara/layer1"
+)
+
+// DefaultSchemaTimeout bounds fetching a remote schema
+const DefaultSchemaTimeout = 30 * time.Second
+
+// maxSchemaBytes caps the size of a fetched schema
+const maxSchemaBytes = 10 << 20 // 10MB
+
+// WithSchema additionally validates documents against a JSON Schema read
+// from a local path or an http(s):// URL, so teams can share a single
+// schema of record. Each source is loaded once per process.
+func WithSchema(source string) Option {
+	return func(v *Validator) {
+		v.schemaSource = source
+	}
+}
+
+// WithSchemaCacheDir keeps fetched remote schemas in dir and reuses them
+// instead of fetching again on later runs
+func WithSchemaCacheDir(dir string) Option {
+	return func(v *Validator) {
+		v.schemaCacheDir = dir
+	}
+}
+
+// WithSchemaTimeout sets the timeout for fetching a remote schema
+func WithSchemaTimeout(timeout time.Duration) Option {
+	return func(v *Validator) {
+		v.schemaTimeout = timeout
+	}
+}
+
+// compiledSchemas holds schemas already loaded in this process, by source
+var compiledSchemas = struct {
+	sync.Mutex
+	bySource map[string]*jsonschema.Schema
+}{bySource: make(map[string]*jsonschema.Schema)}
+
+// validateSchema checks the document against the configured JSON Schema
+func (v *Validator) validateSchema(doc *layer1.GuidanceDocument, result *ValidationResult) {
+	schema, err := v.loadSchema()
+	if err != nil {
+		result.AddError("", fmt.Sprintf("failed to load schema %s: %v", v.schemaSource, err), nil)
+		return
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		result.AddError("", fmt.Sprintf("failed to marshal document for schema validation: %v", err), nil)
+		return
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		result.AddError("", fmt.Sprintf("failed to decode document for schema validation: %v", err), nil)
+		return
+	}
+
+	err = schema.Validate(instance)
+	var validationErr *jsonschema.ValidationError
+	if errors.As(err, &validationErr) {
+		addSchemaErrors(validationErr, result)
+	} else if err != nil {
+		result.AddError("", fmt.Sprintf("schema validation failed: %v", err), nil)
+	}
+}
+
+// addSchemaErrors reports the leaf causes of a schema validation error
+func addSchemaErrors(err *jsonschema.ValidationError, result *ValidationResult) {
+	if len(err.Causes) == 0 {
+		message := err.Error()
+		if out := err.BasicOutput(); out.Error != nil {
+			message = out.Error.String()
+		}
+		result.AddError(instancePath(err.InstanceLocation), message, nil)
+		return
+	}
+	for _, cause := range err.Causes {
+		addSchemaErrors(cause, result)
+	}
+}
+
+// instancePath converts JSON pointer tokens to the validator's path style,
+// e.g. ["categories", "0", "id"] to "categories[0].id"
+func instancePath(tokens []string) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		if _, err := strconv.Atoi(token); err == nil {
+			sb.WriteString("[" + token + "]")
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString(".")
+		}
+		sb.WriteString(token)
+	}
+	return sb.String()
+}
+
+// loadSchema returns the compiled schema for the configured source
+func (v *Validator) loadSchema() (*jsonschema.Schema, error) {
+	compiledSchemas.Lock()
+	defer compiledSchemas.Unlock()
+
+	if schema, ok := compiledSchemas.bySource[v.schemaSource]; ok {
+		return schema, nil
+	}
+
+	var data []byte
+	var err error
+	if isRemoteSchema(v.schemaSource) {
+		data, err = v.fetchSchema()
+	} else {
+		data, err = os.ReadFile(v.schemaSource)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema JSON: %w", err)
+	}
+	location := v.schemaSource
+	if !isRemoteSchema(location) {
+		if location, err = filepath.Abs(location); err != nil {
+			return nil, err
+		}
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(location, doc); err != nil {
+		return nil, err
+	}
+	schema, err := compiler.Compile(location)
+	if err != nil {
+		return nil, err
+	}
+
+	compiledSchemas.bySource[v.schemaSource] = schema
+	return schema, nil
+}
+
+// fetchSchema downloads a remote schema, going through the cache directory
+// when one is configured
+func (v *Validator) fetchSchema() ([]byte, error) {
+	var cachePath string
+	if v.schemaCacheDir != "" {
+		sum := sha256.Sum256([]byte(v.schemaSource))
+		cachePath = filepath.Join(v.schemaCacheDir, hex.EncodeToString(sum[:8])+".json")
+		if data, err := os.ReadFile(cachePath); err == nil {
+			return data, nil
+		}
+	}
+
+	timeout := v.schemaTimeout
+	if timeout <= 0 {
+		timeout = DefaultSchemaTimeout
+	}
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(v.schemaSource)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSchemaBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSchemaBytes {
+		return nil, fmt.Errorf("schema exceeds %d bytes", maxSchemaBytes)
+	}
+
+	if cachePath != "" {
+		if err := os.MkdirAll(v.schemaCacheDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create schema cache: %w", err)
+		}
+		if err := os.WriteFile(cachePath, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to cache schema: %w", err)
+		}
+	}
+	return data, nil
+}
+
+func isRemoteSchema(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
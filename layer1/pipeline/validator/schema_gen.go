@@ -0,0 +1,184 @@
+package validator
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect JSONSchema generates
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// JSONSchema generates a draft-07 JSON Schema describing a Layer-1
+// GuidanceDocument as the validator checks it in lenient mode, built from
+// the same constants (ValidDocumentTypes, ValidNormativities, MinStrength,
+// MaxStrength) so the two stay in sync. Soft issues, which are errors only
+// in strict mode, and checks JSON Schema can't express (duplicate IDs,
+// declared mapping references, ID ordering) aren't part of it. Unknown keys
+// are allowed, as when decoding.
+func JSONSchema() ([]byte, error) {
+	schema := map[string]any{
+		"$schema":     JSONSchemaDraft,
+		"title":       "Gemara Layer-1 Guidance Document",
+		"description": "Generated by the Layer-1 pipeline validator " + version,
+		"type":        "object",
+		"required":    []string{"metadata", "categories"},
+		"properties": map[string]any{
+			"metadata":            schemaRef("Metadata"),
+			"front-matter":        schemaString(),
+			"categories":          schemaArray(schemaRef("Category"), 1),
+			"imported-guidelines": schemaArray(schemaRef("Mapping"), 0),
+			"imported-principles": schemaArray(schemaRef("Mapping"), 0),
+		},
+		"definitions": map[string]any{
+			"DocumentType": map[string]any{
+				"type": "string",
+				"enum": documentTypes(),
+			},
+			"Normativity": map[string]any{
+				"type": "string",
+				"enum": normativities(),
+			},
+			"Metadata": schemaObject([]string{"id", "title", "description", "author"}, map[string]any{
+				"id":                 schemaNonEmpty(),
+				"title":              schemaNonEmpty(),
+				"description":        schemaNonEmpty(),
+				"author":             schemaNonEmpty(),
+				"version":            schemaString(),
+				"last-modified":      schemaString(),
+				"publication-date":   schemaString(),
+				"mapping-references": schemaArray(schemaRef("MappingReference"), 0),
+				"document-type":      schemaRef("DocumentType"),
+				"applicability":      schemaRef("Applicability"),
+				"exemptions":         schemaArray(schemaString(), 0),
+				"revision-history":   schemaArray(schemaRef("Revision"), 0),
+			}),
+			"MappingReference": schemaObject([]string{"id", "title", "version"}, map[string]any{
+				"id":          schemaNonEmpty(),
+				"title":       schemaNonEmpty(),
+				"version":     schemaNonEmpty(),
+				"description": schemaString(),
+				"issuer":      schemaString(),
+				"url":         schemaString(),
+			}),
+			"Applicability": schemaObject(nil, map[string]any{
+				"jurisdictions":      schemaArray(schemaString(), 0),
+				"technology-domains": schemaArray(schemaString(), 0),
+				"industry-sectors":   schemaArray(schemaString(), 0),
+			}),
+			"Revision": schemaObject([]string{"version", "date"}, map[string]any{
+				"version":     schemaString(),
+				"date":        schemaString(),
+				"description": schemaString(),
+			}),
+			"Category": schemaObject([]string{"id", "title", "description"}, map[string]any{
+				"id":            schemaNonEmpty(),
+				"title":         schemaNonEmpty(),
+				"description":   schemaNonEmpty(),
+				"document-type": schemaRef("DocumentType"),
+				"guidelines":    schemaArray(schemaRef("Guideline"), 0),
+			}),
+			"Guideline": schemaObject([]string{"id", "title"}, map[string]any{
+				"id":                 schemaNonEmpty(),
+				"title":              schemaNonEmpty(),
+				"objective":          schemaString(),
+				"recommendations":    schemaArray(schemaString(), 0),
+				"base-guideline-id":  schemaString(),
+				"rationale":          schemaRef("Rationale"),
+				"guideline-parts":    schemaArray(schemaRef("Part"), 0),
+				"guideline-mappings": schemaArray(schemaRef("Mapping"), 0),
+				"principle-mappings": schemaArray(schemaRef("Mapping"), 0),
+				"see-also":           schemaArray(schemaString(), 0),
+				"normativity":        schemaRef("Normativity"),
+			}),
+			// Empty risk and outcome lists encode as null
+			"Rationale": schemaObject(nil, map[string]any{
+				"risks":    schemaNullable(schemaArray(schemaRef("Risk"), 0)),
+				"outcomes": schemaNullable(schemaArray(schemaRef("Outcome"), 0)),
+			}),
+			"Risk": schemaObject([]string{"title", "description"}, map[string]any{
+				"title":       schemaNonEmpty(),
+				"description": schemaNonEmpty(),
+			}),
+			"Outcome": schemaObject([]string{"title", "description"}, map[string]any{
+				"title":       schemaNonEmpty(),
+				"description": schemaNonEmpty(),
+			}),
+			"Part": schemaObject([]string{"id", "text"}, map[string]any{
+				"id":              schemaNonEmpty(),
+				"title":           schemaString(),
+				"text":            schemaNonEmpty(),
+				"recommendations": schemaArray(schemaString(), 0),
+				"normativity":     schemaRef("Normativity"),
+			}),
+			"Mapping": schemaObject([]string{"reference-id"}, map[string]any{
+				"reference-id": schemaNonEmpty(),
+				"entries":      schemaArray(schemaRef("MappingEntry"), 0),
+				"remarks":      schemaString(),
+			}),
+			"MappingEntry": schemaObject([]string{"reference-id", "strength"}, map[string]any{
+				"reference-id": schemaNonEmpty(),
+				"strength": map[string]any{
+					"type":    "integer",
+					"minimum": MinStrength,
+					"maximum": MaxStrength,
+				},
+				"remarks": schemaString(),
+			}),
+		},
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// documentTypes returns the keys of ValidDocumentTypes, sorted
+func documentTypes() []string {
+	var types []string
+	for docType := range ValidDocumentTypes {
+		types = append(types, string(docType))
+	}
+	sort.Strings(types)
+	return types
+}
+
+// normativities returns the keys of ValidNormativities, sorted
+func normativities() []string {
+	var values []string
+	for normativity := range ValidNormativities {
+		values = append(values, string(normativity))
+	}
+	sort.Strings(values)
+	return values
+}
+
+func schemaRef(definition string) map[string]any {
+	return map[string]any{"$ref": "#/definitions/" + definition}
+}
+
+func schemaString() map[string]any {
+	return map[string]any{"type": "string"}
+}
+
+// schemaNonEmpty describes a field the validator reports as "required
+// field is empty"
+func schemaNonEmpty() map[string]any {
+	return map[string]any{"type": "string", "minLength": 1}
+}
+
+func schemaArray(items map[string]any, minItems int) map[string]any {
+	schema := map[string]any{"type": "array", "items": items}
+	if minItems > 0 {
+		schema["minItems"] = minItems
+	}
+	return schema
+}
+
+func schemaNullable(schema map[string]any) map[string]any {
+	return map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+}
+
+func schemaObject(required []string, properties map[string]any) map[string]any {
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
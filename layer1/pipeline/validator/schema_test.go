@@ -0,0 +1,260 @@
+package validator
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ossf/gemara/layer1"
+)
+
+// orgSchema additionally requires a version and numeric category IDs
+const orgSchema = `{
+	"type": "object",
+	"properties": {
+		"metadata": {"type": "object", "required": ["version"]},
+		"categories": {
+			"type": "array",
+			"items": {"properties": {"id": {"type": "string", "pattern": "^[0-9]+$"}}}
+		}
+	}
+}`
+
+func schemaTestDocument() *layer1.GuidanceDocument {
+	return &layer1.GuidanceDocument{
+		Metadata: layer1.Metadata{
+			Id:           "test",
+			Title:        "Test",
+			Description:  "Test",
+			Author:       "Test",
+			DocumentType: "Standard",
+		},
+		Categories: []layer1.Category{
+			{Id: "1", Title: "Cat 1", Description: "Desc"},
+			{Id: "cat-2", Title: "Cat 2", Description: "Desc"},
+		},
+	}
+}
+
+func TestValidator_LocalSchema(t *testing.T) {
+	schemaPath := filepath.Join(t.TempDir(), "layer-1.json")
+	if err := os.WriteFile(schemaPath, []byte(orgSchema), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	result := NewValidator(WithSchema(schemaPath)).Validate(schemaTestDocument())
+
+	if result.Valid {
+		t.Fatal("Expected schema violations to invalidate the document")
+	}
+	paths := make(map[string]bool)
+	for _, err := range result.Errors {
+		paths[err.Path] = true
+	}
+	if !paths["metadata"] || !paths["categories[1].id"] || len(result.Errors) != 2 {
+		t.Errorf("Expected errors at metadata and categories[1].id, got %v", result.Errors)
+	}
+
+	result = NewValidator(WithSchema(filepath.Join(t.TempDir(), "missing.json"))).Validate(schemaTestDocument())
+	if result.Valid {
+		t.Error("Expected an unreadable schema to fail validation")
+	}
+}
+
+func TestValidator_RemoteSchema(t *testing.T) {
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		_, _ = w.Write([]byte(orgSchema))
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	for i := 0; i < 2; i++ {
+		v := NewValidator(WithSchema(server.URL+"/layer-1.json"), WithSchemaCacheDir(cacheDir))
+		if result := v.Validate(schemaTestDocument()); len(result.Errors) != 2 {
+			t.Errorf("Expected 2 schema errors, got %v", result.Errors)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("Expected the schema to be fetched once, got %d", fetches)
+	}
+	if entries, _ := os.ReadDir(cacheDir); len(entries) != 1 {
+		t.Errorf("Expected the schema to be cached on disk, got %d entries", len(entries))
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema failed: %v", err)
+	}
+	schemaPath := filepath.Join(t.TempDir(), "generated.json")
+	if err := os.WriteFile(schemaPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	doc := schemaTestDocument()
+	doc.Metadata.MappingReferences = []layer1.MappingReference{{Id: "NIST", Title: "NIST 800-53", Version: "5"}}
+	doc.Categories[0].Guidelines = []layer1.Guideline{{
+		Id:             "1.1",
+		Title:          "Guide 1.1",
+		Objective:      "Obj",
+		GuidelineParts: []layer1.Part{{Id: "1.1.a", Text: "Text"}},
+		GuidelineMappings: []layer1.Mapping{{
+			ReferenceId: "NIST",
+			Entries:     []layer1.MappingEntry{{ReferenceId: "AC-2", Strength: 80}},
+		}},
+	}}
+	if result := NewValidator(WithSchema(schemaPath)).Validate(doc); !result.Valid {
+		t.Fatalf("Expected a valid document to satisfy the generated schema, got %v", result.Errors)
+	}
+
+	// Every lenient validator error is also reported by the schema, at the
+	// same path
+	doc.Metadata.Author = ""
+	doc.Categories[1].DocumentType = "Policy"
+	doc.Categories[0].Guidelines[0].GuidelineParts[0].Text = ""
+	doc.Categories[0].Guidelines[0].GuidelineMappings[0].Entries[0].Strength = MaxStrength + 1
+	rules := NewValidator().Validate(doc)
+	combined := NewValidator(WithSchema(schemaPath)).Validate(doc)
+	if len(rules.Errors) != 4 {
+		t.Fatalf("Expected 4 validator errors, got %v", rules.Errors)
+	}
+	counts := make(map[string]int)
+	for _, e := range combined.Errors {
+		counts[e.Path]++
+	}
+	for _, e := range rules.Errors {
+		if counts[e.Path] < 2 {
+			t.Errorf("Expected the schema to also report %s, got %v", e.Path, combined.Errors)
+		}
+	}
+}
+
+// TestJSONSchema_RequiredFields checks every definition's required list
+// against the validator: blanking a string field must be a "required field
+// is empty" error exactly when the schema requires the field
+func TestJSONSchema_RequiredFields(t *testing.T) {
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema failed: %v", err)
+	}
+	var schema struct {
+		Definitions map[string]struct {
+			Required   []string                  `json:"required"`
+			Properties map[string]map[string]any `json:"properties"`
+		} `json:"definitions"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Failed to decode schema: %v", err)
+	}
+
+	doc := schemaTestDocument()
+	doc.Metadata.MappingReferences = []layer1.MappingReference{{Id: "NIST", Title: "NIST 800-53", Version: "5"}}
+	doc.Categories[0].Guidelines = []layer1.Guideline{{
+		Id:        "1.1",
+		Title:     "Guide 1.1",
+		Objective: "Obj",
+		Rationale: &layer1.Rationale{
+			Risks:    []layer1.Risk{{Title: "Risk", Description: "Desc"}},
+			Outcomes: []layer1.Outcome{{Title: "Outcome", Description: "Desc"}},
+		},
+		GuidelineParts: []layer1.Part{{Id: "1.1.a", Text: "Text"}},
+		GuidelineMappings: []layer1.Mapping{{
+			ReferenceId: "NIST",
+			Entries:     []layer1.MappingEntry{{ReferenceId: "AC-2", Strength: 80}},
+		}},
+	}}
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Failed to encode document: %v", err)
+	}
+
+	// Where each definition occurs in the document
+	locations := map[string]string{
+		"Metadata":         "metadata",
+		"MappingReference": "metadata.mapping-references[0]",
+		"Category":         "categories[0]",
+		"Guideline":        "categories[0].guidelines[0]",
+		"Risk":             "categories[0].guidelines[0].rationale.risks[0]",
+		"Outcome":          "categories[0].guidelines[0].rationale.outcomes[0]",
+		"Part":             "categories[0].guidelines[0].guideline-parts[0]",
+		"Mapping":          "categories[0].guidelines[0].guideline-mappings[0]",
+		"MappingEntry":     "categories[0].guidelines[0].guideline-mappings[0].entries[0]",
+	}
+	for name, path := range locations {
+		def, ok := schema.Definitions[name]
+		if !ok {
+			t.Fatalf("Expected a %s definition", name)
+		}
+		required := make(map[string]bool)
+		for _, field := range def.Required {
+			required[field] = true
+		}
+		for field, property := range def.Properties {
+			if property["type"] != "string" {
+				continue
+			}
+			var tree map[string]any
+			if err := json.Unmarshal(encoded, &tree); err != nil {
+				t.Fatalf("Failed to decode document: %v", err)
+			}
+			schemaTestNode(t, tree, path)[field] = ""
+			blanked, err := json.Marshal(tree)
+			if err != nil {
+				t.Fatalf("Failed to encode document: %v", err)
+			}
+			var variant layer1.GuidanceDocument
+			if err := json.Unmarshal(blanked, &variant); err != nil {
+				t.Fatalf("Failed to decode document: %v", err)
+			}
+
+			reported := false
+			for _, e := range NewValidator().Validate(&variant).Errors {
+				if e.Path == path+"."+field && e.Message == "required field is empty" {
+					reported = true
+				}
+			}
+			if reported != required[field] {
+				t.Errorf("%s.%s: schema requires it: %v, validator requires it: %v", name, field, required[field], reported)
+			}
+		}
+	}
+}
+
+var schemaTestSegment = regexp.MustCompile(`^([a-z-]+)(?:\[([0-9]+)\])?$`)
+
+// schemaTestNode returns the object at a validator path such as
+// categories[0].guidelines[0] in a decoded JSON document
+func schemaTestNode(t *testing.T, tree map[string]any, path string) map[string]any {
+	t.Helper()
+	node := tree
+	for _, segment := range strings.Split(path, ".") {
+		m := schemaTestSegment.FindStringSubmatch(segment)
+		if m == nil {
+			t.Fatalf("Malformed path segment %q", segment)
+		}
+		var next any = node[m[1]]
+		if m[2] != "" {
+			index, _ := strconv.Atoi(m[2])
+			list, ok := next.([]any)
+			if !ok || index >= len(list) {
+				t.Fatalf("No %s in the test document", segment)
+			}
+			next = list[index]
+		}
+		object, ok := next.(map[string]any)
+		if !ok {
+			t.Fatalf("No object at %s in the test document", segment)
+		}
+		node = object
+	}
+	return node
+}
@@ -0,0 +1,17 @@
+package schemas
+
+// A trimmed Layer-1 schema: documents need metadata with a known
+// document type, and may carry anything else.
+#GuidanceDocument: {
+	metadata: #Metadata
+	...
+}
+
+#Metadata: {
+	id:               string
+	title:            string
+	"document-type"?: #DocumentType
+	...
+}
+
+#DocumentType: "Standard" | "Regulation" | "Best Practice" | "Framework"
@@ -3,7 +3,12 @@ package validator
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/ossf/gemara/layer1"
 	"gopkg.in/yaml.v3"
@@ -14,6 +19,8 @@ type ValidationError struct {
 	Path    string `json:"path"`
 	Message string `json:"message"`
 	Value   any    `json:"value,omitempty"`
+	Line    int    `json:"line,omitempty"`   // Source position, when set by ValidationResult.Locate
+	Column  int    `json:"column,omitempty"`
 }
 
 func (e ValidationError) Error() string {
@@ -23,10 +30,14 @@ func (e ValidationError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Path, e.Message)
 }
 
-// ValidationResult contains all validation errors
+// ValidationResult contains all validation errors. Warnings flag suspicious
+// content and soft issues, such as empty optional fields, that don't
+// violate the schema; they never affect Valid unless WithWarningsAsErrors
+// promotes them.
 type ValidationResult struct {
-	Valid  bool              `json:"valid"`
-	Errors []ValidationError `json:"errors,omitempty"`
+	Valid    bool              `json:"valid"`
+	Errors   []ValidationError `json:"errors,omitempty"`
+	Warnings []ValidationError `json:"warnings,omitempty"`
 }
 
 func (r *ValidationResult) Error() string {
@@ -49,6 +60,24 @@ func (r *ValidationResult) AddError(path, message string, value any) {
 	})
 }
 
+func (r *ValidationResult) AddWarning(path, message string, value any) {
+	r.Warnings = append(r.Warnings, ValidationError{
+		Path:    path,
+		Message: message,
+		Value:   value,
+	})
+}
+
+// version identifies the validator's rule set. Bump it whenever checks are
+// added or changed so stored reports can be traced to the rules that
+// produced them.
+const version = "1.11.0"
+
+// Version returns the validator rule-set version recorded in validation reports
+func Version() string {
+	return version
+}
+
 // ValidDocumentTypes are the allowed document types per CUE schema
 var ValidDocumentTypes = map[layer1.DocumentType]bool{
 	"Standard":      true,
@@ -57,9 +86,34 @@ var ValidDocumentTypes = map[layer1.DocumentType]bool{
 	"Framework":     true,
 }
 
+// ValidNormativities are the allowed normative strengths per CUE schema
+var ValidNormativities = map[layer1.Normativity]bool{
+	"must":   true,
+	"should": true,
+	"may":    true,
+}
+
+// Bounds of a mapping entry's strength
+const (
+	MinStrength = 0
+	MaxStrength = 100
+)
+
 // Validator provides Layer-1 schema validation
 type Validator struct {
-	strict bool // If true, treat warnings as errors
+	strict           bool // If true, soft issues are errors rather than warnings
+	warningsAsErrors bool // If true, every warning is reported as an error
+
+	// Optional JSON Schema of record, see WithSchema
+	schemaSource   string
+	schemaCacheDir string
+	schemaTimeout  time.Duration
+
+	// Optional CUE schema of record, see WithCUESchema
+	cueSchema string
+
+	// Optional format every ID must match, see WithIDPattern
+	idPattern *regexp.Regexp
 }
 
 // Option is a functional option for configuring the validator
@@ -72,6 +126,42 @@ func WithStrictMode(strict bool) Option {
 	}
 }
 
+// WithWarningsAsErrors reports every warning as an error, so a document
+// with warnings is invalid. Unlike strict mode, which only turns soft
+// issues into errors, this also covers content warnings such as duplicate
+// titles or malformed reference URLs.
+func WithWarningsAsErrors(enabled bool) Option {
+	return func(v *Validator) {
+		v.warningsAsErrors = enabled
+	}
+}
+
+// DefaultIDPattern is the ID format WithIDPattern(nil) enforces: letters,
+// digits, dots, underscores and hyphens, which survive OSCAL ID
+// normalization unchanged
+var DefaultIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// WithIDPattern checks that metadata.id and every category, guideline and
+// part ID match re, or DefaultIDPattern if re is nil. Without this option
+// IDs are only checked for presence and uniqueness.
+func WithIDPattern(re *regexp.Regexp) Option {
+	return func(v *Validator) {
+		if re == nil {
+			re = DefaultIDPattern
+		}
+		v.idPattern = re
+	}
+}
+
+// checkIDFormat reports an ID that doesn't match the configured pattern.
+// Empty IDs are left to the required-field checks.
+func (v *Validator) checkIDFormat(path, id string, result *ValidationResult) {
+	if v.idPattern == nil || id == "" || v.idPattern.MatchString(id) {
+		return
+	}
+	result.AddError(path, fmt.Sprintf("ID does not match pattern %s", v.idPattern), id)
+}
+
 // NewValidator creates a new schema validator with optional configuration
 func NewValidator(opts ...Option) *Validator {
 	v := &Validator{strict: false}
@@ -91,11 +181,14 @@ func (v *Validator) Validate(doc *layer1.GuidanceDocument) *ValidationResult {
 	}
 
 	// Validate metadata (required)
-	v.validateMetadata(&doc.Metadata, result)
+	v.validateMetadata(&doc.Metadata, doc.Categories, result)
 
 	// Validate categories
 	v.validateCategories(doc.Categories, result)
 
+	// Check that numeric IDs appear in ascending order
+	v.validateOrdering(doc.Categories, result)
+
 	// Validate imported guidelines mappings
 	for i, mapping := range doc.ImportedGuidelines {
 		v.validateMapping(&mapping, fmt.Sprintf("imported-guidelines[%d]", i), result)
@@ -106,15 +199,36 @@ func (v *Validator) Validate(doc *layer1.GuidanceDocument) *ValidationResult {
 		v.validateMapping(&mapping, fmt.Sprintf("imported-principles[%d]", i), result)
 	}
 
+	// Check that mappings point at declared mapping references
+	v.validateReferenceIDs(doc, result)
+
+	// Validate against the shared schema, if configured
+	if v.schemaSource != "" {
+		v.validateSchema(doc, result)
+	}
+	if v.cueSchema != "" {
+		v.validateCUE(doc, result)
+	}
+
+	if v.warningsAsErrors {
+		for _, w := range result.Warnings {
+			result.AddError(w.Path, w.Message, w.Value)
+		}
+		result.Warnings = nil
+	}
+
 	return result
 }
 
-// validateMetadata validates the Metadata structure
-func (v *Validator) validateMetadata(meta *layer1.Metadata, result *ValidationResult) {
+// validateMetadata validates the Metadata structure. The document type is
+// the default for categories that don't set their own, so it is only
+// reported missing when some category relies on it.
+func (v *Validator) validateMetadata(meta *layer1.Metadata, categories []layer1.Category, result *ValidationResult) {
 	// Required fields per CUE schema
 	if meta.Id == "" {
 		result.AddError("metadata.id", "required field is empty", nil)
 	}
+	v.checkIDFormat("metadata.id", meta.Id, result)
 
 	if meta.Title == "" {
 		result.AddError("metadata.title", "required field is empty", nil)
@@ -135,11 +249,16 @@ func (v *Validator) validateMetadata(meta *layer1.Metadata, result *ValidationRe
 				"must be one of: Standard, Regulation, Best Practice, Framework",
 				meta.DocumentType)
 		}
-	} else if v.strict {
-		// In strict mode, document-type is required
-		result.AddError("metadata.document-type",
-			"required field is empty (strict mode)",
-			nil)
+	} else if !categoriesTyped(categories) {
+		if v.strict {
+			result.AddError("metadata.document-type",
+				"required field is empty (strict mode)",
+				nil)
+		} else {
+			result.AddWarning("metadata.document-type",
+				"field is empty; categories without their own document-type have no type",
+				nil)
+		}
 	}
 
 	// Validate nested applicability if present
@@ -170,6 +289,48 @@ func (v *Validator) validateMappingReference(ref *layer1.MappingReference, path
 	if ref.Version == "" {
 		result.AddError(path+".version", "required field is empty", nil)
 	}
+	// The URL becomes an OSCAL import href, so a malformed one yields
+	// unusable OSCAL even though the Layer-1 document is otherwise valid
+	if ref.Url != "" {
+		if problem := checkReferenceURL(ref.Url); problem != "" {
+			result.AddWarning(path+".url", fmt.Sprintf("mapping reference %q has %s", ref.Id, problem), ref.Url)
+		}
+	}
+}
+
+// checkReferenceURL describes what is wrong with a mapping reference URL,
+// or returns "" if it is a well-formed http(s) or file URL or an explicit
+// relative path such as ./baseline.json
+func checkReferenceURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "a malformed URL"
+	}
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return "a URL without a host"
+		}
+	case "file":
+	case "":
+		if !strings.HasPrefix(rawURL, "./") && !strings.HasPrefix(rawURL, "../") && !strings.HasPrefix(rawURL, "/") {
+			return "a URL without a scheme (use http, https, or file, or a ./ relative path)"
+		}
+	default:
+		return fmt.Sprintf("a URL with unsupported scheme %q (use http, https, or file)", u.Scheme)
+	}
+	return ""
+}
+
+// categoriesTyped reports whether there are categories and every one sets
+// its own document type
+func categoriesTyped(categories []layer1.Category) bool {
+	for _, cat := range categories {
+		if cat.DocumentType == "" {
+			return false
+		}
+	}
+	return len(categories) > 0
 }
 
 // validateCategories validates all categories
@@ -200,15 +361,23 @@ func (v *Validator) validateCategory(cat *layer1.Category, path string, result *
 	if cat.Id == "" {
 		result.AddError(path+".id", "required field is empty", nil)
 	}
+	v.checkIDFormat(path+".id", cat.Id, result)
 	if cat.Title == "" {
 		result.AddError(path+".title", "required field is empty", nil)
 	}
 	if cat.Description == "" {
 		result.AddError(path+".description", "required field is empty", nil)
 	}
+	// An unset type inherits metadata.document-type
+	if cat.DocumentType != "" && !ValidDocumentTypes[cat.DocumentType] {
+		result.AddError(path+".document-type",
+			"must be one of: Standard, Regulation, Best Practice, Framework",
+			cat.DocumentType)
+	}
 
 	// Validate guidelines
 	seenGuidelineIDs := make(map[string]bool)
+	seenGuidelineTitles := make(map[string]string)
 	for i, guide := range cat.Guidelines {
 		guidePath := fmt.Sprintf("%s.guidelines[%d]", path, i)
 
@@ -220,18 +389,43 @@ func (v *Validator) validateCategory(cat *layer1.Category, path string, result *
 			seenGuidelineIDs[guide.Id] = true
 		}
 
+		// Identical titles under distinct IDs usually mean one guideline was
+		// split (e.g. across a page break) during segmentation
+		if title := normalizeTitle(guide.Title); title != "" {
+			if first, ok := seenGuidelineTitles[title]; ok {
+				result.AddWarning(guidePath+".title",
+					fmt.Sprintf("duplicate guideline title within category (same as %s); guidelines may need merging", first),
+					guide.Title)
+			} else {
+				seenGuidelineTitles[title] = guidePath
+			}
+		}
+
 		v.validateGuideline(&guide, guidePath, result)
 	}
 }
 
+// normalizeTitle folds case, whitespace and trailing punctuation so that
+// titles differing only in extraction noise compare equal
+func normalizeTitle(title string) string {
+	title = strings.Join(strings.Fields(strings.ToLower(title)), " ")
+	return strings.TrimRight(title, ".:;,")
+}
+
 // validateGuideline validates a single Guideline
 func (v *Validator) validateGuideline(guide *layer1.Guideline, path string, result *ValidationResult) {
 	if guide.Id == "" {
 		result.AddError(path+".id", "required field is empty", nil)
 	}
+	v.checkIDFormat(path+".id", guide.Id, result)
 	if guide.Title == "" {
 		result.AddError(path+".title", "required field is empty", nil)
 	}
+	// Optional per CUE schema, so a warning even in strict mode
+	if guide.Objective == "" {
+		result.AddWarning(path+".objective", "optional field is empty", nil)
+	}
+	checkNormativity(path+".normativity", guide.Normativity, result)
 
 	// Validate rationale if present
 	if guide.Rationale != nil {
@@ -293,9 +487,18 @@ func (v *Validator) validatePart(part *layer1.Part, path string, result *Validat
 	if part.Id == "" {
 		result.AddError(path+".id", "required field is empty", nil)
 	}
+	v.checkIDFormat(path+".id", part.Id, result)
 	if part.Text == "" {
 		result.AddError(path+".text", "required field is empty", nil)
 	}
+	checkNormativity(path+".normativity", part.Normativity, result)
+}
+
+// checkNormativity reports a set normativity that isn't one of the schema's
+func checkNormativity(path string, normativity layer1.Normativity, result *ValidationResult) {
+	if normativity != "" && !ValidNormativities[normativity] {
+		result.AddError(path, "must be one of: must, should, may", normativity)
+	}
 }
 
 // validateMapping validates a Mapping structure
@@ -310,8 +513,8 @@ func (v *Validator) validateMapping(mapping *layer1.Mapping, path string, result
 			result.AddError(entryPath+".reference-id", "required field is empty", nil)
 		}
 		// Strength should be validated - typically 0-100 or similar range
-		if entry.Strength < 0 || entry.Strength > 100 {
-			result.AddError(entryPath+".strength", "should be between 0 and 100", entry.Strength)
+		if entry.Strength < MinStrength || entry.Strength > MaxStrength {
+			result.AddError(entryPath+".strength", fmt.Sprintf("should be between %d and %d", MinStrength, MaxStrength), entry.Strength)
 		}
 	}
 }
@@ -368,14 +571,12 @@ func (v *Validator) ValidateYAML(data []byte) (*ValidationResult, error) {
 	return v.Validate(&doc), nil
 }
 
-// ValidateFile validates a Layer-1 document from a file path
-func ValidateFile(path string, strict bool) (*ValidationResult, error) {
-	var v *Validator
-	if strict {
-		v = NewValidator(WithStrictMode(true))
-	} else {
-		v = NewValidator()
-	}
+// ValidateFile validates a Layer-1 document from a file path. The format
+// comes from the extension (.json, .yaml, .yml), or from the content for
+// other files. Additional options, such as WithSchema, are applied after
+// the strict mode. A missing file is an error wrapping os.ErrNotExist.
+func ValidateFile(path string, strict bool, opts ...Option) (*ValidationResult, error) {
+	v := NewValidator(append([]Option{WithStrictMode(strict)}, opts...)...)
 	
 	// Read file and determine format
 	data, err := readFileBytes(path)
@@ -383,7 +584,12 @@ func ValidateFile(path string, strict bool) (*ValidationResult, error) {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 	
-	// Try JSON first, then YAML
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return v.ValidateJSON(data)
+	case ".yaml", ".yml":
+		return v.ValidateYAML(data)
+	}
 	if isJSON(data) {
 		return v.ValidateJSON(data)
 	}
@@ -392,8 +598,7 @@ func ValidateFile(path string, strict bool) (*ValidationResult, error) {
 
 // readFileBytes reads file content (extracted for testability)
 func readFileBytes(path string) ([]byte, error) {
-	// Import os package would be needed
-	return nil, fmt.Errorf("file reading not implemented - use ValidateJSON or ValidateYAML directly")
+	return os.ReadFile(path)
 }
 
 // isJSON checks if data looks like JSON
@@ -1,6 +1,12 @@
 package validator
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/ossf/gemara/layer1"
@@ -208,6 +214,79 @@ func TestValidator_InvalidDocumentType(t *testing.T) {
 	}
 }
 
+func TestValidator_CategoryDocumentType(t *testing.T) {
+	doc := &layer1.GuidanceDocument{
+		Metadata: layer1.Metadata{
+			Id:          "test",
+			Title:       "Test",
+			Description: "Test",
+			Author:      "Test",
+		},
+		Categories: []layer1.Category{
+			{Id: "cat-1", Title: "Cat", Description: "Desc", DocumentType: "Regulation"},
+			{Id: "cat-2", Title: "Cat", Description: "Desc", DocumentType: "Guidance"},
+		},
+	}
+
+	result := NewValidator(WithStrictMode(true)).Validate(doc)
+	var paths []string
+	for _, err := range result.Errors {
+		paths = append(paths, err.Path)
+	}
+	if !slices.Contains(paths, "categories[1].document-type") {
+		t.Errorf("Expected error for invalid category document type, got: %v", result.Errors)
+	}
+	// Every category sets a type, so the document needs no default
+	if slices.Contains(paths, "metadata.document-type") {
+		t.Errorf("Expected no metadata document type error when all categories are typed, got: %v", result.Errors)
+	}
+
+	// A category relying on the default requires it in strict mode
+	doc.Categories[1].DocumentType = ""
+	result = NewValidator(WithStrictMode(true)).Validate(doc)
+	found := false
+	for _, err := range result.Errors {
+		if err.Path == "metadata.document-type" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected strict error for missing default document type, got: %v", result.Errors)
+	}
+}
+
+func TestValidator_Normativity(t *testing.T) {
+	doc := &layer1.GuidanceDocument{
+		Metadata: layer1.Metadata{
+			Id:           "test",
+			Title:        "Test",
+			Description:  "Test",
+			Author:       "Test",
+			DocumentType: "Standard",
+		},
+		Categories: []layer1.Category{{
+			Id:          "cat-1",
+			Title:       "Cat",
+			Description: "Desc",
+			Guidelines: []layer1.Guideline{{
+				Id:          "1.1",
+				Title:       "Guide",
+				Objective:   "Obj",
+				Normativity: "must",
+				GuidelineParts: []layer1.Part{
+					{Id: "1.1.a", Text: "Text", Normativity: "may"},
+					{Id: "1.1.b", Text: "Text", Normativity: "SHALL"},
+				},
+			}},
+		}},
+	}
+
+	result := NewValidator().Validate(doc)
+	if len(result.Errors) != 1 || result.Errors[0].Path != "categories[0].guidelines[0].guideline-parts[1].normativity" {
+		t.Errorf("Expected only the SHALL part's normativity rejected, got: %v", result.Errors)
+	}
+}
+
 func TestValidator_ValidDocumentTypes(t *testing.T) {
 	validTypes := []layer1.DocumentType{"Standard", "Regulation", "Best Practice", "Framework"}
 
@@ -285,6 +364,115 @@ func TestValidator_DuplicateIDs(t *testing.T) {
 	}
 }
 
+func TestValidator_DuplicateGuidelineTitles(t *testing.T) {
+	doc := &layer1.GuidanceDocument{
+		Metadata: layer1.Metadata{
+			Id:           "test",
+			Title:        "Test",
+			Description:  "Test",
+			Author:       "Test",
+			DocumentType: "Standard",
+		},
+		Categories: []layer1.Category{
+			{
+				Id:          "1",
+				Title:       "Cat 1",
+				Description: "Desc",
+				Guidelines: []layer1.Guideline{
+					{Id: "1.1", Title: "Protect stored data", Objective: "Obj"},
+					{Id: "1.2", Title: "Protect  Stored Data.", Objective: "Obj"}, // Split across a page break
+					{Id: "1.3", Title: "Encrypt transmissions", Objective: "Obj"},
+				},
+			},
+			{
+				Id:          "2",
+				Title:       "Cat 2",
+				Description: "Desc",
+				Guidelines: []layer1.Guideline{
+					{Id: "2.1", Title: "Protect stored data", Objective: "Obj"}, // Other category, not a duplicate
+				},
+			},
+		},
+	}
+
+	result := NewValidator().Validate(doc)
+
+	if !result.Valid {
+		t.Errorf("Duplicate titles should not invalidate the document, got: %v", result.Errors)
+	}
+	if len(result.Warnings) != 1 {
+		t.Fatalf("Expected 1 duplicate title warning, got %d: %v", len(result.Warnings), result.Warnings)
+	}
+	if result.Warnings[0].Path != "categories[0].guidelines[1].title" {
+		t.Errorf("Expected warning at categories[0].guidelines[1].title, got %v", result.Warnings[0])
+	}
+}
+
+func TestValidator_OrderingWarnings(t *testing.T) {
+	doc := &layer1.GuidanceDocument{
+		Metadata: layer1.Metadata{
+			Id:           "test",
+			Title:        "Test",
+			Description:  "Test",
+			Author:       "Test",
+			DocumentType: "Standard",
+		},
+		Categories: []layer1.Category{
+			{Id: "1", Title: "Cat 1", Description: "Desc"},
+			{Id: "3", Title: "Cat 3", Description: "Desc"},
+			{Id: "2", Title: "Cat 2", Description: "Desc"},
+			{
+				Id:          "10",
+				Title:       "Cat 10",
+				Description: "Desc",
+				Guidelines: []layer1.Guideline{
+					{Id: "REQ-10.2", Title: "Guide 10.2", Objective: "Obj"},
+					{Id: "10.10", Title: "Guide 10.10", Objective: "Obj"}, // Different prefix, not comparable
+					{Id: "REQ-10.1", Title: "Guide 10.1", Objective: "Obj"},
+				},
+			},
+		},
+	}
+
+	result := NewValidator().Validate(doc)
+
+	if !result.Valid {
+		t.Errorf("Ordering problems should not invalidate the document, got: %v", result.Errors)
+	}
+	if len(result.Warnings) != 2 {
+		t.Fatalf("Expected 2 ordering warnings, got %d: %v", len(result.Warnings), result.Warnings)
+	}
+	if result.Warnings[0].Path != "categories[2].id" || result.Warnings[0].Value != "2" {
+		t.Errorf("Expected first category inversion at categories[2], got %v", result.Warnings[0])
+	}
+	if result.Warnings[1].Path != "categories[3].guidelines[2].id" {
+		t.Errorf("Expected guideline inversion at categories[3].guidelines[2], got %v", result.Warnings[1])
+	}
+}
+
+func TestValidator_OrderingNumericNotLexical(t *testing.T) {
+	doc := &layer1.GuidanceDocument{
+		Metadata: layer1.Metadata{
+			Id:           "test",
+			Title:        "Test",
+			Description:  "Test",
+			Author:       "Test",
+			DocumentType: "Standard",
+		},
+		Categories: []layer1.Category{
+			{Id: "2", Title: "Cat 2", Description: "Desc"},
+			{Id: "10", Title: "Cat 10", Description: "Desc"},
+			{Id: "appendix", Title: "Appendix", Description: "Desc"},
+		},
+	}
+
+	result := NewValidator().Validate(doc)
+
+	if len(result.Warnings) != 0 {
+		t.Errorf("Expected no ordering warnings, got: %v", result.Warnings)
+	}
+}
+
 func TestValidator_StrictMode(t *testing.T) {
 	doc := &layer1.GuidanceDocument{
 		Metadata: layer1.Metadata{
@@ -461,6 +649,107 @@ func TestValidator_MappingValidation(t *testing.T) {
 	}
 }
 
+func TestValidator_UndeclaredMappingReference(t *testing.T) {
+	doc := &layer1.GuidanceDocument{
+		Metadata: layer1.Metadata{
+			Id:           "test",
+			Title:        "Test",
+			Description:  "Test",
+			Author:       "Test",
+			DocumentType: "Standard",
+			MappingReferences: []layer1.MappingReference{
+				{Id: "NIST-800-53", Title: "NIST SP 800-53", Version: "rev5"},
+			},
+		},
+		Categories: []layer1.Category{{
+			Id:          "cat-1",
+			Title:       "Cat",
+			Description: "Desc",
+			Guidelines: []layer1.Guideline{{
+				Id:    "1.1",
+				Title: "Guide",
+				GuidelineMappings: []layer1.Mapping{
+					{ReferenceId: "NIST-800-53", Entries: []layer1.MappingEntry{{ReferenceId: "AC-2", Strength: 5}}},
+					{ReferenceId: "ISO-27001", Entries: []layer1.MappingEntry{{ReferenceId: "A.9.2", Strength: 5}}},
+				},
+			}},
+		}},
+	}
+
+	for _, strict := range []bool{true, false} {
+		result := NewValidator(WithStrictMode(strict)).Validate(doc)
+		var dangling []ValidationError
+		for _, err := range result.Errors {
+			if strings.HasSuffix(err.Path, "reference-id") {
+				dangling = append(dangling, err)
+			}
+		}
+		// Entry IDs name controls in the referenced framework, so only the
+		// mapping's own reference-id is resolved
+		if len(dangling) != 1 || dangling[0].Path != "categories[0].guidelines[0].guideline-mappings[1].reference-id" || dangling[0].Value != "ISO-27001" {
+			t.Errorf("strict=%v: expected one error for ISO-27001, got %v", strict, result.Errors)
+		}
+	}
+}
+
+func TestValidator_MappingReferenceURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		wantWarn bool
+	}{
+		{"", false},
+		{"https://www.pcisecuritystandards.org/document_library", false},
+		{"file:///baselines/pci-dss.json", false},
+		{"./baselines/pci-dss.json", false},
+		{"www.example.com/standard", true},
+		{"https://", true},
+		{"ftp://example.com/standard.pdf", true},
+		{"http://exa mple.com/%zz", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			doc := &layer1.GuidanceDocument{
+				Metadata: layer1.Metadata{
+					Id:          "test",
+					Title:       "Test",
+					Description: "Test",
+					Author:      "Test",
+					MappingReferences: []layer1.MappingReference{
+						{Id: "ref-1", Title: "Ref", Version: "1.0", Url: tt.url},
+					},
+				},
+				Categories: []layer1.Category{
+					{
+						Id:          "1",
+						Title:       "Cat 1",
+						Description: "Desc",
+						Guidelines:  []layer1.Guideline{{Id: "1.1", Title: "Guide 1"}},
+					},
+				},
+			}
+
+			result := NewValidator().Validate(doc)
+
+			if !result.Valid {
+				t.Errorf("URL problems should not invalidate the document, got: %v", result.Errors)
+			}
+			warned := false
+			for _, w := range result.Warnings {
+				if w.Path == "metadata.mapping-references[0].url" {
+					warned = true
+					if !strings.Contains(w.Message, "ref-1") {
+						t.Errorf("Expected warning to name the reference, got %q", w.Message)
+					}
+				}
+			}
+			if warned != tt.wantWarn {
+				t.Errorf("URL warning = %v, want %v (warnings: %v)", warned, tt.wantWarn, result.Warnings)
+			}
+		})
+	}
+}
+
 func TestQuickValidate(t *testing.T) {
 	// Valid document
 	validDoc := &layer1.GuidanceDocument{
@@ -554,3 +843,258 @@ func TestValidateJSON(t *testing.T) {
 		t.Error("Expected empty categories to fail validation")
 	}
 }
+
+func TestPathLine(t *testing.T) {
+	data := []byte(`metadata:
+  id: test
+  title: Test
+categories:
+  - id: "1"
+    title: Access
+    guidelines:
+      - id: "1.1"
+        title: Passwords
+      - id: "1.2"
+        guideline-parts:
+          - id: "1.2.a"
+`)
+	
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"", 1},
+		{"metadata.title", 3},
+		{"categories[0].guidelines[1]", 10},
+		{"categories[0].guidelines[1].guideline-parts[0].id", 12},
+		{"categories[0].guidelines[1].title", 10}, // Missing field: its parent's line
+		{"metadata.description", 2},
+		{"categories[3].title", 5},
+	}
+	for _, tt := range tests {
+		if got := PathLine(data, tt.path); got != tt.want {
+			t.Errorf("PathLine(%q) = %d, want %d", tt.path, got, tt.want)
+		}
+	}
+	
+	if got := PathLine([]byte("{\n  \"metadata\": {\n    \"id\": \"x\"\n  }\n}"), "metadata.id"); got != 3 {
+		t.Errorf("Expected line 3 in JSON, got %d", got)
+	}
+	if got := PathLine([]byte("metadata: [unclosed"), "metadata"); got != 0 {
+		t.Errorf("Expected 0 for an unparseable document, got %d", got)
+	}
+}
+
+func TestSourceIndex(t *testing.T) {
+	data := []byte(`metadata:
+  id: test
+  title: Test
+categories:
+  - id: "1"
+    title: Access
+    guidelines:
+      - id: "1.1"
+        title: Passwords
+      - id: "1.2"
+        guideline-parts:
+          - id: "1.2.a"
+`)
+	
+	index, err := IndexSource(data)
+	if err != nil {
+		t.Fatalf("IndexSource failed: %v", err)
+	}
+	tests := []struct {
+		path string
+		want Position
+	}{
+		{"", Position{1, 1}},
+		{"metadata.title", Position{3, 3}},
+		{"categories[0].guidelines[1]", Position{10, 9}},
+		{"categories[0].guidelines[1].guideline-parts[0].id", Position{12, 13}},
+		{"categories[0].guidelines[1].title", Position{10, 9}}, // Missing field: its parent's position
+		{"metadata.description", Position{1, 1}},
+		{"categories[3].title", Position{4, 1}},
+	}
+	for _, tt := range tests {
+		if got, ok := index.Lookup(tt.path); !ok || got != tt.want {
+			t.Errorf("Lookup(%q) = %+v, want %+v", tt.path, got, tt.want)
+		}
+	}
+	
+	if index, err := IndexSource([]byte("{\n  \"metadata\": {\n    \"id\": \"x\"\n  }\n}")); err != nil || index["metadata.id"].Line != 3 {
+		t.Errorf("Expected metadata.id on line 3 of the JSON, got %+v (err %v)", index["metadata.id"], err)
+	}
+	if _, err := IndexSource([]byte("metadata: [unclosed")); err == nil {
+		t.Error("Expected an error for an unparseable document")
+	}
+	
+	result := &ValidationResult{}
+	result.AddError("metadata.description", "required field is empty", nil)
+	result.AddWarning("categories[0].guidelines[0].title", "duplicate title", nil)
+	result.Locate(index)
+	if result.Errors[0].Line != 1 || result.Warnings[0].Line != 9 || result.Warnings[0].Column != 9 {
+		t.Errorf("Unexpected positions: %+v, %+v", result.Errors[0], result.Warnings[0])
+	}
+}
+
+func TestValidateFile(t *testing.T) {
+	dir := t.TempDir()
+	valid := `metadata:
+  id: test-doc
+  title: Test Document
+  description: A document validated from a file
+  author: Test Author
+  document-type: Standard
+categories:
+  - id: cat-1
+    title: Category
+    description: A category
+`
+	path := filepath.Join(dir, "document.yaml")
+	if err := os.WriteFile(path, []byte(valid), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	result, err := ValidateFile(path, true)
+	if err != nil {
+		t.Fatalf("ValidateFile failed: %v", err)
+	}
+	if !result.Valid {
+		t.Errorf("Expected valid document, got errors: %v", result.Errors)
+	}
+
+	// The extension decides the format: YAML content in a .json file is invalid JSON
+	jsonPath := filepath.Join(dir, "document.json")
+	if err := os.WriteFile(jsonPath, []byte(valid), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if result, err := ValidateFile(jsonPath, true); err != nil || result.Valid {
+		t.Errorf("Expected YAML in a .json file to fail validation, got %+v (err: %v)", result, err)
+	}
+
+	if _, err := ValidateFile(filepath.Join(dir, "missing.yaml"), true); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected a not-exist error for a missing file, got %v", err)
+	}
+}
+
+func TestValidator_IDPattern(t *testing.T) {
+	doc := &layer1.GuidanceDocument{
+		Metadata: layer1.Metadata{
+			Id:           "my doc!",
+			Title:        "Test",
+			Description:  "Test",
+			Author:       "Test",
+			DocumentType: "Standard",
+		},
+		Categories: []layer1.Category{{
+			Id:          "cat-1",
+			Title:       "Cat",
+			Description: "Desc",
+			Guidelines: []layer1.Guideline{{
+				Id:             "1.1 a",
+				Title:          "Guide",
+				GuidelineParts: []layer1.Part{{Id: "1.1(a)", Text: "Part text"}},
+			}},
+		}},
+	}
+
+	// Without the option, IDs are only checked for presence and uniqueness
+	if result := NewValidator().Validate(doc); !result.Valid {
+		t.Errorf("Expected no ID format errors without a pattern, got: %v", result.Errors)
+	}
+
+	result := NewValidator(WithIDPattern(nil)).Validate(doc)
+	got := map[string]any{}
+	for _, err := range result.Errors {
+		got[err.Path] = err.Value
+	}
+	want := map[string]any{
+		"metadata.id":                                       "my doc!",
+		"categories[0].guidelines[0].id":                    "1.1 a",
+		"categories[0].guidelines[0].guideline-parts[0].id": "1.1(a)",
+	}
+	for path, value := range want {
+		if got[path] != value {
+			t.Errorf("Expected error at %s for %q, got errors: %v", path, value, result.Errors)
+		}
+	}
+	if _, ok := got["categories[0].id"]; ok {
+		t.Errorf("Expected cat-1 to match the default pattern, got errors: %v", result.Errors)
+	}
+
+	// A custom pattern replaces the default
+	result = NewValidator(WithIDPattern(regexp.MustCompile(`^[a-z]+-\d+$`))).Validate(doc)
+	for _, err := range result.Errors {
+		if err.Path == "categories[0].id" {
+			t.Errorf("Expected cat-1 to match the custom pattern, got %v", err)
+		}
+	}
+	if len(result.Errors) != 3 {
+		t.Errorf("Expected 3 ID format errors with the custom pattern, got: %v", result.Errors)
+	}
+}
+
+func TestValidator_SoftIssues(t *testing.T) {
+	doc := &layer1.GuidanceDocument{
+		Metadata: layer1.Metadata{
+			Id:           "test",
+			Title:        "Test",
+			Description:  "Test",
+			Author:       "Test",
+			DocumentType: "Standard",
+		},
+		Categories: []layer1.Category{
+			{
+				Id:          "1",
+				Title:       "Cat 1",
+				Description: "Desc",
+				Guidelines: []layer1.Guideline{
+					{Id: "1.1", Title: "Guide 1.1"}, // No objective
+				},
+			},
+		},
+	}
+
+	// Lenient: the empty objective is a warning and the document stays valid
+	result := NewValidator().Validate(doc)
+	if !result.Valid || len(result.Errors) != 0 {
+		t.Errorf("Expected a valid document, got: %v", result.Errors)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].Path != "categories[0].guidelines[0].objective" {
+		t.Errorf("Expected an objective warning, got: %v", result.Warnings)
+	}
+
+	// Strict: the objective is optional per schema, so it stays a warning
+	result = NewValidator(WithStrictMode(true)).Validate(doc)
+	if !result.Valid || len(result.Warnings) != 1 {
+		t.Errorf("Expected an objective warning in strict mode, got errors %v, warnings %v", result.Errors, result.Warnings)
+	}
+	result = NewValidator(WithStrictMode(true), WithWarningsAsErrors(true)).Validate(doc)
+	if result.Valid || len(result.Errors) != 1 || len(result.Warnings) != 0 {
+		t.Errorf("Expected the objective warning as an error, got errors %v, warnings %v", result.Errors, result.Warnings)
+	}
+
+	// A mapping reference without a version is a hard error
+	doc.Metadata.MappingReferences = []layer1.MappingReference{{Id: "ref-1", Title: "Ref"}}
+	result = NewValidator().Validate(doc)
+	if result.Valid || len(result.Errors) != 1 || result.Errors[0].Path != "metadata.mapping-references[0].version" {
+		t.Errorf("Expected a required version error, got: %v", result.Errors)
+	}
+	doc.Metadata.MappingReferences = nil
+
+	// Warnings as errors also promotes content warnings
+	doc.Categories[0].Guidelines[0].Objective = "Obj"
+	doc.Categories[0].Guidelines = append(doc.Categories[0].Guidelines,
+		layer1.Guideline{Id: "1.2", Title: "Guide 1.1", Objective: "Obj"})
+	result = NewValidator().Validate(doc)
+	if !result.Valid || len(result.Warnings) != 1 {
+		t.Errorf("Expected a duplicate title warning, got errors %v, warnings %v", result.Errors, result.Warnings)
+	}
+	result = NewValidator(WithWarningsAsErrors(true)).Validate(doc)
+	if result.Valid || len(result.Errors) != 1 || len(result.Warnings) != 0 {
+		t.Fatalf("Expected the warning as an error, got errors %v, warnings %v", result.Errors, result.Warnings)
+	}
+	if result.Errors[0].Path != "categories[0].guidelines[1].title" {
+		t.Errorf("Expected error at categories[0].guidelines[1].title, got %v", result.Errors[0])
+	}
+}
@@ -45,6 +45,19 @@ func NewAssessment(requirementId string, description string, applicability []str
 	return a, err
 }
 
+// IsApplicable reports whether the assessment's applicability includes any of
+// the target applicabilities
+func (a *AssessmentLog) IsApplicable(targetApplicability []string) bool {
+	for _, aa := range a.Applicability {
+		for _, ta := range targetApplicability {
+			if aa == ta {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // AddStep queues a new step in the AssessmentLog
 func (a *AssessmentLog) AddStep(step AssessmentStep) {
 	a.Steps = append(a.Steps, step)
@@ -59,18 +72,16 @@ func (a *AssessmentLog) runStep(targetData interface{}, step AssessmentStep) Res
 }
 
 // Run will execute all steps, halting if any step does not return layer4.Passed.
+// Each run starts from NotRun, so an assessment with no steps stays NotRun and
+// is skipped by result roll-ups rather than reported as Unknown.
 func (a *AssessmentLog) Run(targetData interface{}) Result {
 	a.Result = NotRun
-	if a.Result != NotRun {
-		return a.Result
-	}
+	a.StepsExecuted = 0
 
-	a.Start = Datetime(time.Now().Format(time.RFC3339))
-	err := a.precheck()
-	if err != nil {
-		a.Result = Unknown
+	if err := a.precheck(); err != nil {
 		return a.Result
 	}
+	a.Start = Datetime(time.Now().Format(time.RFC3339))
 	for _, step := range a.Steps {
 		if a.runStep(targetData, step) == Failed {
 			return Failed
@@ -81,14 +92,17 @@ func (a *AssessmentLog) Run(targetData interface{}) Result {
 }
 
 // precheck verifies that the assessment has all the required fields.
-// It returns an error if the assessment is not valid.
+// It returns an error if the assessment is not valid. An assessment with
+// steps but missing fields is Unknown; one with no steps is left NotRun.
 func (a *AssessmentLog) precheck() error {
 	if a.Requirement.EntryId == "" || a.Description == "" || a.Applicability == nil || a.Steps == nil || len(a.Applicability) == 0 || len(a.Steps) == 0 {
 		message := fmt.Sprintf(
 			"expected all AssessmentLog fields to have a value, but got: requirementId=len(%v), description=len=(%v), applicability=len(%v), steps=len(%v)",
 			len(a.Requirement.EntryId), len(a.Description), len(a.Applicability), len(a.Steps),
 		)
-		a.Result = Unknown
+		if len(a.Steps) > 0 {
+			a.Result = Unknown
+		}
 		a.Message = message
 		return errors.New(message)
 	}
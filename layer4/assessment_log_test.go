@@ -191,3 +191,38 @@ func TestNewAssessment(t *testing.T) {
 		})
 	}
 }
+
+// TestRunWithoutSteps ensures an assessment with no steps reports NotRun rather than Unknown
+func TestRunWithoutSteps(t *testing.T) {
+	var empty AssessmentLog
+	if empty.Result != NotRun {
+		t.Errorf("expected un-run assessment to be %s, got %s", NotRun, empty.Result)
+	}
+	if result := empty.Run(nil); result != NotRun {
+		t.Errorf("expected Run with no steps to return %s, got %s", NotRun, result)
+	}
+	if empty.Result != NotRun {
+		t.Errorf("expected assessment with no steps to stay %s, got %s", NotRun, empty.Result)
+	}
+
+	assessment, err := NewAssessment("test", "test", testingApplicability, []AssessmentStep{})
+	if err == nil {
+		t.Error("expected error for assessment with no steps, got nil")
+	}
+	if assessment.Result != NotRun {
+		t.Errorf("expected new assessment with no steps to be %s, got %s", NotRun, assessment.Result)
+	}
+
+	// Missing fields on an assessment that does have steps are still Unknown
+	invalid := AssessmentLog{Steps: []AssessmentStep{passingAssessmentStep}}
+	if result := invalid.Run(nil); result != Unknown {
+		t.Errorf("expected invalid assessment with steps to be %s, got %s", Unknown, result)
+	}
+
+	// Never-run assessments don't change the control evaluation roll-up
+	c := &ControlEvaluation{AssessmentLogs: []*AssessmentLog{assessment}}
+	c.Evaluate(nil, testingApplicability)
+	if c.Result != NotRun {
+		t.Errorf("expected control evaluation to be %s, got %s", NotRun, c.Result)
+	}
+}
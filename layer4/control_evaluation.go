@@ -1,5 +1,7 @@
 package layer4
 
+import "fmt"
+
 // AddAssessment creates a new AssessmentLog object and adds it to the ControlEvaluation.
 func (c *ControlEvaluation) AddAssessment(requirementId string, description string, applicability []string, steps []AssessmentStep) (assessment *AssessmentLog) {
 	assessment, err := NewAssessment(requirementId, description, applicability, steps)
@@ -11,32 +13,93 @@ func (c *ControlEvaluation) AddAssessment(requirementId string, description stri
 	return
 }
 
+// AggregationStrategy determines how the results of a control evaluation's
+// assessments combine into the control evaluation's result.
+type AggregationStrategy int
+
+const (
+	// Worst reports the most severe assessment result (Failed > Unknown > NeedsReview > Passed),
+	// halting at the first failure. This is the default.
+	Worst AggregationStrategy = iota
+	// AllMustPass reports Passed only if every applicable assessment passed. Any other
+	// result fails the control evaluation, halting at the first assessment that did not pass.
+	AllMustPass
+	// AnyPass reports Passed as soon as one applicable assessment passes. If none pass,
+	// the most severe result is reported, as with Worst.
+	AnyPass
+)
+
+type evaluateOpts struct {
+	strategy AggregationStrategy
+}
+
+// EvaluateOption defines an option to tune the behavior of Evaluate.
+type EvaluateOption func(opts *evaluateOpts)
+
+// WithAggregationStrategy is an EvaluateOption that sets how assessment results are
+// combined into the control evaluation result. If unset, Worst is used.
+func WithAggregationStrategy(strategy AggregationStrategy) EvaluateOption {
+	return func(opts *evaluateOpts) {
+		opts.strategy = strategy
+	}
+}
+
 // Evaluate runs each step in each assessment, updating the relevant fields on the control evaluation.
-// It will halt if a step returns a failed result. The targetData is the data that the assessment will be run against.
-// The userApplicability is a slice of strings that determine when the assessment is applicable. The changesAllowed
-// determines whether the assessment is allowed to execute its changes.
-func (c *ControlEvaluation) Evaluate(targetData interface{}, userApplicability []string) {
+// It will halt once the aggregation strategy has decided the result; by default that is when a step
+// returns a failed result. The targetData is the data that the assessment will be run against.
+// The userApplicability is a slice of strings describing the target; only assessments whose applicability includes
+// at least one of them are run. The rest are marked NotApplicable, and if no assessment applies the control
+// evaluation itself is NotApplicable.
+func (c *ControlEvaluation) Evaluate(targetData interface{}, userApplicability []string, opts ...EvaluateOption) {
+	options := evaluateOpts{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	if len(c.AssessmentLogs) == 0 {
 		c.Result = NeedsReview
 		return
 	}
+	var ran bool
 	for _, assessment := range c.AssessmentLogs {
-		var applicable bool
-		for _, aa := range assessment.Applicability {
-			for _, ua := range userApplicability {
-				if aa == ua {
-					applicable = true
-					break
-				}
-			}
+		if !assessment.IsApplicable(userApplicability) {
+			assessment.Result = NotApplicable
+			assessment.Message = fmt.Sprintf("assessment applicability %v does not include any of %v", assessment.Applicability, userApplicability)
+			continue
+		}
+		ran = true
+		result := assessment.Run(targetData)
+		c.Message = assessment.Message
+		if c.aggregate(options.strategy, result) {
+			break
+		}
+	}
+	if !ran {
+		c.Result = NotApplicable
+		c.Message = "no assessments are applicable to the evaluation target"
+	}
+}
+
+// aggregate folds an assessment result into the control evaluation result
+// under the given strategy, reporting whether the result is now decided.
+func (c *ControlEvaluation) aggregate(strategy AggregationStrategy, result Result) bool {
+	switch strategy {
+	case AllMustPass:
+		if result != Passed && result != NotRun {
+			c.Result = Failed
+			return true
 		}
-		if applicable {
-			result := assessment.Run(targetData)
-			c.Result = UpdateAggregateResult(c.Result, result)
-			c.Message = assessment.Message
-			if c.Result == Failed {
-				break
-			}
+		c.Result = UpdateAggregateResult(c.Result, result)
+	case AnyPass:
+		if result == Passed {
+			c.Result = Passed
+			return true
 		}
+		// Keep going while another assessment may still pass
+		c.Result = UpdateAggregateResult(c.Result, result)
+	default:
+		c.Result = UpdateAggregateResult(c.Result, result)
+		return c.Result == Failed
 	}
+	return false
 }
@@ -110,6 +110,84 @@ func TestEvaluate(t *testing.T) {
 	}
 }
 
+// TestEvaluateAggregationStrategies runs the ControlEvaluation fixtures under each aggregation strategy
+func TestEvaluateAggregationStrategies(t *testing.T) {
+	expected := map[string]map[AggregationStrategy]Result{
+		"ControlEvaluation with no AssessmentLogs":                                {Worst: NeedsReview, AllMustPass: NeedsReview, AnyPass: NeedsReview},
+		"ControlEvaluation with one passing AssessmentLog":                        {Worst: Passed, AllMustPass: Passed, AnyPass: Passed},
+		"ControlEvaluation with one failing AssessmentLog":                        {Worst: Failed, AllMustPass: Failed, AnyPass: Failed},
+		"ControlEvaluation with one NeedsReview AssessmentLog":                    {Worst: NeedsReview, AllMustPass: Failed, AnyPass: NeedsReview},
+		"ControlEvaluation with one Unknown AssessmentLog":                        {Worst: Unknown, AllMustPass: Failed, AnyPass: Unknown},
+		"ControlEvaluation with first NeedsReview and then Unknown AssessmentLog": {Worst: Unknown, AllMustPass: Failed, AnyPass: Unknown},
+		"ControlEvaluation with first Unknown and then NeedsReview AssessmentLog": {Worst: Unknown, AllMustPass: Failed, AnyPass: Unknown},
+		"ControlEvaluation with first Failed and then NeedsReview AssessmentLog":  {Worst: Failed, AllMustPass: Failed, AnyPass: Failed},
+		"ControlEvaluation with first Failing and then Passing AssessmentLog":     {Worst: Failed, AllMustPass: Failed, AnyPass: Passed},
+	}
+	strategyNames := map[AggregationStrategy]string{Worst: "Worst", AllMustPass: "AllMustPass", AnyPass: "AnyPass"}
+
+	for _, test := range controlEvaluationTestData {
+		for strategy, want := range expected[test.testName] {
+			t.Run(test.testName+"/"+strategyNames[strategy], func(t *testing.T) {
+				// Fresh control evaluation so earlier runs don't leak into the result
+				c := &ControlEvaluation{AssessmentLogs: test.control.AssessmentLogs}
+				c.Evaluate(nil, testingApplicability, WithAggregationStrategy(strategy))
+
+				if c.Result != want {
+					t.Errorf("Expected Result to be %v, but it was %v", want, c.Result)
+				}
+			})
+		}
+	}
+}
+
+// TestEvaluateApplicability checks that only assessments matching the target applicability are run
+func TestEvaluateApplicability(t *testing.T) {
+	otherApplicability := []string{"other-applicability"}
+
+	t.Run("matching assessment is run", func(t *testing.T) {
+		assessment := passingAssessmentPtr()
+		c := &ControlEvaluation{AssessmentLogs: []*AssessmentLog{assessment}}
+		c.Evaluate(nil, []string{"unrelated", testingApplicability[0]})
+
+		if c.Result != Passed {
+			t.Errorf("Expected Result to be %v, but it was %v", Passed, c.Result)
+		}
+		if assessment.StepsExecuted != 1 {
+			t.Errorf("Expected 1 step to be executed, but %d were", assessment.StepsExecuted)
+		}
+	})
+
+	t.Run("non-matching assessment is skipped", func(t *testing.T) {
+		assessment := failingAssessmentPtr()
+		c := &ControlEvaluation{AssessmentLogs: []*AssessmentLog{assessment}}
+		c.Evaluate(nil, otherApplicability)
+
+		if c.Result != NotApplicable {
+			t.Errorf("Expected Result to be %v, but it was %v", NotApplicable, c.Result)
+		}
+		if assessment.Result != NotApplicable {
+			t.Errorf("Expected assessment Result to be %v, but it was %v", NotApplicable, assessment.Result)
+		}
+		if assessment.StepsExecuted != 0 {
+			t.Errorf("Expected no steps to be executed, but %d were", assessment.StepsExecuted)
+		}
+	})
+
+	t.Run("only matching assessments affect the result", func(t *testing.T) {
+		skipped := failingAssessmentPtr()
+		skipped.Applicability = otherApplicability
+		c := &ControlEvaluation{AssessmentLogs: []*AssessmentLog{skipped, passingAssessmentPtr()}}
+		c.Evaluate(nil, testingApplicability)
+
+		if c.Result != Passed {
+			t.Errorf("Expected Result to be %v, but it was %v", Passed, c.Result)
+		}
+		if skipped.Result != NotApplicable {
+			t.Errorf("Expected skipped assessment Result to be %v, but it was %v", NotApplicable, skipped.Result)
+		}
+	})
+}
+
 func TestAddAssesment(t *testing.T) {
 
 	controlEvaluationTestData[0].control.AddAssessment("test", "test", []string{}, []AssessmentStep{})
@@ -2,13 +2,59 @@ package layer4
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/ossf/gemara/layer2"
 )
 
 var emptyArtifactURIMessage = "no file associated with this alert"
 
+// ErrRequirementNotInCatalog is returned by ToSARIF with WithStrictCatalog when
+// the log references requirements that the supplied catalog does not define.
+var ErrRequirementNotInCatalog = errors.New("requirements not found in catalog")
+
+// maxMappingStrength is the top of the 1-10 mapping strength scale
+const maxMappingStrength = 10
+
+type sarifOpts struct {
+	strictCatalog bool
+	strength      bool
+	strengthRank  bool
+}
+
+// SARIFOption defines an option to tune the behavior of ToSARIF.
+type SARIFOption func(opts *sarifOpts)
+
+// WithStrictCatalog is a SARIFOption that makes ToSARIF fail with ErrRequirementNotInCatalog,
+// listing the missing requirement IDs, when a catalog is supplied but does not define a
+// control/requirement referenced by the log. By default such rules are emitted un-enriched.
+func WithStrictCatalog(strict bool) SARIFOption {
+	return func(opts *sarifOpts) {
+		opts.strictCatalog = strict
+	}
+}
+
+// WithMappingStrength is a SARIFOption that attaches the mapping strength of each result's
+// control as a "mappingStrength" result property. The strength is that of the evaluation's
+// control mapping when set, else the strongest of the catalog control's guideline mapping
+// entries, clamped to 10. Results whose control has no known strength get no property.
+func WithMappingStrength(include bool) SARIFOption {
+	return func(opts *sarifOpts) {
+		opts.strength = include
+	}
+}
+
+// WithStrengthRank is a SARIFOption that sets each result's rank from its level (error 100,
+// warning 50, note 10) scaled by its control's mapping strength out of 10, so findings for
+// strongly-mapped controls sort first. Results whose control has no known strength get no rank.
+func WithStrengthRank(scale bool) SARIFOption {
+	return func(opts *sarifOpts) {
+		opts.strengthRank = scale
+	}
+}
+
 // ToSARIF converts the evaluation results into a SARIF document (v2.1.0).
 // Each AssessmentLog is emitted as a SARIF result. The rule id is derived from
 // the control id and requirement id.
@@ -19,11 +65,18 @@ var emptyArtifactURIMessage = "no file associated with this alert"
 //     For GitHub Code Scanning, typically use a file path like "README.md".
 //   - catalog: Optional catalog data to enrich SARIF output with requirement text
 //     and recommendations. If nil, only basic information is included.
+//   - opts: Optional settings, e.g. WithStrictCatalog to catch log/catalog drift, or
+//     WithMappingStrength and WithStrengthRank to weight results by mapping strength.
 //
 // PhysicalLocation identifies the artifact (file/repository) where the result was found.
 // LogicalLocation identifies the logical component (assessment step) that produced the result.
 // Region is left nil as we don't have file-specific line/column data.
-func (e EvaluationLog) ToSARIF(artifactURI string, catalog *layer2.Catalog) ([]byte, error) {
+func (e EvaluationLog) ToSARIF(artifactURI string, catalog *layer2.Catalog, opts ...SARIFOption) ([]byte, error) {
+	options := sarifOpts{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	report := &SarifReport{
 		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/123e95847b13fbdd4cbe2120fa5e33355d4a042b/Schemata/sarif-schema-2.1.0.json",
 		Version: "2.1.0",
@@ -39,7 +92,16 @@ func (e EvaluationLog) ToSARIF(artifactURI string, catalog *layer2.Catalog) ([]b
 	ruleIdSeen := map[string]bool{}
 	rules := []ReportingDescriptor{}
 
+	// Requirement IDs the catalog does not define, in order of first use
+	var missing []string
+	missingSeen := map[string]bool{}
+
 	for _, evaluation := range e.Evaluations {
+		var strength int64
+		if options.strength || options.strengthRank {
+			strength = mappingStrength(evaluation, catalog)
+		}
+
 		for _, log := range evaluation.AssessmentLogs {
 			if log == nil {
 				continue
@@ -51,6 +113,13 @@ func (e EvaluationLog) ToSARIF(artifactURI string, catalog *layer2.Catalog) ([]b
 			}
 
 			ruleID := log.Requirement.EntryId
+			if catalog != nil && options.strictCatalog && !missingSeen[ruleID] {
+				if _, requirement := findControlAndRequirement(catalog, evaluation.Control.EntryId, ruleID); requirement == nil {
+					missing = append(missing, ruleID)
+					missingSeen[ruleID] = true
+				}
+			}
+
 			if !ruleIdSeen[ruleID] {
 				rule := ReportingDescriptor{ID: ruleID}
 				if log.Description != "" {
@@ -139,10 +208,23 @@ func (e EvaluationLog) ToSARIF(artifactURI string, catalog *layer2.Catalog) ([]b
 					location,
 				},
 			}
+			if strength > 0 {
+				if options.strength {
+					result.Properties = map[string]interface{}{"mappingStrength": strength}
+				}
+				if options.strengthRank {
+					rank := levelRank(level) * float64(strength) / maxMappingStrength
+					result.Rank = &rank
+				}
+			}
 			run.Results = append(run.Results, result)
 		}
 	}
 
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrRequirementNotInCatalog, strings.Join(missing, ", "))
+	}
+
 	// attach rules if any
 	if len(rules) > 0 {
 		run.Tool.Driver.Rules = rules
@@ -165,6 +247,38 @@ func mapResultToSarifLevel(r Result) string {
 	}
 }
 
+// levelRank is the rank of a result at full mapping strength
+func levelRank(level string) float64 {
+	switch level {
+	case "error":
+		return 100
+	case "warning":
+		return 50
+	default:
+		return 10
+	}
+}
+
+// mappingStrength returns the strength of the evaluation's control mapping, falling back to
+// the strongest guideline mapping entry of the control in the catalog, or 0 when unknown.
+// Strengths above the 1-10 scale are clamped to 10, so ranks stay within 0-100.
+func mappingStrength(evaluation *ControlEvaluation, catalog *layer2.Catalog) int64 {
+	if evaluation.Control.Strength > 0 {
+		return min(evaluation.Control.Strength, maxMappingStrength)
+	}
+	control, _ := findControlAndRequirement(catalog, evaluation.Control.EntryId, "")
+	if control == nil {
+		return 0
+	}
+	var strongest int64
+	for _, mapping := range control.GuidelineMappings {
+		for _, entry := range mapping.Entries {
+			strongest = max(strongest, entry.Strength)
+		}
+	}
+	return min(strongest, maxMappingStrength)
+}
+
 // Minimal SARIF v2.1.0 model we need for export without external deps
 type SarifReport struct {
 	Schema  string `json:"$schema"`
@@ -200,10 +314,12 @@ type ReportingDescriptor struct {
 }
 
 type ResultEntry struct {
-	RuleID    string     `json:"ruleId"`
-	Level     string     `json:"level,omitempty"`
-	Message   Message    `json:"message"`
-	Locations []Location `json:"locations,omitempty"`
+	RuleID     string                 `json:"ruleId"`
+	Level      string                 `json:"level,omitempty"`
+	Rank       *float64               `json:"rank,omitempty"`
+	Message    Message                `json:"message"`
+	Locations  []Location             `json:"locations,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
 }
 
 type Message struct {
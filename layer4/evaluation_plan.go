@@ -111,6 +111,63 @@ func (e EvaluationPlan) ToMarkdownChecklist() (string, error) {
 	return buf.String(), nil
 }
 
+// ToEvaluationLog creates an EvaluationLog skeleton from the plan, ready for a runner
+// to attach steps and execute. Each plan becomes a ControlEvaluation and each assessment
+// procedure an AssessmentLog with the requirement and procedure mapped, the procedure's
+// description, no steps and a NotRun result. Applicability is left empty for the runner
+// to fill in from the Layer 2 requirements. Assessments without procedures still get a
+// log so their requirement is not lost.
+func (e EvaluationPlan) ToEvaluationLog() EvaluationLog {
+	log := EvaluationLog{Metadata: e.Metadata}
+
+	for _, plan := range e.Plans {
+		evaluation := &ControlEvaluation{
+			Name:    plan.Control.EntryId,
+			Result:  NotRun,
+			Control: plan.Control,
+		}
+
+		for _, assessment := range plan.Assessments {
+			requirement := assessment.Requirement
+			if requirement.ReferenceId == "" {
+				requirement.ReferenceId = plan.Control.ReferenceId
+			}
+
+			if len(assessment.Procedures) == 0 {
+				evaluation.AssessmentLogs = append(evaluation.AssessmentLogs, &AssessmentLog{
+					Requirement: requirement,
+					Result:      NotRun,
+				})
+				continue
+			}
+
+			for _, procedure := range assessment.Procedures {
+				// Get description with fallback: Description -> Name -> Id
+				description := procedure.Id
+				if procedure.Description != "" {
+					description = procedure.Description
+				} else if procedure.Name != "" {
+					description = procedure.Name
+				}
+
+				evaluation.AssessmentLogs = append(evaluation.AssessmentLogs, &AssessmentLog{
+					Requirement: requirement,
+					Procedure: Mapping{
+						ReferenceId: requirement.ReferenceId,
+						EntryId:     procedure.Id,
+					},
+					Description: description,
+					Result:      NotRun,
+				})
+			}
+		}
+
+		log.Evaluations = append(log.Evaluations, evaluation)
+	}
+
+	return log
+}
+
 // buildChecklistItems converts an AssessmentPlan into checklist items.
 func buildChecklistItems(plan *AssessmentPlan) ([]ChecklistItem, error) {
 	if plan == nil {
@@ -263,6 +263,67 @@ func Test_ToChecklist(t *testing.T) {
 	require.False(t, item.IsAdditionalProcedure)
 }
 
+func Test_ToEvaluationLog(t *testing.T) {
+	plan := EvaluationPlan{
+		Plans: []AssessmentPlan{
+			{
+				Control: Mapping{
+					ReferenceId: "OSPS-B",
+					EntryId:     "OSPS-AC-01",
+				},
+				Assessments: []Assessment{
+					{
+						// Reference falls back to the control's
+						Requirement: Mapping{EntryId: "OSPS-AC-01.01"},
+						Procedures: []AssessmentProcedure{
+							{
+								Id:          "mfa-config",
+								Name:        "Verify MFA configured for repository",
+								Description: "Check that MFA is configured for the repository",
+							},
+							{
+								Id:   "mfa-policy",
+								Name: "Review the MFA policy",
+							},
+						},
+					},
+					{
+						Requirement: Mapping{ReferenceId: "OSPS-B", EntryId: "OSPS-AC-01.02"},
+					},
+				},
+			},
+		},
+		Metadata: Metadata{
+			Id:     "test-plan",
+			Author: Author{Name: "test-author"},
+		},
+	}
+
+	log := plan.ToEvaluationLog()
+
+	require.Equal(t, plan.Metadata, log.Metadata)
+	require.Len(t, log.Evaluations, 1)
+
+	evaluation := log.Evaluations[0]
+	require.Equal(t, "OSPS-AC-01", evaluation.Name)
+	require.Equal(t, plan.Plans[0].Control, evaluation.Control)
+	require.Equal(t, NotRun, evaluation.Result)
+	require.Len(t, evaluation.AssessmentLogs, 3)
+
+	first := evaluation.AssessmentLogs[0]
+	require.Equal(t, Mapping{ReferenceId: "OSPS-B", EntryId: "OSPS-AC-01.01"}, first.Requirement)
+	require.Equal(t, Mapping{ReferenceId: "OSPS-B", EntryId: "mfa-config"}, first.Procedure)
+	require.Equal(t, "Check that MFA is configured for the repository", first.Description)
+	require.Equal(t, NotRun, first.Result)
+	require.Empty(t, first.Steps)
+
+	require.Equal(t, "Review the MFA policy", evaluation.AssessmentLogs[1].Description)
+
+	// Requirements without procedures are kept
+	require.Equal(t, "OSPS-AC-01.02", evaluation.AssessmentLogs[2].Requirement.EntryId)
+	require.Empty(t, evaluation.AssessmentLogs[2].Procedure.EntryId)
+}
+
 func Test_ToChecklist_ErrorCases(t *testing.T) {
 	t.Run("no assessments", func(t *testing.T) {
 		plan := EvaluationPlan{
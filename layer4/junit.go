@@ -0,0 +1,158 @@
+package layer4
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// ToJUnitXML converts the evaluation results into a JUnit XML report for CI systems.
+// Each ControlEvaluation is emitted as a <testsuite> and each AssessmentLog as a <testcase>.
+//
+// Results map onto JUnit outcomes as follows:
+//   - Passed: a passing test case
+//   - Failed: <failure>
+//   - Unknown: <error>, since the assessment could not reach a verdict
+//   - NeedsReview, NotApplicable, NotRun: <skipped>, carrying the result as the message
+//
+// Every test case also records its result in a "result" property, so reports can
+// tell a NeedsReview assessment apart from one that was not run or not applicable.
+// Messages fall back to the assessment description when empty.
+func (e EvaluationLog) ToJUnitXML() ([]byte, error) {
+	report := JUnitTestSuites{Name: e.Metadata.Id}
+
+	for _, evaluation := range e.Evaluations {
+		if evaluation == nil {
+			continue
+		}
+
+		suite := JUnitTestSuite{Name: evaluation.Name}
+		if suite.Name == "" {
+			suite.Name = evaluation.Control.EntryId
+		}
+
+		var suiteTime float64
+		for _, log := range evaluation.AssessmentLogs {
+			if log == nil {
+				continue
+			}
+
+			testCase := JUnitTestCase{
+				Name:      log.Requirement.EntryId,
+				Classname: evaluation.Control.EntryId,
+				Properties: &JUnitProperties{
+					Properties: []JUnitProperty{{Name: "result", Value: log.Result.String()}},
+				},
+			}
+			if seconds, ok := assessmentDuration(log); ok {
+				testCase.Time = formatJUnitSeconds(seconds)
+				suiteTime += seconds
+			}
+
+			msg := log.Message
+			if msg == "" {
+				msg = log.Description
+			}
+
+			switch log.Result {
+			case Passed:
+			case Failed:
+				testCase.Failure = &JUnitFailure{Message: msg, Type: log.Result.String(), Text: log.Description}
+				suite.Failures++
+			case Unknown:
+				testCase.Error = &JUnitFailure{Message: msg, Type: log.Result.String(), Text: log.Description}
+				suite.Errors++
+			default:
+				testCase.Skipped = &JUnitSkipped{Message: fmt.Sprintf("%s: %s", log.Result, msg)}
+				suite.Skipped++
+			}
+
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+
+		suite.Tests = len(suite.TestCases)
+		suite.Time = formatJUnitSeconds(suiteTime)
+
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Skipped += suite.Skipped
+		report.Suites = append(report.Suites, suite)
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// assessmentDuration returns the seconds between an assessment's start and end, if both are set
+func assessmentDuration(log *AssessmentLog) (float64, bool) {
+	if log.Start == "" || log.End == "" {
+		return 0, false
+	}
+	start, err := time.Parse(time.RFC3339, string(log.Start))
+	if err != nil {
+		return 0, false
+	}
+	end, err := time.Parse(time.RFC3339, string(log.End))
+	if err != nil || end.Before(start) {
+		return 0, false
+	}
+	return end.Sub(start).Seconds(), true
+}
+
+func formatJUnitSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
+
+// Minimal JUnit XML model, following the schema accepted by Jenkins and GitLab
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr,omitempty"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+type JUnitTestCase struct {
+	Name       string           `xml:"name,attr"`
+	Classname  string           `xml:"classname,attr"`
+	Time       string           `xml:"time,attr,omitempty"`
+	Properties *JUnitProperties `xml:"properties,omitempty"`
+	Failure    *JUnitFailure    `xml:"failure,omitempty"`
+	Error      *JUnitFailure    `xml:"error,omitempty"`
+	Skipped    *JUnitSkipped    `xml:"skipped,omitempty"`
+}
+
+type JUnitProperties struct {
+	Properties []JUnitProperty `xml:"property"`
+}
+
+type JUnitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type JUnitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
@@ -0,0 +1,93 @@
+package layer4
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToJUnitXML(t *testing.T) {
+	passed := makeAssessmentLog("REQ-1", "should do a thing", Passed, "", nil)
+	passed.Start = "2024-01-01T00:00:00Z"
+	passed.End = "2024-01-01T00:00:02Z"
+
+	evaluationLog := makeEvaluationLog(Author{Name: "gemara"}, []*AssessmentLog{
+		passed,
+		makeAssessmentLog("REQ-2", "should do another thing", Failed, "thing was not done", nil),
+		makeAssessmentLog("REQ-3", "should check a thing", Unknown, "", nil),
+		makeAssessmentLog("REQ-4", "should not apply", NotApplicable, "", nil),
+		makeAssessmentLog("REQ-5", "should not run", NotRun, "", nil),
+		makeAssessmentLog("REQ-6", "should be reviewed", NeedsReview, "", nil),
+		nil,
+	})
+	evaluationLog.Metadata.Id = "eval-1"
+
+	data, err := evaluationLog.ToJUnitXML()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(data), xml.Header))
+
+	var report JUnitTestSuites
+	require.NoError(t, xml.Unmarshal(data, &report))
+
+	require.Equal(t, "eval-1", report.Name)
+	require.Equal(t, 6, report.Tests)
+	require.Equal(t, 1, report.Failures)
+	require.Equal(t, 1, report.Errors)
+	require.Equal(t, 3, report.Skipped)
+	require.Len(t, report.Suites, 1)
+
+	suite := report.Suites[0]
+	require.Equal(t, "Example Control", suite.Name)
+	require.Equal(t, "2.000", suite.Time)
+	require.Len(t, suite.TestCases, 6)
+
+	cases := map[string]JUnitTestCase{}
+	for _, tc := range suite.TestCases {
+		require.Equal(t, "CTRL-1", tc.Classname)
+		cases[tc.Name] = tc
+	}
+
+	require.Nil(t, cases["REQ-1"].Failure)
+	require.Nil(t, cases["REQ-1"].Error)
+	require.Nil(t, cases["REQ-1"].Skipped)
+	require.Equal(t, "2.000", cases["REQ-1"].Time)
+
+	require.NotNil(t, cases["REQ-2"].Failure)
+	require.Equal(t, "thing was not done", cases["REQ-2"].Failure.Message)
+	require.Equal(t, "Failed", cases["REQ-2"].Failure.Type)
+
+	require.NotNil(t, cases["REQ-3"].Error)
+	require.Equal(t, "should check a thing", cases["REQ-3"].Error.Message)
+
+	require.NotNil(t, cases["REQ-4"].Skipped)
+	require.Equal(t, "Not Applicable: should not apply", cases["REQ-4"].Skipped.Message)
+	require.NotNil(t, cases["REQ-5"].Skipped)
+
+	require.NotNil(t, cases["REQ-6"].Skipped)
+	require.Equal(t, "Needs Review: should be reviewed", cases["REQ-6"].Skipped.Message)
+
+	for name, result := range map[string]string{"REQ-1": "Passed", "REQ-4": "Not Applicable", "REQ-5": "Not Run", "REQ-6": "Needs Review"} {
+		require.NotNil(t, cases[name].Properties, name)
+		require.Equal(t, []JUnitProperty{{Name: "result", Value: result}}, cases[name].Properties.Properties, name)
+	}
+}
+
+func TestToJUnitXML_SuiteNameFallback(t *testing.T) {
+	evaluationLog := EvaluationLog{
+		Evaluations: []*ControlEvaluation{
+			{Control: Mapping{EntryId: "CTRL-2"}},
+			nil,
+		},
+	}
+
+	data, err := evaluationLog.ToJUnitXML()
+	require.NoError(t, err)
+
+	var report JUnitTestSuites
+	require.NoError(t, xml.Unmarshal(data, &report))
+	require.Len(t, report.Suites, 1)
+	require.Equal(t, "CTRL-2", report.Suites[0].Name)
+	require.Equal(t, 0, report.Suites[0].Tests)
+}
@@ -251,6 +251,28 @@ func TestToSARIF(t *testing.T) {
 	}
 }
 
+func TestToSARIF_StrictCatalog(t *testing.T) {
+	catalog := makeCatalog("CTRL-1", "Test Control Title", "Test control objective", "REQ-1", "Requirement text", "")
+	evaluationLog := makeEvaluationLog(Author{Name: "gemara"}, []*AssessmentLog{
+		makeAssessmentLog("REQ-1", "in the catalog", Passed, "", nil),
+		makeAssessmentLog("REQ-2", "missing from the catalog", Failed, "", nil),
+		makeAssessmentLog("REQ-2", "reported once", Failed, "", nil),
+		makeAssessmentLog("REQ-3", "also missing", NeedsReview, "", nil),
+	})
+
+	// Lenient by default: unknown requirements produce un-enriched rules
+	_, err := evaluationLog.ToSARIF("", catalog)
+	require.NoError(t, err)
+
+	_, err = evaluationLog.ToSARIF("", catalog, WithStrictCatalog(true))
+	require.ErrorIs(t, err, ErrRequirementNotInCatalog)
+	require.ErrorContains(t, err, "REQ-2, REQ-3")
+
+	// Without a catalog there is nothing to drift from
+	_, err = evaluationLog.ToSARIF("", nil, WithStrictCatalog(true))
+	require.NoError(t, err)
+}
+
 func TestToSARIF_ResultLevels(t *testing.T) {
 	tests := []struct {
 		result    Result
@@ -351,3 +373,56 @@ func toSARIFReport(t *testing.T, data []byte) *SarifReport {
 	require.NotNil(t, &sarif)
 	return &sarif
 }
+
+func TestToSARIF_MappingStrength(t *testing.T) {
+	catalog := makeCatalog("CTRL-1", "Test Control Title", "Test control objective", "REQ-1", "Requirement text", "")
+	catalog.ControlFamilies[0].Controls[0].GuidelineMappings = []layer2.Mapping{
+		{ReferenceId: "NIST-800-53", Entries: []layer2.MappingEntry{{ReferenceId: "AC-2", Strength: 4}}},
+		{ReferenceId: "ISO-27001", Entries: []layer2.MappingEntry{{ReferenceId: "A.9.2", Strength: 8}}},
+	}
+	evaluationLog := makeEvaluationLog(Author{Name: "gemara"}, []*AssessmentLog{
+		makeAssessmentLog("REQ-1", "failed", Failed, "", nil),
+		makeAssessmentLog("REQ-1", "needs review", NeedsReview, "", nil),
+	})
+
+	// Unchanged by default
+	sarifBytes, err := evaluationLog.ToSARIF("", catalog)
+	require.NoError(t, err)
+	require.NotContains(t, string(sarifBytes), "mappingStrength")
+	require.NotContains(t, string(sarifBytes), `"rank"`)
+
+	// The strongest catalog mapping weights the results
+	sarifBytes, err = evaluationLog.ToSARIF("", catalog, WithMappingStrength(true), WithStrengthRank(true))
+	require.NoError(t, err)
+	results := toSARIFReport(t, sarifBytes).Runs[0].Results
+	require.Len(t, results, 2)
+	require.Equal(t, float64(8), results[0].Properties["mappingStrength"])
+	require.NotNil(t, results[0].Rank)
+	require.InDelta(t, 80, *results[0].Rank, 1e-9)
+	require.NotNil(t, results[1].Rank)
+	require.InDelta(t, 40, *results[1].Rank, 1e-9)
+
+	// The log's own control mapping strength takes precedence
+	evaluationLog.Evaluations[0].Control.Strength = 5
+	sarifBytes, err = evaluationLog.ToSARIF("", catalog, WithStrengthRank(true))
+	require.NoError(t, err)
+	results = toSARIFReport(t, sarifBytes).Runs[0].Results
+	require.Nil(t, results[0].Properties)
+	require.InDelta(t, 50, *results[0].Rank, 1e-9)
+
+	// Strengths off the 1-10 scale are clamped, keeping the rank within 100
+	evaluationLog.Evaluations[0].Control.Strength = 25
+	sarifBytes, err = evaluationLog.ToSARIF("", catalog, WithMappingStrength(true), WithStrengthRank(true))
+	require.NoError(t, err)
+	results = toSARIFReport(t, sarifBytes).Runs[0].Results
+	require.Equal(t, float64(10), results[0].Properties["mappingStrength"])
+	require.InDelta(t, 100, *results[0].Rank, 1e-9)
+
+	// Without a known strength there is nothing to weight by
+	evaluationLog.Evaluations[0].Control.Strength = 0
+	sarifBytes, err = evaluationLog.ToSARIF("", nil, WithMappingStrength(true), WithStrengthRank(true))
+	require.NoError(t, err)
+	results = toSARIFReport(t, sarifBytes).Runs[0].Results
+	require.Nil(t, results[0].Properties)
+	require.Nil(t, results[0].Rank)
+}
@@ -28,6 +28,9 @@ package schemas
 	"document-type"?: #DocumentType  @go(DocumentType)
 	applicability?:   #Applicability @go(Applicability,optional=nillable)
 	exemptions?: [...string]
+
+	// Entries from the document's change-history table
+	"revision-history"?: [...#Revision] @go(RevisionHistory) @yaml("revision-history,omitempty")
 }
 
 #DocumentType: "Standard" | "Regulation" | "Best Practice" | "Framework"
@@ -41,11 +44,22 @@ package schemas
 	"industry-sectors"?: [...string] @go(IndustrySectors) @yaml("industry-sectors,omitempty")
 }
 
+// Revision is one release of the document as listed in its change history
+#Revision: {
+	version:      string
+	date:         string
+	description?: string
+}
+
 // Category represents a logical group of guidelines (i.e. control family)
 #Category: {
 	id:          string
 	title:       string
 	description: string
+
+	// Overrides the metadata document-type for this category, e.g. a best practice
+	// appendix in a regulation
+	"document-type"?: #DocumentType @go(DocumentType) @yaml("document-type,omitempty")
 	guidelines?: [...#Guideline]
 }
 
@@ -91,6 +105,9 @@ package schemas
 
 	// This is akin to related controls, but using more explicit terminology
 	"see-also"?: [...string] @go(SeeAlso) @yaml("see-also,omitempty")
+
+	// Strongest RFC 2119 keyword in the guideline's recommendations
+	normativity?: #Normativity
 }
 
 // Parts include sub-statements of a guideline that can be assessed individually
@@ -99,8 +116,15 @@ package schemas
 	title?: string
 	text:   string
 	recommendations?: [...string]
+
+	// Strongest RFC 2119 keyword in the part's text
+	normativity?: #Normativity
 }
 
+// Normative strength of a requirement per RFC 2119: MUST and SHALL, SHOULD and RECOMMENDED,
+// MAY and OPTIONAL
+#Normativity: "must" | "should" | "may"
+
 // Mapping references is the same from Layer2, but intended for Layer 1 to Layer 1 mappings
 // instead of Layer 2 to Layer 1 mappings.
 #MappingReference: {
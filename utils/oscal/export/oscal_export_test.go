@@ -50,7 +50,15 @@ categories:
 		var profileModel oscal.OscalModels
 		profileData, _ := os.ReadFile(profileFilePath)
 		require.NoError(t, json.Unmarshal(profileData, &profileModel))
-		assert.NotNil(t, profileModel.Profile)
+		require.NotNil(t, profileModel.Profile)
+
+		// The profile imports the catalog written alongside it
+		profile := profileModel.Profile
+		require.NotNil(t, profile.BackMatter)
+		resource := (*profile.BackMatter.Resources)[0]
+		assert.Equal(t, catalogModel.Catalog.UUID, (*resource.Props)[0].Value)
+		assert.Equal(t, "file://"+catalogFilePath, (*resource.Rlinks)[0].Href)
+		assert.Equal(t, "#"+resource.UUID, profile.Imports[len(profile.Imports)-1].Href)
 	})
 
 	t.Run("Failure/NotExists", func(t *testing.T) {
@@ -98,6 +106,45 @@ control-families:
 		assert.NotNil(t, catalogModel.Catalog)
 	})
 
+	t.Run("Success/SplitByFamily", func(t *testing.T) {
+		splitYAML := `
+metadata:
+  id: Test
+  title: Test
+  description: ""
+control-families:
+  - id: AC
+    title: Access Control
+  - id: AU
+    title: Audit
+`
+		splitInputPath := filepath.Join(tempDir, "split.yaml")
+		require.NoError(t, os.WriteFile(splitInputPath, []byte(splitYAML), 0600))
+
+		outputDir := filepath.Join(tempDir, "families")
+		args := []string{"--split-by-family", "--output-dir", outputDir}
+		require.NoError(t, Catalog(splitInputPath, args))
+
+		for _, id := range []string{"AC", "AU"} {
+			var catalogModel oscal.OscalModels
+			catalogData, err := os.ReadFile(filepath.Join(outputDir, id+".json"))
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(catalogData, &catalogModel))
+			require.NotNil(t, catalogModel.Catalog)
+			require.NotNil(t, catalogModel.Catalog.Groups)
+			require.Len(t, *catalogModel.Catalog.Groups, 1)
+			assert.Equal(t, id, (*catalogModel.Catalog.Groups)[0].ID)
+		}
+
+		var index CatalogIndex
+		indexData, err := os.ReadFile(filepath.Join(outputDir, "index.json"))
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(indexData, &index))
+		require.Len(t, index.Families, 2)
+		assert.Equal(t, "AC.json", index.Families[0].File)
+		assert.Equal(t, "Audit", index.Families[1].Title)
+	})
+
 	t.Run("Failure/NotExists", func(t *testing.T) {
 		err := Catalog("non-existent-file.yaml", []string{})
 		require.Error(t, err)
@@ -5,6 +5,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	oscal "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 
@@ -31,7 +33,11 @@ func Guidance(path string, args []string) error {
 		return err
 	}
 
-	oscalProfile, err := guidanceDocument.ToOSCALProfile(fmt.Sprintf("file://%s", *catalogOutputFile))
+	// Link the profile to this catalog so the two are a pair
+	oscalProfile, err := guidanceDocument.ToOSCALProfile(
+		fmt.Sprintf("file://%s", *catalogOutputFile),
+		layer1.WithCatalogUUID(oscalCatalog.UUID),
+	)
 	if err != nil {
 		return err
 	}
@@ -51,9 +57,13 @@ func Guidance(path string, args []string) error {
 	return writeOSCALFile(profileOscalModel, *profileOutputFile)
 }
 
+const controlHREF = "https://example/versions/%s#%s"
+
 func Catalog(path string, args []string) error {
 	cmd := flag.NewFlagSet("catalog", flag.ExitOnError)
 	outputFile := cmd.String("output", "catalog.json", "Path to output file")
+	splitByFamily := cmd.Bool("split-by-family", false, "Write one OSCAL Catalog per control family into --output-dir")
+	outputDir := cmd.String("output-dir", "catalog", "Path to output directory when using --split-by-family")
 	if err := cmd.Parse(args); err != nil {
 		return err
 	}
@@ -64,7 +74,11 @@ func Catalog(path string, args []string) error {
 		return err
 	}
 
-	oscalCatalog, err := catalog.ToOSCAL("https://example/versions/%s#%s")
+	if *splitByFamily {
+		return writeCatalogFamilies(catalog, *outputDir)
+	}
+
+	oscalCatalog, err := catalog.ToOSCAL(controlHREF)
 	if err != nil {
 		return err
 	}
@@ -76,6 +90,101 @@ func Catalog(path string, args []string) error {
 	return writeOSCALFile(oscalModel, *outputFile)
 }
 
+// CatalogIndex lists the per-family catalogs written by --split-by-family
+type CatalogIndex struct {
+	Title    string              `json:"title"`
+	Version  string              `json:"version,omitempty"`
+	Families []CatalogIndexEntry `json:"families"`
+}
+
+// CatalogIndexEntry describes a single per-family catalog file
+type CatalogIndexEntry struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	File     string `json:"file"`
+	Controls int    `json:"controls"`
+}
+
+// writeCatalogFamilies writes one OSCAL Catalog per control family into
+// outputDir, along with an index.json describing the files
+func writeCatalogFamilies(catalog *layer2.Catalog, outputDir string) error {
+	if len(catalog.ControlFamilies) == 0 {
+		return fmt.Errorf("catalog has no control families to split")
+	}
+
+	if err := os.MkdirAll(outputDir, 0750); err != nil {
+		return err
+	}
+
+	index := CatalogIndex{
+		Title:   catalog.Metadata.Title,
+		Version: catalog.Metadata.Version,
+	}
+
+	seen := make(map[string]bool)
+	for _, family := range catalog.ControlFamilies {
+		fileName := familyFileName(family.Id)
+		if seen[fileName] {
+			return fmt.Errorf("control families produce duplicate file name %q", fileName)
+		}
+		seen[fileName] = true
+
+		familyCatalog := *catalog
+		familyCatalog.ControlFamilies = []layer2.ControlFamily{family}
+		if family.Title != "" {
+			familyCatalog.Metadata.Title = fmt.Sprintf("%s - %s", catalog.Metadata.Title, family.Title)
+		}
+
+		oscalCatalog, err := familyCatalog.ToOSCAL(controlHREF)
+		if err != nil {
+			return fmt.Errorf("family %s: %w", family.Id, err)
+		}
+
+		oscalModel := oscal.OscalModels{
+			Catalog: &oscalCatalog,
+		}
+		if err := writeOSCALFile(oscalModel, filepath.Join(outputDir, fileName)); err != nil {
+			return err
+		}
+
+		index.Families = append(index.Families, CatalogIndexEntry{
+			ID:       family.Id,
+			Title:    family.Title,
+			File:     fileName,
+			Controls: len(family.Controls),
+		})
+	}
+
+	indexJSON, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	indexFile := filepath.Join(outputDir, "index.json")
+	if err := os.WriteFile(indexFile, indexJSON, 0600); err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully wrote catalog index to %s\n", indexFile)
+	return nil
+}
+
+// familyFileName returns a file-system safe name for a family's catalog
+func familyFileName(id string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, id)
+	if name == "" {
+		name = "family"
+	}
+	return name + ".json"
+}
+
 func writeOSCALFile(model oscal.OscalModels, outputFile string) error {
 	oscalJSON, err := json.MarshalIndent(model, "", "  ") // Using " " for indent
 	if err != nil {